@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
 	"github.com/ggoulart/michael-connelly-api/cmd/router"
+	"github.com/ggoulart/michael-connelly-api/internal/config"
 	"github.com/spf13/viper"
 )
 
@@ -14,7 +15,7 @@ func main() {
 	loadConfigs()
 	r := router.NewRouter()
 
-	if viper.Get("env") == "local" {
+	if !config.IsLambda() {
 		err := r.Run(":3000")
 		if err != nil {
 			log.Panic(fmt.Errorf("failed to start server: %v", err))
@@ -27,14 +28,12 @@ func main() {
 }
 
 func loadConfigs() {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yml")
-	viper.AddConfigPath("./configs")
+	env := config.Env()
 
-	err := viper.ReadInConfig()
+	err := config.ReadFiles(viper.GetViper(), "./configs", env)
 	if err != nil {
 		log.Panic(fmt.Errorf("failed to load config file: %s", err))
 	}
 
-	viper.Set("env", "local")
+	viper.Set("env", env)
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/ggoulart/michael-connelly-api/cmd/router"
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/adaptations"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/config"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
+	"github.com/ggoulart/michael-connelly-api/internal/relationships"
+	"github.com/ggoulart/michael-connelly-api/internal/series"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	err := config.ReadFiles(viper.GetViper(), "./configs", config.Env())
+	if err != nil {
+		log.Fatalf("failed to load config file: %v", err)
+	}
+
+	cfg, err := config.Load(viper.GetViper())
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Storage.Driver != config.StorageDynamoDB {
+		log.Printf("nothing to migrate for storage driver: %s", cfg.Storage.Driver)
+		return
+	}
+
+	ctx := context.Background()
+
+	awsDynamoDBClient, err := config.NewDynamoDB(ctx, cfg.AWS)
+	if err != nil {
+		log.Fatalf("failed to create DynamoDB client: %v", err)
+	}
+
+	dynamodbClient := dynamo.NewClient(awsDynamoDBClient, uuid.New, cfg.Storage.Tables.UniqueKeys)
+	err = dynamodbClient.Migrate(ctx, router.Schema(cfg.Storage.Tables))
+	if err != nil {
+		log.Fatalf("failed to migrate tables: %v", err)
+	}
+
+	err = dynamodbClient.RekeyUniqueKeys(ctx)
+	if err != nil {
+		log.Fatalf("failed to rekey unique keys: %v", err)
+	}
+
+	relationsRepository := relations.NewRepository(dynamodbClient, cfg.Storage.Tables.Relations, cfg.Storage.Tables.Books)
+	seriesRepository := series.NewRepository(dynamodbClient, cfg.Storage.Tables.Series, cfg.Storage.DuplicatePolicy, relationsRepository)
+	err = seriesRepository.RelinkBooks(ctx)
+	if err != nil {
+		log.Fatalf("failed to link series books: %v", err)
+	}
+
+	err = seriesRepository.BackfillSlugs(ctx)
+	if err != nil {
+		log.Fatalf("failed to backfill series slugs: %v", err)
+	}
+
+	relationshipsRepository := relationships.NewRepository(dynamodbClient, cfg.Storage.Tables.Relationships, cfg.Storage.Tables.Books)
+	err = relationshipsRepository.RelinkBooks(ctx)
+	if err != nil {
+		log.Fatalf("failed to link relationship books: %v", err)
+	}
+
+	charactersRepository := characters.NewRepository(dynamodbClient, cfg.Storage.Tables.Characters, cfg.Storage.DuplicatePolicy, relationsRepository, relationshipsRepository)
+	err = charactersRepository.RelinkBooks(ctx)
+	if err != nil {
+		log.Fatalf("failed to link character books: %v", err)
+	}
+
+	err = charactersRepository.BackfillNameKeys(ctx)
+	if err != nil {
+		log.Fatalf("failed to backfill character name keys: %v", err)
+	}
+
+	adaptationsRepository := adaptations.NewRepository(dynamodbClient, cfg.Storage.Tables.Adaptations, dynamo.DuplicateReturnExisting, relationsRepository)
+	booksRepository := books.NewRepository(dynamodbClient, cfg.Storage.Tables.Books, cfg.Storage.DuplicatePolicy, adaptationsRepository)
+	err = booksRepository.MigrateAdaptations(ctx, adaptationsRepository)
+	if err != nil {
+		log.Fatalf("failed to migrate book adaptations: %v", err)
+	}
+
+	err = booksRepository.BackfillSlugs(ctx)
+	if err != nil {
+		log.Fatalf("failed to backfill book slugs: %v", err)
+	}
+
+	err = adaptationsRepository.RelinkBooks(ctx)
+	if err != nil {
+		log.Fatalf("failed to link adaptation books and cast: %v", err)
+	}
+
+	err = adaptationsRepository.BackfillSlugs(ctx)
+	if err != nil {
+		log.Fatalf("failed to backfill adaptation slugs: %v", err)
+	}
+
+	err = charactersRepository.MigrateCast(ctx, actors.NewRepository(dynamodbClient, cfg.Storage.Tables.Actors, dynamo.DuplicateReturnExisting), adaptationsRepository)
+	if err != nil {
+		log.Fatalf("failed to migrate character actors: %v", err)
+	}
+
+	log.Printf("tables are up to date: %v", cfg.Storage.Tables.Names())
+}
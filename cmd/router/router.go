@@ -2,17 +2,25 @@ package router
 
 import (
 	"context"
+	"crypto/rand"
 	"log"
+	"net/http"
+	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/adaptations"
+	"github.com/ggoulart/michael-connelly-api/internal/apikeys"
+	"github.com/ggoulart/michael-connelly-api/internal/auth"
 	"github.com/ggoulart/michael-connelly-api/internal/books"
 	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/config"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
 	"github.com/ggoulart/michael-connelly-api/internal/health"
+	"github.com/ggoulart/michael-connelly-api/internal/memory"
 	"github.com/ggoulart/michael-connelly-api/internal/middleware"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
+	"github.com/ggoulart/michael-connelly-api/internal/relationships"
+	"github.com/ggoulart/michael-connelly-api/internal/search"
 	"github.com/ggoulart/michael-connelly-api/internal/series"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -20,10 +28,18 @@ import (
 )
 
 type Dependencies struct {
-	BooksController      *books.Controller
-	CharactersController *characters.Controller
-	HealthController     *health.Controller
-	SeriesController     *series.Controller
+	Authenticator           auth.Authenticator
+	ActorsController        *actors.Controller
+	AdaptationsController   *adaptations.Controller
+	APIKeysController       *apikeys.Controller
+	KeyAuthenticator        auth.Authenticator
+	RateLimit               config.RateLimitConfig
+	BooksController         *books.Controller
+	CharactersController    *characters.Controller
+	HealthController        *health.Controller
+	RelationshipsController *relationships.Controller
+	SearchController        *search.Controller
+	SeriesController        *series.Controller
 }
 
 func NewRouter() *gin.Engine {
@@ -31,74 +47,197 @@ func NewRouter() *gin.Engine {
 	r := gin.Default()
 
 	r.Use(middleware.Error())
+	r.Use(middleware.APIKey(d.KeyAuthenticator))
 
 	r.GET("/health", d.HealthController.Health)
 
+	actorsWrite := middleware.Authorize(d.Authenticator, "actors:write")
+	adaptationsWrite := middleware.Authorize(d.Authenticator, "adaptations:write")
+	booksWrite := middleware.Authorize(d.Authenticator, "books:write")
+	charactersWrite := middleware.Authorize(d.Authenticator, "characters:write")
+	seriesWrite := middleware.Authorize(d.Authenticator, "series:write")
+	keysAdmin := middleware.Authorize(d.Authenticator, "keys:admin")
+
+	actorsRead := rateLimit(d.RateLimit, "actors")
+	adaptationsRead := rateLimit(d.RateLimit, "adaptations")
+	booksRead := rateLimit(d.RateLimit, "books")
+	charactersRead := rateLimit(d.RateLimit, "characters")
+	seriesRead := rateLimit(d.RateLimit, "series")
+	searchRead := rateLimit(d.RateLimit, "search")
+
+	key := r.Group("/admin/keys")
+	key.POST("", keysAdmin, d.APIKeysController.Create)
+	key.GET("", keysAdmin, d.APIKeysController.GetAll)
+	key.DELETE("/:key", keysAdmin, d.APIKeysController.Delete)
+
+	actor := r.Group("/actors")
+	actor.POST("", actorsWrite, d.ActorsController.Create)
+	actor.GET("", actorsRead, d.ActorsController.GetAll)
+	actor.GET("/:actor", actorsRead, d.ActorsController.GetBy)
+	actor.PUT("/:actor", actorsWrite, d.ActorsController.Update)
+	actor.DELETE("/:actor", actorsWrite, d.ActorsController.Delete)
+
+	adaptation := r.Group("/adaptations")
+	adaptation.POST("", adaptationsWrite, d.AdaptationsController.Create)
+	adaptation.GET("", adaptationsRead, d.AdaptationsController.GetAll)
+	adaptation.GET("/:adaptation", adaptationsRead, d.AdaptationsController.GetBy)
+	adaptation.PUT("/:adaptation", adaptationsWrite, d.AdaptationsController.Update)
+	adaptation.DELETE("/:adaptation", adaptationsWrite, d.AdaptationsController.Delete)
+
 	book := r.Group("/books")
-	book.POST("", middleware.Admin(), d.BooksController.Create)
-	book.GET("", middleware.RateLimit(), d.BooksController.GetAll)
-	book.GET("/:bookID", middleware.RateLimit(), d.BooksController.GetById)
+	book.POST("", booksWrite, d.BooksController.Create)
+	book.GET("", booksRead, d.BooksController.GetAll)
+	book.GET("/:bookID", booksRead, d.BooksController.GetBy)
+	book.GET("/:bookID/series", booksRead, d.SeriesController.GetByBook)
+	book.GET("/:bookID/characters", booksRead, d.CharactersController.GetByBook)
+	book.PUT("/:bookID", booksWrite, d.BooksController.Update)
+	book.PATCH("/:bookID", booksWrite, d.BooksController.Patch)
+	book.DELETE("/:bookID", booksWrite, d.BooksController.Delete)
 
 	character := r.Group("/characters")
-	character.POST("", middleware.Admin(), d.CharactersController.Create)
-	character.GET("/:character", middleware.RateLimit(), d.CharactersController.GetBy)
+	character.POST("", charactersWrite, d.CharactersController.Create)
+	character.GET("", charactersRead, d.CharactersController.GetAll)
+	character.GET("/:character", charactersRead, d.CharactersController.GetBy)
+	character.GET("/:character/books", charactersRead, d.CharactersController.GetBooks)
+	character.GET("/:character/graph", charactersRead, d.RelationshipsController.Graph)
+	character.POST("/:character/relationships", charactersWrite, d.RelationshipsController.Create)
+	character.PUT("/:character", charactersWrite, d.CharactersController.Update)
+	character.PATCH("/:character", charactersWrite, d.CharactersController.Patch)
+	character.DELETE("/:character", charactersWrite, d.CharactersController.Delete)
 
 	series := r.Group("/series")
-	series.POST("", middleware.Admin(), d.SeriesController.Create)
-	series.GET("", middleware.RateLimit(), d.SeriesController.GetAll)
+	series.POST("", seriesWrite, d.SeriesController.Create)
+	series.GET("", seriesRead, d.SeriesController.GetAll)
+	series.GET("/:series", seriesRead, d.SeriesController.GetBy)
+	series.PUT("/:series", seriesWrite, d.SeriesController.Update)
+	series.PATCH("/:series", seriesWrite, d.SeriesController.Patch)
+	series.DELETE("/:series", seriesWrite, d.SeriesController.Delete)
+
+	r.GET("/search", searchRead, d.SearchController.Search)
 
 	return r
 }
 
-func dependencies() Dependencies {
-	region := "us-east-1"
-	booksTable := "books"
-	characterTable := "characters"
-	seriesTable := "series"
+func rateLimit(cfg config.RateLimitConfig, group string) gin.HandlerFunc {
+	limit := cfg.Groups[group]
+	return middleware.NewRateLimiter(middleware.Limit{PerMinute: limit.PerMinute, Burst: limit.Burst}, cfg.TrustedHops, cfg.IdleTimeout).Handler()
+}
 
-	ctx := context.Background()
+type StorageClient interface {
+	actors.DynamoDBClient
+	adaptations.DynamoDBClient
+	apikeys.DynamoDBClient
+	books.DynamoDBClient
+	characters.DynamoClient
+	series.DynamoDBClient
+	health.DynamoClient
+	relations.DynamoDBClient
+	relationships.DynamoDBClient
+}
 
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+func dependencies() Dependencies {
+	cfg, err := config.Load(viper.GetViper())
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	awsDynamoDBClient := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
-		o.BaseEndpoint = aws.String(viper.GetString("aws.dynamodb.endpoint"))
-		o.Credentials = credentials.NewStaticCredentialsProvider("local", "local", "local")
-	})
-	_, err = awsDynamoDBClient.ListTables(ctx, &dynamodb.ListTablesInput{})
-	if err != nil {
-		log.Fatalf("failed to ping DynamoDB: %v", err)
-	}
-
 	uuidGenerator := uuid.New
 
-	dynamodbClient := dynamo.NewClient(awsDynamoDBClient, uuidGenerator)
-	err = dynamodbClient.CreateTables(ctx)
-	if err != nil {
-		log.Fatalf("failed create : %v", err)
+	var storageClient StorageClient
+	switch cfg.Storage.Driver {
+	case config.StorageMemory:
+		storageClient = memory.NewClient(uuidGenerator)
+	case config.StorageDynamoDB:
+		storageClient = dynamodbStorage(cfg, uuidGenerator)
 	}
 
-	healthService := health.NewService(dynamodbClient)
+	authenticator := jwtAuthenticator(cfg.Auth.JWT)
+
+	apiKeysRepository := apikeys.NewRepository(storageClient, cfg.Storage.Tables.APIKeys)
+	apiKeysService := apikeys.NewService(apiKeysRepository, uuidGenerator, rand.Reader, time.Now)
+	apiKeysController := apikeys.NewController(apiKeysService)
+
+	healthService := health.NewService(storageClient)
 	healthController := health.NewController(healthService)
 
-	booksRepository := books.NewRepository(dynamodbClient, booksTable)
-	booksService := books.NewService(booksRepository)
+	actorsRepository := actors.NewRepository(storageClient, cfg.Storage.Tables.Actors, cfg.Storage.DuplicatePolicy)
+	relationsRepository := relations.NewRepository(storageClient, cfg.Storage.Tables.Relations, cfg.Storage.Tables.Books)
+	adaptationsRepository := adaptations.NewRepository(storageClient, cfg.Storage.Tables.Adaptations, cfg.Storage.DuplicatePolicy, relationsRepository)
+	booksRepository := books.NewRepository(storageClient, cfg.Storage.Tables.Books, cfg.Storage.DuplicatePolicy, adaptationsRepository)
+	relationshipsRepository := relationships.NewRepository(storageClient, cfg.Storage.Tables.Relationships, cfg.Storage.Tables.Books)
+	charactersRepository := characters.NewRepository(storageClient, cfg.Storage.Tables.Characters, cfg.Storage.DuplicatePolicy, relationsRepository, relationshipsRepository)
+	seriesRepository := series.NewRepository(storageClient, cfg.Storage.Tables.Series, cfg.Storage.DuplicatePolicy, relationsRepository)
+
+	searchIndex := search.NewIndex(cfg.Search.MaxAge, time.Now)
+
+	booksService := books.NewService(booksRepository, searchIndex, charactersRepository, seriesRepository, adaptationsRepository, relationshipsRepository)
 	booksController := books.NewController(booksService)
 
-	charactersRepository := characters.NewRepository(dynamodbClient, characterTable)
-	charactersService := characters.NewService(charactersRepository, booksRepository)
+	charactersService := characters.NewService(charactersRepository, booksRepository, actorsRepository, adaptationsRepository, searchIndex)
 	charactersController := characters.NewController(charactersService)
 
-	seriesRepository := series.NewRepository(dynamodbClient, seriesTable)
-	seriesService := series.NewService(seriesRepository, booksRepository)
+	adaptationsService := adaptations.NewService(adaptationsRepository, booksRepository, actorsRepository, charactersRepository)
+	adaptationsController := adaptations.NewController(adaptationsService)
+
+	actorsService := actors.NewService(actorsRepository, adaptationsService)
+	actorsController := actors.NewController(actorsService)
+
+	relationshipsService := relationships.NewService(relationshipsRepository, charactersRepository, booksRepository)
+	relationshipsController := relationships.NewController(relationshipsService)
+
+	seriesService := series.NewService(seriesRepository, booksRepository, searchIndex)
 	seriesController := series.NewController(seriesService)
 
+	searchIndex.Register(booksService, charactersService, seriesService)
+	searchController := search.NewController(searchIndex)
+
 	return Dependencies{
-		BooksController:      booksController,
-		CharactersController: charactersController,
-		HealthController:     healthController,
-		SeriesController:     seriesController,
+		Authenticator:           authenticator,
+		ActorsController:        actorsController,
+		AdaptationsController:   adaptationsController,
+		APIKeysController:       apiKeysController,
+		KeyAuthenticator:        apiKeysService,
+		RateLimit:               cfg.RateLimit,
+		BooksController:         booksController,
+		CharactersController:    charactersController,
+		HealthController:        healthController,
+		RelationshipsController: relationshipsController,
+		SearchController:        searchController,
+		SeriesController:        seriesController,
+	}
+}
+
+func dynamodbStorage(cfg config.Config, uuidGenerator func() uuid.UUID) *dynamo.Client {
+	ctx := context.Background()
+
+	awsDynamoDBClient, err := config.NewDynamoDB(ctx, cfg.AWS)
+	if err != nil {
+		log.Fatalf("failed to create DynamoDB client: %v", err)
+	}
+
+	dynamodbClient := dynamo.NewClient(awsDynamoDBClient, uuidGenerator, cfg.Storage.Tables.UniqueKeys)
+	err = dynamodbClient.VerifyTables(ctx, cfg.Storage.Tables.Names()...)
+	if err != nil {
+		log.Fatalf("failed to verify tables, run cmd/migrate first: %v", err)
+	}
+
+	return dynamodbClient
+}
+
+func jwtAuthenticator(cfg config.JWTConfig) *auth.JWT {
+	var keys auth.KeySet
+	if cfg.JWKS != "" {
+		var err error
+		keys, err = auth.LoadKeySet(context.Background(), cfg.JWKS, &http.Client{Timeout: 10 * time.Second})
+		if err != nil {
+			log.Fatalf("failed to load jwks: %v", err)
+		}
 	}
+
+	authenticator, err := auth.NewJWT(keys, []byte(cfg.Secret), cfg.Issuer, cfg.Audience)
+	if err != nil {
+		log.Fatalf("failed to create authenticator, set auth.jwt.jwks or auth.jwt.secret: %v", err)
+	}
+
+	return authenticator
 }
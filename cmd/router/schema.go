@@ -0,0 +1,48 @@
+package router
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/config"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
+	"github.com/ggoulart/michael-connelly-api/internal/relationships"
+)
+
+func Schema(t config.TablesConfig) []dynamo.TableSchema {
+	id := dynamo.Key{Name: "id", Type: types.ScalarAttributeTypeS}
+
+	return []dynamo.TableSchema{
+		{Name: t.UniqueKeys, HashKey: id},
+		{Name: t.Actors, HashKey: id},
+		{Name: t.Adaptations, HashKey: id},
+		{Name: t.APIKeys, HashKey: id},
+		{Name: t.Books, HashKey: id, Indexes: []dynamo.IndexSchema{{
+			Name:     books.YearIndex,
+			HashKey:  dynamo.Key{Name: "entity", Type: types.ScalarAttributeTypeS},
+			RangeKey: &dynamo.Key{Name: "year", Type: types.ScalarAttributeTypeN},
+		}}, Defaults: books.Defaults()},
+		{Name: t.Characters, HashKey: id, Indexes: []dynamo.IndexSchema{{
+			Name:     characters.NameIndex,
+			HashKey:  dynamo.Key{Name: "entity", Type: types.ScalarAttributeTypeS},
+			RangeKey: &dynamo.Key{Name: "name_key", Type: types.ScalarAttributeTypeS},
+		}}, Defaults: characters.Defaults()},
+		{Name: t.Series, HashKey: id},
+		{Name: t.Relations, HashKey: id, Indexes: []dynamo.IndexSchema{
+			{Name: relations.OwnerIndex, HashKey: dynamo.Key{Name: "owner", Type: types.ScalarAttributeTypeS}},
+			{
+				Name:     relations.BookIndex,
+				HashKey:  dynamo.Key{Name: "book_id", Type: types.ScalarAttributeTypeS},
+				RangeKey: &dynamo.Key{Name: "owner_type", Type: types.ScalarAttributeTypeS},
+			},
+			{Name: relations.ActorIndex, HashKey: dynamo.Key{Name: "actor_id", Type: types.ScalarAttributeTypeS}},
+		}},
+		{Name: t.Relationships, HashKey: id, Indexes: []dynamo.IndexSchema{
+			{Name: relationships.FromIndex, HashKey: dynamo.Key{Name: "from_id", Type: types.ScalarAttributeTypeS}},
+			{Name: relationships.ToIndex, HashKey: dynamo.Key{Name: "to_id", Type: types.ScalarAttributeTypeS}},
+			{Name: relationships.StartBookIndex, HashKey: dynamo.Key{Name: "start_book_id", Type: types.ScalarAttributeTypeS}},
+			{Name: relationships.EndBookIndex, HashKey: dynamo.Key{Name: "end_book_id", Type: types.ScalarAttributeTypeS}},
+		}},
+	}
+}
@@ -0,0 +1,35 @@
+package actors
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+)
+
+var ErrInvalidIMDB = &apperr.InvalidError{Reason: "invalid imdb name id"}
+
+var imdbNameID = regexp.MustCompile(`^(?:https?://(?:www\.|m\.)?imdb\.com/name/)?(nm\d+)/?(?:[?#].*)?$`)
+
+type Actor struct {
+	ID      string
+	Name    string
+	IMDB    string
+	Version int
+}
+
+type Role struct {
+	CharacterID   string
+	CharacterName string
+	Adaptations   []books.Adaptation
+}
+
+func ParseIMDBID(imdb string) (string, error) {
+	match := imdbNameID.FindStringSubmatch(imdb)
+	if match == nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidIMDB, imdb)
+	}
+
+	return match[1], nil
+}
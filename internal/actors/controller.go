@@ -0,0 +1,199 @@
+package actors
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Manager interface {
+	Create(ctx context.Context, actor Actor) (Actor, bool, error)
+	Update(ctx context.Context, actor Actor) (Actor, error)
+	Delete(ctx context.Context, actorID string) error
+	GetById(ctx context.Context, actorID string) (Actor, error)
+	GetByIMDB(ctx context.Context, imdb string) (Actor, error)
+	GetAll(ctx context.Context) ([]Actor, error)
+	GetRoles(ctx context.Context, actorID string) ([]Role, error)
+}
+
+type Controller struct {
+	manager Manager
+}
+
+func NewController(manager Manager) *Controller {
+	return &Controller{manager: manager}
+}
+
+func (c *Controller) Create(ctx *gin.Context) {
+	var actorDTO ActorDTO
+	if err := ctx.BindJSON(&actorDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	createdActor, created, err := c.manager.Create(ctx, actorDTO.ToActor())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	ctx.Header("ETag", etag.Format(createdActor.Version))
+	ctx.JSON(status, NewActorDTO(createdActor))
+}
+
+func (c *Controller) Update(ctx *gin.Context) {
+	var getByIDRequest GetByIDRequest
+	if err := ctx.BindUri(&getByIDRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	version, err := etag.Parse(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var actorDTO ActorDTO
+	if err := ctx.BindJSON(&actorDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	actor := actorDTO.ToActor()
+	actor.ID = getByIDRequest.ActorID
+	actor.Version = version
+
+	updatedActor, err := c.manager.Update(ctx, actor)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(updatedActor.Version))
+	ctx.JSON(http.StatusOK, NewActorDTO(updatedActor))
+}
+
+func (c *Controller) Delete(ctx *gin.Context) {
+	var getByIDRequest GetByIDRequest
+	if err := ctx.BindUri(&getByIDRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err := c.manager.Delete(ctx, getByIDRequest.ActorID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) GetBy(ctx *gin.Context) {
+	var getByIDRequest GetByIDRequest
+	if err := ctx.BindUri(&getByIDRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var actor Actor
+	actorID, err := uuid.Parse(getByIDRequest.ActorID)
+	if err != nil {
+		actor, err = c.manager.GetByIMDB(ctx, getByIDRequest.ActorID)
+	} else {
+		actor, err = c.manager.GetById(ctx, actorID.String())
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	roles, err := c.manager.GetRoles(ctx, actor.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(actor.Version))
+	ctx.JSON(http.StatusOK, NewActorRolesDTO(actor, roles))
+}
+
+func (c *Controller) GetAll(ctx *gin.Context) {
+	actorsList, err := c.manager.GetAll(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	sort.Slice(actorsList, func(i, j int) bool {
+		return actorsList[i].Name < actorsList[j].Name
+	})
+
+	actorsDTO := []ActorDTO{}
+	for _, actor := range actorsList {
+		actorsDTO = append(actorsDTO, NewActorDTO(actor))
+	}
+
+	ctx.JSON(http.StatusOK, actorsDTO)
+}
+
+type ActorDTO struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name" binding:"required"`
+	IMDB string `json:"imdb" binding:"required"`
+}
+
+type ActorRolesDTO struct {
+	ActorDTO
+	Characters []RoleDTO `json:"characters"`
+}
+
+type RoleDTO struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Adaptations []books.AdaptationDTO `json:"adaptations"`
+}
+
+func NewActorDTO(actor Actor) ActorDTO {
+	return ActorDTO{
+		ID:   actor.ID,
+		Name: actor.Name,
+		IMDB: actor.IMDB,
+	}
+}
+
+func NewActorRolesDTO(actor Actor, roles []Role) ActorRolesDTO {
+	rolesDTO := []RoleDTO{}
+	for _, role := range roles {
+		adaptations := []books.AdaptationDTO{}
+		for _, a := range role.Adaptations {
+			adaptations = append(adaptations, books.AdaptationDTO{ID: a.ID, Description: a.Description, Type: a.Type, IMDB: a.IMDB})
+		}
+
+		rolesDTO = append(rolesDTO, RoleDTO{ID: role.CharacterID, Name: role.CharacterName, Adaptations: adaptations})
+	}
+
+	return ActorRolesDTO{ActorDTO: NewActorDTO(actor), Characters: rolesDTO}
+}
+
+func (r *ActorDTO) ToActor() Actor {
+	return Actor{
+		Name: r.Name,
+		IMDB: r.IMDB,
+	}
+}
+
+type GetByIDRequest struct {
+	ActorID string `uri:"actor" binding:"required"`
+}
@@ -0,0 +1,312 @@
+package actors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestController_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when request body is an invalid json",
+			reqBody: `}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var syntaxErr *json.SyntaxError
+				assert.True(t, errors.As(err, &syntaxErr))
+			},
+		},
+		{
+			name:    "when imdb is missing",
+			reqBody: `{"name": "Titus Welliver"}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when actor already exists",
+			reqBody: `{"name": "Titus Welliver", "imdb": "nm0920038"}`,
+			setup: func(m *ManagerMock) {
+				m.On("Create", mock.Anything, Actor{Name: "Titus Welliver", IMDB: "nm0920038"}).Return(Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 2}, false, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"2"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"actor-id","name":"Titus Welliver","imdb":"nm0920038"}`, r.Body.String())
+			},
+		},
+		{
+			name:    "when actor is created",
+			reqBody: `{"name": "Titus Welliver", "imdb": "nm0920038"}`,
+			setup: func(m *ManagerMock) {
+				m.On("Create", mock.Anything, Actor{Name: "Titus Welliver", IMDB: "nm0920038"}).Return(Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 1}, true, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, `{"id":"actor-id","name":"Titus Welliver","imdb":"nm0920038"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/actors", strings.NewReader(tt.reqBody))
+
+			c.Create(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when If-Match header is missing",
+			setup: func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, etag.ErrMissing))
+			},
+		},
+		{
+			name:    "when actor is updated",
+			ifMatch: `"2"`,
+			setup: func(m *ManagerMock) {
+				m.On("Update", mock.Anything, Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 2}).Return(Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 3}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"actor-id","name":"Titus Welliver","imdb":"nm0920038"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Params = gin.Params{{Key: "actor", Value: "actor-id"}}
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/actors/actor-id", strings.NewReader(`{"name": "Titus Welliver", "imdb": "nm0920038"}`))
+			if tt.ifMatch != "" {
+				ctx.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			c.Update(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name: "when actor is referenced",
+			setup: func(m *ManagerMock) {
+				m.On("Delete", mock.Anything, "actor-id").Return(&apperr.ReferencedError{Resource: "actor", ID: "actor-id"}).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, apperr.ErrReferenced))
+			},
+		},
+		{
+			name: "when actor is deleted",
+			setup: func(m *ManagerMock) {
+				m.On("Delete", mock.Anything, "actor-id").Return(nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusNoContent, r.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Params = gin.Params{{Key: "actor", Value: "actor-id"}}
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/actors/actor-id", nil)
+
+			c.Delete(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetBy(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when actor is not found by imdb",
+			actor: "nm0920038",
+			setup: func(m *ManagerMock) {
+				m.On("GetByIMDB", mock.Anything, "nm0920038").Return(Actor{}, dynamo.ErrNotFound).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, dynamo.ErrNotFound))
+			},
+		},
+		{
+			name:  "when failed to get actor roles",
+			actor: "c6767b2d-438b-4d4c-8b1a-659130a640ca",
+			setup: func(m *ManagerMock) {
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Actor{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Titus Welliver", IMDB: "nm0920038"}, nil).Once()
+				m.On("GetRoles", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return([]Role(nil), assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:  "when actor is found by imdb with characters and adaptations",
+			actor: "nm0920038",
+			setup: func(m *ManagerMock) {
+				m.On("GetByIMDB", mock.Anything, "nm0920038").Return(Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 1}, nil).Once()
+				m.On("GetRoles", mock.Anything, "actor-id").Return([]Role{
+					{CharacterID: "character-id", CharacterName: "Harry Bosch", Adaptations: []books.Adaptation{{ID: "adaptation-id", Description: "Bosch", Type: "series", IMDB: "tt3502248"}}},
+					{CharacterID: "other-character-id", CharacterName: "Jerry Edgar"},
+				}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"1"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"actor-id","name":"Titus Welliver","imdb":"nm0920038","characters":[{"id":"character-id","name":"Harry Bosch","adaptations":[{"id":"adaptation-id","description":"Bosch","type":"series","imdb":"tt3502248"}]},{"id":"other-character-id","name":"Jerry Edgar","adaptations":[]}]}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Params = gin.Params{{Key: "actor", Value: tt.actor}}
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/actors/"+tt.actor, nil)
+
+			c.GetBy(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetAll(t *testing.T) {
+	m := new(ManagerMock)
+	m.On("GetAll", mock.Anything).Return([]Actor{{ID: "id-2", Name: "Titus Welliver", IMDB: "nm0920038"}, {ID: "id-1", Name: "Matthew McConaughey", IMDB: "nm0000190"}}, nil).Once()
+	c := NewController(m)
+
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/actors", nil)
+
+	c.GetAll(ctx)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `[{"id":"id-1","name":"Matthew McConaughey","imdb":"nm0000190"},{"id":"id-2","name":"Titus Welliver","imdb":"nm0920038"}]`, recorder.Body.String())
+	m.AssertExpectations(t)
+}
+
+type ManagerMock struct {
+	mock.Mock
+}
+
+func (m *ManagerMock) Create(ctx context.Context, actor Actor) (Actor, bool, error) {
+	args := m.Called(ctx, actor)
+	return args.Get(0).(Actor), args.Bool(1), args.Error(2)
+}
+
+func (m *ManagerMock) Update(ctx context.Context, actor Actor) (Actor, error) {
+	args := m.Called(ctx, actor)
+	return args.Get(0).(Actor), args.Error(1)
+}
+
+func (m *ManagerMock) Delete(ctx context.Context, actorID string) error {
+	args := m.Called(ctx, actorID)
+	return args.Error(0)
+}
+
+func (m *ManagerMock) GetById(ctx context.Context, actorID string) (Actor, error) {
+	args := m.Called(ctx, actorID)
+	return args.Get(0).(Actor), args.Error(1)
+}
+
+func (m *ManagerMock) GetByIMDB(ctx context.Context, imdb string) (Actor, error) {
+	args := m.Called(ctx, imdb)
+	return args.Get(0).(Actor), args.Error(1)
+}
+
+func (m *ManagerMock) GetAll(ctx context.Context) ([]Actor, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Actor), args.Error(1)
+}
+
+func (m *ManagerMock) GetRoles(ctx context.Context, actorID string) ([]Role, error) {
+	args := m.Called(ctx, actorID)
+	return args.Get(0).([]Role), args.Error(1)
+}
@@ -0,0 +1,247 @@
+package actors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+)
+
+type DynamoDBClient interface {
+	Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error)
+	Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error
+	Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error
+	GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error)
+	GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error)
+	BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error)
+	GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error)
+}
+
+type Repository struct {
+	dynamoDBClient  DynamoDBClient
+	tableName       string
+	duplicatePolicy dynamo.DuplicatePolicy
+}
+
+func NewRepository(dynamoDBClient DynamoDBClient, tableName string, duplicatePolicy dynamo.DuplicatePolicy) *Repository {
+	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName, duplicatePolicy: duplicatePolicy}
+}
+
+func (r *Repository) Save(ctx context.Context, actor Actor) (Actor, bool, error) {
+	imdbID, err := ParseIMDBID(actor.IMDB)
+	if err != nil {
+		return Actor{}, false, err
+	}
+
+	actorItem, err := attributevalue.MarshalMap(newDBActor(actor))
+	if err != nil {
+		return Actor{}, false, fmt.Errorf("failed to marshal actor: %w", err)
+	}
+
+	id, err := r.dynamoDBClient.Save(ctx, r.tableName, actorItem, imdbID)
+	if errors.Is(err, dynamo.ErrDuplicated) {
+		return r.saveDuplicated(ctx, actor, err)
+	}
+	if err != nil {
+		return Actor{}, false, err
+	}
+
+	actor.ID = id
+	actor.Version = 1
+
+	return actor, true, nil
+}
+
+func (r *Repository) saveDuplicated(ctx context.Context, actor Actor, duplicatedErr error) (Actor, bool, error) {
+	if r.duplicatePolicy == dynamo.DuplicateReject {
+		return Actor{}, false, duplicatedErr
+	}
+
+	existingActor, err := r.getDuplicated(ctx, actor, duplicatedErr)
+	if err != nil {
+		return Actor{}, false, err
+	}
+
+	if r.duplicatePolicy == dynamo.DuplicateReturnExisting {
+		return existingActor, false, nil
+	}
+
+	actor.ID = existingActor.ID
+	actor.Version = existingActor.Version
+
+	updatedActor, err := r.Update(ctx, actor)
+
+	return updatedActor, false, err
+}
+
+func (r *Repository) getDuplicated(ctx context.Context, actor Actor, duplicatedErr error) (Actor, error) {
+	var withID *dynamo.DuplicatedError
+	if errors.As(duplicatedErr, &withID) {
+		return r.GetById(ctx, withID.ID)
+	}
+
+	return r.GetByIMDB(ctx, actor.IMDB)
+}
+
+func (r *Repository) Resolve(ctx context.Context, actor Actor) (Actor, error) {
+	if actor.ID != "" {
+		return r.GetById(ctx, actor.ID)
+	}
+
+	existingActor, err := r.GetByIMDB(ctx, actor.IMDB)
+	if err == nil {
+		return existingActor, nil
+	}
+	if !errors.Is(err, dynamo.ErrNotFound) {
+		return Actor{}, err
+	}
+
+	savedActor, _, err := r.Save(ctx, actor)
+
+	return savedActor, err
+}
+
+func (r *Repository) Update(ctx context.Context, actor Actor) (Actor, error) {
+	newIMDBID, err := ParseIMDBID(actor.IMDB)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	currentActor, err := r.GetById(ctx, actor.ID)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	currentIMDBID, err := ParseIMDBID(currentActor.IMDB)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	if actor.Version == dynamo.AnyVersion {
+		actor.Version = currentActor.Version
+	}
+
+	actorItem, err := attributevalue.MarshalMap(newDBActor(actor))
+	if err != nil {
+		return Actor{}, fmt.Errorf("failed to marshal actor: %w", err)
+	}
+
+	err = r.dynamoDBClient.Update(ctx, r.tableName, actor.ID, actor.Version, actorItem, currentIMDBID, newIMDBID)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	actor.Version++
+
+	return actor, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, actorID string) error {
+	actor, err := r.GetById(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	imdbID, err := ParseIMDBID(actor.IMDB)
+	if err != nil {
+		return err
+	}
+
+	return r.dynamoDBClient.Delete(ctx, r.tableName, actorID, imdbID)
+}
+
+func (r *Repository) GetById(ctx context.Context, actorID string) (Actor, error) {
+	item, err := r.dynamoDBClient.GetByID(ctx, r.tableName, actorID)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	var dbActor DBActor
+	err = attributevalue.UnmarshalMap(item, &dbActor)
+	if err != nil {
+		return Actor{}, fmt.Errorf("failed to unmarshal actor: %w", err)
+	}
+
+	return dbActor.toActor(), nil
+}
+
+func (r *Repository) GetByIMDB(ctx context.Context, imdb string) (Actor, error) {
+	imdbID, err := ParseIMDBID(imdb)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	item, err := r.dynamoDBClient.GetByUniqueKey(ctx, r.tableName, imdbID)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	var dbActor DBActor
+	err = attributevalue.UnmarshalMap(item, &dbActor)
+	if err != nil {
+		return Actor{}, fmt.Errorf("failed to unmarshal actor: %w", err)
+	}
+
+	return dbActor.toActor(), nil
+}
+
+func (r *Repository) GetByIds(ctx context.Context, actorIDs []string) ([]Actor, error) {
+	items, err := r.dynamoDBClient.BatchGetByIDs(ctx, r.tableName, actorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return toActorList(items)
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]Actor, error) {
+	items, err := r.dynamoDBClient.GetAll(ctx, r.tableName)
+	if err != nil {
+		return []Actor{}, err
+	}
+
+	return toActorList(items)
+}
+
+func toActorList(items []map[string]types.AttributeValue) ([]Actor, error) {
+	var actorsList []Actor
+	for _, item := range items {
+		var dbActor DBActor
+		err := attributevalue.UnmarshalMap(item, &dbActor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal actor: %w", err)
+		}
+
+		actorsList = append(actorsList, dbActor.toActor())
+	}
+
+	return actorsList, nil
+}
+
+type DBActor struct {
+	ID      string `dynamodbav:"id"`
+	Name    string `dynamodbav:"name"`
+	IMDB    string `dynamodbav:"imdb"`
+	Version int    `dynamodbav:"version,omitempty"`
+}
+
+func newDBActor(actor Actor) DBActor {
+	return DBActor{
+		ID:      actor.ID,
+		Name:    actor.Name,
+		IMDB:    actor.IMDB,
+		Version: actor.Version,
+	}
+}
+
+func (d *DBActor) toActor() Actor {
+	return Actor{
+		ID:      d.ID,
+		Name:    d.Name,
+		IMDB:    d.IMDB,
+		Version: d.Version,
+	}
+}
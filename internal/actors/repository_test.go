@@ -0,0 +1,370 @@
+package actors
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRepository_Save(t *testing.T) {
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{
+		"id":   &types.AttributeValueMemberS{Value: ""},
+		"name": &types.AttributeValueMemberS{Value: "Titus Welliver"},
+		"imdb": &types.AttributeValueMemberS{Value: "https://www.imdb.com/name/nm0920038"},
+	}
+	existingItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "nm0920038"}, "version": &types.AttributeValueMemberN{Value: "2"}}
+	tests := []struct {
+		name        string
+		policy      dynamo.DuplicatePolicy
+		imdb        string
+		setup       func(*MockDynamoDBClient)
+		want        Actor
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name:    "when imdb is not a name id",
+			policy:  dynamo.DuplicateReject,
+			imdb:    "https://www.imdb.com/title/tt3502248",
+			setup:   func(m *MockDynamoDBClient) {},
+			wantErr: fmt.Errorf("%w: %s", ErrInvalidIMDB, "https://www.imdb.com/title/tt3502248"),
+		},
+		{
+			name:    "when imdb only contains a name id",
+			policy:  dynamo.DuplicateReject,
+			imdb:    "https://example.com/profiles/nm0920038",
+			setup:   func(m *MockDynamoDBClient) {},
+			wantErr: fmt.Errorf("%w: %s", ErrInvalidIMDB, "https://example.com/profiles/nm0920038"),
+		},
+		{
+			name:    "when imdb is a name id with trailing characters",
+			policy:  dynamo.DuplicateReject,
+			imdb:    "nm0920038abc",
+			setup:   func(m *MockDynamoDBClient) {},
+			wantErr: fmt.Errorf("%w: %s", ErrInvalidIMDB, "nm0920038abc"),
+		},
+		{
+			name:   "when failed to save actor because already exists",
+			policy: dynamo.DuplicateReject,
+			imdb:   "https://www.imdb.com/name/nm0920038",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Save", ctx, "table-name", item, "nm0920038", []dynamo.Write(nil)).Return("", &dynamo.DuplicatedError{ID: "random-id"}).Once()
+			},
+			wantErr: &dynamo.DuplicatedError{ID: "random-id"},
+		},
+		{
+			name:   "when actor already exists and policy returns existing",
+			policy: dynamo.DuplicateReturnExisting,
+			imdb:   "https://www.imdb.com/name/nm0920038",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Save", ctx, "table-name", item, "nm0920038", []dynamo.Write(nil)).Return("", &dynamo.DuplicatedError{ID: "random-id"}).Once()
+				m.On("GetByID", ctx, "table-name", "random-id").Return(existingItem, nil).Once()
+			},
+			want: Actor{ID: "random-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 2},
+		},
+		{
+			name:   "when actor already exists and policy upserts",
+			policy: dynamo.DuplicateUpsert,
+			imdb:   "https://www.imdb.com/name/nm0920038",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Save", ctx, "table-name", item, "nm0920038", []dynamo.Write(nil)).Return("", &dynamo.DuplicatedError{ID: "random-id"}).Once()
+				m.On("GetByID", ctx, "table-name", "random-id").Return(existingItem, nil).Twice()
+				updateItem := maps.Clone(item)
+				updateItem["id"] = &types.AttributeValueMemberS{Value: "random-id"}
+				updateItem["version"] = &types.AttributeValueMemberN{Value: "2"}
+				m.On("Update", ctx, "table-name", "random-id", 2, updateItem, "nm0920038", "nm0920038", []dynamo.Write(nil)).Return(nil).Once()
+			},
+			want: Actor{ID: "random-id", Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038", Version: 3},
+		},
+		{
+			name:   "when failed to save actor",
+			policy: dynamo.DuplicateReject,
+			imdb:   "https://www.imdb.com/name/nm0920038",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Save", ctx, "table-name", item, "nm0920038", []dynamo.Write(nil)).Return("", assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when successfully saved actor",
+			policy: dynamo.DuplicateReject,
+			imdb:   "https://www.imdb.com/name/nm0920038",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Save", ctx, "table-name", item, "nm0920038", []dynamo.Write(nil)).Return("random-id", nil).Once()
+			},
+			want:        Actor{ID: "random-id", Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038", Version: 1},
+			wantCreated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", tt.policy)
+
+			got, created, err := r.Save(ctx, Actor{Name: "Titus Welliver", IMDB: tt.imdb})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Resolve(t *testing.T) {
+	ctx := context.Background()
+	titus := Actor{Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038"}
+	tests := []struct {
+		name    string
+		actor   Actor
+		setup   func(*MockDynamoDBClient)
+		want    Actor
+		wantErr error
+	}{
+		{
+			name:  "when actor is referenced by id",
+			actor: Actor{ID: "random-id"},
+			setup: func(m *MockDynamoDBClient) {
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "nm0920038"}, "version": &types.AttributeValueMemberN{Value: "1"}}
+				m.On("GetByID", ctx, "table-name", "random-id").Return(item, nil).Once()
+			},
+			want: Actor{ID: "random-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 1},
+		},
+		{
+			name:  "when actor referenced by id does not exist",
+			actor: Actor{ID: "random-id"},
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(map[string]types.AttributeValue{}, dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name:  "when failed to get actor by imdb",
+			actor: titus,
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByUniqueKey", ctx, "table-name", "nm0920038").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when actor already exists",
+			actor: titus,
+			setup: func(m *MockDynamoDBClient) {
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "nm0920038"}, "version": &types.AttributeValueMemberN{Value: "1"}}
+				m.On("GetByUniqueKey", ctx, "table-name", "nm0920038").Return(item, nil).Once()
+			},
+			want: Actor{ID: "random-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 1},
+		},
+		{
+			name:  "when actor does not exist it is created",
+			actor: titus,
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByUniqueKey", ctx, "table-name", "nm0920038").Return(map[string]types.AttributeValue{}, dynamo.ErrNotFound).Once()
+				item := map[string]types.AttributeValue{
+					"id":   &types.AttributeValueMemberS{Value: ""},
+					"name": &types.AttributeValueMemberS{Value: "Titus Welliver"},
+					"imdb": &types.AttributeValueMemberS{Value: "https://www.imdb.com/name/nm0920038"},
+				}
+				m.On("Save", ctx, "table-name", item, "nm0920038", []dynamo.Write(nil)).Return("random-id", nil).Once()
+			},
+			want: Actor{ID: "random-id", Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038", Version: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject)
+
+			got, err := r.Resolve(ctx, tt.actor)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "nm0920038"}, "version": &types.AttributeValueMemberN{Value: "2"}}
+	item := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "random-id"},
+		"name":    &types.AttributeValueMemberS{Value: "Titus Welliver"},
+		"imdb":    &types.AttributeValueMemberS{Value: "nm0920039"},
+		"version": &types.AttributeValueMemberN{Value: "2"},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    Actor
+		wantErr error
+	}{
+		{
+			name: "when failed to get current actor",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to update actor",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Update", ctx, "table-name", "random-id", 2, item, "nm0920038", "nm0920039", []dynamo.Write(nil)).Return(dynamo.ErrVersionMismatch).Once()
+			},
+			wantErr: dynamo.ErrVersionMismatch,
+		},
+		{
+			name: "when successfully updated actor",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Update", ctx, "table-name", "random-id", 2, item, "nm0920038", "nm0920039", []dynamo.Write(nil)).Return(nil).Once()
+			},
+			want: Actor{ID: "random-id", Name: "Titus Welliver", IMDB: "nm0920039", Version: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject)
+
+			got, err := r.Update(ctx, Actor{ID: "random-id", Name: "Titus Welliver", IMDB: "nm0920039", Version: 2})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "https://www.imdb.com/name/nm0920038/"}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to get current actor",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(map[string]types.AttributeValue{}, dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name: "when successfully deleted actor",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Delete", ctx, "table-name", "random-id", "nm0920038", []dynamo.Write(nil)).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject)
+
+			err := r.Delete(ctx, "random-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByIds(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    []Actor
+		wantErr error
+	}{
+		{
+			name: "when failed to get actors",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"id-1", "id-2"}).Return([]map[string]types.AttributeValue(nil), dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name: "when successfully got actors",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"id-1", "id-2"}).Return([]map[string]types.AttributeValue{
+					{"id": &types.AttributeValueMemberS{Value: "id-1"}, "name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "nm0920038"}},
+					{"id": &types.AttributeValueMemberS{Value: "id-2"}, "name": &types.AttributeValueMemberS{Value: "Matthew McConaughey"}, "imdb": &types.AttributeValueMemberS{Value: "nm0000190"}},
+				}, nil).Once()
+			},
+			want: []Actor{{ID: "id-1", Name: "Titus Welliver", IMDB: "nm0920038"}, {ID: "id-2", Name: "Matthew McConaughey", IMDB: "nm0000190"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject)
+
+			got, err := r.GetByIds(ctx, []string{"id-1", "id-2"})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+type MockDynamoDBClient struct {
+	mock.Mock
+}
+
+func (m *MockDynamoDBClient) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error) {
+	args := m.Called(ctx, tableName, item, uniqueKey, writes)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, version, item, oldUniqueKey, newUniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, uniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, id)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, value)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, ids)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
@@ -0,0 +1,71 @@
+package actors
+
+import (
+	"context"
+
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+)
+
+type StorageActor interface {
+	Save(ctx context.Context, actor Actor) (Actor, bool, error)
+	Update(ctx context.Context, actor Actor) (Actor, error)
+	Delete(ctx context.Context, actorID string) error
+	GetById(ctx context.Context, actorID string) (Actor, error)
+	GetByIMDB(ctx context.Context, imdb string) (Actor, error)
+	GetAll(ctx context.Context) ([]Actor, error)
+}
+
+type Filmography interface {
+	GetByActor(ctx context.Context, actorID string) ([]Role, error)
+}
+
+type Service struct {
+	storageActor StorageActor
+	filmography  Filmography
+}
+
+func NewService(storageActor StorageActor, filmography Filmography) *Service {
+	return &Service{storageActor: storageActor, filmography: filmography}
+}
+
+func (s *Service) Create(ctx context.Context, actor Actor) (Actor, bool, error) {
+	return s.storageActor.Save(ctx, actor)
+}
+
+func (s *Service) Update(ctx context.Context, actor Actor) (Actor, error) {
+	return s.storageActor.Update(ctx, actor)
+}
+
+func (s *Service) Delete(ctx context.Context, actorID string) error {
+	roles, err := s.filmography.GetByActor(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	if len(roles) > 0 {
+		return &apperr.ReferencedError{Resource: "actor", ID: actorID}
+	}
+
+	return s.storageActor.Delete(ctx, actorID)
+}
+
+func (s *Service) GetById(ctx context.Context, actorID string) (Actor, error) {
+	return s.storageActor.GetById(ctx, actorID)
+}
+
+func (s *Service) GetByIMDB(ctx context.Context, imdb string) (Actor, error) {
+	return s.storageActor.GetByIMDB(ctx, imdb)
+}
+
+func (s *Service) GetAll(ctx context.Context) ([]Actor, error) {
+	actorsList, err := s.storageActor.GetAll(ctx)
+	if err != nil {
+		return []Actor{}, err
+	}
+
+	return actorsList, nil
+}
+
+func (s *Service) GetRoles(ctx context.Context, actorID string) ([]Role, error) {
+	return s.filmography.GetByActor(ctx, actorID)
+}
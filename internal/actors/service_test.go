@@ -0,0 +1,139 @@
+package actors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageActorMock, *FilmographyMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get actor roles",
+			setup: func(_ *StorageActorMock, f *FilmographyMock) {
+				f.On("GetByActor", ctx, "actor-id").Return([]Role{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when actor still plays characters",
+			setup: func(_ *StorageActorMock, f *FilmographyMock) {
+				f.On("GetByActor", ctx, "actor-id").Return([]Role{{CharacterID: "character-id", CharacterName: "Harry Bosch"}}, nil).Once()
+			},
+			wantErr: &apperr.ReferencedError{Resource: "actor", ID: "actor-id"},
+		},
+		{
+			name: "when successfully deleted actor",
+			setup: func(s *StorageActorMock, f *FilmographyMock) {
+				f.On("GetByActor", ctx, "actor-id").Return([]Role{}, nil).Once()
+				s.On("Delete", ctx, "actor-id").Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageActor := new(StorageActorMock)
+			filmography := new(FilmographyMock)
+			tt.setup(storageActor, filmography)
+
+			s := NewService(storageActor, filmography)
+
+			err := s.Delete(ctx, "actor-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			storageActor.AssertExpectations(t)
+			filmography.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetRoles(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*FilmographyMock)
+		want    []Role
+		wantErr error
+	}{
+		{
+			name: "when failed to get actor roles",
+			setup: func(f *FilmographyMock) {
+				f.On("GetByActor", ctx, "actor-id").Return([]Role(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully got actor roles",
+			setup: func(f *FilmographyMock) {
+				f.On("GetByActor", ctx, "actor-id").Return([]Role{{CharacterID: "character-id", CharacterName: "Harry Bosch", Adaptations: []books.Adaptation{{Description: "Bosch", IMDB: "tt3502248"}}}}, nil).Once()
+			},
+			want: []Role{{CharacterID: "character-id", CharacterName: "Harry Bosch", Adaptations: []books.Adaptation{{Description: "Bosch", IMDB: "tt3502248"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filmography := new(FilmographyMock)
+			tt.setup(filmography)
+
+			s := NewService(new(StorageActorMock), filmography)
+
+			got, err := s.GetRoles(ctx, "actor-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			filmography.AssertExpectations(t)
+		})
+	}
+}
+
+type StorageActorMock struct {
+	mock.Mock
+}
+
+func (s *StorageActorMock) Save(ctx context.Context, actor Actor) (Actor, bool, error) {
+	args := s.Called(ctx, actor)
+	return args.Get(0).(Actor), args.Bool(1), args.Error(2)
+}
+
+func (s *StorageActorMock) Update(ctx context.Context, actor Actor) (Actor, error) {
+	args := s.Called(ctx, actor)
+	return args.Get(0).(Actor), args.Error(1)
+}
+
+func (s *StorageActorMock) Delete(ctx context.Context, actorID string) error {
+	args := s.Called(ctx, actorID)
+	return args.Error(0)
+}
+
+func (s *StorageActorMock) GetById(ctx context.Context, actorID string) (Actor, error) {
+	args := s.Called(ctx, actorID)
+	return args.Get(0).(Actor), args.Error(1)
+}
+
+func (s *StorageActorMock) GetByIMDB(ctx context.Context, imdb string) (Actor, error) {
+	args := s.Called(ctx, imdb)
+	return args.Get(0).(Actor), args.Error(1)
+}
+
+func (s *StorageActorMock) GetAll(ctx context.Context) ([]Actor, error) {
+	args := s.Called(ctx)
+	return args.Get(0).([]Actor), args.Error(1)
+}
+
+type FilmographyMock struct {
+	mock.Mock
+}
+
+func (f *FilmographyMock) GetByActor(ctx context.Context, actorID string) ([]Role, error) {
+	args := f.Called(ctx, actorID)
+	return args.Get(0).([]Role), args.Error(1)
+}
@@ -0,0 +1,39 @@
+package adaptations
+
+import (
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+)
+
+type Type string
+
+const (
+	TypeSeries  Type = "series"
+	TypeSeason  Type = "season"
+	TypeFilm    Type = "film"
+	TypeEpisode Type = "episode"
+)
+
+type Adaptation struct {
+	ID          string
+	Slug        string
+	Title       string
+	Type        Type
+	IMDB        string
+	ReleaseDate string
+	EndDate     string
+	Network     string
+	Books       []books.Book
+	Cast        []CastMember
+	Version     int
+}
+
+type CastMember struct {
+	Actor     actors.Actor
+	Character characters.Character
+}
+
+func (a Adaptation) summary() books.Adaptation {
+	return books.Adaptation{ID: a.ID, Description: a.Title, Type: string(a.Type), IMDB: a.IMDB}
+}
@@ -0,0 +1,247 @@
+package adaptations
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Manager interface {
+	Create(ctx context.Context, adaptation Adaptation) (Adaptation, bool, error)
+	Update(ctx context.Context, adaptation Adaptation) (Adaptation, error)
+	Delete(ctx context.Context, adaptationID string) error
+	GetById(ctx context.Context, adaptationID string) (Adaptation, error)
+	GetBySlug(ctx context.Context, slug string) (Adaptation, error)
+	GetAll(ctx context.Context, adaptationType Type) ([]Adaptation, error)
+}
+
+type Controller struct {
+	manager Manager
+}
+
+func NewController(manager Manager) *Controller {
+	return &Controller{manager: manager}
+}
+
+func (c *Controller) Create(ctx *gin.Context) {
+	var adaptationDTO AdaptationDTO
+	if err := ctx.BindJSON(&adaptationDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	createdAdaptation, created, err := c.manager.Create(ctx, adaptationDTO.ToAdaptation())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	ctx.Header("ETag", etag.Format(createdAdaptation.Version))
+	ctx.JSON(status, NewAdaptationDTO(createdAdaptation))
+}
+
+func (c *Controller) Update(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	version, err := etag.Parse(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var adaptationDTO AdaptationDTO
+	if err := ctx.BindJSON(&adaptationDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	adaptation := adaptationDTO.ToAdaptation()
+	adaptation.ID = idRequest.AdaptationID
+	adaptation.Version = version
+
+	updatedAdaptation, err := c.manager.Update(ctx, adaptation)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(updatedAdaptation.Version))
+	ctx.JSON(http.StatusOK, NewAdaptationDTO(updatedAdaptation))
+}
+
+func (c *Controller) Delete(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err := c.manager.Delete(ctx, idRequest.AdaptationID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) GetBy(ctx *gin.Context) {
+	var getByRequest GetByRequest
+	if err := ctx.BindUri(&getByRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var adaptation Adaptation
+	adaptationID, err := uuid.Parse(getByRequest.Adaptation)
+	if err != nil {
+		adaptation, err = c.manager.GetBySlug(ctx, getByRequest.Adaptation)
+	} else {
+		adaptation, err = c.manager.GetById(ctx, adaptationID.String())
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(adaptation.Version))
+	ctx.JSON(http.StatusOK, NewAdaptationDTO(adaptation))
+}
+
+func (c *Controller) GetAll(ctx *gin.Context) {
+	var getAllRequest GetAllRequest
+	if err := ctx.BindQuery(&getAllRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	adaptationsList, err := c.manager.GetAll(ctx, Type(getAllRequest.Type))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	adaptationsDTO := []AdaptationDTO{}
+	for _, adaptation := range adaptationsList {
+		adaptationsDTO = append(adaptationsDTO, NewAdaptationDTO(adaptation))
+	}
+
+	ctx.JSON(http.StatusOK, adaptationsDTO)
+}
+
+type AdaptationDTO struct {
+	ID          string              `json:"id,omitempty"`
+	Slug        string              `json:"slug,omitempty"`
+	Title       string              `json:"title" binding:"required"`
+	Type        string              `json:"type" binding:"required,oneof=series season film episode"`
+	IMDB        string              `json:"imdb" binding:"required"`
+	ReleaseDate string              `json:"releaseDate,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	EndDate     string              `json:"endDate,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	Network     string              `json:"network,omitempty"`
+	BookTitles  []string            `json:"bookTitles,omitempty"`
+	Books       []AdaptationBookDTO `json:"books,omitempty"`
+	Cast        []CastDTO           `json:"cast,omitempty" binding:"omitempty,dive"`
+}
+
+type AdaptationBookDTO struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Year  int    `json:"year"`
+}
+
+type CastDTO struct {
+	Actor       CastActorDTO `json:"actor"`
+	CharacterID string       `json:"characterId,omitempty"`
+	Character   string       `json:"character" binding:"required"`
+}
+
+type CastActorDTO struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name" binding:"required_without=ID"`
+	IMDB string `json:"imdb" binding:"required_without=ID"`
+}
+
+func NewAdaptationDTO(adaptation Adaptation) AdaptationDTO {
+	var bookTitles []string
+	var booksDTO []AdaptationBookDTO
+	for _, b := range adaptation.Books {
+		bookTitles = append(bookTitles, b.Title)
+		booksDTO = append(booksDTO, AdaptationBookDTO{ID: b.ID, Title: b.Title, Year: b.Year})
+	}
+
+	var castDTO []CastDTO
+	for _, member := range adaptation.Cast {
+		castDTO = append(castDTO, CastDTO{
+			Actor:       CastActorDTO{ID: member.Actor.ID, Name: member.Actor.Name, IMDB: member.Actor.IMDB},
+			CharacterID: member.Character.ID,
+			Character:   member.Character.Name,
+		})
+	}
+
+	return AdaptationDTO{
+		ID:          adaptation.ID,
+		Slug:        adaptation.Slug,
+		Title:       adaptation.Title,
+		Type:        string(adaptation.Type),
+		IMDB:        adaptation.IMDB,
+		ReleaseDate: adaptation.ReleaseDate,
+		EndDate:     adaptation.EndDate,
+		Network:     adaptation.Network,
+		BookTitles:  bookTitles,
+		Books:       booksDTO,
+		Cast:        castDTO,
+	}
+}
+
+func (r *AdaptationDTO) ToAdaptation() Adaptation {
+	var booksList []books.Book
+	for _, bookTitle := range r.BookTitles {
+		booksList = append(booksList, books.Book{Title: bookTitle})
+	}
+
+	var cast []CastMember
+	for _, member := range r.Cast {
+		cast = append(cast, CastMember{
+			Actor:     actors.Actor{ID: member.Actor.ID, Name: member.Actor.Name, IMDB: member.Actor.IMDB},
+			Character: characters.Character{Name: member.Character},
+		})
+	}
+
+	return Adaptation{
+		Title:       r.Title,
+		Type:        Type(r.Type),
+		IMDB:        r.IMDB,
+		ReleaseDate: r.ReleaseDate,
+		EndDate:     r.EndDate,
+		Network:     r.Network,
+		Books:       booksList,
+		Cast:        cast,
+	}
+}
+
+type GetAllRequest struct {
+	Type string `form:"type" binding:"omitempty,oneof=series season film episode"`
+}
+
+type IDRequest struct {
+	AdaptationID string `uri:"adaptation" binding:"required,uuid"`
+}
+
+type GetByRequest struct {
+	Adaptation string `uri:"adaptation" binding:"required"`
+}
@@ -0,0 +1,414 @@
+package adaptations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestController_Create(t *testing.T) {
+	reqBody := `{"title": "Bosch", "type": "series", "imdb": "tt3502248", "releaseDate": "2014-02-06", "network": "Prime Video", "bookTitles": ["The Black Echo"], "cast": [{"actor": {"name": "Titus Welliver", "imdb": "nm0920038"}, "character": "Harry Bosch"}]}`
+	input := Adaptation{
+		Title:       "Bosch",
+		Type:        TypeSeries,
+		IMDB:        "tt3502248",
+		ReleaseDate: "2014-02-06",
+		Network:     "Prime Video",
+		Books:       []books.Book{{Title: "The Black Echo"}},
+		Cast:        []CastMember{{Actor: actors.Actor{Name: "Titus Welliver", IMDB: "nm0920038"}, Character: characters.Character{Name: "Harry Bosch"}}},
+	}
+	output := Adaptation{
+		ID:          "adaptation-id",
+		Slug:        "bosch",
+		Title:       "Bosch",
+		Type:        TypeSeries,
+		IMDB:        "tt3502248",
+		ReleaseDate: "2014-02-06",
+		Network:     "Prime Video",
+		Books:       []books.Book{{ID: "book-id", Title: "The Black Echo", Year: 1992}},
+		Cast:        []CastMember{{Actor: actors.Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}, Character: characters.Character{ID: "character-id", Name: "Harry Bosch"}}},
+	}
+	resBody := `{"id":"adaptation-id","slug":"bosch","title":"Bosch","type":"series","imdb":"tt3502248","releaseDate":"2014-02-06","network":"Prime Video","bookTitles":["The Black Echo"],"books":[{"id":"book-id","title":"The Black Echo","year":1992}],"cast":[{"actor":{"id":"actor-id","name":"Titus Welliver","imdb":"nm0920038"},"characterId":"character-id","character":"Harry Bosch"}]}`
+	tests := []struct {
+		name     string
+		reqBody  string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when request body is an invalid json",
+			reqBody: `}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var syntaxErr *json.SyntaxError
+				assert.True(t, errors.As(err, &syntaxErr))
+			},
+		},
+		{
+			name:    "when type is not supported",
+			reqBody: `{"title": "Bosch", "type": "podcast", "imdb": "tt3502248"}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when release date is not a date",
+			reqBody: `{"title": "Bosch", "type": "series", "imdb": "tt3502248", "releaseDate": "February 2014"}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when cast member has no character",
+			reqBody: `{"title": "Bosch", "type": "series", "imdb": "tt3502248", "cast": [{"actor": {"name": "Titus Welliver", "imdb": "nm0920038"}}]}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when cast actor has neither id nor name",
+			reqBody: `{"title": "Bosch", "type": "series", "imdb": "tt3502248", "cast": [{"actor": {"imdb": "nm0920038"}, "character": "Harry Bosch"}]}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when cast actor is referenced by id",
+			reqBody: `{"title": "Bosch", "type": "series", "imdb": "tt3502248", "cast": [{"actor": {"id": "actor-id"}, "character": "Harry Bosch"}]}`,
+			setup: func(m *ManagerMock) {
+				byID := Adaptation{Title: "Bosch", Type: TypeSeries, IMDB: "tt3502248", Cast: []CastMember{{Actor: actors.Actor{ID: "actor-id"}, Character: characters.Character{Name: "Harry Bosch"}}}}
+				m.On("Create", mock.Anything, byID).Return(Adaptation{}, false, dynamo.ErrNotFound).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, dynamo.ErrNotFound))
+			},
+		},
+		{
+			name:    "when character does not exist",
+			reqBody: reqBody,
+			setup: func(m *ManagerMock) {
+				m.On("Create", mock.Anything, input).Return(Adaptation{}, false, dynamo.ErrNotFound).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, dynamo.ErrNotFound))
+			},
+		},
+		{
+			name:    "when adaptation already exists",
+			reqBody: reqBody,
+			setup: func(m *ManagerMock) {
+				existing := output
+				existing.Version = 2
+				m.On("Create", mock.Anything, input).Return(existing, false, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"2"`, r.Header().Get("ETag"))
+				assert.Equal(t, resBody, r.Body.String())
+			},
+		},
+		{
+			name:    "when adaptation is created",
+			reqBody: reqBody,
+			setup: func(m *ManagerMock) {
+				created := output
+				created.Version = 1
+				m.On("Create", mock.Anything, input).Return(created, true, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, `"1"`, r.Header().Get("ETag"))
+				assert.Equal(t, resBody, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/adaptations", strings.NewReader(tt.reqBody))
+
+			c.Create(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when If-Match header is missing",
+			setup: func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, etag.ErrMissing))
+			},
+		},
+		{
+			name:    "when adaptation is updated",
+			ifMatch: `"2"`,
+			setup: func(m *ManagerMock) {
+				m.On("Update", mock.Anything, Adaptation{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Lincoln Lawyer", Type: TypeFilm, IMDB: "tt1189340", Version: 2}).Return(Adaptation{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "the-lincoln-lawyer", Title: "The Lincoln Lawyer", Type: TypeFilm, IMDB: "tt1189340", Version: 3}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","slug":"the-lincoln-lawyer","title":"The Lincoln Lawyer","type":"film","imdb":"tt1189340"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Params = gin.Params{{Key: "adaptation", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/adaptations/c6767b2d-438b-4d4c-8b1a-659130a640ca", strings.NewReader(`{"title": "The Lincoln Lawyer", "type": "film", "imdb": "tt1189340"}`))
+			if tt.ifMatch != "" {
+				ctx.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			c.Update(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		adaptation string
+		setup      func(*ManagerMock)
+		expected   func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:       "when adaptation id is not a uuid",
+			adaptation: "bosch",
+			setup:      func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:       "when adaptation is deleted",
+			adaptation: "c6767b2d-438b-4d4c-8b1a-659130a640ca",
+			setup: func(m *ManagerMock) {
+				m.On("Delete", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusNoContent, r.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Params = gin.Params{{Key: "adaptation", Value: tt.adaptation}}
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/adaptations/"+tt.adaptation, nil)
+
+			c.Delete(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetBy(t *testing.T) {
+	tests := []struct {
+		name       string
+		adaptation string
+		setup      func(*ManagerMock)
+		expected   func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:       "when adaptation is not found by slug",
+			adaptation: "bosch",
+			setup: func(m *ManagerMock) {
+				m.On("GetBySlug", mock.Anything, "bosch").Return(Adaptation{}, dynamo.ErrNotFound).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, dynamo.ErrNotFound))
+			},
+		},
+		{
+			name:       "when adaptation is found by id",
+			adaptation: "c6767b2d-438b-4d4c-8b1a-659130a640ca",
+			setup: func(m *ManagerMock) {
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Adaptation{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "bosch", Title: "Bosch", Type: TypeSeries, IMDB: "tt3502248", Version: 1}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"1"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","slug":"bosch","title":"Bosch","type":"series","imdb":"tt3502248"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Params = gin.Params{{Key: "adaptation", Value: tt.adaptation}}
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/adaptations/"+tt.adaptation, nil)
+
+			c.GetBy(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when type is not supported",
+			query: "?type=podcast",
+			setup: func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name: "when there are no adaptations",
+			setup: func(m *ManagerMock) {
+				m.On("GetAll", mock.Anything, Type("")).Return([]Adaptation(nil), nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[]`, r.Body.String())
+			},
+		},
+		{
+			name:  "when filtered by type",
+			query: "?type=film",
+			setup: func(m *ManagerMock) {
+				m.On("GetAll", mock.Anything, TypeFilm).Return([]Adaptation{{ID: "adaptation-id", Slug: "the-lincoln-lawyer", Title: "The Lincoln Lawyer", Type: TypeFilm, IMDB: "tt1189340", ReleaseDate: "2011-03-18"}}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[{"id":"adaptation-id","slug":"the-lincoln-lawyer","title":"The Lincoln Lawyer","type":"film","imdb":"tt1189340","releaseDate":"2011-03-18"}]`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			tt.setup(m)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/adaptations"+tt.query, nil)
+
+			c.GetAll(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+type ManagerMock struct {
+	mock.Mock
+}
+
+func (m *ManagerMock) Create(ctx context.Context, adaptation Adaptation) (Adaptation, bool, error) {
+	args := m.Called(ctx, adaptation)
+	return args.Get(0).(Adaptation), args.Bool(1), args.Error(2)
+}
+
+func (m *ManagerMock) Update(ctx context.Context, adaptation Adaptation) (Adaptation, error) {
+	args := m.Called(ctx, adaptation)
+	return args.Get(0).(Adaptation), args.Error(1)
+}
+
+func (m *ManagerMock) Delete(ctx context.Context, adaptationID string) error {
+	args := m.Called(ctx, adaptationID)
+	return args.Error(0)
+}
+
+func (m *ManagerMock) GetById(ctx context.Context, adaptationID string) (Adaptation, error) {
+	args := m.Called(ctx, adaptationID)
+	return args.Get(0).(Adaptation), args.Error(1)
+}
+
+func (m *ManagerMock) GetBySlug(ctx context.Context, slug string) (Adaptation, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(Adaptation), args.Error(1)
+}
+
+func (m *ManagerMock) GetAll(ctx context.Context, adaptationType Type) ([]Adaptation, error) {
+	args := m.Called(ctx, adaptationType)
+	return args.Get(0).([]Adaptation), args.Error(1)
+}
@@ -0,0 +1,633 @@
+package adaptations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
+)
+
+type DynamoDBClient interface {
+	NewID() string
+	Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error)
+	Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error
+	Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error
+	GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error)
+	GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error)
+	GetBySlug(ctx context.Context, tableName string, slug string) (map[string]types.AttributeValue, error)
+	BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error)
+	GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error)
+}
+
+type Links interface {
+	Writes(owner string, ownerID string, current []relations.Link, links []relations.Link) ([]dynamo.Write, error)
+	Replace(ctx context.Context, owner string, ownerID string, links []relations.Link) error
+	GetByBook(ctx context.Context, owner string, bookID string) ([]relations.Link, error)
+	GetByBooks(ctx context.Context, owner string, bookIDs []string) (map[string][]relations.Link, error)
+	CastWrites(current []relations.Cast, cast []relations.Cast) ([]dynamo.Write, error)
+	PutCast(ctx context.Context, cast []relations.Cast) error
+	GetCastByCharacters(ctx context.Context, characterIDs []string) (map[string][]relations.Cast, error)
+	GetCastByActor(ctx context.Context, actorID string) ([]relations.Cast, error)
+	Apply(ctx context.Context, writes []dynamo.Write) error
+}
+
+const linkOwner = "adaptation"
+
+type Repository struct {
+	dynamoDBClient  DynamoDBClient
+	tableName       string
+	duplicatePolicy dynamo.DuplicatePolicy
+	links           Links
+}
+
+func NewRepository(dynamoDBClient DynamoDBClient, tableName string, duplicatePolicy dynamo.DuplicatePolicy, links Links) *Repository {
+	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName, duplicatePolicy: duplicatePolicy, links: links}
+}
+
+func (r *Repository) Save(ctx context.Context, adaptation Adaptation) (Adaptation, bool, error) {
+	adaptation.ID = r.dynamoDBClient.NewID()
+
+	writes, err := r.writes(Adaptation{ID: adaptation.ID}, adaptation)
+	if err != nil {
+		return Adaptation{}, false, err
+	}
+
+	for _, slug := range dynamo.SlugCandidates("", adaptation.Title, adaptation.ID) {
+		adaptation.Slug = slug
+		err = r.save(ctx, adaptation, writes)
+		if !errors.Is(err, dynamo.ErrSlugTaken) {
+			break
+		}
+	}
+	if errors.Is(err, dynamo.ErrDuplicated) {
+		return r.saveDuplicated(ctx, adaptation, err)
+	}
+	if err != nil {
+		return Adaptation{}, false, err
+	}
+
+	adaptation.Version = 1
+
+	return adaptation, true, nil
+}
+
+func (r *Repository) save(ctx context.Context, adaptation Adaptation, writes []dynamo.Write) error {
+	adaptationItem, err := attributevalue.MarshalMap(newDBAdaptation(adaptation))
+	if err != nil {
+		return fmt.Errorf("failed to marshal adaptation: %w", err)
+	}
+
+	return relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		_, err := r.dynamoDBClient.Save(ctx, r.tableName, adaptationItem, adaptation.Title, append(writes, dynamo.ClaimSlugWrite(r.tableName, adaptation.Slug, adaptation.ID))...)
+		return err
+	})
+}
+
+func (r *Repository) saveDuplicated(ctx context.Context, adaptation Adaptation, duplicatedErr error) (Adaptation, bool, error) {
+	if r.duplicatePolicy == dynamo.DuplicateReject {
+		return Adaptation{}, false, duplicatedErr
+	}
+
+	existingAdaptation, err := r.getDuplicated(ctx, adaptation, duplicatedErr)
+	if err != nil {
+		return Adaptation{}, false, err
+	}
+
+	if r.duplicatePolicy == dynamo.DuplicateReturnExisting {
+		return existingAdaptation, false, nil
+	}
+
+	adaptation.ID = existingAdaptation.ID
+	adaptation.Version = existingAdaptation.Version
+
+	updatedAdaptation, err := r.Update(ctx, adaptation)
+
+	return updatedAdaptation, false, err
+}
+
+func (r *Repository) getDuplicated(ctx context.Context, adaptation Adaptation, duplicatedErr error) (Adaptation, error) {
+	var withID *dynamo.DuplicatedError
+	if errors.As(duplicatedErr, &withID) {
+		return r.GetById(ctx, withID.ID)
+	}
+
+	return r.GetByTitle(ctx, adaptation.Title)
+}
+
+func (r *Repository) Update(ctx context.Context, adaptation Adaptation) (Adaptation, error) {
+	currentAdaptation, err := r.GetById(ctx, adaptation.ID)
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	if adaptation.Version == dynamo.AnyVersion {
+		adaptation.Version = currentAdaptation.Version
+	}
+
+	writes, err := r.writes(currentAdaptation, adaptation)
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	for _, slug := range dynamo.SlugCandidates(currentAdaptation.Slug, adaptation.Title, adaptation.ID) {
+		adaptation.Slug = slug
+		err = r.update(ctx, adaptation, currentAdaptation.Title, writes)
+		if !errors.Is(err, dynamo.ErrSlugTaken) {
+			break
+		}
+	}
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	adaptation.Version++
+
+	return adaptation, nil
+}
+
+func (r *Repository) update(ctx context.Context, adaptation Adaptation, currentTitle string, writes []dynamo.Write) error {
+	adaptationItem, err := attributevalue.MarshalMap(newDBAdaptation(adaptation))
+	if err != nil {
+		return fmt.Errorf("failed to marshal adaptation: %w", err)
+	}
+
+	return relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		return r.dynamoDBClient.Update(ctx, r.tableName, adaptation.ID, adaptation.Version, adaptationItem, currentTitle, adaptation.Title, append(writes, dynamo.ClaimSlugWrite(r.tableName, adaptation.Slug, adaptation.ID))...)
+	})
+}
+
+func (r *Repository) Delete(ctx context.Context, adaptationID string) error {
+	adaptation, err := r.GetById(ctx, adaptationID)
+	if err != nil {
+		return err
+	}
+
+	writes, err := r.writes(adaptation, Adaptation{ID: adaptationID})
+	if err != nil {
+		return err
+	}
+
+	return relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		if adaptation.Slug != "" {
+			writes = append(writes, dynamo.ReleaseSlugWrite(r.tableName, adaptation.Slug, adaptationID))
+		}
+
+		return r.dynamoDBClient.Delete(ctx, r.tableName, adaptationID, adaptation.Title, writes...)
+	})
+}
+
+func (r *Repository) GetById(ctx context.Context, adaptationID string) (Adaptation, error) {
+	item, err := r.dynamoDBClient.GetByID(ctx, r.tableName, adaptationID)
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	var dbAdaptation DBAdaptation
+	err = attributevalue.UnmarshalMap(item, &dbAdaptation)
+	if err != nil {
+		return Adaptation{}, fmt.Errorf("failed to unmarshal adaptation: %w", err)
+	}
+
+	return dbAdaptation.toAdaptation(), nil
+}
+
+func (r *Repository) GetByTitle(ctx context.Context, title string) (Adaptation, error) {
+	item, err := r.dynamoDBClient.GetByUniqueKey(ctx, r.tableName, title)
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	var dbAdaptation DBAdaptation
+	err = attributevalue.UnmarshalMap(item, &dbAdaptation)
+	if err != nil {
+		return Adaptation{}, fmt.Errorf("failed to unmarshal adaptation: %w", err)
+	}
+
+	return dbAdaptation.toAdaptation(), nil
+}
+
+func (r *Repository) GetBySlug(ctx context.Context, slug string) (Adaptation, error) {
+	item, err := r.dynamoDBClient.GetBySlug(ctx, r.tableName, slug)
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	var dbAdaptation DBAdaptation
+	err = attributevalue.UnmarshalMap(item, &dbAdaptation)
+	if err != nil {
+		return Adaptation{}, fmt.Errorf("failed to unmarshal adaptation: %w", err)
+	}
+
+	return dbAdaptation.toAdaptation(), nil
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]Adaptation, error) {
+	items, err := r.dynamoDBClient.GetAll(ctx, r.tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAdaptationList(items)
+}
+
+func (r *Repository) GetByBook(ctx context.Context, bookID string) ([]Adaptation, error) {
+	adaptationsList, _, err := r.getByBooks(ctx, []string{bookID})
+
+	return adaptationsList, err
+}
+
+func (r *Repository) GetByBooks(ctx context.Context, bookIDs []string) (map[string][]books.Adaptation, error) {
+	adaptationsList, idsByBook, err := r.getByBooks(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	adaptationsByID := map[string]Adaptation{}
+	for _, adaptation := range adaptationsList {
+		adaptationsByID[adaptation.ID] = adaptation
+	}
+
+	adaptationsByBook := map[string][]books.Adaptation{}
+	for bookID, adaptationIDs := range idsByBook {
+		adaptationsByBook[bookID] = nil
+		for _, adaptationID := range adaptationIDs {
+			adaptationsByBook[bookID] = append(adaptationsByBook[bookID], adaptationsByID[adaptationID].summary())
+		}
+	}
+
+	return adaptationsByBook, nil
+}
+
+func (r *Repository) getByBooks(ctx context.Context, bookIDs []string) ([]Adaptation, map[string][]string, error) {
+	linksByBook, err := r.links.GetByBooks(ctx, linkOwner, bookIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var adaptationIDs []string
+	idsByBook := map[string][]string{}
+	for _, bookID := range bookIDs {
+		if _, ok := idsByBook[bookID]; ok {
+			continue
+		}
+
+		idsByBook[bookID] = nil
+		for _, link := range linksByBook[bookID] {
+			idsByBook[bookID] = append(idsByBook[bookID], link.OwnerID)
+			if !slices.Contains(adaptationIDs, link.OwnerID) {
+				adaptationIDs = append(adaptationIDs, link.OwnerID)
+			}
+		}
+	}
+
+	adaptationsList, err := r.batchGet(ctx, adaptationIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return adaptationsList, idsByBook, nil
+}
+
+func (r *Repository) GetByActor(ctx context.Context, actorID string) ([]Adaptation, error) {
+	cast, err := r.links.GetCastByActor(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.batchGet(ctx, adaptationIDs(cast))
+}
+
+func (r *Repository) GetActorIDs(ctx context.Context, characterIDs []string) (map[string][]string, error) {
+	castByCharacter, err := r.links.GetCastByCharacters(ctx, characterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	actorIDs := map[string][]string{}
+	for characterID, cast := range castByCharacter {
+		for _, member := range cast {
+			if !slices.Contains(actorIDs[characterID], member.ActorID) {
+				actorIDs[characterID] = append(actorIDs[characterID], member.ActorID)
+			}
+		}
+	}
+
+	return actorIDs, nil
+}
+
+func (r *Repository) RemoveCharacter(ctx context.Context, characterID string) error {
+	castByCharacter, err := r.links.GetCastByCharacters(ctx, []string{characterID})
+	if err != nil {
+		return err
+	}
+
+	adaptationsList, err := r.batchGet(ctx, adaptationIDs(castByCharacter[characterID]))
+	if err != nil {
+		return err
+	}
+
+	for _, adaptation := range adaptationsList {
+		current := adaptation
+		adaptation.Cast = slices.DeleteFunc(slices.Clone(adaptation.Cast), func(c CastMember) bool { return c.Character.ID == characterID })
+
+		err = r.write(ctx, current, adaptation)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) HasBook(ctx context.Context, bookID string) (bool, error) {
+	links, err := r.links.GetByBook(ctx, linkOwner, bookID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(links) > 0, nil
+}
+
+func (r *Repository) RemoveBook(ctx context.Context, bookID string) error {
+	adaptationsList, err := r.GetByBook(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	for _, adaptation := range adaptationsList {
+		current := adaptation
+		adaptation.Books = slices.DeleteFunc(slices.Clone(adaptation.Books), func(b books.Book) bool { return b.ID == bookID })
+
+		err = r.write(ctx, current, adaptation)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) BackfillSlugs(ctx context.Context) error {
+	adaptationsList, err := r.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, adaptation := range adaptationsList {
+		if adaptation.Slug != "" {
+			continue
+		}
+
+		adaptation.Version = dynamo.AnyVersion
+		_, err = r.Update(ctx, adaptation)
+		if err != nil {
+			return fmt.Errorf("%w. adaptation: %s", err, adaptation.ID)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) RelinkBooks(ctx context.Context) error {
+	adaptationsList, err := r.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, adaptation := range adaptationsList {
+		err = r.links.Replace(ctx, linkOwner, adaptation.ID, newLinks(adaptation))
+		if err != nil {
+			return err
+		}
+
+		err = r.links.PutCast(ctx, newCast(adaptation))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) Import(ctx context.Context, bookID string, legacy []books.Adaptation) error {
+	for _, legacyAdaptation := range legacy {
+		adaptation, err := r.GetByTitle(ctx, legacyAdaptation.Description)
+		if errors.Is(err, dynamo.ErrNotFound) {
+			adaptation = Adaptation{Title: legacyAdaptation.Description, IMDB: legacyAdaptation.IMDB, Books: []books.Book{{ID: bookID}}}
+			_, _, err = r.Save(ctx, adaptation)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(adaptation.Books, func(b books.Book) bool { return b.ID == bookID }) {
+			continue
+		}
+
+		adaptation.Books = append(adaptation.Books, books.Book{ID: bookID})
+		_, err = r.Update(ctx, adaptation)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) AddCast(ctx context.Context, characterID string, bookIDs []string, actorIDs []string) (bool, error) {
+	adaptationsList, _, err := r.getByBooks(ctx, bookIDs)
+	if err != nil {
+		return false, err
+	}
+
+	for _, adaptation := range adaptationsList {
+		current := adaptation
+		adaptation.Cast = slices.Clone(adaptation.Cast)
+		for _, actorID := range actorIDs {
+			member := CastMember{Actor: actors.Actor{ID: actorID}, Character: characters.Character{ID: characterID}}
+			if !slices.ContainsFunc(adaptation.Cast, func(c CastMember) bool { return c.Actor.ID == actorID && c.Character.ID == characterID }) {
+				adaptation.Cast = append(adaptation.Cast, member)
+			}
+		}
+
+		err = r.write(ctx, current, adaptation)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return len(adaptationsList) > 0, nil
+}
+
+func (r *Repository) write(ctx context.Context, current Adaptation, adaptation Adaptation) error {
+	writes, err := r.writes(current, adaptation)
+	if err != nil {
+		return err
+	}
+
+	adaptationItem, err := attributevalue.MarshalMap(newDBAdaptation(adaptation))
+	if err != nil {
+		return fmt.Errorf("failed to marshal adaptation: %w", err)
+	}
+
+	return relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		return r.dynamoDBClient.Update(ctx, r.tableName, adaptation.ID, adaptation.Version, adaptationItem, adaptation.Title, adaptation.Title, writes...)
+	})
+}
+
+func (r *Repository) writes(current Adaptation, adaptation Adaptation) ([]dynamo.Write, error) {
+	writes, err := r.links.Writes(linkOwner, adaptation.ID, newLinks(current), newLinks(adaptation))
+	if err != nil {
+		return nil, err
+	}
+
+	castWrites, err := r.links.CastWrites(newCast(current), newCast(adaptation))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(writes, castWrites...), nil
+}
+
+func (r *Repository) batchGet(ctx context.Context, adaptationIDs []string) ([]Adaptation, error) {
+	if len(adaptationIDs) == 0 {
+		return nil, nil
+	}
+
+	items, err := r.dynamoDBClient.BatchGetByIDs(ctx, r.tableName, adaptationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAdaptationList(items)
+}
+
+func toAdaptationList(items []map[string]types.AttributeValue) ([]Adaptation, error) {
+	var dbAdaptations []DBAdaptation
+	err := attributevalue.UnmarshalListOfMaps(items, &dbAdaptations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal adaptations: %w", err)
+	}
+
+	var adaptationsList []Adaptation
+	for _, dbAdaptation := range dbAdaptations {
+		adaptationsList = append(adaptationsList, dbAdaptation.toAdaptation())
+	}
+
+	return adaptationsList, nil
+}
+
+func newLinks(adaptation Adaptation) []relations.Link {
+	var links []relations.Link
+	for _, book := range adaptation.Books {
+		links = append(links, relations.Link{BookID: book.ID})
+	}
+
+	return links
+}
+
+func newCast(adaptation Adaptation) []relations.Cast {
+	var cast []relations.Cast
+	for _, member := range adaptation.Cast {
+		castMember := relations.Cast{AdaptationID: adaptation.ID, CharacterID: member.Character.ID, ActorID: member.Actor.ID}
+		if !slices.Contains(cast, castMember) {
+			cast = append(cast, castMember)
+		}
+	}
+
+	return cast
+}
+
+func adaptationIDs(cast []relations.Cast) []string {
+	var ids []string
+	for _, member := range cast {
+		if !slices.Contains(ids, member.AdaptationID) {
+			ids = append(ids, member.AdaptationID)
+		}
+	}
+
+	return ids
+}
+
+type DBAdaptation struct {
+	ID          string         `dynamodbav:"id"`
+	Slug        string         `dynamodbav:"slug,omitempty"`
+	Title       string         `dynamodbav:"title"`
+	Type        string         `dynamodbav:"type,omitempty"`
+	IMDB        string         `dynamodbav:"imdb"`
+	ReleaseDate string         `dynamodbav:"release_date,omitempty"`
+	EndDate     string         `dynamodbav:"end_date,omitempty"`
+	Network     string         `dynamodbav:"network,omitempty"`
+	Books       []string       `dynamodbav:"books"`
+	Cast        []DBCastMember `dynamodbav:"cast,omitempty"`
+	Version     int            `dynamodbav:"version,omitempty"`
+}
+
+type DBCastMember struct {
+	ActorID     string `dynamodbav:"actor_id"`
+	CharacterID string `dynamodbav:"character_id"`
+}
+
+func newDBAdaptation(adaptation Adaptation) DBAdaptation {
+	bookIDs := []string{}
+	for _, book := range adaptation.Books {
+		bookIDs = append(bookIDs, book.ID)
+	}
+
+	var cast []DBCastMember
+	for _, member := range adaptation.Cast {
+		cast = append(cast, DBCastMember{ActorID: member.Actor.ID, CharacterID: member.Character.ID})
+	}
+
+	return DBAdaptation{
+		ID:          adaptation.ID,
+		Slug:        adaptation.Slug,
+		Title:       adaptation.Title,
+		Type:        string(adaptation.Type),
+		IMDB:        adaptation.IMDB,
+		ReleaseDate: adaptation.ReleaseDate,
+		EndDate:     adaptation.EndDate,
+		Network:     adaptation.Network,
+		Books:       bookIDs,
+		Cast:        cast,
+		Version:     adaptation.Version,
+	}
+}
+
+func (d *DBAdaptation) toAdaptation() Adaptation {
+	var booksList []books.Book
+	for _, bookID := range d.Books {
+		booksList = append(booksList, books.Book{ID: bookID})
+	}
+
+	var cast []CastMember
+	for _, member := range d.Cast {
+		cast = append(cast, CastMember{Actor: actors.Actor{ID: member.ActorID}, Character: characters.Character{ID: member.CharacterID}})
+	}
+
+	return Adaptation{
+		ID:          d.ID,
+		Slug:        d.Slug,
+		Title:       d.Title,
+		Type:        Type(d.Type),
+		IMDB:        d.IMDB,
+		ReleaseDate: d.ReleaseDate,
+		EndDate:     d.EndDate,
+		Network:     d.Network,
+		Books:       booksList,
+		Cast:        cast,
+		Version:     d.Version,
+	}
+}
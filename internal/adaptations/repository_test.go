@@ -0,0 +1,919 @@
+package adaptations
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/memory"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRepository_Save(t *testing.T) {
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "random-id"},
+		"slug":  &types.AttributeValueMemberS{Value: "bosch"},
+		"title": &types.AttributeValueMemberS{Value: "Bosch"},
+		"type":  &types.AttributeValueMemberS{Value: "series"},
+		"imdb":  &types.AttributeValueMemberS{Value: "tt3502248"},
+		"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+		"cast": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"actor_id":     &types.AttributeValueMemberS{Value: "actor-id"},
+			"character_id": &types.AttributeValueMemberS{Value: "character-id"},
+		}}}},
+	}
+	existingItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "bosch"}, "title": &types.AttributeValueMemberS{Value: "Bosch"}, "imdb": &types.AttributeValueMemberS{Value: "tt3502248"}, "version": &types.AttributeValueMemberN{Value: "2"}}
+	adaptation := Adaptation{
+		Title: "Bosch",
+		Type:  TypeSeries,
+		IMDB:  "tt3502248",
+		Books: []books.Book{{ID: "book-id"}},
+		Cast:  []CastMember{{Actor: actors.Actor{ID: "actor-id"}, Character: characters.Character{ID: "character-id"}}},
+	}
+	bookLinks := []relations.Link{{BookID: "book-id"}}
+	writes := []dynamo.Write{dynamo.PutWrite("relations", "adaptation#random-id#book-id", map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id"}})}
+	cast := []relations.Cast{{AdaptationID: "random-id", CharacterID: "character-id", ActorID: "actor-id"}}
+	castWrites := []dynamo.Write{dynamo.PutWrite("relations", "cast#random-id#character-id#actor-id", map[string]types.AttributeValue{"actor_id": &types.AttributeValueMemberS{Value: "actor-id"}})}
+	adaptationWrites := append(slices.Clone(writes), castWrites...)
+	saveWrites := append(slices.Clone(adaptationWrites), dynamo.ClaimSlugWrite("table-name", "bosch", "random-id"))
+	tests := []struct {
+		name        string
+		policy      dynamo.DuplicatePolicy
+		setup       func(*MockDynamoDBClient, *LinksMock)
+		want        Adaptation
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name:   "when failed to save adaptation because already exists",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("random-id").Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), cast).Return(castWrites, nil).Once()
+				m.On("Save", ctx, "table-name", item, "Bosch", saveWrites).Return("", &dynamo.DuplicatedError{ID: "random-id"}).Once()
+			},
+			wantErr: &dynamo.DuplicatedError{ID: "random-id"},
+		},
+		{
+			name:   "when adaptation already exists and policy returns existing",
+			policy: dynamo.DuplicateReturnExisting,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("random-id").Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), cast).Return(castWrites, nil).Once()
+				m.On("Save", ctx, "table-name", item, "Bosch", saveWrites).Return("", &dynamo.DuplicatedError{ID: "random-id"}).Once()
+				m.On("GetByID", ctx, "table-name", "random-id").Return(existingItem, nil).Once()
+			},
+			want: Adaptation{ID: "random-id", Slug: "bosch", Title: "Bosch", IMDB: "tt3502248", Version: 2},
+		},
+		{
+			name:   "when failed to build adaptation book link writes",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("random-id").Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), bookLinks).Return([]dynamo.Write(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when failed to build adaptation cast writes",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("random-id").Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), cast).Return([]dynamo.Write(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when slug is taken it is suffixed",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("random-id").Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), cast).Return(castWrites, nil).Once()
+				m.On("Save", ctx, "table-name", item, "Bosch", saveWrites).Return("", dynamo.ErrSlugTaken).Once()
+				suffixedItem := maps.Clone(item)
+				suffixedItem["slug"] = &types.AttributeValueMemberS{Value: "bosch-2"}
+				suffixedWrites := append(slices.Clone(adaptationWrites), dynamo.ClaimSlugWrite("table-name", "bosch-2", "random-id"))
+				m.On("Save", ctx, "table-name", suffixedItem, "Bosch", suffixedWrites).Return("random-id", nil).Once()
+			},
+			want: Adaptation{
+				ID:      "random-id",
+				Slug:    "bosch-2",
+				Title:   "Bosch",
+				Type:    TypeSeries,
+				IMDB:    "tt3502248",
+				Books:   []books.Book{{ID: "book-id"}},
+				Cast:    []CastMember{{Actor: actors.Actor{ID: "actor-id"}, Character: characters.Character{ID: "character-id"}}},
+				Version: 1,
+			},
+			wantCreated: true,
+		},
+		{
+			name:   "when successfully saved adaptation with its book links",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("random-id").Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), cast).Return(castWrites, nil).Once()
+				m.On("Save", ctx, "table-name", item, "Bosch", saveWrites).Return("random-id", nil).Once()
+			},
+			want: Adaptation{
+				ID:      "random-id",
+				Slug:    "bosch",
+				Title:   "Bosch",
+				Type:    TypeSeries,
+				IMDB:    "tt3502248",
+				Books:   []books.Book{{ID: "book-id"}},
+				Cast:    []CastMember{{Actor: actors.Actor{ID: "actor-id"}, Character: characters.Character{ID: "character-id"}}},
+				Version: 1,
+			},
+			wantCreated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", tt.policy, links)
+
+			got, created, err := r.Save(ctx, adaptation)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "bosch"}, "title": &types.AttributeValueMemberS{Value: "Bosch"}, "imdb": &types.AttributeValueMemberS{Value: "tt3502248"}, "version": &types.AttributeValueMemberN{Value: "2"}}
+	item := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "random-id"},
+		"slug":    &types.AttributeValueMemberS{Value: "bosch"},
+		"title":   &types.AttributeValueMemberS{Value: "Bosch: Legacy"},
+		"imdb":    &types.AttributeValueMemberS{Value: "tt14286784"},
+		"books":   &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+		"version": &types.AttributeValueMemberN{Value: "2"},
+	}
+	writes := []dynamo.Write{dynamo.PutWrite("relations", "adaptation#random-id#book-id", map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id"}})}
+	updateWrites := append(slices.Clone(writes), dynamo.ClaimSlugWrite("table-name", "bosch", "random-id"))
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		want    Adaptation
+		wantErr error
+	}{
+		{
+			name: "when failed to get current adaptation",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(map[string]types.AttributeValue{}, dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name: "when failed to update adaptation",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), []relations.Link{{BookID: "book-id"}}).Return(writes, nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), []relations.Cast(nil)).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Update", ctx, "table-name", "random-id", 2, item, "Bosch", "Bosch: Legacy", updateWrites).Return(dynamo.ErrVersionMismatch).Once()
+			},
+			wantErr: dynamo.ErrVersionMismatch,
+		},
+		{
+			name: "when adaptation has no slug yet it is generated from the new title",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				withoutSlug := maps.Clone(current)
+				delete(withoutSlug, "slug")
+				m.On("GetByID", ctx, "table-name", "random-id").Return(withoutSlug, nil).Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), []relations.Link{{BookID: "book-id"}}).Return(writes, nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), []relations.Cast(nil)).Return([]dynamo.Write(nil), nil).Once()
+				generatedItem := maps.Clone(item)
+				generatedItem["slug"] = &types.AttributeValueMemberS{Value: "bosch-legacy"}
+				generatedWrites := append(slices.Clone(writes), dynamo.ClaimSlugWrite("table-name", "bosch-legacy", "random-id"))
+				m.On("Update", ctx, "table-name", "random-id", 2, generatedItem, "Bosch", "Bosch: Legacy", generatedWrites).Return(nil).Once()
+			},
+			want: Adaptation{ID: "random-id", Slug: "bosch-legacy", Title: "Bosch: Legacy", IMDB: "tt14286784", Books: []books.Book{{ID: "book-id"}}, Version: 3},
+		},
+		{
+			name: "when successfully updated adaptation",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), []relations.Link{{BookID: "book-id"}}).Return(writes, nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), []relations.Cast(nil)).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Update", ctx, "table-name", "random-id", 2, item, "Bosch", "Bosch: Legacy", updateWrites).Return(nil).Once()
+			},
+			want: Adaptation{ID: "random-id", Slug: "bosch", Title: "Bosch: Legacy", IMDB: "tt14286784", Books: []books.Book{{ID: "book-id"}}, Version: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			got, err := r.Update(ctx, Adaptation{ID: "random-id", Title: "Bosch: Legacy", IMDB: "tt14286784", Books: []books.Book{{ID: "book-id"}}, Version: 2})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "random-id"},
+		"slug":  &types.AttributeValueMemberS{Value: "bosch"},
+		"title": &types.AttributeValueMemberS{Value: "Bosch"},
+		"imdb":  &types.AttributeValueMemberS{Value: "tt3502248"},
+		"cast": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"actor_id":     &types.AttributeValueMemberS{Value: "actor-id"},
+			"character_id": &types.AttributeValueMemberS{Value: "character-id"},
+		}}}},
+	}
+	castWrites := []dynamo.Write{dynamo.DeleteWrite("relations", "cast#random-id#character-id#actor-id")}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get current adaptation",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(map[string]types.AttributeValue{}, dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name: "when successfully deleted adaptation",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), []relations.Link(nil)).Return([]dynamo.Write(nil), nil).Once()
+				l.On("CastWrites", []relations.Cast{{AdaptationID: "random-id", CharacterID: "character-id", ActorID: "actor-id"}}, []relations.Cast(nil)).Return(castWrites, nil).Once()
+				m.On("Delete", ctx, "table-name", "random-id", "Bosch", append(slices.Clone(castWrites), dynamo.ReleaseSlugWrite("table-name", "bosch", "random-id"))).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			err := r.Delete(ctx, "random-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByBooks(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		want    map[string][]books.Adaptation
+		wantErr error
+	}{
+		{
+			name: "when failed to get book links",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBooks", ctx, "adaptation", []string{"book-1", "book-2", "book-1"}).Return(map[string][]relations.Link(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to get adaptations",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBooks", ctx, "adaptation", []string{"book-1", "book-2", "book-1"}).Return(map[string][]relations.Link{"book-1": {{OwnerID: "adaptation-1"}}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"adaptation-1"}).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when books have no adaptations",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBooks", ctx, "adaptation", []string{"book-1", "book-2", "book-1"}).Return(map[string][]relations.Link{}, nil).Once()
+			},
+			want: map[string][]books.Adaptation{"book-1": nil, "book-2": nil},
+		},
+		{
+			name: "when successfully got adaptations by book in one batch",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBooks", ctx, "adaptation", []string{"book-1", "book-2", "book-1"}).Return(map[string][]relations.Link{
+					"book-1": {{OwnerID: "adaptation-1"}},
+					"book-2": {{OwnerID: "adaptation-2"}, {OwnerID: "adaptation-1"}},
+				}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"adaptation-1", "adaptation-2"}).Return([]map[string]types.AttributeValue{
+					{"id": &types.AttributeValueMemberS{Value: "adaptation-1"}, "title": &types.AttributeValueMemberS{Value: "Bosch"}, "type": &types.AttributeValueMemberS{Value: "series"}, "imdb": &types.AttributeValueMemberS{Value: "tt3502248"}},
+					{"id": &types.AttributeValueMemberS{Value: "adaptation-2"}, "title": &types.AttributeValueMemberS{Value: "Bosch: Legacy"}, "type": &types.AttributeValueMemberS{Value: "series"}, "imdb": &types.AttributeValueMemberS{Value: "tt14286784"}},
+				}, nil).Once()
+			},
+			want: map[string][]books.Adaptation{
+				"book-1": {{ID: "adaptation-1", Description: "Bosch", Type: "series", IMDB: "tt3502248"}},
+				"book-2": {{ID: "adaptation-2", Description: "Bosch: Legacy", Type: "series", IMDB: "tt14286784"}, {ID: "adaptation-1", Description: "Bosch", Type: "series", IMDB: "tt3502248"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			got, err := r.GetByBooks(ctx, []string{"book-1", "book-2", "book-1"})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetActorIDs(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*LinksMock)
+		want    map[string][]string
+		wantErr error
+	}{
+		{
+			name: "when failed to get character cast",
+			setup: func(l *LinksMock) {
+				l.On("GetCastByCharacters", ctx, []string{"character-1", "character-2", "character-3"}).Return(map[string][]relations.Cast(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully got actor ids",
+			setup: func(l *LinksMock) {
+				l.On("GetCastByCharacters", ctx, []string{"character-1", "character-2", "character-3"}).Return(map[string][]relations.Cast{
+					"character-1": {
+						{AdaptationID: "adaptation-1", CharacterID: "character-1", ActorID: "actor-1"},
+						{AdaptationID: "adaptation-2", CharacterID: "character-1", ActorID: "actor-1"},
+					},
+					"character-3": {{AdaptationID: "adaptation-2", CharacterID: "character-3", ActorID: "actor-3"}},
+				}, nil).Once()
+			},
+			want: map[string][]string{"character-1": {"actor-1"}, "character-3": {"actor-3"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			got, err := r.GetActorIDs(ctx, []string{"character-1", "character-2", "character-3"})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByActor(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		want    []Adaptation
+		wantErr error
+	}{
+		{
+			name: "when failed to get actor cast",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetCastByActor", ctx, "actor-id").Return([]relations.Cast(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when actor is not cast",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetCastByActor", ctx, "actor-id").Return([]relations.Cast(nil), nil).Once()
+			},
+		},
+		{
+			name: "when successfully got adaptations by actor",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetCastByActor", ctx, "actor-id").Return([]relations.Cast{
+					{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-id"},
+					{AdaptationID: "adaptation-id", CharacterID: "character-2", ActorID: "actor-id"},
+				}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"adaptation-id"}).Return([]map[string]types.AttributeValue{
+					{"id": &types.AttributeValueMemberS{Value: "adaptation-id"}, "title": &types.AttributeValueMemberS{Value: "Bosch"}, "imdb": &types.AttributeValueMemberS{Value: "tt3502248"}},
+				}, nil).Once()
+			},
+			want: []Adaptation{{ID: "adaptation-id", Title: "Bosch", IMDB: "tt3502248"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			got, err := r.GetByActor(ctx, "actor-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_PageStorageCalls(t *testing.T) {
+	ctx := context.Background()
+	client := &countingClient{Client: memory.NewClient(uuid.New), calls: map[string]int{}}
+	r := NewRepository(client, "adaptations", dynamo.DuplicateReject, relations.NewRepository(client, "relations", "books"))
+
+	bookIDs := []string{"book-1", "book-2", "book-3"}
+	characterIDs := []string{"character-1", "character-2", "character-3"}
+	for i, bookID := range bookIDs {
+		_, _, err := r.Save(ctx, Adaptation{
+			Title: "Adaptation " + bookID,
+			Type:  TypeFilm,
+			Books: []books.Book{{ID: bookID}},
+			Cast:  []CastMember{{Actor: actors.Actor{ID: "actor-" + bookID}, Character: characters.Character{ID: characterIDs[i]}}},
+		})
+		assert.NoError(t, err)
+	}
+	clear(client.calls)
+
+	adaptationsByBook, err := r.GetByBooks(ctx, bookIDs)
+	assert.NoError(t, err)
+	assert.Len(t, adaptationsByBook, 3)
+	assert.Equal(t, map[string]int{"GetReferences": 1, "BatchGetByIDs": 1}, client.calls)
+	clear(client.calls)
+
+	actorIDs, err := r.GetActorIDs(ctx, characterIDs)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"character-1": {"actor-book-1"}, "character-2": {"actor-book-2"}, "character-3": {"actor-book-3"}}, actorIDs)
+	assert.Equal(t, map[string]int{"GetReferences": 1}, client.calls)
+}
+
+func TestRepository_UpdateRecastsCharacter(t *testing.T) {
+	ctx := context.Background()
+	client := memory.NewClient(uuid.New)
+	r := NewRepository(client, "adaptations", dynamo.DuplicateReject, relations.NewRepository(client, "relations", "books"))
+
+	adaptation, _, err := r.Save(ctx, Adaptation{
+		Title: "Bosch",
+		Type:  TypeSeries,
+		Cast:  []CastMember{{Actor: actors.Actor{ID: "actor-1"}, Character: characters.Character{ID: "character-1"}}},
+	})
+	assert.NoError(t, err)
+
+	adaptation.Cast = []CastMember{{Actor: actors.Actor{ID: "actor-2"}, Character: characters.Character{ID: "character-1"}}}
+	_, err = r.Update(ctx, adaptation)
+	assert.NoError(t, err)
+
+	actorIDs, err := r.GetActorIDs(ctx, []string{"character-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"character-1": {"actor-2"}}, actorIDs)
+}
+
+func TestRepository_RemoveCharacter(t *testing.T) {
+	ctx := context.Background()
+	castMember := func(actorID string, characterID string) types.AttributeValue {
+		return &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"actor_id":     &types.AttributeValueMemberS{Value: actorID},
+			"character_id": &types.AttributeValueMemberS{Value: characterID},
+		}}
+	}
+	adaptationItem := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "adaptation-id"},
+		"title":   &types.AttributeValueMemberS{Value: "Bosch"},
+		"imdb":    &types.AttributeValueMemberS{Value: "tt3502248"},
+		"cast":    &types.AttributeValueMemberL{Value: []types.AttributeValue{castMember("actor-1", "character-1"), castMember("actor-2", "character-2")}},
+		"version": &types.AttributeValueMemberN{Value: "1"},
+	}
+	characterCast := []relations.Cast{{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-1"}}
+	currentCast := append(slices.Clone(characterCast), relations.Cast{AdaptationID: "adaptation-id", CharacterID: "character-2", ActorID: "actor-2"})
+	castWrites := []dynamo.Write{dynamo.DeleteWrite("relations", "cast#adaptation-id#character-1#actor-1")}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get character cast",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetCastByCharacters", ctx, []string{"character-1"}).Return(map[string][]relations.Cast(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when character is not cast",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetCastByCharacters", ctx, []string{"character-1"}).Return(map[string][]relations.Cast{}, nil).Once()
+			},
+		},
+		{
+			name: "when failed to update adaptation",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetCastByCharacters", ctx, []string{"character-1"}).Return(map[string][]relations.Cast{"character-1": characterCast}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"adaptation-id"}).Return([]map[string]types.AttributeValue{adaptationItem}, nil).Once()
+				l.On("Writes", "adaptation", "adaptation-id", []relations.Link(nil), []relations.Link(nil)).Return([]dynamo.Write(nil), nil).Once()
+				l.On("CastWrites", currentCast, currentCast[1:]).Return(castWrites, nil).Once()
+				m.On("Update", ctx, "table-name", "adaptation-id", 1, mock.Anything, "Bosch", "Bosch", castWrites).Return(dynamo.ErrVersionMismatch).Once()
+			},
+			wantErr: dynamo.ErrVersionMismatch,
+		},
+		{
+			name: "when character is removed from the adaptation cast",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetCastByCharacters", ctx, []string{"character-1"}).Return(map[string][]relations.Cast{"character-1": characterCast}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"adaptation-id"}).Return([]map[string]types.AttributeValue{adaptationItem}, nil).Once()
+				l.On("Writes", "adaptation", "adaptation-id", []relations.Link(nil), []relations.Link(nil)).Return([]dynamo.Write(nil), nil).Once()
+				l.On("CastWrites", currentCast, currentCast[1:]).Return(castWrites, nil).Once()
+				item := map[string]types.AttributeValue{
+					"id":      &types.AttributeValueMemberS{Value: "adaptation-id"},
+					"title":   &types.AttributeValueMemberS{Value: "Bosch"},
+					"imdb":    &types.AttributeValueMemberS{Value: "tt3502248"},
+					"books":   &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+					"cast":    &types.AttributeValueMemberL{Value: []types.AttributeValue{castMember("actor-2", "character-2")}},
+					"version": &types.AttributeValueMemberN{Value: "1"},
+				}
+				m.On("Update", ctx, "table-name", "adaptation-id", 1, item, "Bosch", "Bosch", castWrites).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			err := r.RemoveCharacter(ctx, "character-1")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_RelinkBooks(t *testing.T) {
+	ctx := context.Background()
+	adaptationItem := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "adaptation-id"},
+		"title": &types.AttributeValueMemberS{Value: "Bosch"},
+		"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+		"cast": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"actor_id":     &types.AttributeValueMemberS{Value: "actor-id"},
+			"character_id": &types.AttributeValueMemberS{Value: "character-id"},
+		}}}},
+	}
+	cast := []relations.Cast{{AdaptationID: "adaptation-id", CharacterID: "character-id", ActorID: "actor-id"}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get all adaptations",
+			setup: func(m *MockDynamoDBClient, _ *LinksMock) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to link adaptation books",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{adaptationItem}, nil).Once()
+				l.On("Replace", ctx, "adaptation", "adaptation-id", []relations.Link{{BookID: "book-id"}}).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to index adaptation cast",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{adaptationItem}, nil).Once()
+				l.On("Replace", ctx, "adaptation", "adaptation-id", []relations.Link{{BookID: "book-id"}}).Return(nil).Once()
+				l.On("PutCast", ctx, cast).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully relinked adaptation books and cast",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{adaptationItem}, nil).Once()
+				l.On("Replace", ctx, "adaptation", "adaptation-id", []relations.Link{{BookID: "book-id"}}).Return(nil).Once()
+				l.On("PutCast", ctx, cast).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			err := r.RelinkBooks(ctx)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Import(t *testing.T) {
+	ctx := context.Background()
+	legacy := []books.Adaptation{{Description: "Bosch", IMDB: "tt3502248"}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get adaptation by title",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByUniqueKey", ctx, "table-name", "Bosch").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when adaptation does not exist it is created",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByUniqueKey", ctx, "table-name", "Bosch").Return(map[string]types.AttributeValue{}, dynamo.ErrNotFound).Once()
+				item := map[string]types.AttributeValue{
+					"id":    &types.AttributeValueMemberS{Value: "random-id"},
+					"slug":  &types.AttributeValueMemberS{Value: "bosch"},
+					"title": &types.AttributeValueMemberS{Value: "Bosch"},
+					"imdb":  &types.AttributeValueMemberS{Value: "tt3502248"},
+					"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+				}
+				m.On("NewID").Return("random-id").Once()
+				l.On("Writes", "adaptation", "random-id", []relations.Link(nil), []relations.Link{{BookID: "book-id"}}).Return([]dynamo.Write(nil), nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), []relations.Cast(nil)).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Save", ctx, "table-name", item, "Bosch", []dynamo.Write{dynamo.ClaimSlugWrite("table-name", "bosch", "random-id")}).Return("random-id", nil).Once()
+			},
+		},
+		{
+			name: "when adaptation already lists the book",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByUniqueKey", ctx, "table-name", "Bosch").Return(map[string]types.AttributeValue{
+					"id":    &types.AttributeValueMemberS{Value: "random-id"},
+					"title": &types.AttributeValueMemberS{Value: "Bosch"},
+					"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+				}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			err := r.Import(ctx, "book-id", legacy)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_AddCast(t *testing.T) {
+	ctx := context.Background()
+	adaptationItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "adaptation-id"}, "title": &types.AttributeValueMemberS{Value: "Bosch"}, "imdb": &types.AttributeValueMemberS{Value: "tt3502248"}, "version": &types.AttributeValueMemberN{Value: "1"}}
+	cast := []relations.Cast{{AdaptationID: "adaptation-id", CharacterID: "character-id", ActorID: "actor-id"}}
+	castWrites := []dynamo.Write{dynamo.PutWrite("relations", "cast#adaptation-id#character-id#actor-id", map[string]types.AttributeValue{"actor_id": &types.AttributeValueMemberS{Value: "actor-id"}})}
+	tests := []struct {
+		name       string
+		setup      func(*MockDynamoDBClient, *LinksMock)
+		wantPlaced bool
+		wantErr    error
+	}{
+		{
+			name: "when books have no adaptations",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBooks", ctx, "adaptation", []string{"book-id"}).Return(map[string][]relations.Link{}, nil).Once()
+			},
+		},
+		{
+			name: "when failed to update adaptation",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBooks", ctx, "adaptation", []string{"book-id"}).Return(map[string][]relations.Link{"book-id": {{OwnerID: "adaptation-id"}}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"adaptation-id"}).Return([]map[string]types.AttributeValue{adaptationItem}, nil).Once()
+				l.On("Writes", "adaptation", "adaptation-id", []relations.Link(nil), []relations.Link(nil)).Return([]dynamo.Write(nil), nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), cast).Return(castWrites, nil).Once()
+				m.On("Update", ctx, "table-name", "adaptation-id", 1, mock.Anything, "Bosch", "Bosch", castWrites).Return(dynamo.ErrVersionMismatch).Once()
+			},
+			wantErr: dynamo.ErrVersionMismatch,
+		},
+		{
+			name: "when cast is added to the book adaptations",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBooks", ctx, "adaptation", []string{"book-id"}).Return(map[string][]relations.Link{"book-id": {{OwnerID: "adaptation-id"}}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "table-name", []string{"adaptation-id"}).Return([]map[string]types.AttributeValue{adaptationItem}, nil).Once()
+				l.On("Writes", "adaptation", "adaptation-id", []relations.Link(nil), []relations.Link(nil)).Return([]dynamo.Write(nil), nil).Once()
+				l.On("CastWrites", []relations.Cast(nil), cast).Return(castWrites, nil).Once()
+				item := map[string]types.AttributeValue{
+					"id":    &types.AttributeValueMemberS{Value: "adaptation-id"},
+					"title": &types.AttributeValueMemberS{Value: "Bosch"},
+					"imdb":  &types.AttributeValueMemberS{Value: "tt3502248"},
+					"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+					"cast": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+						"actor_id":     &types.AttributeValueMemberS{Value: "actor-id"},
+						"character_id": &types.AttributeValueMemberS{Value: "character-id"},
+					}}}},
+					"version": &types.AttributeValueMemberN{Value: "1"},
+				}
+				m.On("Update", ctx, "table-name", "adaptation-id", 1, item, "Bosch", "Bosch", castWrites).Return(nil).Once()
+			},
+			wantPlaced: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, links)
+
+			placed, err := r.AddCast(ctx, "character-id", []string{"book-id"}, []string{"actor-id"})
+
+			assert.Equal(t, tt.wantPlaced, placed)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+type MockDynamoDBClient struct {
+	mock.Mock
+}
+
+func (m *MockDynamoDBClient) NewID() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockDynamoDBClient) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error) {
+	args := m.Called(ctx, tableName, item, uniqueKey, writes)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, version, item, oldUniqueKey, newUniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, uniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, id)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, value)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetBySlug(ctx context.Context, tableName string, slug string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, slug)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, ids)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+type LinksMock struct {
+	mock.Mock
+}
+
+func (l *LinksMock) Writes(owner string, ownerID string, current []relations.Link, links []relations.Link) ([]dynamo.Write, error) {
+	args := l.Called(owner, ownerID, current, links)
+	return args.Get(0).([]dynamo.Write), args.Error(1)
+}
+
+func (l *LinksMock) Replace(ctx context.Context, owner string, ownerID string, links []relations.Link) error {
+	args := l.Called(ctx, owner, ownerID, links)
+	return args.Error(0)
+}
+
+func (l *LinksMock) GetByBook(ctx context.Context, owner string, bookID string) ([]relations.Link, error) {
+	args := l.Called(ctx, owner, bookID)
+	return args.Get(0).([]relations.Link), args.Error(1)
+}
+
+func (l *LinksMock) Apply(ctx context.Context, writes []dynamo.Write) error {
+	args := l.Called(ctx, writes)
+	return args.Error(0)
+}
+
+func (l *LinksMock) GetByBooks(ctx context.Context, owner string, bookIDs []string) (map[string][]relations.Link, error) {
+	args := l.Called(ctx, owner, bookIDs)
+	return args.Get(0).(map[string][]relations.Link), args.Error(1)
+}
+
+func (l *LinksMock) CastWrites(current []relations.Cast, cast []relations.Cast) ([]dynamo.Write, error) {
+	args := l.Called(current, cast)
+	return args.Get(0).([]dynamo.Write), args.Error(1)
+}
+
+func (l *LinksMock) PutCast(ctx context.Context, cast []relations.Cast) error {
+	args := l.Called(ctx, cast)
+	return args.Error(0)
+}
+
+func (l *LinksMock) GetCastByCharacters(ctx context.Context, characterIDs []string) (map[string][]relations.Cast, error) {
+	args := l.Called(ctx, characterIDs)
+	return args.Get(0).(map[string][]relations.Cast), args.Error(1)
+}
+
+func (l *LinksMock) GetCastByActor(ctx context.Context, actorID string) ([]relations.Cast, error) {
+	args := l.Called(ctx, actorID)
+	return args.Get(0).([]relations.Cast), args.Error(1)
+}
+
+type countingClient struct {
+	*memory.Client
+	calls map[string]int
+}
+
+func (c *countingClient) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
+	c.calls["GetByID"]++
+	return c.Client.GetByID(ctx, tableName, id)
+}
+
+func (c *countingClient) BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	c.calls["BatchGetByIDs"]++
+	return c.Client.BatchGetByIDs(ctx, tableName, ids)
+}
+
+func (c *countingClient) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
+	c.calls["GetAll"]++
+	return c.Client.GetAll(ctx, tableName)
+}
+
+func (c *countingClient) Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error) {
+	c.calls["Query"]++
+	return c.Client.Query(ctx, tableName, query)
+}
+
+func (c *countingClient) GetReferences(ctx context.Context, tableName string, ids []string) (map[string][]string, error) {
+	c.calls["GetReferences"]++
+	return c.Client.GetReferences(ctx, tableName, ids)
+}
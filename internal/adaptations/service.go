@@ -0,0 +1,277 @@
+package adaptations
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+)
+
+type StorageAdaptation interface {
+	Save(ctx context.Context, adaptation Adaptation) (Adaptation, bool, error)
+	Update(ctx context.Context, adaptation Adaptation) (Adaptation, error)
+	Delete(ctx context.Context, adaptationID string) error
+	GetById(ctx context.Context, adaptationID string) (Adaptation, error)
+	GetBySlug(ctx context.Context, slug string) (Adaptation, error)
+	GetByActor(ctx context.Context, actorID string) ([]Adaptation, error)
+	GetAll(ctx context.Context) ([]Adaptation, error)
+}
+
+type StorageBook interface {
+	GetByIds(ctx context.Context, bookIDs []string) ([]books.Book, error)
+	GetBookListByTitles(ctx context.Context, bookTitles []string) ([]books.Book, error)
+}
+
+type StorageActor interface {
+	Resolve(ctx context.Context, actor actors.Actor) (actors.Actor, error)
+	GetByIds(ctx context.Context, actorIDs []string) ([]actors.Actor, error)
+}
+
+type StorageCharacter interface {
+	GetByName(ctx context.Context, characterName string) (characters.Character, error)
+	GetByIds(ctx context.Context, characterIDs []string) ([]characters.Character, error)
+}
+
+type Service struct {
+	storageAdaptation StorageAdaptation
+	storageBook       StorageBook
+	storageActor      StorageActor
+	storageCharacter  StorageCharacter
+}
+
+func NewService(storageAdaptation StorageAdaptation, storageBook StorageBook, storageActor StorageActor, storageCharacter StorageCharacter) *Service {
+	return &Service{storageAdaptation: storageAdaptation, storageBook: storageBook, storageActor: storageActor, storageCharacter: storageCharacter}
+}
+
+func (s *Service) Create(ctx context.Context, adaptation Adaptation) (Adaptation, bool, error) {
+	adaptation, err := s.resolve(ctx, adaptation)
+	if err != nil {
+		return Adaptation{}, false, err
+	}
+
+	savedAdaptation, created, err := s.storageAdaptation.Save(ctx, adaptation)
+	if err != nil {
+		return Adaptation{}, false, err
+	}
+
+	if !created {
+		err = s.load(ctx, []Adaptation{savedAdaptation})
+		if err != nil {
+			return Adaptation{}, false, err
+		}
+	}
+
+	return savedAdaptation, created, nil
+}
+
+func (s *Service) Update(ctx context.Context, adaptation Adaptation) (Adaptation, error) {
+	adaptation, err := s.resolve(ctx, adaptation)
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	return s.storageAdaptation.Update(ctx, adaptation)
+}
+
+func (s *Service) Delete(ctx context.Context, adaptationID string) error {
+	return s.storageAdaptation.Delete(ctx, adaptationID)
+}
+
+func (s *Service) GetById(ctx context.Context, adaptationID string) (Adaptation, error) {
+	adaptation, err := s.storageAdaptation.GetById(ctx, adaptationID)
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	err = s.load(ctx, []Adaptation{adaptation})
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	return adaptation, nil
+}
+
+func (s *Service) GetBySlug(ctx context.Context, slug string) (Adaptation, error) {
+	adaptation, err := s.storageAdaptation.GetBySlug(ctx, slug)
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	err = s.load(ctx, []Adaptation{adaptation})
+	if err != nil {
+		return Adaptation{}, err
+	}
+
+	return adaptation, nil
+}
+
+func (s *Service) GetAll(ctx context.Context, adaptationType Type) ([]Adaptation, error) {
+	adaptationsList, err := s.storageAdaptation.GetAll(ctx)
+	if err != nil {
+		return []Adaptation{}, err
+	}
+
+	if adaptationType != "" {
+		adaptationsList = slices.DeleteFunc(adaptationsList, func(a Adaptation) bool { return a.Type != adaptationType })
+	}
+
+	err = s.load(ctx, adaptationsList)
+	if err != nil {
+		return []Adaptation{}, err
+	}
+
+	return adaptationsList, nil
+}
+
+func (s *Service) GetByActor(ctx context.Context, actorID string) ([]actors.Role, error) {
+	adaptationsList, err := s.storageAdaptation.GetByActor(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var characterIDs []string
+	adaptationsByCharacter := map[string][]books.Adaptation{}
+	for _, adaptation := range adaptationsList {
+		for _, member := range adaptation.Cast {
+			if member.Actor.ID != actorID {
+				continue
+			}
+
+			if !slices.Contains(characterIDs, member.Character.ID) {
+				characterIDs = append(characterIDs, member.Character.ID)
+			}
+			adaptationsByCharacter[member.Character.ID] = append(adaptationsByCharacter[member.Character.ID], adaptation.summary())
+		}
+	}
+
+	roles := []actors.Role{}
+	if len(characterIDs) == 0 {
+		return roles, nil
+	}
+
+	charactersList, err := s.storageCharacter.GetByIds(ctx, characterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(charactersList, func(a, b characters.Character) int {
+		return cmp.Or(cmp.Compare(dynamo.NormalizeKey(a.Name), dynamo.NormalizeKey(b.Name)), cmp.Compare(a.ID, b.ID))
+	})
+
+	for _, character := range charactersList {
+		roles = append(roles, actors.Role{CharacterID: character.ID, CharacterName: character.Name, Adaptations: adaptationsByCharacter[character.ID]})
+	}
+
+	return roles, nil
+}
+
+func (s *Service) resolve(ctx context.Context, adaptation Adaptation) (Adaptation, error) {
+	if len(adaptation.Books) > 0 {
+		var bookTitles []string
+		for _, book := range adaptation.Books {
+			bookTitles = append(bookTitles, book.Title)
+		}
+
+		booksList, err := s.storageBook.GetBookListByTitles(ctx, bookTitles)
+		if err != nil {
+			return Adaptation{}, err
+		}
+
+		adaptation.Books = booksList
+	}
+
+	var cast []CastMember
+	for _, member := range adaptation.Cast {
+		actor, err := s.storageActor.Resolve(ctx, member.Actor)
+		if err != nil {
+			return Adaptation{}, err
+		}
+
+		character, err := s.storageCharacter.GetByName(ctx, member.Character.Name)
+		if err != nil {
+			return Adaptation{}, err
+		}
+
+		cast = append(cast, CastMember{Actor: actor, Character: character})
+	}
+	adaptation.Cast = cast
+
+	return adaptation, nil
+}
+
+func (s *Service) load(ctx context.Context, adaptationsList []Adaptation) error {
+	var bookIDs, actorIDs, characterIDs []string
+	for _, adaptation := range adaptationsList {
+		for _, book := range adaptation.Books {
+			if !slices.Contains(bookIDs, book.ID) {
+				bookIDs = append(bookIDs, book.ID)
+			}
+		}
+
+		for _, member := range adaptation.Cast {
+			if !slices.Contains(actorIDs, member.Actor.ID) {
+				actorIDs = append(actorIDs, member.Actor.ID)
+			}
+			if !slices.Contains(characterIDs, member.Character.ID) {
+				characterIDs = append(characterIDs, member.Character.ID)
+			}
+		}
+	}
+
+	booksByID := map[string]books.Book{}
+	if len(bookIDs) > 0 {
+		booksList, err := s.storageBook.GetByIds(ctx, bookIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, book := range booksList {
+			booksByID[book.ID] = book
+		}
+	}
+
+	actorsByID := map[string]actors.Actor{}
+	charactersByID := map[string]characters.Character{}
+	if len(actorIDs) > 0 {
+		actorsList, err := s.storageActor.GetByIds(ctx, actorIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, actor := range actorsList {
+			actorsByID[actor.ID] = actor
+		}
+
+		charactersList, err := s.storageCharacter.GetByIds(ctx, characterIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, character := range charactersList {
+			charactersByID[character.ID] = character
+		}
+	}
+
+	for _, adaptation := range adaptationsList {
+		for i, book := range adaptation.Books {
+			if loaded, ok := booksByID[book.ID]; ok {
+				adaptation.Books[i] = loaded
+			}
+		}
+
+		for i, member := range adaptation.Cast {
+			if loaded, ok := actorsByID[member.Actor.ID]; ok {
+				adaptation.Cast[i].Actor = loaded
+			}
+			if loaded, ok := charactersByID[member.Character.ID]; ok {
+				adaptation.Cast[i].Character = loaded
+			}
+		}
+	}
+
+	return nil
+}
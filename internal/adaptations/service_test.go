@@ -0,0 +1,363 @@
+package adaptations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+	adaptation := Adaptation{
+		Title: "Bosch",
+		Type:  TypeSeries,
+		IMDB:  "tt3502248",
+		Books: []books.Book{{Title: "The Black Echo"}},
+		Cast:  []CastMember{{Actor: actors.Actor{Name: "Titus Welliver", IMDB: "nm0920038"}, Character: characters.Character{Name: "Harry Bosch"}}},
+	}
+	resolved := Adaptation{
+		Title: "Bosch",
+		Type:  TypeSeries,
+		IMDB:  "tt3502248",
+		Books: []books.Book{{ID: "book-id", Title: "The Black Echo"}},
+		Cast:  []CastMember{{Actor: actors.Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}, Character: characters.Character{ID: "character-id", Name: "Harry Bosch"}}},
+	}
+	tests := []struct {
+		name        string
+		setup       func(*StorageAdaptationMock, *StorageBookMock, *StorageActorMock, *StorageCharacterMock)
+		want        Adaptation
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name: "when failed to get books by title",
+			setup: func(sa *StorageAdaptationMock, sb *StorageBookMock, sac *StorageActorMock, sc *StorageCharacterMock) {
+				sb.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book(nil), dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name: "when failed to resolve actor",
+			setup: func(sa *StorageAdaptationMock, sb *StorageBookMock, sac *StorageActorMock, sc *StorageCharacterMock) {
+				sb.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{{ID: "book-id", Title: "The Black Echo"}}, nil).Once()
+				sac.On("Resolve", ctx, actors.Actor{Name: "Titus Welliver", IMDB: "nm0920038"}).Return(actors.Actor{}, actors.ErrInvalidIMDB).Once()
+			},
+			wantErr: actors.ErrInvalidIMDB,
+		},
+		{
+			name: "when character does not exist",
+			setup: func(sa *StorageAdaptationMock, sb *StorageBookMock, sac *StorageActorMock, sc *StorageCharacterMock) {
+				sb.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{{ID: "book-id", Title: "The Black Echo"}}, nil).Once()
+				sac.On("Resolve", ctx, actors.Actor{Name: "Titus Welliver", IMDB: "nm0920038"}).Return(actors.Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}, nil).Once()
+				sc.On("GetByName", ctx, "Harry Bosch").Return(characters.Character{}, dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name: "when adaptation already exists it is loaded",
+			setup: func(sa *StorageAdaptationMock, sb *StorageBookMock, sac *StorageActorMock, sc *StorageCharacterMock) {
+				sb.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{{ID: "book-id", Title: "The Black Echo"}}, nil).Once()
+				sac.On("Resolve", ctx, actors.Actor{Name: "Titus Welliver", IMDB: "nm0920038"}).Return(actors.Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}, nil).Once()
+				sc.On("GetByName", ctx, "Harry Bosch").Return(characters.Character{ID: "character-id", Name: "Harry Bosch"}, nil).Once()
+				sa.On("Save", ctx, resolved).Return(Adaptation{ID: "adaptation-id", Title: "Bosch", Books: []books.Book{{ID: "book-id"}}, Version: 2}, false, nil).Once()
+				sb.On("GetByIds", ctx, []string{"book-id"}).Return([]books.Book{{ID: "book-id", Title: "The Black Echo", Year: 1992}}, nil).Once()
+			},
+			want: Adaptation{ID: "adaptation-id", Title: "Bosch", Books: []books.Book{{ID: "book-id", Title: "The Black Echo", Year: 1992}}, Version: 2},
+		},
+		{
+			name: "when adaptation is created",
+			setup: func(sa *StorageAdaptationMock, sb *StorageBookMock, sac *StorageActorMock, sc *StorageCharacterMock) {
+				sb.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{{ID: "book-id", Title: "The Black Echo"}}, nil).Once()
+				sac.On("Resolve", ctx, actors.Actor{Name: "Titus Welliver", IMDB: "nm0920038"}).Return(actors.Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}, nil).Once()
+				sc.On("GetByName", ctx, "Harry Bosch").Return(characters.Character{ID: "character-id", Name: "Harry Bosch"}, nil).Once()
+				saved := resolved
+				saved.ID = "adaptation-id"
+				sa.On("Save", ctx, resolved).Return(saved, true, nil).Once()
+			},
+			want: Adaptation{
+				ID:    "adaptation-id",
+				Title: "Bosch",
+				Type:  TypeSeries,
+				IMDB:  "tt3502248",
+				Books: []books.Book{{ID: "book-id", Title: "The Black Echo"}},
+				Cast:  []CastMember{{Actor: actors.Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}, Character: characters.Character{ID: "character-id", Name: "Harry Bosch"}}},
+			},
+			wantCreated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageAdaptation := new(StorageAdaptationMock)
+			storageBook := new(StorageBookMock)
+			storageActor := new(StorageActorMock)
+			storageCharacter := new(StorageCharacterMock)
+			tt.setup(storageAdaptation, storageBook, storageActor, storageCharacter)
+
+			s := NewService(storageAdaptation, storageBook, storageActor, storageCharacter)
+
+			got, created, err := s.Create(ctx, adaptation)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			storageAdaptation.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+			storageActor.AssertExpectations(t)
+			storageCharacter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetById(t *testing.T) {
+	ctx := context.Background()
+	stored := Adaptation{
+		ID:    "adaptation-id",
+		Title: "Bosch",
+		Books: []books.Book{{ID: "book-id"}},
+		Cast:  []CastMember{{Actor: actors.Actor{ID: "actor-id"}, Character: characters.Character{ID: "character-id"}}},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*StorageAdaptationMock, *StorageBookMock, *StorageActorMock, *StorageCharacterMock)
+		want    Adaptation
+		wantErr error
+	}{
+		{
+			name: "when adaptation is not found",
+			setup: func(sa *StorageAdaptationMock, sb *StorageBookMock, sac *StorageActorMock, sc *StorageCharacterMock) {
+				sa.On("GetById", ctx, "adaptation-id").Return(Adaptation{}, dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name: "when failed to get cast actors",
+			setup: func(sa *StorageAdaptationMock, sb *StorageBookMock, sac *StorageActorMock, sc *StorageCharacterMock) {
+				sa.On("GetById", ctx, "adaptation-id").Return(stored, nil).Once()
+				sb.On("GetByIds", ctx, []string{"book-id"}).Return([]books.Book{{ID: "book-id", Title: "The Black Echo"}}, nil).Once()
+				sac.On("GetByIds", ctx, []string{"actor-id"}).Return([]actors.Actor(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when adaptation is loaded with books and cast",
+			setup: func(sa *StorageAdaptationMock, sb *StorageBookMock, sac *StorageActorMock, sc *StorageCharacterMock) {
+				sa.On("GetById", ctx, "adaptation-id").Return(stored, nil).Once()
+				sb.On("GetByIds", ctx, []string{"book-id"}).Return([]books.Book{{ID: "book-id", Title: "The Black Echo"}}, nil).Once()
+				sac.On("GetByIds", ctx, []string{"actor-id"}).Return([]actors.Actor{{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}}, nil).Once()
+				sc.On("GetByIds", ctx, []string{"character-id"}).Return([]characters.Character{{ID: "character-id", Name: "Harry Bosch"}}, nil).Once()
+			},
+			want: Adaptation{
+				ID:    "adaptation-id",
+				Title: "Bosch",
+				Books: []books.Book{{ID: "book-id", Title: "The Black Echo"}},
+				Cast:  []CastMember{{Actor: actors.Actor{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}, Character: characters.Character{ID: "character-id", Name: "Harry Bosch"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageAdaptation := new(StorageAdaptationMock)
+			storageBook := new(StorageBookMock)
+			storageActor := new(StorageActorMock)
+			storageCharacter := new(StorageCharacterMock)
+			tt.setup(storageAdaptation, storageBook, storageActor, storageCharacter)
+
+			s := NewService(storageAdaptation, storageBook, storageActor, storageCharacter)
+
+			got, err := s.GetById(ctx, "adaptation-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageAdaptation.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+			storageActor.AssertExpectations(t)
+			storageCharacter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetAll(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		adaptationType Type
+		want           []Adaptation
+	}{
+		{
+			name: "when no type is given",
+			want: []Adaptation{{ID: "id-1", Title: "Bosch", Type: TypeSeries}, {ID: "id-2", Title: "The Lincoln Lawyer", Type: TypeFilm}},
+		},
+		{
+			name:           "when filtered by type",
+			adaptationType: TypeFilm,
+			want:           []Adaptation{{ID: "id-2", Title: "The Lincoln Lawyer", Type: TypeFilm}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageAdaptation := new(StorageAdaptationMock)
+			storageAdaptation.On("GetAll", ctx).Return([]Adaptation{{ID: "id-1", Title: "Bosch", Type: TypeSeries}, {ID: "id-2", Title: "The Lincoln Lawyer", Type: TypeFilm}}, nil).Once()
+
+			s := NewService(storageAdaptation, new(StorageBookMock), new(StorageActorMock), new(StorageCharacterMock))
+
+			got, err := s.GetAll(ctx, tt.adaptationType)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+			storageAdaptation.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetByActor(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageAdaptationMock, *StorageCharacterMock)
+		want    []actors.Role
+		wantErr error
+	}{
+		{
+			name: "when failed to get adaptations by actor",
+			setup: func(sa *StorageAdaptationMock, sc *StorageCharacterMock) {
+				sa.On("GetByActor", ctx, "actor-id").Return([]Adaptation(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when actor has no roles",
+			setup: func(sa *StorageAdaptationMock, sc *StorageCharacterMock) {
+				sa.On("GetByActor", ctx, "actor-id").Return([]Adaptation(nil), nil).Once()
+			},
+			want: []actors.Role{},
+		},
+		{
+			name: "when successfully got roles sorted by character name",
+			setup: func(sa *StorageAdaptationMock, sc *StorageCharacterMock) {
+				sa.On("GetByActor", ctx, "actor-id").Return([]Adaptation{
+					{ID: "adaptation-1", Title: "Bosch", Type: TypeSeries, IMDB: "tt3502248", Cast: []CastMember{
+						{Actor: actors.Actor{ID: "actor-id"}, Character: characters.Character{ID: "character-2"}},
+						{Actor: actors.Actor{ID: "other-actor-id"}, Character: characters.Character{ID: "character-3"}},
+					}},
+					{ID: "adaptation-2", Title: "Bosch: Legacy", Type: TypeSeries, IMDB: "tt14286784", Cast: []CastMember{
+						{Actor: actors.Actor{ID: "actor-id"}, Character: characters.Character{ID: "character-2"}},
+						{Actor: actors.Actor{ID: "actor-id"}, Character: characters.Character{ID: "character-1"}},
+					}},
+				}, nil).Once()
+				sc.On("GetByIds", ctx, []string{"character-2", "character-1"}).Return([]characters.Character{{ID: "character-2", Name: "Harry Bosch"}, {ID: "character-1", Name: "Hieronymus Bosch"}}, nil).Once()
+			},
+			want: []actors.Role{
+				{CharacterID: "character-2", CharacterName: "Harry Bosch", Adaptations: []books.Adaptation{
+					{ID: "adaptation-1", Description: "Bosch", Type: "series", IMDB: "tt3502248"},
+					{ID: "adaptation-2", Description: "Bosch: Legacy", Type: "series", IMDB: "tt14286784"},
+				}},
+				{CharacterID: "character-1", CharacterName: "Hieronymus Bosch", Adaptations: []books.Adaptation{
+					{ID: "adaptation-2", Description: "Bosch: Legacy", Type: "series", IMDB: "tt14286784"},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageAdaptation := new(StorageAdaptationMock)
+			storageCharacter := new(StorageCharacterMock)
+			tt.setup(storageAdaptation, storageCharacter)
+
+			s := NewService(storageAdaptation, new(StorageBookMock), new(StorageActorMock), storageCharacter)
+
+			got, err := s.GetByActor(ctx, "actor-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageAdaptation.AssertExpectations(t)
+			storageCharacter.AssertExpectations(t)
+		})
+	}
+}
+
+type StorageAdaptationMock struct {
+	mock.Mock
+}
+
+func (s *StorageAdaptationMock) Save(ctx context.Context, adaptation Adaptation) (Adaptation, bool, error) {
+	args := s.Called(ctx, adaptation)
+	return args.Get(0).(Adaptation), args.Bool(1), args.Error(2)
+}
+
+func (s *StorageAdaptationMock) Update(ctx context.Context, adaptation Adaptation) (Adaptation, error) {
+	args := s.Called(ctx, adaptation)
+	return args.Get(0).(Adaptation), args.Error(1)
+}
+
+func (s *StorageAdaptationMock) Delete(ctx context.Context, adaptationID string) error {
+	args := s.Called(ctx, adaptationID)
+	return args.Error(0)
+}
+
+func (s *StorageAdaptationMock) GetById(ctx context.Context, adaptationID string) (Adaptation, error) {
+	args := s.Called(ctx, adaptationID)
+	return args.Get(0).(Adaptation), args.Error(1)
+}
+
+func (s *StorageAdaptationMock) GetBySlug(ctx context.Context, slug string) (Adaptation, error) {
+	args := s.Called(ctx, slug)
+	return args.Get(0).(Adaptation), args.Error(1)
+}
+
+func (s *StorageAdaptationMock) GetByActor(ctx context.Context, actorID string) ([]Adaptation, error) {
+	args := s.Called(ctx, actorID)
+	return args.Get(0).([]Adaptation), args.Error(1)
+}
+
+func (s *StorageAdaptationMock) GetAll(ctx context.Context) ([]Adaptation, error) {
+	args := s.Called(ctx)
+	return args.Get(0).([]Adaptation), args.Error(1)
+}
+
+type StorageBookMock struct {
+	mock.Mock
+}
+
+func (s *StorageBookMock) GetByIds(ctx context.Context, bookIDs []string) ([]books.Book, error) {
+	args := s.Called(ctx, bookIDs)
+	return args.Get(0).([]books.Book), args.Error(1)
+}
+
+func (s *StorageBookMock) GetBookListByTitles(ctx context.Context, bookTitles []string) ([]books.Book, error) {
+	args := s.Called(ctx, bookTitles)
+	return args.Get(0).([]books.Book), args.Error(1)
+}
+
+type StorageActorMock struct {
+	mock.Mock
+}
+
+func (s *StorageActorMock) Resolve(ctx context.Context, actor actors.Actor) (actors.Actor, error) {
+	args := s.Called(ctx, actor)
+	return args.Get(0).(actors.Actor), args.Error(1)
+}
+
+func (s *StorageActorMock) GetByIds(ctx context.Context, actorIDs []string) ([]actors.Actor, error) {
+	args := s.Called(ctx, actorIDs)
+	return args.Get(0).([]actors.Actor), args.Error(1)
+}
+
+type StorageCharacterMock struct {
+	mock.Mock
+}
+
+func (s *StorageCharacterMock) GetByName(ctx context.Context, characterName string) (characters.Character, error) {
+	args := s.Called(ctx, characterName)
+	return args.Get(0).(characters.Character), args.Error(1)
+}
+
+func (s *StorageCharacterMock) GetByIds(ctx context.Context, characterIDs []string) ([]characters.Character, error) {
+	args := s.Called(ctx, characterIDs)
+	return args.Get(0).([]characters.Character), args.Error(1)
+}
@@ -0,0 +1,50 @@
+package apikeys
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+const (
+	TierFree     = "free"
+	TierStandard = "standard"
+	TierPartner  = "partner"
+)
+
+const keySeparator = "."
+
+type APIKey struct {
+	ID         string
+	Owner      string
+	Scopes     []string
+	Tier       string
+	SecretHash string
+	Revoked    bool
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+func (k APIKey) matches(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(k.SecretHash), []byte(hashSecret(secret))) == 1
+}
+
+func formatKey(id string, secret string) string {
+	return id + keySeparator + secret
+}
+
+func parseKey(key string) (string, string, bool) {
+	id, secret, ok := strings.Cut(key, keySeparator)
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+
+	return id, secret, true
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
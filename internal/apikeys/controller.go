@@ -0,0 +1,114 @@
+package apikeys
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Manager interface {
+	Create(ctx context.Context, key APIKey) (APIKey, string, error)
+	GetAll(ctx context.Context) ([]APIKey, error)
+	Revoke(ctx context.Context, keyID string) error
+}
+
+type Controller struct {
+	manager Manager
+}
+
+func NewController(manager Manager) *Controller {
+	return &Controller{manager: manager}
+}
+
+func (c *Controller) Create(ctx *gin.Context) {
+	var keyRequest KeyRequest
+	if err := ctx.BindJSON(&keyRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	createdKey, secret, err := c.manager.Create(ctx, keyRequest.ToAPIKey())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	keyDTO := NewKeyDTO(createdKey)
+	keyDTO.Key = secret
+
+	ctx.JSON(http.StatusCreated, keyDTO)
+}
+
+func (c *Controller) GetAll(ctx *gin.Context) {
+	keys, err := c.manager.GetAll(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	keysDTO := []KeyDTO{}
+	for _, key := range keys {
+		keysDTO = append(keysDTO, NewKeyDTO(key))
+	}
+
+	ctx.JSON(http.StatusOK, keysDTO)
+}
+
+func (c *Controller) Delete(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err := c.manager.Revoke(ctx, idRequest.KeyID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+type KeyRequest struct {
+	Owner  string   `json:"owner" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,required"`
+	Tier   string   `json:"tier" binding:"required,oneof=free standard partner"`
+}
+
+func (r *KeyRequest) ToAPIKey() APIKey {
+	return APIKey{Owner: r.Owner, Scopes: r.Scopes, Tier: r.Tier}
+}
+
+type KeyDTO struct {
+	ID         string     `json:"id"`
+	Key        string     `json:"key,omitempty"`
+	Owner      string     `json:"owner"`
+	Scopes     []string   `json:"scopes"`
+	Tier       string     `json:"tier"`
+	Revoked    bool       `json:"revoked"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+func NewKeyDTO(key APIKey) KeyDTO {
+	keyDTO := KeyDTO{
+		ID:        key.ID,
+		Owner:     key.Owner,
+		Scopes:    key.Scopes,
+		Tier:      key.Tier,
+		Revoked:   key.Revoked,
+		CreatedAt: key.CreatedAt,
+	}
+	if !key.LastUsedAt.IsZero() {
+		keyDTO.LastUsedAt = &key.LastUsedAt
+	}
+
+	return keyDTO
+}
+
+type IDRequest struct {
+	KeyID string `uri:"key" binding:"required,uuid"`
+}
@@ -0,0 +1,226 @@
+package apikeys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestController_Create(t *testing.T) {
+	requestKey := APIKey{Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner}
+	tests := []struct {
+		name     string
+		reqBody  string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when request body is an invalid json",
+			reqBody: `}`,
+			setup:   func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var syntaxErr *json.SyntaxError
+				assert.True(t, errors.As(err, &syntaxErr))
+			},
+		},
+		{
+			name:    "when request body has an unknown tier",
+			reqBody: `{"owner":"partner-team","scopes":["books:write"],"tier":"gold"}`,
+			setup:   func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when request body misses the scopes",
+			reqBody: `{"owner":"partner-team","scopes":[],"tier":"partner"}`,
+			setup:   func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when create api key service fails",
+			reqBody: `{"owner":"partner-team","scopes":["books:write"],"tier":"partner"}`,
+			setup: func(m *ManagerMock) {
+				m.On("Create", mock.Anything, requestKey).Return(APIKey{}, "", assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when create api key is successful",
+			reqBody: `{"owner":"partner-team","scopes":["books:write"],"tier":"partner"}`,
+			setup: func(m *ManagerMock) {
+				createdKey := APIKey{ID: "key-id", Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, SecretHash: "hash", CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}
+				m.On("Create", mock.Anything, requestKey).Return(createdKey, "key-id.secret", nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, `{"id":"key-id","key":"key-id.secret","owner":"partner-team","scopes":["books:write"],"tier":"partner","revoked":false,"createdAt":"2026-10-01T12:00:00Z"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/admin/keys", strings.NewReader(tt.reqBody))
+
+			tt.setup(m)
+
+			c.Create(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name: "when get api keys service fails",
+			setup: func(m *ManagerMock) {
+				m.On("GetAll", mock.Anything).Return([]APIKey{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name: "when get api keys is successful",
+			setup: func(m *ManagerMock) {
+				keys := []APIKey{{ID: "key-id", Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierFree, SecretHash: "hash", Revoked: true, CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), LastUsedAt: time.Date(2026, 10, 2, 8, 30, 0, 0, time.UTC)}}
+				m.On("GetAll", mock.Anything).Return(keys, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[{"id":"key-id","owner":"partner-team","scopes":["books:write"],"tier":"free","revoked":true,"createdAt":"2026-10-01T12:00:00Z","lastUsedAt":"2026-10-02T08:30:00Z"}]`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/admin/keys", nil)
+
+			tt.setup(m)
+
+			c.GetAll(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Delete(t *testing.T) {
+	keyID := "0b6a1c8e-2f3d-4a5b-9c7d-1e2f3a4b5c6d"
+	tests := []struct {
+		name     string
+		keyID    string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when key id is not a uuid",
+			keyID: "key-id",
+			setup: func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when revoke api key service fails",
+			keyID: keyID,
+			setup: func(m *ManagerMock) {
+				m.On("Revoke", mock.Anything, keyID).Return(assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:  "when revoke api key is successful",
+			keyID: keyID,
+			setup: func(m *ManagerMock) {
+				m.On("Revoke", mock.Anything, keyID).Return(nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusNoContent, r.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/admin/keys/"+tt.keyID, nil)
+			ctx.Params = gin.Params{{Key: "key", Value: tt.keyID}}
+
+			tt.setup(m)
+
+			c.Delete(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+type ManagerMock struct {
+	mock.Mock
+}
+
+func (m *ManagerMock) Create(ctx context.Context, key APIKey) (APIKey, string, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(APIKey), args.String(1), args.Error(2)
+}
+
+func (m *ManagerMock) GetAll(ctx context.Context) ([]APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]APIKey), args.Error(1)
+}
+
+func (m *ManagerMock) Revoke(ctx context.Context, keyID string) error {
+	args := m.Called(ctx, keyID)
+	return args.Error(0)
+}
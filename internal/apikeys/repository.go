@@ -0,0 +1,138 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+)
+
+type DynamoDBClient interface {
+	GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error)
+	GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error)
+	WriteItems(ctx context.Context, tableName string, puts []map[string]types.AttributeValue, deleteIDs []string, writes ...dynamo.Write) error
+	SetAttributes(ctx context.Context, tableName string, id string, attributes map[string]types.AttributeValue, conditions map[string]types.AttributeValue) error
+}
+
+type Repository struct {
+	dynamoDBClient DynamoDBClient
+	tableName      string
+}
+
+func NewRepository(dynamoDBClient DynamoDBClient, tableName string) *Repository {
+	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName}
+}
+
+func (r *Repository) Save(ctx context.Context, key APIKey) error {
+	item, err := attributevalue.MarshalMap(newDBAPIKey(key))
+	if err != nil {
+		return fmt.Errorf("failed to marshal api key: %w", err)
+	}
+
+	return r.dynamoDBClient.WriteItems(ctx, r.tableName, []map[string]types.AttributeValue{item}, nil)
+}
+
+func (r *Repository) SetLastUsed(ctx context.Context, keyID string, lastUsedAt time.Time) error {
+	value, err := attributevalue.Marshal(lastUsedAt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal api key last used at: %w", err)
+	}
+
+	return r.dynamoDBClient.SetAttributes(ctx, r.tableName, keyID,
+		map[string]types.AttributeValue{"last_used_at": value},
+		map[string]types.AttributeValue{"revoked": &types.AttributeValueMemberBOOL{Value: false}},
+	)
+}
+
+func (r *Repository) SetRevoked(ctx context.Context, keyID string) error {
+	err := r.dynamoDBClient.SetAttributes(ctx, r.tableName, keyID, map[string]types.AttributeValue{"revoked": &types.AttributeValueMemberBOOL{Value: true}}, nil)
+	if errors.Is(err, dynamo.ErrConditionFailed) {
+		return fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, keyID)
+	}
+
+	return err
+}
+
+func (r *Repository) GetById(ctx context.Context, keyID string) (APIKey, error) {
+	item, err := r.dynamoDBClient.GetByID(ctx, r.tableName, keyID)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	var dbKey DBAPIKey
+	err = attributevalue.UnmarshalMap(item, &dbKey)
+	if err != nil {
+		return APIKey{}, fmt.Errorf("failed to unmarshal api key: %w", err)
+	}
+
+	return dbKey.toAPIKey(), nil
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]APIKey, error) {
+	items, err := r.dynamoDBClient.GetAll(ctx, r.tableName)
+	if err != nil {
+		return []APIKey{}, err
+	}
+
+	var keys []APIKey
+	for _, item := range items {
+		var dbKey DBAPIKey
+		err = attributevalue.UnmarshalMap(item, &dbKey)
+		if err != nil {
+			return []APIKey{}, fmt.Errorf("failed to unmarshal api key: %w", err)
+		}
+
+		keys = append(keys, dbKey.toAPIKey())
+	}
+
+	return keys, nil
+}
+
+type DBAPIKey struct {
+	ID         string     `dynamodbav:"id"`
+	Owner      string     `dynamodbav:"owner"`
+	Scopes     []string   `dynamodbav:"scopes"`
+	Tier       string     `dynamodbav:"tier"`
+	SecretHash string     `dynamodbav:"secret_hash"`
+	Revoked    bool       `dynamodbav:"revoked"`
+	CreatedAt  time.Time  `dynamodbav:"created_at"`
+	LastUsedAt *time.Time `dynamodbav:"last_used_at,omitempty"`
+}
+
+func newDBAPIKey(key APIKey) DBAPIKey {
+	dbKey := DBAPIKey{
+		ID:         key.ID,
+		Owner:      key.Owner,
+		Scopes:     key.Scopes,
+		Tier:       key.Tier,
+		SecretHash: key.SecretHash,
+		Revoked:    key.Revoked,
+		CreatedAt:  key.CreatedAt,
+	}
+	if !key.LastUsedAt.IsZero() {
+		dbKey.LastUsedAt = &key.LastUsedAt
+	}
+
+	return dbKey
+}
+
+func (d *DBAPIKey) toAPIKey() APIKey {
+	key := APIKey{
+		ID:         d.ID,
+		Owner:      d.Owner,
+		Scopes:     d.Scopes,
+		Tier:       d.Tier,
+		SecretHash: d.SecretHash,
+		Revoked:    d.Revoked,
+		CreatedAt:  d.CreatedAt,
+	}
+	if d.LastUsedAt != nil {
+		key.LastUsedAt = *d.LastUsedAt
+	}
+
+	return key
+}
@@ -0,0 +1,264 @@
+package apikeys
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var createdAt = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+var lastUsedAt = time.Date(2026, 10, 2, 8, 30, 0, 0, time.UTC)
+
+func keyItem(revoked bool, lastUsed *time.Time) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		"id":          &types.AttributeValueMemberS{Value: "key-id"},
+		"owner":       &types.AttributeValueMemberS{Value: "partner-team"},
+		"scopes":      &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "books:write"}}},
+		"tier":        &types.AttributeValueMemberS{Value: TierPartner},
+		"secret_hash": &types.AttributeValueMemberS{Value: "hash"},
+		"revoked":     &types.AttributeValueMemberBOOL{Value: revoked},
+		"created_at":  &types.AttributeValueMemberS{Value: "2026-10-01T12:00:00Z"},
+	}
+	if lastUsed != nil {
+		item["last_used_at"] = &types.AttributeValueMemberS{Value: lastUsed.Format(time.RFC3339)}
+	}
+
+	return item
+}
+
+func TestRepository_Save(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		key     APIKey
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to write api key",
+			key:  APIKey{ID: "key-id", Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, SecretHash: "hash", CreatedAt: createdAt},
+			setup: func(m *MockDynamoDBClient) {
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue{keyItem(false, nil)}, []string(nil)).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully saved a new api key",
+			key:  APIKey{ID: "key-id", Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, SecretHash: "hash", CreatedAt: createdAt},
+			setup: func(m *MockDynamoDBClient) {
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue{keyItem(false, nil)}, []string(nil)).Return(nil).Once()
+			},
+		},
+		{
+			name: "when successfully saved a used and revoked api key",
+			key:  APIKey{ID: "key-id", Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, SecretHash: "hash", Revoked: true, CreatedAt: createdAt, LastUsedAt: lastUsedAt},
+			setup: func(m *MockDynamoDBClient) {
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue{keyItem(true, &lastUsedAt)}, []string(nil)).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name")
+
+			err := r.Save(ctx, tt.key)
+
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_SetLastUsed(t *testing.T) {
+	ctx := context.Background()
+	attributes := map[string]types.AttributeValue{"last_used_at": &types.AttributeValueMemberS{Value: "2026-10-02T08:30:00Z"}}
+	conditions := map[string]types.AttributeValue{"revoked": &types.AttributeValueMemberBOOL{Value: false}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when api key was revoked",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("SetAttributes", ctx, "table-name", "key-id", attributes, conditions).Return(dynamo.ErrConditionFailed).Once()
+			},
+			wantErr: dynamo.ErrConditionFailed,
+		},
+		{
+			name: "when successfully set last used at",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("SetAttributes", ctx, "table-name", "key-id", attributes, conditions).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name")
+
+			err := r.SetLastUsed(ctx, "key-id", lastUsedAt)
+
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_SetRevoked(t *testing.T) {
+	ctx := context.Background()
+	attributes := map[string]types.AttributeValue{"revoked": &types.AttributeValueMemberBOOL{Value: true}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when api key does not exist",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("SetAttributes", ctx, "table-name", "key-id", attributes, map[string]types.AttributeValue(nil)).Return(dynamo.ErrConditionFailed).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, "key-id"),
+		},
+		{
+			name: "when failed to revoke api key",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("SetAttributes", ctx, "table-name", "key-id", attributes, map[string]types.AttributeValue(nil)).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully revoked api key",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("SetAttributes", ctx, "table-name", "key-id", attributes, map[string]types.AttributeValue(nil)).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name")
+
+			err := r.SetRevoked(ctx, "key-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetById(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    APIKey
+		wantErr error
+	}{
+		{
+			name: "when failed to get api key",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "key-id").Return(map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully got api key",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "key-id").Return(keyItem(true, &lastUsedAt), nil).Once()
+			},
+			want: APIKey{ID: "key-id", Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, SecretHash: "hash", Revoked: true, CreatedAt: createdAt, LastUsedAt: lastUsedAt},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name")
+
+			got, err := r.GetById(ctx, "key-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetAll(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    []APIKey
+		wantErr error
+	}{
+		{
+			name: "when failed to get api keys",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			want:    []APIKey{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully got api keys",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{keyItem(false, nil)}, nil).Once()
+			},
+			want: []APIKey{{ID: "key-id", Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, SecretHash: "hash", CreatedAt: createdAt}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name")
+
+			got, err := r.GetAll(ctx)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+type MockDynamoDBClient struct {
+	mock.Mock
+}
+
+func (m *MockDynamoDBClient) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, id)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) WriteItems(ctx context.Context, tableName string, puts []map[string]types.AttributeValue, deleteIDs []string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, puts, deleteIDs)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) SetAttributes(ctx context.Context, tableName string, id string, attributes map[string]types.AttributeValue, conditions map[string]types.AttributeValue) error {
+	args := m.Called(ctx, tableName, id, attributes, conditions)
+	return args.Error(0)
+}
@@ -0,0 +1,96 @@
+package apikeys
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ggoulart/michael-connelly-api/internal/auth"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/google/uuid"
+)
+
+const secretSize = 32
+const lastUsedResolution = time.Minute
+
+type StorageKey interface {
+	Save(ctx context.Context, key APIKey) error
+	GetById(ctx context.Context, keyID string) (APIKey, error)
+	SetLastUsed(ctx context.Context, keyID string, lastUsedAt time.Time) error
+	SetRevoked(ctx context.Context, keyID string) error
+	GetAll(ctx context.Context) ([]APIKey, error)
+}
+
+type Service struct {
+	storageKey StorageKey
+	uuidGen    func() uuid.UUID
+	random     io.Reader
+	now        func() time.Time
+}
+
+func NewService(storageKey StorageKey, uuidGen func() uuid.UUID, random io.Reader, now func() time.Time) *Service {
+	return &Service{storageKey: storageKey, uuidGen: uuidGen, random: random, now: now}
+}
+
+func (s *Service) Create(ctx context.Context, key APIKey) (APIKey, string, error) {
+	secretBytes := make([]byte, secretSize)
+	_, err := io.ReadFull(s.random, secretBytes)
+	if err != nil {
+		return APIKey{}, "", fmt.Errorf("failed to generate api key secret: %w", err)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	key.ID = s.uuidGen().String()
+	key.SecretHash = hashSecret(secret)
+	key.Revoked = false
+	key.CreatedAt = s.now().UTC()
+	key.LastUsedAt = time.Time{}
+
+	err = s.storageKey.Save(ctx, key)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	return key, formatKey(key.ID, secret), nil
+}
+
+func (s *Service) GetAll(ctx context.Context) ([]APIKey, error) {
+	return s.storageKey.GetAll(ctx)
+}
+
+func (s *Service) Revoke(ctx context.Context, keyID string) error {
+	return s.storageKey.SetRevoked(ctx, keyID)
+}
+
+func (s *Service) Authenticate(ctx context.Context, token string) (auth.Principal, error) {
+	keyID, secret, ok := parseKey(token)
+	if !ok {
+		return auth.Principal{}, fmt.Errorf("%w: malformed api key", auth.ErrUnauthenticated)
+	}
+
+	key, err := s.storageKey.GetById(ctx, keyID)
+	if errors.Is(err, dynamo.ErrNotFound) {
+		return auth.Principal{}, fmt.Errorf("%w: unknown api key. id: %s", auth.ErrUnauthenticated, keyID)
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	if !key.matches(secret) {
+		return auth.Principal{}, fmt.Errorf("%w: wrong api key secret. id: %s", auth.ErrUnauthenticated, keyID)
+	}
+
+	if key.Revoked {
+		return auth.Principal{}, fmt.Errorf("%w: revoked api key. id: %s", auth.ErrUnauthenticated, keyID)
+	}
+
+	now := s.now().UTC()
+	if now.Sub(key.LastUsedAt) >= lastUsedResolution {
+		_ = s.storageKey.SetLastUsed(ctx, key.ID, now)
+	}
+
+	return auth.Principal{Subject: key.Owner, Scopes: key.Scopes, KeyID: key.ID, Tier: key.Tier}, nil
+}
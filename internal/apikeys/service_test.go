@@ -0,0 +1,229 @@
+package apikeys
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ggoulart/michael-connelly-api/internal/auth"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var keyUUID = uuid.MustParse("0b6a1c8e-2f3d-4a5b-9c7d-1e2f3a4b5c6d")
+var now = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+const secret = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+
+func newTestService(storageKey StorageKey) *Service {
+	return NewService(storageKey, func() uuid.UUID { return keyUUID }, bytes.NewReader(make([]byte, secretSize)), func() time.Time { return now })
+}
+
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+	request := APIKey{Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, Revoked: true}
+	want := APIKey{ID: keyUUID.String(), Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, SecretHash: hashSecret(secret), CreatedAt: now}
+	tests := []struct {
+		name       string
+		setup      func(*StorageKeyMock)
+		want       APIKey
+		wantSecret string
+		wantErr    error
+	}{
+		{
+			name: "when failed to save api key",
+			setup: func(m *StorageKeyMock) {
+				m.On("Save", ctx, want).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully created api key",
+			setup: func(m *StorageKeyMock) {
+				m.On("Save", ctx, want).Return(nil).Once()
+			},
+			want:       want,
+			wantSecret: keyUUID.String() + "." + secret,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(StorageKeyMock)
+			tt.setup(m)
+
+			s := newTestService(m)
+
+			got, gotSecret, err := s.Create(ctx, request)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSecret, gotSecret)
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageKeyMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to revoke api key",
+			setup: func(m *StorageKeyMock) {
+				m.On("SetRevoked", ctx, "key-id").Return(dynamo.ErrNotFound).Once()
+			},
+			wantErr: dynamo.ErrNotFound,
+		},
+		{
+			name: "when successfully revoked api key",
+			setup: func(m *StorageKeyMock) {
+				m.On("SetRevoked", ctx, "key-id").Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(StorageKeyMock)
+			tt.setup(m)
+
+			s := newTestService(m)
+
+			err := s.Revoke(ctx, "key-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	key := APIKey{ID: "key-id", Owner: "partner-team", Scopes: []string{"books:write"}, Tier: TierPartner, SecretHash: hashSecret(secret), CreatedAt: now.Add(-time.Hour)}
+	principal := auth.Principal{Subject: "partner-team", Scopes: []string{"books:write"}, KeyID: "key-id", Tier: TierPartner}
+	tests := []struct {
+		name    string
+		token   string
+		setup   func(*StorageKeyMock)
+		want    auth.Principal
+		wantErr error
+	}{
+		{
+			name:    "when api key is malformed",
+			token:   "key-id",
+			setup:   func(*StorageKeyMock) {},
+			wantErr: fmt.Errorf("%w: malformed api key", auth.ErrUnauthenticated),
+		},
+		{
+			name:  "when api key does not exist",
+			token: "key-id." + secret,
+			setup: func(m *StorageKeyMock) {
+				m.On("GetById", ctx, "key-id").Return(APIKey{}, fmt.Errorf("%w. id: key-id", dynamo.ErrNotFound)).Once()
+			},
+			wantErr: fmt.Errorf("%w: unknown api key. id: key-id", auth.ErrUnauthenticated),
+		},
+		{
+			name:  "when failed to get api key",
+			token: "key-id." + secret,
+			setup: func(m *StorageKeyMock) {
+				m.On("GetById", ctx, "key-id").Return(APIKey{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when secret does not match",
+			token: "key-id.wrong",
+			setup: func(m *StorageKeyMock) {
+				m.On("GetById", ctx, "key-id").Return(key, nil).Once()
+			},
+			wantErr: fmt.Errorf("%w: wrong api key secret. id: key-id", auth.ErrUnauthenticated),
+		},
+		{
+			name:  "when api key is revoked",
+			token: "key-id." + secret,
+			setup: func(m *StorageKeyMock) {
+				revokedKey := key
+				revokedKey.Revoked = true
+				m.On("GetById", ctx, "key-id").Return(revokedKey, nil).Once()
+			},
+			wantErr: fmt.Errorf("%w: revoked api key. id: key-id", auth.ErrUnauthenticated),
+		},
+		{
+			name:  "when failed to record last use it still authenticates",
+			token: "key-id." + secret,
+			setup: func(m *StorageKeyMock) {
+				m.On("GetById", ctx, "key-id").Return(key, nil).Once()
+				m.On("SetLastUsed", ctx, "key-id", now).Return(fmt.Errorf("%w. id: key-id", dynamo.ErrConditionFailed)).Once()
+			},
+			want: principal,
+		},
+		{
+			name:  "when successfully authenticated and recorded last use",
+			token: "key-id." + secret,
+			setup: func(m *StorageKeyMock) {
+				m.On("GetById", ctx, "key-id").Return(key, nil).Once()
+				m.On("SetLastUsed", ctx, "key-id", now).Return(nil).Once()
+			},
+			want: principal,
+		},
+		{
+			name:  "when successfully authenticated a recently used key",
+			token: "key-id." + secret,
+			setup: func(m *StorageKeyMock) {
+				recentKey := key
+				recentKey.LastUsedAt = now.Add(-time.Second)
+				m.On("GetById", ctx, "key-id").Return(recentKey, nil).Once()
+			},
+			want: principal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(StorageKeyMock)
+			tt.setup(m)
+
+			s := newTestService(m)
+
+			got, err := s.Authenticate(ctx, tt.token)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+type StorageKeyMock struct {
+	mock.Mock
+}
+
+func (m *StorageKeyMock) Save(ctx context.Context, key APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *StorageKeyMock) GetById(ctx context.Context, keyID string) (APIKey, error) {
+	args := m.Called(ctx, keyID)
+	return args.Get(0).(APIKey), args.Error(1)
+}
+
+func (m *StorageKeyMock) SetLastUsed(ctx context.Context, keyID string, lastUsedAt time.Time) error {
+	args := m.Called(ctx, keyID, lastUsedAt)
+	return args.Error(0)
+}
+
+func (m *StorageKeyMock) SetRevoked(ctx context.Context, keyID string) error {
+	args := m.Called(ctx, keyID)
+	return args.Error(0)
+}
+
+func (m *StorageKeyMock) GetAll(ctx context.Context) ([]APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]APIKey), args.Error(1)
+}
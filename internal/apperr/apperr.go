@@ -0,0 +1,35 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrReferenced = errors.New("referenced")
+
+type ReferencedError struct {
+	Resource string
+	ID       string
+}
+
+func (e *ReferencedError) Error() string {
+	return fmt.Sprintf("%s is %s. id: %s", e.Resource, ErrReferenced, e.ID)
+}
+
+func (e *ReferencedError) Unwrap() error {
+	return ErrReferenced
+}
+
+var ErrInvalid = errors.New("invalid")
+
+type InvalidError struct {
+	Reason string
+}
+
+func (e *InvalidError) Error() string {
+	return e.Reason
+}
+
+func (e *InvalidError) Unwrap() error {
+	return ErrInvalid
+}
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+type Principal struct {
+	Subject string
+	Scopes  []string
+	KeyID   string
+	Tier    string
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (Principal, error)
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var ErrInvalidKeySet = errors.New("invalid key set")
+
+const (
+	keyTypeRSA = "RSA"
+	keyTypeOct = "oct"
+)
+
+type KeySet map[string]any
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+func LoadKeySet(ctx context.Context, source string, client *http.Client) (KeySet, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwks file: %w", err)
+		}
+
+		return ParseKeySet(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build jwks request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks: %w", err)
+	}
+
+	return ParseKeySet(data)
+}
+
+func ParseKeySet(data []byte) (KeySet, error) {
+	var set jwks
+	err := json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeySet, err)
+	}
+
+	keys := KeySet{}
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		switch key.Kty {
+		case keyTypeRSA:
+			publicKey, err := key.rsaPublicKey()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w. kid: %s", ErrInvalidKeySet, err, key.Kid)
+			}
+			keys[key.Kid] = publicKey
+		case keyTypeOct:
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("%w: invalid k. kid: %s", ErrInvalidKeySet, key.Kid)
+			}
+			keys[key.Kid] = secret
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no signing keys", ErrInvalidKeySet)
+	}
+
+	return keys, nil
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil || len(n) == 0 {
+		return nil, errors.New("invalid n")
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid e")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWKS = `{"keys":[
+	{"kty":"RSA","kid":"rsa-key","use":"sig","n":"AQAB","e":"AQAB"},
+	{"kty":"oct","kid":"oct-key","k":"c2VjcmV0"},
+	{"kty":"RSA","kid":"enc-key","use":"enc","n":"AQAB","e":"AQAB"},
+	{"kty":"EC","kid":"ec-key","crv":"P-256","x":"AQAB","y":"AQAB"}
+]}`
+
+func TestParseKeySet(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    KeySet
+		wantErr bool
+	}{
+		{
+			name: "when key set has rsa and oct signing keys",
+			data: testJWKS,
+			want: KeySet{
+				"rsa-key": &rsa.PublicKey{N: big.NewInt(65537), E: 65537},
+				"oct-key": []byte("secret"),
+			},
+		},
+		{
+			name:    "when key set is not json",
+			data:    "keys",
+			wantErr: true,
+		},
+		{
+			name:    "when key set has no signing keys",
+			data:    `{"keys":[{"kty":"EC","kid":"ec-key"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "when rsa key has no modulus",
+			data:    `{"keys":[{"kty":"RSA","kid":"rsa-key","e":"AQAB"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "when oct key is not base64url",
+			data:    `{"keys":[{"kty":"oct","kid":"oct-key","k":"***"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeySet([]byte(tt.data))
+
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidKeySet)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	ctx := context.Background()
+	want := KeySet{
+		"rsa-key": &rsa.PublicKey{N: big.NewInt(65537), E: 65537},
+		"oct-key": []byte("secret"),
+	}
+
+	t.Run("when source is a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(path, []byte(testJWKS), 0o600))
+
+		got, err := LoadKeySet(ctx, path, http.DefaultClient)
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("when file does not exist", func(t *testing.T) {
+		_, err := LoadKeySet(ctx, filepath.Join(t.TempDir(), "missing.json"), http.DefaultClient)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("when source is a url", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(testJWKS))
+		}))
+		defer server.Close()
+
+		got, err := LoadKeySet(ctx, server.URL, server.Client())
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("when url responds with an error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		_, err := LoadKeySet(ctx, server.URL, server.Client())
+
+		assert.EqualError(t, err, "failed to fetch jwks: unexpected status 500")
+	})
+}
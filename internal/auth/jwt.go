@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrNoKeys = errors.New("no verification keys configured")
+
+type JWT struct {
+	keys   KeySet
+	secret []byte
+	parser *jwt.Parser
+}
+
+func NewJWT(keys KeySet, secret []byte, issuer string, audience string) (*JWT, error) {
+	if len(keys) == 0 && len(secret) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+	)
+
+	return &JWT{keys: keys, secret: secret, parser: parser}, nil
+}
+
+func (j *JWT) Authenticate(ctx context.Context, token string) (Principal, error) {
+	var tokenClaims claims
+	_, err := j.parser.ParseWithClaims(token, &tokenClaims, j.key)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+
+	if tokenClaims.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: missing sub", ErrUnauthenticated)
+	}
+
+	return Principal{Subject: tokenClaims.Subject, Scopes: tokenClaims.scopes()}, nil
+}
+
+func (j *JWT) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if secret, ok := j.keys[kid].([]byte); ok {
+			return secret, nil
+		}
+		if kid == "" && len(j.secret) > 0 {
+			return j.secret, nil
+		}
+	case jwt.SigningMethodRS256.Alg():
+		if publicKey, ok := j.keys[kid].(*rsa.PublicKey); ok {
+			return publicKey, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown key. alg: %s, kid: %q", token.Method.Alg(), kid)
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+}
+
+func (c claims) scopes() []string {
+	scopes := strings.Fields(c.Scope)
+	for _, scope := range c.Scp {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJWT(t *testing.T) {
+	_, err := NewJWT(nil, nil, "issuer", "audience")
+
+	assert.ErrorIs(t, err, ErrNoKeys)
+}
+
+func TestJWT_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := KeySet{"rsa-key": &privateKey.PublicKey, "oct-key": []byte("jwks-secret")}
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   "partner",
+			"iss":   "issuer",
+			"aud":   "audience",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": "books:write series:write",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		token   func(*testing.T) string
+		want    Principal
+		wantErr bool
+	}{
+		{
+			name: "when token is signed with the configured secret",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, "", []byte("secret"), validClaims())
+			},
+			want: Principal{Subject: "partner", Scopes: []string{"books:write", "series:write"}},
+		},
+		{
+			name: "when token is signed with a jwks oct key",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, "oct-key", []byte("jwks-secret"), validClaims())
+			},
+			want: Principal{Subject: "partner", Scopes: []string{"books:write", "series:write"}},
+		},
+		{
+			name: "when token is signed with a jwks rsa key and uses scp",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "scope")
+				claims["scp"] = []string{"characters:write"}
+				return sign(t, jwt.SigningMethodRS256, "rsa-key", privateKey, claims)
+			},
+			want: Principal{Subject: "partner", Scopes: []string{"characters:write"}},
+		},
+		{
+			name: "when token is signed with an unknown rsa key",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodRS256, "rsa-key", otherKey, validClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name: "when token kid is unknown",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodRS256, "missing-key", privateKey, validClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name: "when token is signed with a wrong secret",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, "", []byte("wrong"), validClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name: "when token uses an unsupported algorithm",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS512, "", []byte("secret"), validClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name: "when token is expired",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return sign(t, jwt.SigningMethodHS256, "", []byte("secret"), claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "when token has no exp",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return sign(t, jwt.SigningMethodHS256, "", []byte("secret"), claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "when token audience does not match",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				return sign(t, jwt.SigningMethodHS256, "", []byte("secret"), claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "when token issuer does not match",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["iss"] = "other"
+				return sign(t, jwt.SigningMethodHS256, "", []byte("secret"), claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "when token has no subject",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "sub")
+				return sign(t, jwt.SigningMethodHS256, "", []byte("secret"), claims)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "when token is malformed",
+			token:   func(t *testing.T) string { return "not-a-token" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := NewJWT(keys, []byte("secret"), "issuer", "audience")
+			require.NoError(t, err)
+
+			got, err := j.Authenticate(ctx, tt.token(t))
+
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnauthenticated)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	principal := Principal{Subject: "partner", Scopes: []string{"books:write"}}
+
+	assert.True(t, principal.HasScope("books:write"))
+	assert.False(t, principal.HasScope("series:write"))
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
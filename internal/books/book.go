@@ -2,13 +2,17 @@ package books
 
 type Book struct {
 	ID          string
+	Slug        string
 	Title       string
 	Year        int
 	Blurb       string
 	Adaptations []Adaptation
+	Version     int
 }
 
 type Adaptation struct {
+	ID          string
 	Description string
+	Type        string
 	IMDB        string
 }
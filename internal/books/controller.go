@@ -5,15 +5,24 @@ import (
 	"net/http"
 	"sort"
 
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type Manager interface {
-	Create(ctx context.Context, book Book) (Book, error)
+	Create(ctx context.Context, book Book) (Book, bool, error)
+	Update(ctx context.Context, book Book) (Book, error)
+	Delete(ctx context.Context, bookID string, cascade bool) error
 	GetById(ctx context.Context, bookID string) (Book, error)
+	GetBySlug(ctx context.Context, slug string) (Book, error)
 	GetAll(ctx context.Context) ([]Book, error)
+	GetPage(ctx context.Context, limit int32, cursor string) ([]Book, string, error)
+	GetByYearRange(ctx context.Context, yearFrom int, yearTo int) ([]Book, error)
 }
 
+const defaultPageLimit = 20
+
 type Controller struct {
 	manager Manager
 }
@@ -29,32 +38,158 @@ func (c *Controller) Create(ctx *gin.Context) {
 		return
 	}
 
-	createdBook, err := c.manager.Create(ctx, bookDTO.ToBook())
+	createdBook, created, err := c.manager.Create(ctx, bookDTO.ToBook())
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, NewBookDTO(createdBook))
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	ctx.Header("ETag", etag.Format(createdBook.Version))
+	ctx.JSON(status, NewBookDTO(createdBook))
 }
 
-func (c *Controller) GetById(ctx *gin.Context) {
+func (c *Controller) Update(ctx *gin.Context) {
 	var getByIDRequest GetByIDRequest
 	if err := ctx.BindUri(&getByIDRequest); err != nil {
 		ctx.Error(err)
 		return
 	}
 
+	version, err := etag.Parse(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var bookDTO BookDTO
+	if err := ctx.BindJSON(&bookDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	book := bookDTO.ToBook()
+	book.ID = getByIDRequest.BookID
+	book.Version = version
+
+	updatedBook, err := c.manager.Update(ctx, book)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(updatedBook.Version))
+	ctx.JSON(http.StatusOK, NewBookDTO(updatedBook))
+}
+
+func (c *Controller) Patch(ctx *gin.Context) {
+	var getByIDRequest GetByIDRequest
+	if err := ctx.BindUri(&getByIDRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	version, err := etag.Parse(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var patchBookDTO PatchBookDTO
+	if err := ctx.BindJSON(&patchBookDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
 	book, err := c.manager.GetById(ctx, getByIDRequest.BookID)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
+	book = patchBookDTO.ApplyTo(book)
+	book.Version = version
+
+	updatedBook, err := c.manager.Update(ctx, book)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(updatedBook.Version))
+	ctx.JSON(http.StatusOK, NewBookDTO(updatedBook))
+}
+
+func (c *Controller) Delete(ctx *gin.Context) {
+	var getByIDRequest GetByIDRequest
+	if err := ctx.BindUri(&getByIDRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var deleteRequest DeleteRequest
+	if err := ctx.BindQuery(&deleteRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err := c.manager.Delete(ctx, getByIDRequest.BookID, deleteRequest.Cascade)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) GetBy(ctx *gin.Context) {
+	var getByIDRequest GetByIDRequest
+	if err := ctx.BindUri(&getByIDRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var book Book
+	bookID, err := uuid.Parse(getByIDRequest.BookID)
+	if err != nil {
+		book, err = c.manager.GetBySlug(ctx, getByIDRequest.BookID)
+	} else {
+		book, err = c.manager.GetById(ctx, bookID.String())
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(book.Version))
 	ctx.JSON(http.StatusOK, NewBookDTO(book))
 }
 
 func (c *Controller) GetAll(ctx *gin.Context) {
+	var getAllRequest GetAllRequest
+	if err := ctx.BindQuery(&getAllRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if getAllRequest.YearFrom > 0 || getAllRequest.YearTo > 0 {
+		c.getByYearRange(ctx, getAllRequest)
+		return
+	}
+
+	if getAllRequest.Limit == 0 && getAllRequest.Cursor == "" {
+		c.getAll(ctx)
+		return
+	}
+
+	c.getPage(ctx, getAllRequest)
+}
+
+func (c *Controller) getAll(ctx *gin.Context) {
 	books, err := c.manager.GetAll(ctx)
 	if err != nil {
 		ctx.Error(err)
@@ -73,30 +208,76 @@ func (c *Controller) GetAll(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, booksDTO)
 }
 
+func (c *Controller) getPage(ctx *gin.Context, getAllRequest GetAllRequest) {
+	limit := getAllRequest.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	books, nextCursor, err := c.manager.GetPage(ctx, limit, getAllRequest.Cursor)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	booksDTO := []BookDTO{}
+	for _, book := range books {
+		booksDTO = append(booksDTO, NewBookDTO(book))
+	}
+
+	ctx.JSON(http.StatusOK, BooksPageDTO{Items: booksDTO, NextCursor: nextCursor})
+}
+
+func (c *Controller) getByYearRange(ctx *gin.Context, getAllRequest GetAllRequest) {
+	books, err := c.manager.GetByYearRange(ctx, getAllRequest.YearFrom, getAllRequest.YearTo)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	booksDTO := []BookDTO{}
+	for _, book := range books {
+		booksDTO = append(booksDTO, NewBookDTO(book))
+	}
+
+	ctx.JSON(http.StatusOK, booksDTO)
+}
+
 type BookDTO struct {
 	ID          string          `json:"id,omitempty"`
+	Slug        string          `json:"slug,omitempty"`
 	Title       string          `json:"title" binding:"required"`
 	Year        int             `json:"year" binding:"required,gte=1956"`
 	Blurb       string          `json:"blurb"`
 	Adaptations []AdaptationDTO `json:"adaptations,omitempty"`
 }
 
+type BooksPageDTO struct {
+	Items      []BookDTO `json:"items"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+}
+
 type AdaptationDTO struct {
-	Description string `json:"description" binding:"required"`
-	IMDB        string `json:"imdb" binding:"required"`
+	ID          string `json:"id,omitempty"`
+	Description string `json:"description"`
+	Type        string `json:"type,omitempty"`
+	IMDB        string `json:"imdb"`
 }
 
 func NewBookDTO(book Book) BookDTO {
 	var adaptations []AdaptationDTO
 	for _, a := range book.Adaptations {
 		adaptations = append(adaptations, AdaptationDTO{
+			ID:          a.ID,
 			Description: a.Description,
+			Type:        a.Type,
 			IMDB:        a.IMDB,
 		})
 	}
 
 	return BookDTO{
 		ID:          book.ID,
+		Slug:        book.Slug,
 		Title:       book.Title,
 		Year:        book.Year,
 		Blurb:       book.Blurb,
@@ -105,22 +286,44 @@ func NewBookDTO(book Book) BookDTO {
 }
 
 func (r *BookDTO) ToBook() Book {
-	var adaptations []Adaptation
-	for _, a := range r.Adaptations {
-		adaptations = append(adaptations, Adaptation{
-			Description: a.Description,
-			IMDB:        a.IMDB,
-		})
+	return Book{
+		Title: r.Title,
+		Year:  r.Year,
+		Blurb: r.Blurb,
 	}
+}
 
-	return Book{
-		Title:       r.Title,
-		Year:        r.Year,
-		Blurb:       r.Blurb,
-		Adaptations: adaptations,
+type PatchBookDTO struct {
+	Title *string `json:"title" binding:"omitempty,min=1"`
+	Year  *int    `json:"year" binding:"omitempty,gte=1956"`
+	Blurb *string `json:"blurb"`
+}
+
+func (r *PatchBookDTO) ApplyTo(book Book) Book {
+	if r.Title != nil {
+		book.Title = *r.Title
+	}
+	if r.Year != nil {
+		book.Year = *r.Year
 	}
+	if r.Blurb != nil {
+		book.Blurb = *r.Blurb
+	}
+
+	return book
 }
 
 type GetByIDRequest struct {
 	BookID string `uri:"bookID" binding:"required"`
 }
+
+type DeleteRequest struct {
+	Cascade bool `form:"cascade"`
+}
+
+type GetAllRequest struct {
+	Limit    int32  `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor   string `form:"cursor"`
+	YearFrom int    `form:"yearFrom" binding:"omitempty,gte=1956"`
+	YearTo   int    `form:"yearTo" binding:"omitempty,gte=1956,gtefield=YearFrom"`
+}
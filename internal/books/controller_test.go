@@ -9,6 +9,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
@@ -36,7 +39,7 @@ func TestController_Create(t *testing.T) {
 			reqBody: `{"title": "The Black Echo", "year": 1992, "blurb": "a random blurb"}`,
 			setup: func(m *ManagerMock) {
 				reqBook := Book{Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}
-				m.On("Create", mock.Anything, reqBook).Return(Book{}, assert.AnError).Once()
+				m.On("Create", mock.Anything, reqBook).Return(Book{}, false, assert.AnError).Once()
 			},
 			expected: func(_ *httptest.ResponseRecorder, err error) {
 				assert.True(t, errors.Is(err, assert.AnError))
@@ -46,14 +49,29 @@ func TestController_Create(t *testing.T) {
 			name:    "when create book service is successful",
 			reqBody: `{"title": "The Black Echo", "year": 1992, "blurb": "a random blurb", "adaptations": [{"description": "Bosch S03","imdb": "https://www.imdb.com/title/tt3502248/episodes/?season=3"}]}`,
 			setup: func(m *ManagerMock) {
-				reqBook := Book{Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Adaptations: []Adaptation{{Description: "Bosch S03", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}}}
-				respBook := Book{ID: "a-string", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Adaptations: []Adaptation{{Description: "Bosch S03", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}}}
-				m.On("Create", mock.Anything, reqBook).Return(respBook, nil).Once()
+				reqBook := Book{Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}
+				respBook := Book{ID: "a-string", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Adaptations: []Adaptation{{ID: "adaptation-id", Description: "Bosch S03", Type: "season", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}}}
+				m.On("Create", mock.Anything, reqBook).Return(respBook, true, nil).Once()
 			},
 			expected: func(r *httptest.ResponseRecorder, err error) {
 				assert.Nil(t, err)
 				assert.Equal(t, http.StatusCreated, r.Code)
-				assert.Equal(t, `{"id":"a-string","title":"The Black Echo","year":1992,"blurb":"a random blurb","adaptations":[{"description":"Bosch S03","imdb":"https://www.imdb.com/title/tt3502248/episodes/?season=3"}]}`, r.Body.String())
+				assert.Equal(t, `{"id":"a-string","title":"The Black Echo","year":1992,"blurb":"a random blurb","adaptations":[{"id":"adaptation-id","description":"Bosch S03","type":"season","imdb":"https://www.imdb.com/title/tt3502248/episodes/?season=3"}]}`, r.Body.String())
+			},
+		},
+		{
+			name:    "when create book service returns an existing book",
+			reqBody: `{"title": "The Black Echo", "year": 1992, "blurb": "a random blurb"}`,
+			setup: func(m *ManagerMock) {
+				reqBook := Book{Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}
+				respBook := Book{ID: "a-string", Title: "The Black Echo", Year: 1992, Blurb: "an old blurb", Version: 2}
+				m.On("Create", mock.Anything, reqBook).Return(respBook, false, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"2"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"a-string","title":"The Black Echo","year":1992,"blurb":"an old blurb"}`, r.Body.String())
 			},
 		},
 	}
@@ -78,9 +96,199 @@ func TestController_Create(t *testing.T) {
 	}
 }
 
-func TestController_GetById(t *testing.T) {
+func TestController_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  string
+		ifMatch  string
+		setup    func(*ManagerMock, *gin.Context)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when missing book id req param",
+			reqBody: `{"title": "The Black Echo", "year": 1992}`,
+			setup:   func(_ *ManagerMock, _ *gin.Context) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when If-Match header is missing",
+			reqBody: `{"title": "The Black Echo", "year": 1992}`,
+			setup: func(_ *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, etag.ErrMissing))
+			},
+		},
+		{
+			name:    "when request body is missing required fields",
+			ifMatch: `"2"`,
+			reqBody: `{"blurb": "a random blurb"}`,
+			setup: func(_ *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when update book service fails",
+			ifMatch: `"2"`,
+			reqBody: `{"title": "The Black Echo", "year": 1992, "blurb": "a random blurb"}`,
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+				reqBook := Book{ID: "a-book-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 2}
+				m.On("Update", mock.Anything, reqBook).Return(Book{}, dynamo.ErrVersionMismatch).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, dynamo.ErrVersionMismatch))
+			},
+		},
+		{
+			name:    "when update book service is successful",
+			ifMatch: `"2"`,
+			reqBody: `{"title": "The Black Echo", "year": 1992, "blurb": "a random blurb"}`,
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+				reqBook := Book{ID: "a-book-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 2}
+				respBook := Book{ID: "a-book-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 3}
+				m.On("Update", mock.Anything, reqBook).Return(respBook, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"a-book-id","title":"The Black Echo","year":1992,"blurb":"a random blurb"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/books/a-book-id", strings.NewReader(tt.reqBody))
+
+			ctx.Request.Header.Set("If-Match", tt.ifMatch)
+
+			tt.setup(m, ctx)
+
+			c.Update(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Patch(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  string
+		ifMatch  string
+		setup    func(*ManagerMock, *gin.Context)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when request body is an invalid json",
+			reqBody: `}`,
+			ifMatch: `"2"`,
+			setup: func(_ *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var syntaxErr *json.SyntaxError
+				assert.True(t, errors.As(err, &syntaxErr))
+			},
+		},
+		{
+			name:    "when If-Match header is not a version",
+			reqBody: `{"title": "The Black Echo"}`,
+			ifMatch: `"abc"`,
+			setup: func(_ *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, etag.ErrInvalid))
+			},
+		},
+		{
+			name:    "when get book service fails",
+			reqBody: `{"title": "The Black Echo"}`,
+			ifMatch: `"2"`,
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+				m.On("GetById", mock.Anything, "a-book-id").Return(Book{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when update book service fails",
+			reqBody: `{"title": "The Black Echo"}`,
+			ifMatch: `"2"`,
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+				m.On("GetById", mock.Anything, "a-book-id").Return(Book{ID: "a-book-id", Title: "The Black Ecko", Year: 1992, Version: 2}, nil).Once()
+				m.On("Update", mock.Anything, Book{ID: "a-book-id", Title: "The Black Echo", Year: 1992, Version: 2}).Return(Book{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when patch book is successful",
+			reqBody: `{"title": "The Black Echo"}`,
+			ifMatch: `"2"`,
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+				m.On("GetById", mock.Anything, "a-book-id").Return(Book{ID: "a-book-id", Title: "The Black Ecko", Year: 1992, Version: 2}, nil).Once()
+				m.On("Update", mock.Anything, Book{ID: "a-book-id", Title: "The Black Echo", Year: 1992, Version: 2}).Return(Book{ID: "a-book-id", Title: "The Black Echo", Year: 1992, Version: 3}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"a-book-id","title":"The Black Echo","year":1992,"blurb":""}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/books/a-book-id", strings.NewReader(tt.reqBody))
+
+			ctx.Request.Header.Set("If-Match", tt.ifMatch)
+
+			tt.setup(m, ctx)
+
+			c.Patch(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Delete(t *testing.T) {
 	tests := []struct {
 		name     string
+		query    string
 		setup    func(*ManagerMock, *gin.Context)
 		expected func(*httptest.ResponseRecorder, error)
 	}{
@@ -93,10 +301,69 @@ func TestController_GetById(t *testing.T) {
 			},
 		},
 		{
-			name: "when get book service fails",
+			name: "when delete book service fails",
 			setup: func(m *ManagerMock, ctx *gin.Context) {
 				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
-				m.On("GetById", mock.Anything, "a-book-id").Return(Book{}, assert.AnError).Once()
+				m.On("Delete", mock.Anything, "a-book-id", false).Return(&apperr.ReferencedError{Resource: "book", ID: "a-book-id"}).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, apperr.ErrReferenced))
+			},
+		},
+		{
+			name:  "when delete book with cascade is successful",
+			query: "?cascade=true",
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
+				m.On("Delete", mock.Anything, "a-book-id", true).Return(nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusNoContent, r.Code)
+				assert.Empty(t, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/books/book-id"+tt.query, nil)
+
+			tt.setup(m, ctx)
+
+			c.Delete(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetBy(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*ManagerMock, *gin.Context)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when missing book id req param",
+			setup: func(_ *ManagerMock, _ *gin.Context) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name: "when get book service fails",
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Book{}, assert.AnError).Once()
 			},
 			expected: func(_ *httptest.ResponseRecorder, err error) {
 				assert.True(t, errors.Is(err, assert.AnError))
@@ -105,14 +372,39 @@ func TestController_GetById(t *testing.T) {
 		{
 			name: "when get book service is successful",
 			setup: func(m *ManagerMock, ctx *gin.Context) {
-				ctx.Params = gin.Params{{Key: "bookID", Value: "a-book-id"}}
-				respBook := Book{ID: "a-string", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}
-				m.On("GetById", mock.Anything, "a-book-id").Return(respBook, nil).Once()
+				ctx.Params = gin.Params{{Key: "bookID", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				respBook := Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "the-black-echo", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 5}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(respBook, nil).Once()
 			},
 			expected: func(r *httptest.ResponseRecorder, err error) {
 				assert.Nil(t, err)
 				assert.Equal(t, http.StatusOK, r.Code)
-				assert.Equal(t, `{"id":"a-string","title":"The Black Echo","year":1992,"blurb":"a random blurb"}`, r.Body.String())
+				assert.Equal(t, `"5"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","slug":"the-black-echo","title":"The Black Echo","year":1992,"blurb":"a random blurb"}`, r.Body.String())
+			},
+		},
+		{
+			name: "when get book by slug service fails",
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "the-black-echo"}}
+				m.On("GetBySlug", mock.Anything, "the-black-echo").Return(Book{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name: "when get book by slug service is successful",
+			setup: func(m *ManagerMock, ctx *gin.Context) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "the-black-echo"}}
+				respBook := Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "the-black-echo", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 5}
+				m.On("GetBySlug", mock.Anything, "the-black-echo").Return(respBook, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"5"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","slug":"the-black-echo","title":"The Black Echo","year":1992,"blurb":"a random blurb"}`, r.Body.String())
 			},
 		},
 	}
@@ -127,7 +419,7 @@ func TestController_GetById(t *testing.T) {
 
 			tt.setup(m, ctx)
 
-			c.GetById(ctx)
+			c.GetBy(ctx)
 
 			ctx.Writer.WriteHeaderNow()
 
@@ -190,22 +482,199 @@ func TestController_GetAll(t *testing.T) {
 	}
 }
 
+func TestController_GetAllPaginated(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when only cursor is sent",
+			query: "?cursor=a-cursor",
+			setup: func(m *ManagerMock) {
+				m.On("GetPage", mock.Anything, int32(20), "a-cursor").Return([]Book{}, "", nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"items":[]}`, r.Body.String())
+			},
+		},
+		{
+			name:  "when limit is above maximum",
+			query: "?limit=101",
+			setup: func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when get page service fails",
+			query: "?limit=2",
+			setup: func(m *ManagerMock) {
+				m.On("GetPage", mock.Anything, int32(2), "").Return([]Book{}, "", assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:  "when get page service is successful",
+			query: "?limit=2&cursor=a-cursor",
+			setup: func(m *ManagerMock) {
+				respBooks := []Book{
+					{ID: "123", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"},
+					{ID: "456", Title: "The Black Ice", Year: 1993, Blurb: "a random blurb"},
+				}
+				m.On("GetPage", mock.Anything, int32(2), "a-cursor").Return(respBooks, "next-cursor", nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"items":[{"id":"123","title":"The Black Echo","year":1992,"blurb":"a random blurb"},{"id":"456","title":"The Black Ice","year":1993,"blurb":"a random blurb"}],"nextCursor":"next-cursor"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/books"+tt.query, nil)
+
+			tt.setup(m)
+
+			c.GetAll(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetAllByYearRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when year range is inverted",
+			query: "?yearFrom=2009&yearTo=2000",
+			setup: func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when get by year range service fails",
+			query: "?yearFrom=2000",
+			setup: func(m *ManagerMock) {
+				m.On("GetByYearRange", mock.Anything, 2000, 0).Return([]Book{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:  "when no books in range",
+			query: "?yearTo=1960",
+			setup: func(m *ManagerMock) {
+				m.On("GetByYearRange", mock.Anything, 0, 1960).Return([]Book{}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[]`, r.Body.String())
+			},
+		},
+		{
+			name:  "when get by year range service is successful",
+			query: "?yearFrom=2000&yearTo=2009&limit=1",
+			setup: func(m *ManagerMock) {
+				respBooks := []Book{
+					{ID: "123", Title: "The Closers", Year: 2005, Blurb: "a random blurb"},
+					{ID: "456", Title: "The Overlook", Year: 2007, Blurb: "a random blurb"},
+				}
+				m.On("GetByYearRange", mock.Anything, 2000, 2009).Return(respBooks, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[{"id":"123","title":"The Closers","year":2005,"blurb":"a random blurb"},{"id":"456","title":"The Overlook","year":2007,"blurb":"a random blurb"}]`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/books"+tt.query, nil)
+
+			tt.setup(m)
+
+			c.GetAll(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
 type ManagerMock struct {
 	Manager
 	mock.Mock
 }
 
-func (m *ManagerMock) Create(ctx context.Context, book Book) (Book, error) {
+func (m *ManagerMock) Create(ctx context.Context, book Book) (Book, bool, error) {
+	args := m.Called(ctx, book)
+	return args.Get(0).(Book), args.Bool(1), args.Error(2)
+}
+
+func (m *ManagerMock) Update(ctx context.Context, book Book) (Book, error) {
 	args := m.Called(ctx, book)
 	return args.Get(0).(Book), args.Error(1)
 }
 
+func (m *ManagerMock) Delete(ctx context.Context, bookID string, cascade bool) error {
+	args := m.Called(ctx, bookID, cascade)
+	return args.Error(0)
+}
+
 func (m *ManagerMock) GetById(ctx context.Context, bookID string) (Book, error) {
 	args := m.Called(ctx, bookID)
 	return args.Get(0).(Book), args.Error(1)
 }
 
+func (m *ManagerMock) GetBySlug(ctx context.Context, slug string) (Book, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(Book), args.Error(1)
+}
+
 func (m *ManagerMock) GetAll(ctx context.Context) ([]Book, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]Book), args.Error(1)
 }
+
+func (m *ManagerMock) GetPage(ctx context.Context, limit int32, cursor string) ([]Book, string, error) {
+	args := m.Called(ctx, limit, cursor)
+	return args.Get(0).([]Book), args.String(1), args.Error(2)
+}
+
+func (m *ManagerMock) GetByYearRange(ctx context.Context, yearFrom int, yearTo int) ([]Book, error) {
+	args := m.Called(ctx, yearFrom, yearTo)
+	return args.Get(0).([]Book), args.Error(1)
+}
@@ -4,60 +4,279 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
 )
 
 type DynamoDBClient interface {
-	Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string) (string, error)
+	NewID() string
+	Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error)
+	Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error
+	Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error
 	GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error)
 	GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error)
+	GetBySlug(ctx context.Context, tableName string, slug string) (map[string]types.AttributeValue, error)
+	BatchGetFound(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error)
+	BatchGetByUniqueKeys(ctx context.Context, tableName string, values []string) ([]map[string]types.AttributeValue, error)
 	GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error)
+	GetPage(ctx context.Context, tableName string, limit int32, cursor string) ([]map[string]types.AttributeValue, string, error)
+	Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error)
+}
+
+type Adaptations interface {
+	GetByBooks(ctx context.Context, bookIDs []string) (map[string][]Adaptation, error)
+}
+
+type AdaptationImporter interface {
+	Import(ctx context.Context, bookID string, legacy []Adaptation) error
+}
+
+const YearIndex = "year-index"
+const bookEntity = "book"
+
+func Defaults() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"entity": &types.AttributeValueMemberS{Value: bookEntity}}
 }
 
 type Repository struct {
-	dynamoDBClient DynamoDBClient
-	tableName      string
+	dynamoDBClient  DynamoDBClient
+	tableName       string
+	duplicatePolicy dynamo.DuplicatePolicy
+	adaptations     Adaptations
 }
 
-func NewRepository(dynamoDBClient DynamoDBClient, tableName string) *Repository {
-	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName}
+func NewRepository(dynamoDBClient DynamoDBClient, tableName string, duplicatePolicy dynamo.DuplicatePolicy, adaptations Adaptations) *Repository {
+	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName, duplicatePolicy: duplicatePolicy, adaptations: adaptations}
 }
 
-func (r *Repository) Save(ctx context.Context, book Book) (Book, error) {
+func (r *Repository) Save(ctx context.Context, book Book) (Book, bool, error) {
+	book.ID = r.dynamoDBClient.NewID()
+
+	var err error
+	for _, slug := range dynamo.SlugCandidates("", book.Title, book.ID) {
+		book.Slug = slug
+		err = r.save(ctx, book)
+		if !errors.Is(err, dynamo.ErrSlugTaken) {
+			break
+		}
+	}
+	if errors.Is(err, dynamo.ErrDuplicated) {
+		return r.saveDuplicated(ctx, book, err)
+	}
+	if err != nil {
+		return Book{}, false, err
+	}
+
+	book.Version = 1
+
+	return book, true, nil
+}
+
+func (r *Repository) save(ctx context.Context, book Book) error {
 	bookItem, err := attributevalue.MarshalMap(newDBBook(book))
 	if err != nil {
-		return Book{}, fmt.Errorf("failed to marshal book: %w", err)
+		return fmt.Errorf("failed to marshal book: %w", err)
+	}
+
+	_, err = r.dynamoDBClient.Save(ctx, r.tableName, bookItem, book.Title, dynamo.ClaimSlugWrite(r.tableName, book.Slug, book.ID))
+
+	return err
+}
+
+func (r *Repository) saveDuplicated(ctx context.Context, book Book, duplicatedErr error) (Book, bool, error) {
+	if r.duplicatePolicy == dynamo.DuplicateReject {
+		return Book{}, false, duplicatedErr
+	}
+
+	existingBook, err := r.getDuplicated(ctx, book, duplicatedErr)
+	if err != nil {
+		return Book{}, false, err
+	}
+
+	if r.duplicatePolicy == dynamo.DuplicateReturnExisting {
+		return existingBook, false, nil
 	}
 
-	id, err := r.dynamoDBClient.Save(ctx, r.tableName, bookItem, book.Title)
+	book.ID = existingBook.ID
+	book.Version = existingBook.Version
+
+	updatedBook, err := r.Update(ctx, book)
+
+	return updatedBook, false, err
+}
+
+func (r *Repository) getDuplicated(ctx context.Context, book Book, duplicatedErr error) (Book, error) {
+	var withID *dynamo.DuplicatedError
+	if errors.As(duplicatedErr, &withID) {
+		return r.GetById(ctx, withID.ID)
+	}
+
+	return r.GetByTitle(ctx, book.Title)
+}
+
+func (r *Repository) Update(ctx context.Context, book Book) (Book, error) {
+	currentBook, err := r.getByID(ctx, book.ID)
 	if err != nil {
-		if errors.Is(err, dynamo.ErrDuplicated) {
-			return r.GetByTitle(ctx, book.Title)
+		return Book{}, err
+	}
+
+	if book.Version == dynamo.AnyVersion {
+		book.Version = currentBook.Version
+	}
+
+	for _, slug := range dynamo.SlugCandidates(currentBook.Slug, book.Title, book.ID) {
+		book.Slug = slug
+		err = r.update(ctx, book, currentBook)
+		if !errors.Is(err, dynamo.ErrSlugTaken) {
+			break
 		}
+	}
+	if err != nil {
 		return Book{}, err
 	}
 
-	book.ID = id
+	book.Version++
+
+	booksList := []Book{book}
+	err = r.loadAdaptations(ctx, booksList)
+	if err != nil {
+		return Book{}, err
+	}
+
+	return booksList[0], nil
+}
+
+func (r *Repository) update(ctx context.Context, book Book, currentBook DBBook) error {
+	dbBook := newDBBook(book)
+	dbBook.Adaptations = currentBook.Adaptations
+
+	bookItem, err := attributevalue.MarshalMap(dbBook)
+	if err != nil {
+		return fmt.Errorf("failed to marshal book: %w", err)
+	}
+
+	return r.dynamoDBClient.Update(ctx, r.tableName, book.ID, book.Version, bookItem, currentBook.Title, book.Title, dynamo.ClaimSlugWrite(r.tableName, book.Slug, book.ID))
+}
+
+func (r *Repository) Delete(ctx context.Context, bookID string) error {
+	book, err := r.getByID(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	var writes []dynamo.Write
+	if book.Slug != "" {
+		writes = append(writes, dynamo.ReleaseSlugWrite(r.tableName, book.Slug, bookID))
+	}
+
+	err = r.dynamoDBClient.Delete(ctx, r.tableName, bookID, book.Title, writes...)
+	if errors.Is(err, dynamo.ErrReferenced) {
+		return &apperr.ReferencedError{Resource: "book", ID: bookID}
+	}
+
+	return err
+}
+
+func (r *Repository) BackfillSlugs(ctx context.Context) error {
+	items, err := r.dynamoDBClient.GetAll(ctx, r.tableName)
+	if err != nil {
+		return err
+	}
+
+	booksList, err := toBookList(items)
+	if err != nil {
+		return err
+	}
+
+	for _, book := range booksList {
+		if book.Slug != "" {
+			continue
+		}
+
+		book.Version = dynamo.AnyVersion
+		_, err = r.Update(ctx, book)
+		if err != nil {
+			return fmt.Errorf("%w. book: %s", err, book.ID)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) MigrateAdaptations(ctx context.Context, importer AdaptationImporter) error {
+	items, err := r.dynamoDBClient.GetAll(ctx, r.tableName)
+	if err != nil {
+		return err
+	}
+
+	var dbBooks []DBBook
+	err = attributevalue.UnmarshalListOfMaps(items, &dbBooks)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal books: %w", err)
+	}
+
+	for _, dbBook := range dbBooks {
+		if len(dbBook.Adaptations) == 0 {
+			continue
+		}
+
+		var legacy []Adaptation
+		for _, a := range dbBook.Adaptations {
+			legacy = append(legacy, Adaptation{Description: a.Description, IMDB: a.IMDB})
+		}
+
+		err = importer.Import(ctx, dbBook.ID, legacy)
+		if err != nil {
+			return fmt.Errorf("%w. book: %s", err, dbBook.ID)
+		}
+
+		dbBook.Adaptations = nil
+		bookItem, err := attributevalue.MarshalMap(dbBook)
+		if err != nil {
+			return fmt.Errorf("failed to marshal book: %w", err)
+		}
 
-	return book, err
+		err = r.dynamoDBClient.Update(ctx, r.tableName, dbBook.ID, dbBook.Version, bookItem, dbBook.Title, dbBook.Title)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (r *Repository) GetById(ctx context.Context, bookID string) (Book, error) {
-	item, err := r.dynamoDBClient.GetByID(ctx, r.tableName, bookID)
+	dbBook, err := r.getByID(ctx, bookID)
 	if err != nil {
 		return Book{}, err
 	}
 
+	booksList := []Book{dbBook.toBook()}
+	err = r.loadAdaptations(ctx, booksList)
+	if err != nil {
+		return Book{}, err
+	}
+
+	return booksList[0], nil
+}
+
+func (r *Repository) getByID(ctx context.Context, bookID string) (DBBook, error) {
+	item, err := r.dynamoDBClient.GetByID(ctx, r.tableName, bookID)
+	if err != nil {
+		return DBBook{}, err
+	}
+
 	var dbBook DBBook
 	err = attributevalue.UnmarshalMap(item, &dbBook)
 	if err != nil {
-		return Book{}, fmt.Errorf("failed to unmarshal book: %w", err)
+		return DBBook{}, fmt.Errorf("failed to unmarshal book: %w", err)
 	}
 
-	return dbBook.toBook(), nil
+	return dbBook, nil
 }
 
 func (r *Repository) GetByTitle(ctx context.Context, bookTitle string) (Book, error) {
@@ -72,24 +291,64 @@ func (r *Repository) GetByTitle(ctx context.Context, bookTitle string) (Book, er
 		return Book{}, fmt.Errorf("failed to unmarshal book: %w", err)
 	}
 
-	return dbBook.toBook(), nil
+	booksList := []Book{dbBook.toBook()}
+	err = r.loadAdaptations(ctx, booksList)
+	if err != nil {
+		return Book{}, err
+	}
+
+	return booksList[0], nil
 }
 
-func (r *Repository) GetBookListByTitles(ctx context.Context, bookTitles []string) ([]Book, error) {
-	var booksList []Book
+func (r *Repository) GetBySlug(ctx context.Context, slug string) (Book, error) {
+	item, err := r.dynamoDBClient.GetBySlug(ctx, r.tableName, slug)
+	if err != nil {
+		return Book{}, err
+	}
 
-	for _, bookTitle := range bookTitles {
-		book, err := r.GetByTitle(ctx, bookTitle)
-		if err != nil {
-			return nil, err
-		}
+	var dbBook DBBook
+	err = attributevalue.UnmarshalMap(item, &dbBook)
+	if err != nil {
+		return Book{}, fmt.Errorf("failed to unmarshal book: %w", err)
+	}
+
+	booksList := []Book{dbBook.toBook()}
+	err = r.loadAdaptations(ctx, booksList)
+	if err != nil {
+		return Book{}, err
+	}
+
+	return booksList[0], nil
+}
+
+func (r *Repository) GetByIds(ctx context.Context, bookIDs []string) ([]Book, error) {
+	items, err := r.dynamoDBClient.BatchGetFound(ctx, r.tableName, bookIDs)
+	if err != nil {
+		return nil, err
+	}
 
-		booksList = append(booksList, book)
+	booksList, err := toBookList(items)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.loadAdaptations(ctx, booksList)
+	if err != nil {
+		return nil, err
 	}
 
 	return booksList, nil
 }
 
+func (r *Repository) GetBookListByTitles(ctx context.Context, bookTitles []string) ([]Book, error) {
+	items, err := r.dynamoDBClient.BatchGetByUniqueKeys(ctx, r.tableName, bookTitles)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBookList(items)
+}
+
 func (r *Repository) GetAll(ctx context.Context) ([]Book, error) {
 	items, err := r.dynamoDBClient.GetAll(ctx, r.tableName)
 	if err != nil {
@@ -107,15 +366,117 @@ func (r *Repository) GetAll(ctx context.Context) ([]Book, error) {
 		booksList = append(booksList, dbBook.toBook())
 	}
 
+	err = r.loadAdaptations(ctx, booksList)
+	if err != nil {
+		return []Book{}, err
+	}
+
+	return booksList, nil
+}
+
+func (r *Repository) GetPage(ctx context.Context, limit int32, cursor string) ([]Book, string, error) {
+	items, nextCursor, err := r.dynamoDBClient.GetPage(ctx, r.tableName, limit, cursor)
+	if err != nil {
+		return []Book{}, "", err
+	}
+
+	var booksList []Book
+	for _, item := range items {
+		var dbBook DBBook
+		err = attributevalue.UnmarshalMap(item, &dbBook)
+		if err != nil {
+			return []Book{}, "", fmt.Errorf("failed to unmarshal book: %w", err)
+		}
+
+		booksList = append(booksList, dbBook.toBook())
+	}
+
+	err = r.loadAdaptations(ctx, booksList)
+	if err != nil {
+		return []Book{}, "", err
+	}
+
+	return booksList, nextCursor, nil
+}
+
+func (r *Repository) GetByYearRange(ctx context.Context, yearFrom int, yearTo int) ([]Book, error) {
+	query := dynamo.Query{
+		IndexName: YearIndex,
+		HashKey:   "entity",
+		HashValue: &types.AttributeValueMemberS{Value: bookEntity},
+		RangeKey:  "year",
+	}
+	if yearFrom > 0 {
+		query.From = &types.AttributeValueMemberN{Value: strconv.Itoa(yearFrom)}
+	}
+	if yearTo > 0 {
+		query.To = &types.AttributeValueMemberN{Value: strconv.Itoa(yearTo)}
+	}
+
+	items, err := r.dynamoDBClient.Query(ctx, r.tableName, query)
+	if err != nil {
+		return []Book{}, err
+	}
+
+	booksList, err := toBookList(items)
+	if err != nil {
+		return []Book{}, err
+	}
+
+	err = r.loadAdaptations(ctx, booksList)
+	if err != nil {
+		return []Book{}, err
+	}
+
+	return booksList, nil
+}
+
+func (r *Repository) loadAdaptations(ctx context.Context, booksList []Book) error {
+	if len(booksList) == 0 {
+		return nil
+	}
+
+	var bookIDs []string
+	for _, book := range booksList {
+		bookIDs = append(bookIDs, book.ID)
+	}
+
+	adaptationsByBook, err := r.adaptations.GetByBooks(ctx, bookIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range booksList {
+		booksList[i].Adaptations = adaptationsByBook[booksList[i].ID]
+	}
+
+	return nil
+}
+
+func toBookList(items []map[string]types.AttributeValue) ([]Book, error) {
+	var booksList []Book
+	for _, item := range items {
+		var dbBook DBBook
+		err := attributevalue.UnmarshalMap(item, &dbBook)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal book: %w", err)
+		}
+
+		booksList = append(booksList, dbBook.toBook())
+	}
+
 	return booksList, nil
 }
 
 type DBBook struct {
 	ID          string         `dynamodbav:"id"`
+	Entity      string         `dynamodbav:"entity"`
+	Slug        string         `dynamodbav:"slug,omitempty"`
 	Title       string         `dynamodbav:"title"`
 	Year        int            `dynamodbav:"year"`
 	Blurb       string         `dynamodbav:"blurb"`
-	Adaptations []DBAdaptation `dynamodbav:"adaptations"`
+	Adaptations []DBAdaptation `dynamodbav:"adaptations,omitempty"`
+	Version     int            `dynamodbav:"version,omitempty"`
 }
 
 type DBAdaptation struct {
@@ -124,39 +485,24 @@ type DBAdaptation struct {
 }
 
 func newDBBook(book Book) DBBook {
-	var adaptations []DBAdaptation
-
-	for _, a := range book.Adaptations {
-		adaptations = append(adaptations, DBAdaptation{
-			Description: a.Description,
-			IMDB:        a.IMDB,
-		})
-	}
-
 	return DBBook{
-		ID:          book.ID,
-		Title:       book.Title,
-		Year:        book.Year,
-		Blurb:       book.Blurb,
-		Adaptations: adaptations,
+		ID:      book.ID,
+		Entity:  bookEntity,
+		Slug:    book.Slug,
+		Title:   book.Title,
+		Year:    book.Year,
+		Blurb:   book.Blurb,
+		Version: book.Version,
 	}
 }
 
 func (b *DBBook) toBook() Book {
-	var adaptations []Adaptation
-
-	for _, a := range b.Adaptations {
-		adaptations = append(adaptations, Adaptation{
-			Description: a.Description,
-			IMDB:        a.IMDB,
-		})
-	}
-
 	return Book{
-		ID:          b.ID,
-		Title:       b.Title,
-		Year:        b.Year,
-		Blurb:       b.Blurb,
-		Adaptations: adaptations,
+		ID:      b.ID,
+		Slug:    b.Slug,
+		Title:   b.Title,
+		Year:    b.Year,
+		Blurb:   b.Blurb,
+		Version: b.Version,
 	}
 }
@@ -3,11 +3,13 @@ package books
 import (
 	"context"
 	"fmt"
+	"maps"
 	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,86 +17,373 @@ import (
 
 func TestRepository_Save(t *testing.T) {
 	ctx := context.Background()
+	blurb := "For LAPD homicide cop Harry Bosch — hero, maverick, nighthawk — the body in the drainpipe at Mulholland dam is more than another anonymous statistic.  This one is personal. The dead man, Billy Meadows, was a fellow Vietnam “tunnel rat” who fought side by side with him in a nightmare underground war that brought them to the depths of hell.  Now, Bosch is about to relive the horrors of Nam.  From a dangerous maze of blind alleys to a daring criminal heist beneath the city to the tortuous link that must be uncovered, his survival instincts will once again be tested to their limit. Joining with an enigmatic female FBI agent, pitted against enemies within his own department, Bosch must make the agonizing choice between justice and vengeance, as he tracks down a killer whose true face will shock him. The Black Echo won the Edgar Award for Best First Mystery Novel awarded by the Mystery Writers of America."
+	item := map[string]types.AttributeValue{
+		"id":     &types.AttributeValueMemberS{Value: "random-id"},
+		"entity": &types.AttributeValueMemberS{Value: "book"},
+		"slug":   &types.AttributeValueMemberS{Value: "the-black-echo"},
+		"title":  &types.AttributeValueMemberS{Value: "The Black Echo"},
+		"blurb":  &types.AttributeValueMemberS{Value: blurb},
+		"year":   &types.AttributeValueMemberN{Value: "1992"},
+	}
+	adaptations := map[string][]Adaptation{"existing-id": {{ID: "adaptation-id", Description: "Bosch S03", Type: "season", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}}}
+	existingItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "existing-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-echo"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}, "version": &types.AttributeValueMemberN{Value: "2"}}
+	claim := []dynamo.Write{dynamo.ClaimSlugWrite("table-name", "the-black-echo", "random-id")}
+	tests := []struct {
+		name        string
+		policy      dynamo.DuplicatePolicy
+		setup       func(*MockDynamoDBClient, *AdaptationsMock)
+		want        Book
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name:   "when failed to save book because already exists",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("NewID").Return("random-id").Once()
+				m.On("Save", ctx, "table-name", item, "The Black Echo", claim).Return("", &dynamo.DuplicatedError{ID: "existing-id"}).Once()
+			},
+			wantErr: &dynamo.DuplicatedError{ID: "existing-id"},
+		},
+		{
+			name:   "when failed to get existing book",
+			policy: dynamo.DuplicateReturnExisting,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("NewID").Return("random-id").Once()
+				m.On("Save", ctx, "table-name", item, "The Black Echo", claim).Return("", &dynamo.DuplicatedError{ID: "existing-id"}).Once()
+				m.On("GetByID", ctx, "table-name", "existing-id").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when book already exists and policy returns existing",
+			policy: dynamo.DuplicateReturnExisting,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("NewID").Return("random-id").Once()
+				m.On("Save", ctx, "table-name", item, "The Black Echo", claim).Return("", &dynamo.DuplicatedError{ID: "existing-id"}).Once()
+				m.On("GetByID", ctx, "table-name", "existing-id").Return(existingItem, nil).Once()
+				a.On("GetByBooks", ctx, []string{"existing-id"}).Return(adaptations, nil).Once()
+			},
+			want: Book{ID: "existing-id", Slug: "the-black-echo", Title: "The Black Echo", Adaptations: adaptations["existing-id"], Version: 2},
+		},
+		{
+			name:   "when book already exists without its id and policy returns existing",
+			policy: dynamo.DuplicateReturnExisting,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("NewID").Return("random-id").Once()
+				m.On("Save", ctx, "table-name", item, "The Black Echo", claim).Return("", dynamo.ErrDuplicated).Once()
+				m.On("GetByUniqueKey", ctx, "table-name", "The Black Echo").Return(existingItem, nil).Once()
+				a.On("GetByBooks", ctx, []string{"existing-id"}).Return(adaptations, nil).Once()
+			},
+			want: Book{ID: "existing-id", Slug: "the-black-echo", Title: "The Black Echo", Adaptations: adaptations["existing-id"], Version: 2},
+		},
+		{
+			name:   "when book already exists and policy upserts",
+			policy: dynamo.DuplicateUpsert,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("NewID").Return("random-id").Once()
+				m.On("Save", ctx, "table-name", item, "The Black Echo", claim).Return("", &dynamo.DuplicatedError{ID: "existing-id"}).Once()
+				m.On("GetByID", ctx, "table-name", "existing-id").Return(existingItem, nil).Twice()
+				updateItem := maps.Clone(item)
+				updateItem["id"] = &types.AttributeValueMemberS{Value: "existing-id"}
+				updateItem["version"] = &types.AttributeValueMemberN{Value: "2"}
+				m.On("Update", ctx, "table-name", "existing-id", 2, updateItem, "The Black Echo", "The Black Echo", []dynamo.Write{dynamo.ClaimSlugWrite("table-name", "the-black-echo", "existing-id")}).Return(nil).Once()
+				a.On("GetByBooks", ctx, []string{"existing-id"}).Return(adaptations, nil).Twice()
+			},
+			want: Book{ID: "existing-id", Slug: "the-black-echo", Title: "The Black Echo", Year: 1992, Blurb: blurb, Adaptations: adaptations["existing-id"], Version: 3},
+		},
+		{
+			name:   "when failed to save book",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("NewID").Return("random-id").Once()
+				m.On("Save", ctx, "table-name", item, "The Black Echo", claim).Return("", assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when slug is taken it is suffixed",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("NewID").Return("random-id").Once()
+				m.On("Save", ctx, "table-name", item, "The Black Echo", claim).Return("", dynamo.ErrSlugTaken).Once()
+				suffixedItem := maps.Clone(item)
+				suffixedItem["slug"] = &types.AttributeValueMemberS{Value: "the-black-echo-2"}
+				m.On("Save", ctx, "table-name", suffixedItem, "The Black Echo", []dynamo.Write{dynamo.ClaimSlugWrite("table-name", "the-black-echo-2", "random-id")}).Return("random-id", nil).Once()
+			},
+			want:        Book{ID: "random-id", Slug: "the-black-echo-2", Title: "The Black Echo", Year: 1992, Blurb: blurb, Version: 1},
+			wantCreated: true,
+		},
+		{
+			name:   "when successfully saved book",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("NewID").Return("random-id").Once()
+				m.On("Save", ctx, "table-name", item, "The Black Echo", claim).Return("random-id", nil).Once()
+			},
+			want:        Book{ID: "random-id", Slug: "the-black-echo", Title: "The Black Echo", Year: 1992, Blurb: blurb, Version: 1},
+			wantCreated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", tt.policy, mockAdaptations)
+
+			got, created, err := r.Save(ctx, Book{Title: "The Black Echo", Year: 1992, Blurb: blurb})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			mockAdaptations.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "random-id"},
+		"entity":  &types.AttributeValueMemberS{Value: "book"},
+		"slug":    &types.AttributeValueMemberS{Value: "the-black-ecko"},
+		"title":   &types.AttributeValueMemberS{Value: "The Black Echo"},
+		"blurb":   &types.AttributeValueMemberS{Value: "a random blurb"},
+		"year":    &types.AttributeValueMemberN{Value: "1992"},
+		"version": &types.AttributeValueMemberN{Value: "2"},
+	}
+	claim := []dynamo.Write{dynamo.ClaimSlugWrite("table-name", "the-black-ecko", "random-id")}
 	tests := []struct {
 		name    string
-		setup   func(*MockDynamoDBClient)
+		setup   func(*MockDynamoDBClient, *AdaptationsMock)
 		want    Book
 		wantErr error
 	}{
 		{
-			name: "when failed to save book because already exists",
-			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{
-					"id":    &types.AttributeValueMemberS{Value: ""},
-					"title": &types.AttributeValueMemberS{Value: "The Black Echo"},
-					"blurb": &types.AttributeValueMemberS{Value: "For LAPD homicide cop Harry Bosch — hero, maverick, nighthawk — the body in the drainpipe at Mulholland dam is more than another anonymous statistic.  This one is personal. The dead man, Billy Meadows, was a fellow Vietnam “tunnel rat” who fought side by side with him in a nightmare underground war that brought them to the depths of hell.  Now, Bosch is about to relive the horrors of Nam.  From a dangerous maze of blind alleys to a daring criminal heist beneath the city to the tortuous link that must be uncovered, his survival instincts will once again be tested to their limit. Joining with an enigmatic female FBI agent, pitted against enemies within his own department, Bosch must make the agonizing choice between justice and vengeance, as he tracks down a killer whose true face will shock him. The Black Echo won the Edgar Award for Best First Mystery Novel awarded by the Mystery Writers of America."},
-					"year":  &types.AttributeValueMemberN{Value: "1992"},
-					"adaptations": &types.AttributeValueMemberL{Value: []types.AttributeValue{
-						&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
-							"description": &types.AttributeValueMemberS{Value: "Bosch S03"},
-							"imdb":        &types.AttributeValueMemberS{Value: "https://www.imdb.com/title/tt3502248/episodes/?season=3"},
-						}}}},
-				}
-				m.On("Save", ctx, "table-name", item, "The Black Echo").Return("", dynamo.ErrDuplicated).Once()
-				output := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}}
-				m.On("GetByUniqueKey", ctx, "table-name", "The Black Echo").Return(output, nil).Once()
+			name: "when failed to get current book",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
 			},
-			want: Book{ID: "random-id", Title: "The Black Echo"},
-		},
-		{
-			name: "when failed to save book",
-			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{
-					"id":    &types.AttributeValueMemberS{Value: ""},
-					"title": &types.AttributeValueMemberS{Value: "The Black Echo"},
-					"blurb": &types.AttributeValueMemberS{Value: "For LAPD homicide cop Harry Bosch — hero, maverick, nighthawk — the body in the drainpipe at Mulholland dam is more than another anonymous statistic.  This one is personal. The dead man, Billy Meadows, was a fellow Vietnam “tunnel rat” who fought side by side with him in a nightmare underground war that brought them to the depths of hell.  Now, Bosch is about to relive the horrors of Nam.  From a dangerous maze of blind alleys to a daring criminal heist beneath the city to the tortuous link that must be uncovered, his survival instincts will once again be tested to their limit. Joining with an enigmatic female FBI agent, pitted against enemies within his own department, Bosch must make the agonizing choice between justice and vengeance, as he tracks down a killer whose true face will shock him. The Black Echo won the Edgar Award for Best First Mystery Novel awarded by the Mystery Writers of America."},
-					"year":  &types.AttributeValueMemberN{Value: "1992"},
-					"adaptations": &types.AttributeValueMemberL{Value: []types.AttributeValue{
-						&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
-							"description": &types.AttributeValueMemberS{Value: "Bosch S03"},
-							"imdb":        &types.AttributeValueMemberS{Value: "https://www.imdb.com/title/tt3502248/episodes/?season=3"},
-						}}}},
-				}
-				m.On("Save", ctx, "table-name", item, "The Black Echo").Return("", assert.AnError).Once()
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to update book",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-ecko"}, "title": &types.AttributeValueMemberS{Value: "The Black Ecko"}}
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Update", ctx, "table-name", "random-id", 2, item, "The Black Ecko", "The Black Echo", claim).Return(assert.AnError).Once()
 			},
 			wantErr: assert.AnError,
 		},
 		{
-			name: "when successfully saved book",
-			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{
-					"id":    &types.AttributeValueMemberS{Value: ""},
-					"title": &types.AttributeValueMemberS{Value: "The Black Echo"},
-					"blurb": &types.AttributeValueMemberS{Value: "For LAPD homicide cop Harry Bosch — hero, maverick, nighthawk — the body in the drainpipe at Mulholland dam is more than another anonymous statistic.  This one is personal. The dead man, Billy Meadows, was a fellow Vietnam “tunnel rat” who fought side by side with him in a nightmare underground war that brought them to the depths of hell.  Now, Bosch is about to relive the horrors of Nam.  From a dangerous maze of blind alleys to a daring criminal heist beneath the city to the tortuous link that must be uncovered, his survival instincts will once again be tested to their limit. Joining with an enigmatic female FBI agent, pitted against enemies within his own department, Bosch must make the agonizing choice between justice and vengeance, as he tracks down a killer whose true face will shock him. The Black Echo won the Edgar Award for Best First Mystery Novel awarded by the Mystery Writers of America."},
-					"year":  &types.AttributeValueMemberN{Value: "1992"},
-					"adaptations": &types.AttributeValueMemberL{Value: []types.AttributeValue{
-						&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
-							"description": &types.AttributeValueMemberS{Value: "Bosch S03"},
-							"imdb":        &types.AttributeValueMemberS{Value: "https://www.imdb.com/title/tt3502248/episodes/?season=3"},
-						}}}},
-				}
-				m.On("Save", ctx, "table-name", item, "The Black Echo").Return("random-id", nil).Once()
+			name: "when successfully updated book",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-ecko"}, "title": &types.AttributeValueMemberS{Value: "The Black Ecko"}}
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Update", ctx, "table-name", "random-id", 2, item, "The Black Ecko", "The Black Echo", claim).Return(nil).Once()
+				a.On("GetByBooks", ctx, []string{"random-id"}).Return(map[string][]Adaptation{}, nil).Once()
+			},
+			want: Book{ID: "random-id", Slug: "the-black-ecko", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 3},
+		},
+		{
+			name: "when book has no slug yet it is generated from the new title",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "title": &types.AttributeValueMemberS{Value: "The Black Ecko"}}
+				updateItem := maps.Clone(item)
+				updateItem["slug"] = &types.AttributeValueMemberS{Value: "the-black-echo"}
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Update", ctx, "table-name", "random-id", 2, updateItem, "The Black Ecko", "The Black Echo", []dynamo.Write{dynamo.ClaimSlugWrite("table-name", "the-black-echo", "random-id")}).Return(nil).Once()
+				a.On("GetByBooks", ctx, []string{"random-id"}).Return(map[string][]Adaptation{}, nil).Once()
+			},
+			want: Book{ID: "random-id", Slug: "the-black-echo", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 3},
+		},
+		{
+			name: "when keeping legacy adaptations not yet migrated",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				legacy := &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+					"description": &types.AttributeValueMemberS{Value: "Bosch S03"},
+					"imdb":        &types.AttributeValueMemberS{Value: "https://www.imdb.com/title/tt3502248/episodes/?season=3"},
+				}}}}
+				current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-ecko"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}, "adaptations": legacy}
+				updateItem := maps.Clone(item)
+				updateItem["adaptations"] = legacy
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Update", ctx, "table-name", "random-id", 2, updateItem, "The Black Echo", "The Black Echo", claim).Return(nil).Once()
+				a.On("GetByBooks", ctx, []string{"random-id"}).Return(map[string][]Adaptation{}, nil).Once()
 			},
-			want: Book{ID: "random-id", Title: "The Black Echo", Year: 1992, Blurb: "For LAPD homicide cop Harry Bosch — hero, maverick, nighthawk — the body in the drainpipe at Mulholland dam is more than another anonymous statistic.  This one is personal. The dead man, Billy Meadows, was a fellow Vietnam “tunnel rat” who fought side by side with him in a nightmare underground war that brought them to the depths of hell.  Now, Bosch is about to relive the horrors of Nam.  From a dangerous maze of blind alleys to a daring criminal heist beneath the city to the tortuous link that must be uncovered, his survival instincts will once again be tested to their limit. Joining with an enigmatic female FBI agent, pitted against enemies within his own department, Bosch must make the agonizing choice between justice and vengeance, as he tracks down a killer whose true face will shock him. The Black Echo won the Edgar Award for Best First Mystery Novel awarded by the Mystery Writers of America.", Adaptations: []Adaptation{{Description: "Bosch S03", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}}},
+			want: Book{ID: "random-id", Slug: "the-black-ecko", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 3},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
-			tt.setup(mockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
 
-			r := NewRepository(mockDynamoDBClient, "table-name")
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
 
-			got, err := r.Save(ctx, Book{
-				Title:       "The Black Echo",
-				Year:        1992,
-				Blurb:       "For LAPD homicide cop Harry Bosch — hero, maverick, nighthawk — the body in the drainpipe at Mulholland dam is more than another anonymous statistic.  This one is personal. The dead man, Billy Meadows, was a fellow Vietnam “tunnel rat” who fought side by side with him in a nightmare underground war that brought them to the depths of hell.  Now, Bosch is about to relive the horrors of Nam.  From a dangerous maze of blind alleys to a daring criminal heist beneath the city to the tortuous link that must be uncovered, his survival instincts will once again be tested to their limit. Joining with an enigmatic female FBI agent, pitted against enemies within his own department, Bosch must make the agonizing choice between justice and vengeance, as he tracks down a killer whose true face will shock him. The Black Echo won the Edgar Award for Best First Mystery Novel awarded by the Mystery Writers of America.",
-				Adaptations: []Adaptation{{Description: "Bosch S03", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}},
-			})
+			got, err := r.Update(ctx, Book{ID: "random-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: 2})
 
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
 			mockDynamoDBClient.AssertExpectations(t)
+			mockAdaptations.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_UpdateAnyVersion(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	mockAdaptations := new(AdaptationsMock)
+	item := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "random-id"},
+		"entity":  &types.AttributeValueMemberS{Value: "book"},
+		"slug":    &types.AttributeValueMemberS{Value: "the-black-ecko"},
+		"title":   &types.AttributeValueMemberS{Value: "The Black Echo"},
+		"blurb":   &types.AttributeValueMemberS{Value: "a random blurb"},
+		"year":    &types.AttributeValueMemberN{Value: "1992"},
+		"version": &types.AttributeValueMemberN{Value: "4"},
+	}
+	claim := []dynamo.Write{dynamo.ClaimSlugWrite("table-name", "the-black-ecko", "random-id")}
+	current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-ecko"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}, "version": &types.AttributeValueMemberN{Value: "4"}}
+	mockDynamoDBClient.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+	mockDynamoDBClient.On("Update", ctx, "table-name", "random-id", 4, item, "The Black Echo", "The Black Echo", claim).Return(nil).Once()
+	mockAdaptations.On("GetByBooks", ctx, []string{"random-id"}).Return(map[string][]Adaptation{}, nil).Once()
+	r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
+
+	got, err := r.Update(ctx, Book{ID: "random-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Version: dynamo.AnyVersion})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, got.Version)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *AdaptationsMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get current book",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("GetByID", ctx, "table-name", "random-id").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to delete book",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-echo"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}}
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Delete", ctx, "table-name", "random-id", "The Black Echo", []dynamo.Write{dynamo.ReleaseSlugWrite("table-name", "the-black-echo", "random-id")}).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when book is still referenced",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-echo"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}}
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Delete", ctx, "table-name", "random-id", "The Black Echo", []dynamo.Write{dynamo.ReleaseSlugWrite("table-name", "the-black-echo", "random-id")}).Return(fmt.Errorf("%w. id: %s", dynamo.ErrReferenced, "random-id")).Once()
+			},
+			wantErr: &apperr.ReferencedError{Resource: "book", ID: "random-id"},
+		},
+		{
+			name: "when successfully deleted book",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-echo"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}}
+				m.On("GetByID", ctx, "table-name", "random-id").Return(current, nil).Once()
+				m.On("Delete", ctx, "table-name", "random-id", "The Black Echo", []dynamo.Write{dynamo.ReleaseSlugWrite("table-name", "the-black-echo", "random-id")}).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
+
+			err := r.Delete(ctx, "random-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			mockAdaptations.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_MigrateAdaptations(t *testing.T) {
+	ctx := context.Background()
+	legacyItem := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "book-id"},
+		"title": &types.AttributeValueMemberS{Value: "The Black Echo"},
+		"adaptations": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"description": &types.AttributeValueMemberS{Value: "Bosch S03"},
+				"imdb":        &types.AttributeValueMemberS{Value: "https://www.imdb.com/title/tt3502248/episodes/?season=3"},
+			}}}},
+		"version": &types.AttributeValueMemberN{Value: "2"},
+	}
+	migratedItem := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "book-id"},
+		"entity":  &types.AttributeValueMemberS{Value: ""},
+		"title":   &types.AttributeValueMemberS{Value: "The Black Echo"},
+		"blurb":   &types.AttributeValueMemberS{Value: ""},
+		"year":    &types.AttributeValueMemberN{Value: "0"},
+		"version": &types.AttributeValueMemberN{Value: "2"},
+	}
+	legacy := []Adaptation{{Description: "Bosch S03", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *AdaptationImporterMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get all books",
+			setup: func(m *MockDynamoDBClient, i *AdaptationImporterMock) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to import adaptations",
+			setup: func(m *MockDynamoDBClient, i *AdaptationImporterMock) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{legacyItem}, nil).Once()
+				i.On("Import", ctx, "book-id", legacy).Return(assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. book: %s", assert.AnError, "book-id"),
+		},
+		{
+			name: "when successfully migrated adaptations",
+			setup: func(m *MockDynamoDBClient, i *AdaptationImporterMock) {
+				migrated := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "other-id"}, "title": &types.AttributeValueMemberS{Value: "The Black Ice"}}
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{legacyItem, migrated}, nil).Once()
+				i.On("Import", ctx, "book-id", legacy).Return(nil).Once()
+				m.On("Update", ctx, "table-name", "book-id", 2, migratedItem, "The Black Echo", "The Black Echo", []dynamo.Write(nil)).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockImporter := new(AdaptationImporterMock)
+			tt.setup(mockDynamoDBClient, mockImporter)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, new(AdaptationsMock))
+			err := r.MigrateAdaptations(ctx, mockImporter)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			mockImporter.AssertExpectations(t)
 		})
 	}
 }
@@ -103,20 +392,20 @@ func TestRepository_GetById(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
 		name    string
-		setup   func(*MockDynamoDBClient)
+		setup   func(*MockDynamoDBClient, *AdaptationsMock)
 		want    Book
 		wantErr error
 	}{
 		{
 			name: "when failed to get book by id",
-			setup: func(m *MockDynamoDBClient) {
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
 				m.On("GetByID", ctx, "table-name", "random-id").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
 			},
 			wantErr: assert.AnError,
 		},
 		{
 			name: "when failed to unmarshal book",
-			setup: func(m *MockDynamoDBClient) {
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
 				item := map[string]types.AttributeValue{"title": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}}
 				m.On("GetByID", ctx, "table-name", "random-id").Return(item, nil).Once()
 			},
@@ -124,32 +413,37 @@ func TestRepository_GetById(t *testing.T) {
 		},
 		{
 			name: "when successfully get book by id",
-			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{
-					"id": &types.AttributeValueMemberS{Value: "random-id"},
-					"adaptations": &types.AttributeValueMemberL{Value: []types.AttributeValue{
-						&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
-							"description": &types.AttributeValueMemberS{Value: "Bosch S03"},
-							"imdb":        &types.AttributeValueMemberS{Value: "https://www.imdb.com/title/tt3502248/episodes/?season=3"},
-						}}}},
-				}
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}
+				m.On("GetByID", ctx, "table-name", "random-id").Return(item, nil).Once()
+				a.On("GetByBooks", ctx, []string{"random-id"}).Return(map[string][]Adaptation{"random-id": {{ID: "adaptation-id", Description: "Bosch S03", Type: "season"}}}, nil).Once()
+			},
+			want: Book{ID: "random-id", Adaptations: []Adaptation{{ID: "adaptation-id", Description: "Bosch S03", Type: "season"}}},
+		},
+		{
+			name: "when failed to get adaptations",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}
 				m.On("GetByID", ctx, "table-name", "random-id").Return(item, nil).Once()
+				a.On("GetByBooks", ctx, []string{"random-id"}).Return(map[string][]Adaptation{}, assert.AnError).Once()
 			},
-			want: Book{ID: "random-id", Title: "", Year: 0, Blurb: "", Adaptations: []Adaptation{{Description: "Bosch S03", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}}},
+			wantErr: assert.AnError,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
-			tt.setup(mockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
 
-			r := NewRepository(mockDynamoDBClient, "table-name")
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
 
 			got, err := r.GetById(ctx, "random-id")
 
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
 			mockDynamoDBClient.AssertExpectations(t)
+			mockAdaptations.AssertExpectations(t)
 		})
 	}
 }
@@ -158,20 +452,20 @@ func TestRepository_GetByTitle(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
 		name    string
-		setup   func(*MockDynamoDBClient)
-		want    []Book
+		setup   func(*MockDynamoDBClient, *AdaptationsMock)
+		want    Book
 		wantErr error
 	}{
 		{
 			name: "when failed to get book by title",
-			setup: func(m *MockDynamoDBClient) {
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
 				m.On("GetByUniqueKey", ctx, "table-name", "The Black Echo").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
 			},
 			wantErr: assert.AnError,
 		},
 		{
 			name: "when failed to unmarshal book",
-			setup: func(m *MockDynamoDBClient) {
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
 				output := map[string]types.AttributeValue{"title": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}}
 				m.On("GetByUniqueKey", ctx, "table-name", "The Black Echo").Return(output, nil).Once()
 			},
@@ -179,9 +473,142 @@ func TestRepository_GetByTitle(t *testing.T) {
 		},
 		{
 			name: "when successfully get book by title",
-			setup: func(m *MockDynamoDBClient) {
-				output := map[string]types.AttributeValue{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				output := map[string]types.AttributeValue{"slug": &types.AttributeValueMemberS{Value: "the-black-echo"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}}
 				m.On("GetByUniqueKey", ctx, "table-name", "The Black Echo").Return(output, nil).Once()
+				a.On("GetByBooks", ctx, []string{""}).Return(map[string][]Adaptation{}, nil).Once()
+			},
+			want: Book{Slug: "the-black-echo", Title: "The Black Echo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
+			got, err := r.GetByTitle(ctx, "The Black Echo")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestRepository_GetBySlug(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *AdaptationsMock)
+		want    Book
+		wantErr error
+	}{
+		{
+			name: "when failed to get book by slug",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("GetBySlug", ctx, "table-name", "the-black-echo").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get book by slug",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				output := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "slug": &types.AttributeValueMemberS{Value: "the-black-echo"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo Reissued"}}
+				m.On("GetBySlug", ctx, "table-name", "the-black-echo").Return(output, nil).Once()
+				a.On("GetByBooks", ctx, []string{"random-id"}).Return(map[string][]Adaptation{}, nil).Once()
+			},
+			want: Book{ID: "random-id", Slug: "the-black-echo", Title: "The Black Echo Reissued"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
+			got, err := r.GetBySlug(ctx, "the-black-echo")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestRepository_GetByIds(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *AdaptationsMock)
+		want    []Book
+		wantErr error
+	}{
+		{
+			name: "when failed to get books by ids",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("BatchGetFound", ctx, "table-name", []string{"book-id-1", "book-id-2"}).Return([]map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to unmarshal book",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				output := []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}}}
+				m.On("BatchGetFound", ctx, "table-name", []string{"book-id-1", "book-id-2"}).Return(output, nil).Once()
+			},
+			wantErr: fmt.Errorf("failed to unmarshal book: %w", &attributevalue.UnmarshalTypeError{Value: "map", Type: reflect.TypeOf("string")}),
+		},
+		{
+			name: "when successfully get books by ids",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				output := []map[string]types.AttributeValue{
+					{"id": &types.AttributeValueMemberS{Value: "book-id-1"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}},
+					{"id": &types.AttributeValueMemberS{Value: "book-id-2"}, "title": &types.AttributeValueMemberS{Value: "The Black Ice"}},
+				}
+				m.On("BatchGetFound", ctx, "table-name", []string{"book-id-1", "book-id-2"}).Return(output, nil).Once()
+				a.On("GetByBooks", ctx, []string{"book-id-1", "book-id-2"}).Return(map[string][]Adaptation{"book-id-2": {{ID: "adaptation-id", Description: "Bosch S01"}}}, nil).Once()
+			},
+			want: []Book{{ID: "book-id-1", Title: "The Black Echo"}, {ID: "book-id-2", Title: "The Black Ice", Adaptations: []Adaptation{{ID: "adaptation-id", Description: "Bosch S01"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
+			got, err := r.GetByIds(ctx, []string{"book-id-1", "book-id-2"})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			mockAdaptations.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetBookListByTitles(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *AdaptationsMock)
+		want    []Book
+		wantErr error
+	}{
+		{
+			name: "when failed to get books by titles",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("BatchGetByUniqueKeys", ctx, "table-name", []string{"The Black Echo"}).Return([]map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get books by titles",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				output := []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}}
+				m.On("BatchGetByUniqueKeys", ctx, "table-name", []string{"The Black Echo"}).Return(output, nil).Once()
 			},
 			want: []Book{{Title: "The Black Echo"}},
 		},
@@ -189,13 +616,16 @@ func TestRepository_GetByTitle(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
-			tt.setup(mockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
 
-			r := NewRepository(mockDynamoDBClient, "table-name")
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
 			got, err := r.GetBookListByTitles(ctx, []string{"The Black Echo"})
 
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			mockAdaptations.AssertExpectations(t)
 		})
 	}
 }
@@ -204,13 +634,13 @@ func TestRepository_GetAll(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
 		name    string
-		setup   func(*MockDynamoDBClient)
+		setup   func(*MockDynamoDBClient, *AdaptationsMock)
 		want    []Book
 		wantErr error
 	}{
 		{
 			name: "when failed to get all books",
-			setup: func(m *MockDynamoDBClient) {
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
 				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{}, assert.AnError).Once()
 			},
 			want:    []Book{},
@@ -218,7 +648,7 @@ func TestRepository_GetAll(t *testing.T) {
 		},
 		{
 			name: "when failed to unmarshal book",
-			setup: func(m *MockDynamoDBClient) {
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
 				output := []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}}}
 				m.On("GetAll", ctx, "table-name").Return(output, nil).Once()
 			},
@@ -227,9 +657,10 @@ func TestRepository_GetAll(t *testing.T) {
 		},
 		{
 			name: "when successfully get all books",
-			setup: func(m *MockDynamoDBClient) {
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
 				output := []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}}
 				m.On("GetAll", ctx, "table-name").Return(output, nil).Once()
+				a.On("GetByBooks", ctx, []string{""}).Return(map[string][]Adaptation{}, nil).Once()
 			},
 			want: []Book{{Title: "The Black Echo"}},
 		},
@@ -237,9 +668,10 @@ func TestRepository_GetAll(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
-			tt.setup(mockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
 
-			r := NewRepository(mockDynamoDBClient, "table-name")
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
 			got, err := r.GetAll(ctx)
 
 			assert.Equal(t, tt.want, got)
@@ -248,16 +680,160 @@ func TestRepository_GetAll(t *testing.T) {
 	}
 }
 
+func TestRepository_GetPage(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		setup      func(*MockDynamoDBClient, *AdaptationsMock)
+		want       []Book
+		wantCursor string
+		wantErr    error
+	}{
+		{
+			name: "when failed to get page of books",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("GetPage", ctx, "table-name", int32(10), "a-cursor").Return([]map[string]types.AttributeValue{}, "", assert.AnError).Once()
+			},
+			want:    []Book{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to unmarshal book",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				output := []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}}}
+				m.On("GetPage", ctx, "table-name", int32(10), "a-cursor").Return(output, "next-cursor", nil).Once()
+			},
+			want:    []Book{},
+			wantErr: fmt.Errorf("failed to unmarshal book: %w", &attributevalue.UnmarshalTypeError{Value: "map", Type: reflect.TypeOf("string")}),
+		},
+		{
+			name: "when successfully get page of books",
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				output := []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}}
+				m.On("GetPage", ctx, "table-name", int32(10), "a-cursor").Return(output, "next-cursor", nil).Once()
+				a.On("GetByBooks", ctx, []string{""}).Return(map[string][]Adaptation{}, nil).Once()
+			},
+			want:       []Book{{Title: "The Black Echo"}},
+			wantCursor: "next-cursor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
+			got, gotCursor, err := r.GetPage(ctx, 10, "a-cursor")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCursor, gotCursor)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			mockAdaptations.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByYearRange(t *testing.T) {
+	ctx := context.Background()
+	query := dynamo.Query{
+		IndexName: "year-index",
+		HashKey:   "entity",
+		HashValue: &types.AttributeValueMemberS{Value: "book"},
+		RangeKey:  "year",
+		From:      &types.AttributeValueMemberN{Value: "2000"},
+		To:        &types.AttributeValueMemberN{Value: "2009"},
+	}
+	tests := []struct {
+		name     string
+		yearFrom int
+		yearTo   int
+		setup    func(*MockDynamoDBClient, *AdaptationsMock)
+		want     []Book
+		wantErr  error
+	}{
+		{
+			name:     "when failed to query books",
+			yearFrom: 2000,
+			yearTo:   2009,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				m.On("Query", ctx, "table-name", query).Return([]map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			want:    []Book{},
+			wantErr: assert.AnError,
+		},
+		{
+			name:     "when successfully query books in range",
+			yearFrom: 2000,
+			yearTo:   2009,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				output := []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Closers"}, "year": &types.AttributeValueMemberN{Value: "2005"}}}
+				m.On("Query", ctx, "table-name", query).Return(output, nil).Once()
+				a.On("GetByBooks", ctx, []string{""}).Return(map[string][]Adaptation{}, nil).Once()
+			},
+			want: []Book{{Title: "The Closers", Year: 2005}},
+		},
+		{
+			name:     "when successfully query books from a year",
+			yearFrom: 2000,
+			setup: func(m *MockDynamoDBClient, a *AdaptationsMock) {
+				fromQuery := query
+				fromQuery.To = nil
+				output := []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Closers"}, "year": &types.AttributeValueMemberN{Value: "2005"}}}
+				m.On("Query", ctx, "table-name", fromQuery).Return(output, nil).Once()
+				a.On("GetByBooks", ctx, []string{""}).Return(map[string][]Adaptation{}, nil).Once()
+			},
+			want: []Book{{Title: "The Closers", Year: 2005}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockAdaptations := new(AdaptationsMock)
+			tt.setup(mockDynamoDBClient, mockAdaptations)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, mockAdaptations)
+			got, err := r.GetByYearRange(ctx, tt.yearFrom, tt.yearTo)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			mockAdaptations.AssertExpectations(t)
+		})
+	}
+}
+
 type MockDynamoDBClient struct {
 	DynamoDBClient
 	mock.Mock
 }
 
-func (m *MockDynamoDBClient) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string) (string, error) {
-	args := m.Called(ctx, tableName, item, uniqueKey)
+func (m *MockDynamoDBClient) NewID() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockDynamoDBClient) GetBySlug(ctx context.Context, tableName string, slug string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, slug)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error) {
+	args := m.Called(ctx, tableName, item, uniqueKey, writes)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockDynamoDBClient) Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, version, item, oldUniqueKey, newUniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, uniqueKey, writes)
+	return args.Error(0)
+}
+
 func (m *MockDynamoDBClient) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
 	args := m.Called(ctx, tableName, id)
 	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
@@ -272,3 +848,41 @@ func (m *MockDynamoDBClient) GetAll(ctx context.Context, tableName string) ([]ma
 	args := m.Called(ctx, tableName)
 	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
 }
+
+func (m *MockDynamoDBClient) GetPage(ctx context.Context, tableName string, limit int32, cursor string) ([]map[string]types.AttributeValue, string, error) {
+	args := m.Called(ctx, tableName, limit, cursor)
+	return args.Get(0).([]map[string]types.AttributeValue), args.String(1), args.Error(2)
+}
+
+func (m *MockDynamoDBClient) Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, query)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) BatchGetFound(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, ids)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) BatchGetByUniqueKeys(ctx context.Context, tableName string, values []string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, values)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+type AdaptationsMock struct {
+	mock.Mock
+}
+
+func (m *AdaptationsMock) GetByBooks(ctx context.Context, bookIDs []string) (map[string][]Adaptation, error) {
+	args := m.Called(ctx, bookIDs)
+	return args.Get(0).(map[string][]Adaptation), args.Error(1)
+}
+
+type AdaptationImporterMock struct {
+	mock.Mock
+}
+
+func (m *AdaptationImporterMock) Import(ctx context.Context, bookID string, legacy []Adaptation) error {
+	args := m.Called(ctx, bookID, legacy)
+	return args.Error(0)
+}
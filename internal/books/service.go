@@ -1,29 +1,90 @@
 package books
 
-import "context"
+import (
+	"context"
+
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+	"github.com/ggoulart/michael-connelly-api/internal/search"
+)
+
+const searchDocumentType = "book"
 
 type StorageBook interface {
-	Save(ctx context.Context, book Book) (Book, error)
+	Save(ctx context.Context, book Book) (Book, bool, error)
+	Update(ctx context.Context, book Book) (Book, error)
+	Delete(ctx context.Context, bookID string) error
 	GetById(ctx context.Context, bookID string) (Book, error)
 	GetByTitle(ctx context.Context, bookTitle string) (Book, error)
+	GetBySlug(ctx context.Context, slug string) (Book, error)
 	GetAll(ctx context.Context) ([]Book, error)
+	GetPage(ctx context.Context, limit int32, cursor string) ([]Book, string, error)
+	GetByYearRange(ctx context.Context, yearFrom int, yearTo int) ([]Book, error)
+}
+
+type Referrer interface {
+	HasBook(ctx context.Context, bookID string) (bool, error)
+	RemoveBook(ctx context.Context, bookID string) error
 }
 
 type Service struct {
 	storageBook StorageBook
+	indexer     search.Indexer
+	referrers   []Referrer
 }
 
-func NewService(storageBook StorageBook) *Service {
-	return &Service{storageBook: storageBook}
+func NewService(storageBook StorageBook, indexer search.Indexer, referrers ...Referrer) *Service {
+	return &Service{storageBook: storageBook, indexer: indexer, referrers: referrers}
 }
 
-func (s *Service) Create(ctx context.Context, book Book) (Book, error) {
-	savedBook, err := s.storageBook.Save(ctx, book)
+func (s *Service) Create(ctx context.Context, book Book) (Book, bool, error) {
+	savedBook, created, err := s.storageBook.Save(ctx, book)
+	if err != nil {
+		return Book{}, false, err
+	}
+
+	s.indexer.Put(newSearchDocument(savedBook))
+
+	return savedBook, created, nil
+}
+
+func (s *Service) Update(ctx context.Context, book Book) (Book, error) {
+	updatedBook, err := s.storageBook.Update(ctx, book)
 	if err != nil {
 		return Book{}, err
 	}
 
-	return savedBook, nil
+	s.indexer.Put(newSearchDocument(updatedBook))
+
+	return updatedBook, nil
+}
+
+func (s *Service) Delete(ctx context.Context, bookID string, cascade bool) error {
+	for _, referrer := range s.referrers {
+		if cascade {
+			err := referrer.RemoveBook(ctx, bookID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		referenced, err := referrer.HasBook(ctx, bookID)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return &apperr.ReferencedError{Resource: "book", ID: bookID}
+		}
+	}
+
+	err := s.storageBook.Delete(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	s.indexer.Remove(searchDocumentType, bookID)
+
+	return nil
 }
 
 func (s *Service) GetById(ctx context.Context, bookID string) (Book, error) {
@@ -44,6 +105,15 @@ func (s *Service) GetByTitle(ctx context.Context, bookTitle string) (Book, error
 	return book, nil
 }
 
+func (s *Service) GetBySlug(ctx context.Context, slug string) (Book, error) {
+	book, err := s.storageBook.GetBySlug(ctx, slug)
+	if err != nil {
+		return Book{}, err
+	}
+
+	return book, nil
+}
+
 func (s *Service) GetAll(ctx context.Context) ([]Book, error) {
 	books, err := s.storageBook.GetAll(ctx)
 	if err != nil {
@@ -52,3 +122,39 @@ func (s *Service) GetAll(ctx context.Context) ([]Book, error) {
 
 	return books, nil
 }
+
+func (s *Service) GetPage(ctx context.Context, limit int32, cursor string) ([]Book, string, error) {
+	books, nextCursor, err := s.storageBook.GetPage(ctx, limit, cursor)
+	if err != nil {
+		return []Book{}, "", err
+	}
+
+	return books, nextCursor, nil
+}
+
+func (s *Service) GetByYearRange(ctx context.Context, yearFrom int, yearTo int) ([]Book, error) {
+	books, err := s.storageBook.GetByYearRange(ctx, yearFrom, yearTo)
+	if err != nil {
+		return []Book{}, err
+	}
+
+	return books, nil
+}
+
+func (s *Service) Documents(ctx context.Context) ([]search.Document, error) {
+	books, err := s.storageBook.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []search.Document
+	for _, book := range books {
+		documents = append(documents, newSearchDocument(book))
+	}
+
+	return documents, nil
+}
+
+func newSearchDocument(book Book) search.Document {
+	return search.Document{Type: searchDocumentType, ID: book.ID, Title: book.Title, Body: book.Blurb}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+	"github.com/ggoulart/michael-connelly-api/internal/search"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,38 +14,170 @@ func TestService_Create(t *testing.T) {
 	ctx := context.Background()
 	receivedBook := Book{Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}
 	tests := []struct {
-		name    string
-		setup   func(s *StorageMock)
-		want    Book
-		wantErr error
+		name        string
+		setup       func(*StorageMock, *IndexerMock)
+		want        Book
+		wantCreated bool
+		wantErr     error
 	}{
 		{
 			name: "failed to save book",
-			setup: func(s *StorageMock) {
-				s.On("Save", ctx, receivedBook).Return(Book{}, assert.AnError)
+			setup: func(s *StorageMock, _ *IndexerMock) {
+				s.On("Save", ctx, receivedBook).Return(Book{}, false, assert.AnError)
 			},
 			wantErr: assert.AnError,
 		},
+		{
+			name: "successfully returned existing book",
+			setup: func(s *StorageMock, i *IndexerMock) {
+				existingBook := Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Year: 1992, Blurb: "an old blurb"}
+				s.On("Save", ctx, receivedBook).Return(existingBook, false, nil)
+				i.On("Put", search.Document{Type: "book", ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Body: "an old blurb"})
+			},
+			want: Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Year: 1992, Blurb: "an old blurb"},
+		},
 		{
 			name: "successfully saved book",
-			setup: func(s *StorageMock) {
+			setup: func(s *StorageMock, i *IndexerMock) {
 				savedBook := Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}
-				s.On("Save", ctx, receivedBook).Return(savedBook, nil)
+				s.On("Save", ctx, receivedBook).Return(savedBook, true, nil)
+				i.On("Put", search.Document{Type: "book", ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Body: "a random blurb"})
 			},
-			want: Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"},
+			want:        Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"},
+			wantCreated: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			storage := new(StorageMock)
-			tt.setup(storage)
+			indexer := new(IndexerMock)
+			tt.setup(storage, indexer)
+
+			s := NewService(storage, indexer)
+
+			got, created, err := s.Create(context.Background(), receivedBook)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			indexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	ctx := context.Background()
+	receivedBook := Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}
+	tests := []struct {
+		name    string
+		setup   func(*StorageMock, *IndexerMock)
+		want    Book
+		wantErr error
+	}{
+		{
+			name: "failed to update book",
+			setup: func(s *StorageMock, _ *IndexerMock) {
+				s.On("Update", ctx, receivedBook).Return(Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "successfully updated book",
+			setup: func(s *StorageMock, i *IndexerMock) {
+				s.On("Update", ctx, receivedBook).Return(receivedBook, nil)
+				i.On("Put", search.Document{Type: "book", ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Body: "a random blurb"})
+			},
+			want: receivedBook,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := new(StorageMock)
+			indexer := new(IndexerMock)
+			tt.setup(storage, indexer)
 
-			s := NewService(storage)
+			s := NewService(storage, indexer)
 
-			got, err := s.Create(context.Background(), receivedBook)
+			got, err := s.Update(ctx, receivedBook)
 
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
+			indexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		cascade bool
+		setup   func(*StorageMock, *ReferrerMock, *IndexerMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to check references",
+			setup: func(_ *StorageMock, r *ReferrerMock, _ *IndexerMock) {
+				r.On("HasBook", ctx, "a-book-id").Return(false, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when book is still referenced",
+			setup: func(_ *StorageMock, r *ReferrerMock, _ *IndexerMock) {
+				r.On("HasBook", ctx, "a-book-id").Return(true, nil)
+			},
+			wantErr: &apperr.ReferencedError{Resource: "book", ID: "a-book-id"},
+		},
+		{
+			name:    "when failed to remove references on cascade",
+			cascade: true,
+			setup: func(_ *StorageMock, r *ReferrerMock, _ *IndexerMock) {
+				r.On("RemoveBook", ctx, "a-book-id").Return(assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to delete book",
+			setup: func(s *StorageMock, r *ReferrerMock, _ *IndexerMock) {
+				r.On("HasBook", ctx, "a-book-id").Return(false, nil)
+				s.On("Delete", ctx, "a-book-id").Return(assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:    "when successfully deleted book on cascade",
+			cascade: true,
+			setup: func(s *StorageMock, r *ReferrerMock, i *IndexerMock) {
+				r.On("RemoveBook", ctx, "a-book-id").Return(nil)
+				s.On("Delete", ctx, "a-book-id").Return(nil)
+				i.On("Remove", "book", "a-book-id")
+			},
+		},
+		{
+			name: "when successfully deleted book without references",
+			setup: func(s *StorageMock, r *ReferrerMock, i *IndexerMock) {
+				r.On("HasBook", ctx, "a-book-id").Return(false, nil)
+				s.On("Delete", ctx, "a-book-id").Return(nil)
+				i.On("Remove", "book", "a-book-id")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := new(StorageMock)
+			referrer := new(ReferrerMock)
+			indexer := new(IndexerMock)
+			tt.setup(storage, referrer, indexer)
+
+			s := NewService(storage, indexer, referrer)
+
+			err := s.Delete(ctx, "a-book-id", tt.cascade)
+
+			assert.Equal(t, tt.wantErr, err)
+			storage.AssertExpectations(t)
+			referrer.AssertExpectations(t)
+			indexer.AssertExpectations(t)
 		})
 	}
 }
@@ -77,7 +211,7 @@ func TestService_GetById(t *testing.T) {
 			storage := new(StorageMock)
 			tt.setup(storage)
 
-			s := NewService(storage)
+			s := NewService(storage, new(IndexerMock))
 
 			got, err := s.GetById(context.Background(), "a-random-book-id")
 
@@ -116,7 +250,7 @@ func TestService_GetByTitle(t *testing.T) {
 			storage := new(StorageMock)
 			tt.setup(storage)
 
-			s := NewService(storage)
+			s := NewService(storage, new(IndexerMock))
 
 			got, err := s.GetByTitle(ctx, "The Black Echo")
 
@@ -126,6 +260,45 @@ func TestService_GetByTitle(t *testing.T) {
 	}
 }
 
+func TestService_GetBySlug(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(s *StorageMock)
+		want    Book
+		wantErr error
+	}{
+		{
+			name: "failed to get book",
+			setup: func(s *StorageMock) {
+				s.On("GetBySlug", ctx, "the-black-echo").Return(Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "successfully get book",
+			setup: func(s *StorageMock) {
+				returnedBook := Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "the-black-echo", Title: "The Black Echo", Year: 1992}
+				s.On("GetBySlug", ctx, "the-black-echo").Return(returnedBook, nil)
+			},
+			want: Book{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "the-black-echo", Title: "The Black Echo", Year: 1992},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := new(StorageMock)
+			tt.setup(storage)
+
+			s := NewService(storage, new(IndexerMock))
+
+			got, err := s.GetBySlug(ctx, "the-black-echo")
+
+			assert.Equal(t, got, tt.want)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
 func TestService_GetAll(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
@@ -155,7 +328,7 @@ func TestService_GetAll(t *testing.T) {
 			storage := new(StorageMock)
 			tt.setup(storage)
 
-			s := NewService(storage)
+			s := NewService(storage, new(IndexerMock))
 
 			got, err := s.GetAll(ctx)
 
@@ -165,16 +338,145 @@ func TestService_GetAll(t *testing.T) {
 	}
 }
 
+func TestService_GetPage(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		setup      func(s *StorageMock)
+		want       []Book
+		wantCursor string
+		wantErr    error
+	}{
+		{
+			name: "failed to get page of books",
+			setup: func(s *StorageMock) {
+				s.On("GetPage", ctx, int32(10), "a-cursor").Return([]Book{}, "", assert.AnError)
+			},
+			want:    []Book{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "successfully get page of books",
+			setup: func(s *StorageMock) {
+				s.On("GetPage", ctx, int32(10), "a-cursor").Return([]Book{{Title: "The Black Echo"}}, "next-cursor", nil)
+			},
+			want:       []Book{{Title: "The Black Echo"}},
+			wantCursor: "next-cursor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := new(StorageMock)
+			tt.setup(storage)
+
+			s := NewService(storage, new(IndexerMock))
+
+			got, gotCursor, err := s.GetPage(ctx, 10, "a-cursor")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCursor, gotCursor)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_GetByYearRange(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(s *StorageMock)
+		want    []Book
+		wantErr error
+	}{
+		{
+			name: "failed to get books by year range",
+			setup: func(s *StorageMock) {
+				s.On("GetByYearRange", ctx, 2000, 2009).Return([]Book{}, assert.AnError)
+			},
+			want:    []Book{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "successfully get books by year range",
+			setup: func(s *StorageMock) {
+				s.On("GetByYearRange", ctx, 2000, 2009).Return([]Book{{Title: "The Closers", Year: 2005}}, nil)
+			},
+			want: []Book{{Title: "The Closers", Year: 2005}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := new(StorageMock)
+			tt.setup(storage)
+
+			s := NewService(storage, new(IndexerMock))
+
+			got, err := s.GetByYearRange(ctx, 2000, 2009)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_Documents(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(s *StorageMock)
+		want    []search.Document
+		wantErr error
+	}{
+		{
+			name: "failed to get all books",
+			setup: func(s *StorageMock) {
+				s.On("GetAll", ctx).Return([]Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "successfully get search documents",
+			setup: func(s *StorageMock) {
+				s.On("GetAll", ctx).Return([]Book{{ID: "a-book-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}}, nil)
+			},
+			want: []search.Document{{Type: "book", ID: "a-book-id", Title: "The Black Echo", Body: "a random blurb"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := new(StorageMock)
+			tt.setup(storage)
+
+			s := NewService(storage, new(IndexerMock))
+
+			got, err := s.Documents(ctx)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
 type StorageMock struct {
 	StorageBook
 	mock.Mock
 }
 
-func (s *StorageMock) Save(ctx context.Context, book Book) (Book, error) {
+func (s *StorageMock) Save(ctx context.Context, book Book) (Book, bool, error) {
+	args := s.Called(ctx, book)
+	return args.Get(0).(Book), args.Bool(1), args.Error(2)
+}
+
+func (s *StorageMock) Update(ctx context.Context, book Book) (Book, error) {
 	args := s.Called(ctx, book)
 	return args.Get(0).(Book), args.Error(1)
 }
 
+func (s *StorageMock) Delete(ctx context.Context, bookID string) error {
+	args := s.Called(ctx, bookID)
+	return args.Error(0)
+}
+
 func (s *StorageMock) GetById(ctx context.Context, bookID string) (Book, error) {
 	args := s.Called(ctx, bookID)
 	return args.Get(0).(Book), args.Error(1)
@@ -185,7 +487,48 @@ func (s *StorageMock) GetByTitle(ctx context.Context, bookTitle string) (Book, e
 	return args.Get(0).(Book), args.Error(1)
 }
 
+func (s *StorageMock) GetBySlug(ctx context.Context, slug string) (Book, error) {
+	args := s.Called(ctx, slug)
+	return args.Get(0).(Book), args.Error(1)
+}
+
 func (s *StorageMock) GetAll(ctx context.Context) ([]Book, error) {
 	args := s.Called(ctx)
 	return args.Get(0).([]Book), args.Error(1)
 }
+
+func (s *StorageMock) GetPage(ctx context.Context, limit int32, cursor string) ([]Book, string, error) {
+	args := s.Called(ctx, limit, cursor)
+	return args.Get(0).([]Book), args.String(1), args.Error(2)
+}
+
+func (s *StorageMock) GetByYearRange(ctx context.Context, yearFrom int, yearTo int) ([]Book, error) {
+	args := s.Called(ctx, yearFrom, yearTo)
+	return args.Get(0).([]Book), args.Error(1)
+}
+
+type ReferrerMock struct {
+	mock.Mock
+}
+
+func (r *ReferrerMock) HasBook(ctx context.Context, bookID string) (bool, error) {
+	args := r.Called(ctx, bookID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *ReferrerMock) RemoveBook(ctx context.Context, bookID string) error {
+	args := r.Called(ctx, bookID)
+	return args.Error(0)
+}
+
+type IndexerMock struct {
+	mock.Mock
+}
+
+func (i *IndexerMock) Put(document search.Document) {
+	i.Called(document)
+}
+
+func (i *IndexerMock) Remove(documentType string, id string) {
+	i.Called(documentType, id)
+}
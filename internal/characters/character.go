@@ -1,17 +1,56 @@
 package characters
 
 import (
+	"strings"
+
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
 	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
 )
 
 type Character struct {
-	ID     string
+	ID      string
+	Name    string
+	Books   []Appearance
+	Actors  []actors.Actor
+	Version int
+}
+
+type Role string
+
+const (
+	RoleProtagonist Role = "protagonist"
+	RoleSupporting  Role = "supporting"
+	RoleCameo       Role = "cameo"
+	RoleMentioned   Role = "mentioned"
+)
+
+type Appearance struct {
+	books.Book
+	Role Role
+	Note string
+}
+
+type Filter struct {
+	BookID string
+	Actor  string
 	Name   string
-	Books  []books.Book
-	Actors []Actor
 }
 
-type Actor struct {
-	Name string
-	IMDB string
+func (f Filter) matches(character Character) bool {
+	if f.Name != "" && !strings.Contains(dynamo.NormalizeKey(character.Name), dynamo.NormalizeKey(f.Name)) {
+		return false
+	}
+
+	if f.Actor == "" {
+		return true
+	}
+
+	for _, actor := range character.Actors {
+		if strings.Contains(dynamo.NormalizeKey(actor.Name), dynamo.NormalizeKey(f.Actor)) {
+			return true
+		}
+	}
+
+	return false
 }
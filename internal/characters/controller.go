@@ -3,17 +3,30 @@ package characters
 import (
 	"context"
 	"net/http"
+	"slices"
+	"strings"
 
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type Manager interface {
-	Create(ctx context.Context, character Character, bookTitles []string) (Character, error)
+	Create(ctx context.Context, character Character, appearances []Appearance) (Character, bool, error)
+	Update(ctx context.Context, character Character, appearances []Appearance) (Character, error)
+	Delete(ctx context.Context, characterID string) error
 	GetById(ctx context.Context, characterID string) (Character, error)
 	GetByName(ctx context.Context, characterName string) (Character, error)
+	GetByBook(ctx context.Context, bookID string) ([]Character, error)
+	List(ctx context.Context, filter Filter, limit int32, cursor string) ([]Character, string, error)
 }
 
+const defaultPageLimit = 20
+
+var ErrActorsReadOnly = &apperr.InvalidError{Reason: "actors are set through adaptation cast"}
+
 type Controller struct {
 	manager Manager
 }
@@ -29,13 +42,116 @@ func (c *Controller) Create(ctx *gin.Context) {
 		return
 	}
 
-	createdCharacter, err := c.manager.Create(ctx, characterDTO.ToCharacter(), characterDTO.BookTitles)
+	if characterDTO.Actors != nil {
+		ctx.Error(ErrActorsReadOnly)
+		return
+	}
+
+	createdCharacter, created, err := c.manager.Create(ctx, characterDTO.ToCharacter(), characterDTO.ToAppearances())
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, NewCharacterDTO(createdCharacter))
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	ctx.Header("ETag", etag.Format(createdCharacter.Version))
+	ctx.JSON(status, NewCharacterDTO(createdCharacter, false))
+}
+
+func (c *Controller) Update(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	version, err := etag.Parse(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var characterDTO CharacterDTO
+	if err := ctx.BindJSON(&characterDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if characterDTO.Actors != nil {
+		ctx.Error(ErrActorsReadOnly)
+		return
+	}
+
+	character := characterDTO.ToCharacter()
+	character.ID = idRequest.CharacterID
+	character.Version = version
+
+	updatedCharacter, err := c.manager.Update(ctx, character, characterDTO.ToAppearances())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(updatedCharacter.Version))
+	ctx.JSON(http.StatusOK, NewCharacterDTO(updatedCharacter, false))
+}
+
+func (c *Controller) Patch(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	version, err := etag.Parse(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var patchCharacterDTO PatchCharacterDTO
+	if err := ctx.BindJSON(&patchCharacterDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	character, err := c.manager.GetById(ctx, idRequest.CharacterID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	character, appearances := patchCharacterDTO.ApplyTo(character)
+	character.Version = version
+
+	updatedCharacter, err := c.manager.Update(ctx, character, appearances)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(updatedCharacter.Version))
+	ctx.JSON(http.StatusOK, NewCharacterDTO(updatedCharacter, false))
+}
+
+func (c *Controller) Delete(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err := c.manager.Delete(ctx, idRequest.CharacterID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
 }
 
 func (c *Controller) GetBy(ctx *gin.Context) {
@@ -45,76 +161,294 @@ func (c *Controller) GetBy(ctx *gin.Context) {
 		return
 	}
 
+	var expandRequest ExpandRequest
+	if err := ctx.BindQuery(&expandRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
 	characterID, err := uuid.Parse(getByRequest.Character)
 	if err != nil {
-		c.getByName(ctx, getByRequest.Character)
+		c.getByName(ctx, getByRequest.Character, expandRequest.Has("books"))
 		return
 	}
 
-	c.getById(ctx, characterID.String())
+	c.getById(ctx, characterID.String(), expandRequest.Has("books"))
 	return
 }
 
-func (c *Controller) getById(ctx *gin.Context, characterID string) {
+func (c *Controller) GetAll(ctx *gin.Context) {
+	var listRequest ListRequest
+	if err := ctx.BindQuery(&listRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	limit := listRequest.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	filter := Filter{BookID: listRequest.Book, Actor: listRequest.Actor, Name: listRequest.Name}
+	characters, nextCursor, err := c.manager.List(ctx, filter, limit, listRequest.Cursor)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	charactersDTO := []CharacterDTO{}
+	for _, character := range characters {
+		charactersDTO = append(charactersDTO, NewCharacterDTO(character, false))
+	}
+
+	ctx.JSON(http.StatusOK, CharactersPageDTO{Items: charactersDTO, NextCursor: nextCursor})
+}
+
+func (c *Controller) GetBooks(ctx *gin.Context) {
+	var getByRequest GetByRequest
+	if err := ctx.BindUri(&getByRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var rolesRequest RolesRequest
+	if err := ctx.BindQuery(&rolesRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	character, err := c.getCharacter(ctx, getByRequest.Character)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	booksDTO := []CharacterBookDTO{}
+	for _, book := range character.Books {
+		if rolesRequest.Role == "" || book.Role == Role(rolesRequest.Role) {
+			booksDTO = append(booksDTO, NewCharacterBookDTO(book, false))
+		}
+	}
+
+	ctx.JSON(http.StatusOK, booksDTO)
+}
+
+func (c *Controller) GetByBook(ctx *gin.Context) {
+	var bookRequest BookRequest
+	if err := ctx.BindUri(&bookRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	characters, err := c.manager.GetByBook(ctx, bookRequest.BookID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	bookCharactersDTO := []BookCharacterDTO{}
+	for _, character := range characters {
+		bookCharactersDTO = append(bookCharactersDTO, NewBookCharacterDTO(character, bookRequest.BookID))
+	}
+
+	ctx.JSON(http.StatusOK, bookCharactersDTO)
+}
+
+func (c *Controller) getCharacter(ctx *gin.Context, character string) (Character, error) {
+	characterID, err := uuid.Parse(character)
+	if err != nil {
+		return c.manager.GetByName(ctx, character)
+	}
+
+	return c.manager.GetById(ctx, characterID.String())
+}
+
+func (c *Controller) getById(ctx *gin.Context, characterID string, expandBooks bool) {
 	character, err := c.manager.GetById(ctx, characterID)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, NewCharacterDTO(character))
+	ctx.Header("ETag", etag.Format(character.Version))
+	ctx.JSON(http.StatusOK, NewCharacterDTO(character, expandBooks))
 }
 
-func (c *Controller) getByName(ctx *gin.Context, characterName string) {
+func (c *Controller) getByName(ctx *gin.Context, characterName string, expandBooks bool) {
 	character, err := c.manager.GetByName(ctx, characterName)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, NewCharacterDTO(character))
+	ctx.Header("ETag", etag.Format(character.Version))
+	ctx.JSON(http.StatusOK, NewCharacterDTO(character, expandBooks))
 }
 
 type CharacterDTO struct {
-	ID         string     `json:"id,omitempty"`
-	Name       string     `json:"name" binding:"required"`
-	Actors     []ActorDTO `json:"actors,omitempty"`
-	BookTitles []string   `json:"bookTitles,omitempty"`
+	ID          string             `json:"id,omitempty"`
+	Name        string             `json:"name" binding:"required"`
+	Actors      []ActorDTO         `json:"actors,omitempty"`
+	BookTitles  []string           `json:"bookTitles,omitempty"`
+	Appearances []AppearanceDTO    `json:"appearances,omitempty" binding:"omitempty,dive"`
+	Books       []CharacterBookDTO `json:"books,omitempty"`
+}
+
+type AppearanceDTO struct {
+	BookTitle string `json:"bookTitle" binding:"required"`
+	Role      string `json:"role,omitempty" binding:"omitempty,oneof=protagonist supporting cameo mentioned"`
+	Note      string `json:"note,omitempty"`
 }
 
 type ActorDTO struct {
-	Name string `json:"name" binding:"required"`
-	IMDB string `json:"imdb" binding:"required"`
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	IMDB string `json:"imdb"`
 }
 
-func NewCharacterDTO(character Character) CharacterDTO {
+type CharacterBookDTO struct {
+	ID          string                `json:"id"`
+	Title       string                `json:"title"`
+	Year        int                   `json:"year"`
+	Role        string                `json:"role,omitempty"`
+	Note        string                `json:"note,omitempty"`
+	Blurb       string                `json:"blurb,omitempty"`
+	Adaptations []books.AdaptationDTO `json:"adaptations,omitempty"`
+}
+
+type CharactersPageDTO struct {
+	Items      []CharacterDTO `json:"items"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+type BookCharacterDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role,omitempty"`
+	Note string `json:"note,omitempty"`
+}
+
+func NewCharacterDTO(character Character, expandBooks bool) CharacterDTO {
 	var booksTitles []string
+	var booksDTO []CharacterBookDTO
 	for _, b := range character.Books {
 		booksTitles = append(booksTitles, b.Title)
+
+		booksDTO = append(booksDTO, NewCharacterBookDTO(b, expandBooks))
+	}
+
+	var actorsDTO []ActorDTO
+	for _, a := range character.Actors {
+		actorsDTO = append(actorsDTO, ActorDTO{ID: a.ID, Name: a.Name, IMDB: a.IMDB})
 	}
 
 	return CharacterDTO{
 		ID:         character.ID,
 		Name:       character.Name,
+		Actors:     actorsDTO,
 		BookTitles: booksTitles,
+		Books:      booksDTO,
 	}
 }
 
+func NewBookCharacterDTO(character Character, bookID string) BookCharacterDTO {
+	bookCharacterDTO := BookCharacterDTO{ID: character.ID, Name: character.Name}
+	for _, appearance := range character.Books {
+		if appearance.ID == bookID {
+			bookCharacterDTO.Role = string(appearance.Role)
+			bookCharacterDTO.Note = appearance.Note
+		}
+	}
+
+	return bookCharacterDTO
+}
+
+func NewCharacterBookDTO(appearance Appearance, expandBooks bool) CharacterBookDTO {
+	bookDTO := CharacterBookDTO{ID: appearance.ID, Title: appearance.Title, Year: appearance.Year, Role: string(appearance.Role), Note: appearance.Note}
+	if expandBooks {
+		fullBookDTO := books.NewBookDTO(appearance.Book)
+		bookDTO.Blurb = fullBookDTO.Blurb
+		bookDTO.Adaptations = fullBookDTO.Adaptations
+	}
+
+	return bookDTO
+}
+
 func (r *CharacterDTO) ToCharacter() Character {
-	var actorList []Actor
+	return Character{Name: r.Name}
+}
 
-	for _, actor := range r.Actors {
-		actorList = append(actorList, Actor{
-			Name: actor.Name,
-			IMDB: actor.IMDB,
+func (r *CharacterDTO) ToAppearances() []Appearance {
+	appearances := []Appearance{}
+	for _, bookTitle := range r.BookTitles {
+		appearances = append(appearances, Appearance{Book: books.Book{Title: bookTitle}})
+	}
+
+	for _, appearance := range r.Appearances {
+		appearances = append(appearances, Appearance{
+			Book: books.Book{Title: appearance.BookTitle},
+			Role: Role(appearance.Role),
+			Note: appearance.Note,
 		})
 	}
 
-	character := Character{Name: r.Name, Actors: actorList}
+	return appearances
+}
+
+type PatchCharacterDTO struct {
+	Name        *string          `json:"name" binding:"omitempty,min=1"`
+	BookTitles  *[]string        `json:"bookTitles"`
+	Appearances *[]AppearanceDTO `json:"appearances" binding:"omitempty,dive"`
+}
+
+func (r *PatchCharacterDTO) ApplyTo(character Character) (Character, []Appearance) {
+	if r.Name != nil {
+		character.Name = *r.Name
+	}
+
+	if r.BookTitles == nil && r.Appearances == nil {
+		return character, nil
+	}
 
-	return character
+	characterDTO := CharacterDTO{}
+	if r.BookTitles != nil {
+		characterDTO.BookTitles = *r.BookTitles
+	}
+	if r.Appearances != nil {
+		characterDTO.Appearances = *r.Appearances
+	}
+
+	return character, characterDTO.ToAppearances()
 }
 
 type GetByRequest struct {
 	Character string `uri:"character" binding:"required"`
 }
+
+type ExpandRequest struct {
+	Expand string `form:"expand"`
+}
+
+func (r *ExpandRequest) Has(field string) bool {
+	return slices.Contains(strings.Split(r.Expand, ","), field)
+}
+
+type IDRequest struct {
+	CharacterID string `uri:"character" binding:"required,uuid"`
+}
+
+type RolesRequest struct {
+	Role string `form:"role" binding:"omitempty,oneof=protagonist supporting cameo mentioned"`
+}
+
+type BookRequest struct {
+	BookID string `uri:"bookID" binding:"required,uuid"`
+}
+
+type ListRequest struct {
+	Book   string `form:"book" binding:"omitempty,uuid"`
+	Actor  string `form:"actor"`
+	Name   string `form:"name~"`
+	Limit  int32  `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor string `form:"cursor"`
+}
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
 	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
@@ -33,12 +35,20 @@ func TestController_Create(t *testing.T) {
 				assert.True(t, errors.As(err, &syntaxErr))
 			},
 		},
+		{
+			name:    "when request body has actors",
+			reqBody: `{"name":"Harry Bosch","actors":[{"name":"Titus Welliver","imdb":"nm0920038"}]}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, ErrActorsReadOnly))
+			},
+		},
 		{
 			name:    "when create character service fails",
 			reqBody: `{"name":"Harry Bosch"}`,
 			setup: func(m *ManagerMock) {
 				reqCharacter := Character{Name: "Harry Bosch"}
-				m.On("Create", mock.Anything, reqCharacter, []string(nil)).Return(Character{}, assert.AnError).Once()
+				m.On("Create", mock.Anything, reqCharacter, []Appearance{}).Return(Character{}, false, assert.AnError).Once()
 			},
 			expected: func(_ *httptest.ResponseRecorder, err error) {
 				assert.True(t, errors.Is(err, assert.AnError))
@@ -46,16 +56,55 @@ func TestController_Create(t *testing.T) {
 		},
 		{
 			name:    "when create character is successful",
-			reqBody: `{"name":"Harry Bosch", "actors": [{"name":"Titus Welliver", "imdb":  "https://www.imdb.com/name/nm0920038"}], "bookTitles": ["The Black Echo"]}`,
+			reqBody: `{"name":"Harry Bosch", "bookTitles": ["The Black Echo"]}`,
 			setup: func(m *ManagerMock) {
-				reqCharacter := Character{Name: "Harry Bosch", Actors: []Actor{{Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038"}}}
-				respCharacter := Character{ID: "random-id", Name: "Harry Bosch", Books: []books.Book{{Title: "The Black Echo"}}}
-				m.On("Create", mock.Anything, reqCharacter, []string{"The Black Echo"}).Return(respCharacter, nil).Once()
+				reqCharacter := Character{Name: "Harry Bosch"}
+				respCharacter := Character{ID: "random-id", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}}}, Actors: []actors.Actor{{ID: "actor-id", Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038"}}}
+				m.On("Create", mock.Anything, reqCharacter, []Appearance{{Book: books.Book{Title: "The Black Echo"}}}).Return(respCharacter, true, nil).Once()
 			},
 			expected: func(r *httptest.ResponseRecorder, err error) {
 				assert.Nil(t, err)
 				assert.Equal(t, http.StatusCreated, r.Code)
-				assert.Equal(t, `{"id":"random-id","name":"Harry Bosch","bookTitles":["The Black Echo"]}`, r.Body.String())
+				assert.Equal(t, `{"id":"random-id","name":"Harry Bosch","actors":[{"id":"actor-id","name":"Titus Welliver","imdb":"https://www.imdb.com/name/nm0920038"}],"bookTitles":["The Black Echo"],"books":[{"id":"book-id","title":"The Black Echo","year":1992}]}`, r.Body.String())
+			},
+		},
+		{
+			name:    "when appearance role is invalid",
+			reqBody: `{"name":"Mickey Haller", "appearances": [{"bookTitle":"The Black Echo", "role":"villain"}]}`,
+			setup:   func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when create character with appearance roles is successful",
+			reqBody: `{"name":"Mickey Haller", "bookTitles": ["The Lincoln Lawyer"], "appearances": [{"bookTitle":"The Overlook", "role":"cameo", "note":"phone call with Bosch"}]}`,
+			setup: func(m *ManagerMock) {
+				reqCharacter := Character{Name: "Mickey Haller"}
+				reqAppearances := []Appearance{{Book: books.Book{Title: "The Lincoln Lawyer"}}, {Book: books.Book{Title: "The Overlook"}, Role: RoleCameo, Note: "phone call with Bosch"}}
+				respCharacter := Character{ID: "random-id", Name: "Mickey Haller", Books: []Appearance{{Book: books.Book{ID: "book-id", Title: "The Lincoln Lawyer", Year: 2005}}, {Book: books.Book{ID: "other-book-id", Title: "The Overlook", Year: 2007}, Role: RoleCameo, Note: "phone call with Bosch"}}}
+				m.On("Create", mock.Anything, reqCharacter, reqAppearances).Return(respCharacter, true, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, `{"id":"random-id","name":"Mickey Haller","bookTitles":["The Lincoln Lawyer","The Overlook"],"books":[{"id":"book-id","title":"The Lincoln Lawyer","year":2005},{"id":"other-book-id","title":"The Overlook","year":2007,"role":"cameo","note":"phone call with Bosch"}]}`, r.Body.String())
+			},
+		},
+		{
+			name:    "when create character returns an existing character",
+			reqBody: `{"name":"Harry Bosch"}`,
+			setup: func(m *ManagerMock) {
+				reqCharacter := Character{Name: "Harry Bosch"}
+				respCharacter := Character{ID: "random-id", Name: "Harry Bosch", Version: 4}
+				m.On("Create", mock.Anything, reqCharacter, []Appearance{}).Return(respCharacter, false, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"4"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"random-id","name":"Harry Bosch"}`, r.Body.String())
 			},
 		},
 	}
@@ -80,9 +129,255 @@ func TestController_Create(t *testing.T) {
 	}
 }
 
+func TestController_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  string
+		ifMatch  string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when character id is not a uuid",
+			reqBody: `{"name":"Harry Bosch"}`,
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "Harry Bosch"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when If-Match header is missing",
+			reqBody: `{"name":"Harry Bosch"}`,
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, etag.ErrMissing))
+			},
+		},
+		{
+			name:    "when request body has actors",
+			ifMatch: `"2"`,
+			reqBody: `{"name":"Harry Bosch","actors":[]}`,
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, ErrActorsReadOnly))
+			},
+		},
+		{
+			name:    "when update character service fails",
+			ifMatch: `"2"`,
+			reqBody: `{"name":"Harry Bosch"}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				reqCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Version: 2}
+				m.On("Update", mock.Anything, reqCharacter, []Appearance{}).Return(Character{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when update character is successful",
+			ifMatch: `"2"`,
+			reqBody: `{"name":"Harry Bosch", "bookTitles": ["The Black Echo"]}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				reqCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Version: 2}
+				respCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id", Title: "The Black Echo", Year: 1992}}}, Version: 3}
+				m.On("Update", mock.Anything, reqCharacter, []Appearance{{Book: books.Book{Title: "The Black Echo"}}}).Return(respCharacter, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Harry Bosch","bookTitles":["The Black Echo"],"books":[{"id":"book-id","title":"The Black Echo","year":1992}]}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/characters/c6767b2d-438b-4d4c-8b1a-659130a640ca", strings.NewReader(tt.reqBody))
+
+			ctx.Request.Header.Set("If-Match", tt.ifMatch)
+
+			tt.setup(ctx, m)
+
+			c.Update(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Patch(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  string
+		ifMatch  string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when get character service fails",
+			ifMatch: `"2"`,
+			reqBody: `{"name":"Harry Bosch"}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Character{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when patch only changes the name",
+			ifMatch: `"2"`,
+			reqBody: `{"name":"Hieronymus Bosch"}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}, nil).Once()
+				reqCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Hieronymus Bosch", Version: 2}
+				m.On("Update", mock.Anything, reqCharacter, []Appearance(nil)).Return(Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Hieronymus Bosch", Version: 3}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Hieronymus Bosch"}`, r.Body.String())
+			},
+		},
+		{
+			name:    "when patch replaces the book titles",
+			ifMatch: `"2"`,
+			reqBody: `{"bookTitles": ["The Black Echo"]}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}, nil).Once()
+				reqCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Version: 2}
+				m.On("Update", mock.Anything, reqCharacter, []Appearance{{Book: books.Book{Title: "The Black Echo"}}}).Return(Character{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when patch character appearances is successful",
+			ifMatch: `"2"`,
+			reqBody: `{"appearances": [{"bookTitle":"The Black Echo", "role":"protagonist"}]}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}, nil).Once()
+				reqCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Version: 2}
+				respCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id", Title: "The Black Echo", Year: 1992}, Role: RoleProtagonist}}, Version: 3}
+				m.On("Update", mock.Anything, reqCharacter, []Appearance{{Book: books.Book{Title: "The Black Echo"}, Role: RoleProtagonist}}).Return(respCharacter, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Harry Bosch","bookTitles":["The Black Echo"],"books":[{"id":"book-id","title":"The Black Echo","year":1992,"role":"protagonist"}]}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/characters/c6767b2d-438b-4d4c-8b1a-659130a640ca", strings.NewReader(tt.reqBody))
+
+			ctx.Request.Header.Set("If-Match", tt.ifMatch)
+
+			tt.setup(ctx, m)
+
+			c.Patch(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name: "when character id is not a uuid",
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "Harry Bosch"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name: "when delete character service fails",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("Delete", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name: "when delete character is successful",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("Delete", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusNoContent, r.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/characters/c6767b2d-438b-4d4c-8b1a-659130a640ca", nil)
+
+			tt.setup(ctx, m)
+
+			c.Delete(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
 func TestController_GetById(t *testing.T) {
 	tests := []struct {
 		name     string
+		query    string
 		setup    func(*gin.Context, *ManagerMock)
 		expected func(*httptest.ResponseRecorder, error)
 	}{
@@ -108,13 +403,29 @@ func TestController_GetById(t *testing.T) {
 			name: "when get character service is successful",
 			setup: func(ctx *gin.Context, m *ManagerMock) {
 				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
-				respCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}
+				respCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}}}, Actors: []actors.Actor{{ID: "actor-id", Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038"}}, Version: 5}
 				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(respCharacter, nil).Once()
 			},
 			expected: func(r *httptest.ResponseRecorder, err error) {
 				assert.Nil(t, err)
 				assert.Equal(t, http.StatusOK, r.Code)
-				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Harry Bosch"}`, r.Body.String())
+				assert.Equal(t, `"5"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Harry Bosch","actors":[{"id":"actor-id","name":"Titus Welliver","imdb":"https://www.imdb.com/name/nm0920038"}],"bookTitles":["The Black Echo"],"books":[{"id":"book-id","title":"The Black Echo","year":1992}]}`, r.Body.String())
+			},
+		},
+		{
+			name:  "when get character with expanded books",
+			query: "expand=books",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				book := books.Book{ID: "book-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb", Adaptations: []books.Adaptation{{Description: "Bosch S03", IMDB: "https://www.imdb.com/title/tt3502248/episodes/?season=3"}}}
+				respCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: book}}, Version: 5}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(respCharacter, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Harry Bosch","bookTitles":["The Black Echo"],"books":[{"id":"book-id","title":"The Black Echo","year":1992,"blurb":"a random blurb","adaptations":[{"description":"Bosch S03","imdb":"https://www.imdb.com/title/tt3502248/episodes/?season=3"}]}]}`, r.Body.String())
 			},
 		},
 	}
@@ -125,7 +436,7 @@ func TestController_GetById(t *testing.T) {
 
 			recorder := httptest.NewRecorder()
 			ctx, _ := gin.CreateTestContext(recorder)
-			ctx.Request = httptest.NewRequest(http.MethodGet, "/characters/c6767b2d-438b-4d4c-8b1a-659130a640ca", nil)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/characters/c6767b2d-438b-4d4c-8b1a-659130a640ca?"+tt.query, nil)
 
 			tt.setup(ctx, m)
 
@@ -139,6 +450,225 @@ func TestController_GetById(t *testing.T) {
 	}
 }
 
+func TestController_GetAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when book is not a uuid",
+			query: "?book=the-black-echo",
+			setup: func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when limit is out of range",
+			query: "?limit=101",
+			setup: func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when list characters service fails",
+			query: "",
+			setup: func(m *ManagerMock) {
+				m.On("List", mock.Anything, Filter{}, int32(20), "").Return([]Character{}, "", assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:  "when list characters service is successful",
+			query: "?book=a7767b2d-438b-4d4c-8b1a-659130a640ca&actor=welliver&name~=bos&limit=1&cursor=some-cursor",
+			setup: func(m *ManagerMock) {
+				filter := Filter{BookID: "a7767b2d-438b-4d4c-8b1a-659130a640ca", Actor: "welliver", Name: "bos"}
+				respCharacters := []Character{{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "a7767b2d-438b-4d4c-8b1a-659130a640ca", Title: "The Black Echo", Year: 1992}}}, Actors: []actors.Actor{{Name: "Titus Welliver", IMDB: "nm0920460"}}}}
+				m.On("List", mock.Anything, filter, int32(1), "some-cursor").Return(respCharacters, "next-cursor", nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"items":[{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Harry Bosch","actors":[{"name":"Titus Welliver","imdb":"nm0920460"}],"bookTitles":["The Black Echo"],"books":[{"id":"a7767b2d-438b-4d4c-8b1a-659130a640ca","title":"The Black Echo","year":1992}]}],"nextCursor":"next-cursor"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/characters"+tt.query, nil)
+
+			tt.setup(m)
+
+			c.GetAll(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetBooks(t *testing.T) {
+	character := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Mickey Haller", Books: []Appearance{
+		{Book: books.Book{ID: "book-id", Title: "The Lincoln Lawyer", Year: 2005}, Role: RoleProtagonist},
+		{Book: books.Book{ID: "other-book-id", Title: "The Overlook", Year: 2007}, Role: RoleCameo, Note: "phone call with Bosch"},
+	}}
+	tests := []struct {
+		name     string
+		target   string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:   "when role is invalid",
+			target: "/characters/c6767b2d-438b-4d4c-8b1a-659130a640ca/books?role=villain",
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:   "when get character service fails",
+			target: "/characters/c6767b2d-438b-4d4c-8b1a-659130a640ca/books",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Character{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:   "when get all character books is successful",
+			target: "/characters/c6767b2d-438b-4d4c-8b1a-659130a640ca/books",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(character, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[{"id":"book-id","title":"The Lincoln Lawyer","year":2005,"role":"protagonist"},{"id":"other-book-id","title":"The Overlook","year":2007,"role":"cameo","note":"phone call with Bosch"}]`, r.Body.String())
+			},
+		},
+		{
+			name:   "when get character books by name and role is successful",
+			target: "/characters/mickey-haller/books?role=cameo",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "character", Value: "mickey-haller"}}
+				m.On("GetByName", mock.Anything, "mickey-haller").Return(character, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[{"id":"other-book-id","title":"The Overlook","year":2007,"role":"cameo","note":"phone call with Bosch"}]`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			tt.setup(ctx, m)
+
+			c.GetBooks(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetByBook(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name: "when book id is not a uuid",
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "the-black-echo"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name: "when get characters by book service fails",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetByBook", mock.Anything, "a7767b2d-438b-4d4c-8b1a-659130a640ca").Return([]Character{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name: "when get characters by book service is successful",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				respCharacters := []Character{
+					{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{
+						{Book: books.Book{ID: "b6767b2d-438b-4d4c-8b1a-659130a640ca"}, Role: RoleSupporting},
+						{Book: books.Book{ID: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}, Role: RoleCameo, Note: "Testifies at the trial"},
+					}},
+					{ID: "d6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Renée Ballard", Books: []Appearance{{Book: books.Book{ID: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}}}},
+				}
+				m.On("GetByBook", mock.Anything, "a7767b2d-438b-4d4c-8b1a-659130a640ca").Return(respCharacters, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Harry Bosch","role":"cameo","note":"Testifies at the trial"},{"id":"d6767b2d-438b-4d4c-8b1a-659130a640ca","name":"Renée Ballard"}]`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/books/a7767b2d-438b-4d4c-8b1a-659130a640ca/characters", nil)
+
+			tt.setup(ctx, m)
+
+			c.GetByBook(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
 func TestController_GetByName(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -210,12 +740,32 @@ func (m *ManagerMock) GetByName(ctx context.Context, characterName string) (Char
 	return args.Get(0).(Character), args.Error(1)
 }
 
-func (m *ManagerMock) Create(ctx context.Context, character Character, bookTitle []string) (Character, error) {
-	args := m.Called(ctx, character, bookTitle)
-	return args.Get(0).(Character), args.Error(1)
+func (m *ManagerMock) Create(ctx context.Context, character Character, appearances []Appearance) (Character, bool, error) {
+	args := m.Called(ctx, character, appearances)
+	return args.Get(0).(Character), args.Bool(1), args.Error(2)
 }
 
 func (m *ManagerMock) GetById(ctx context.Context, characterID string) (Character, error) {
 	args := m.Called(ctx, characterID)
 	return args.Get(0).(Character), args.Error(1)
 }
+
+func (m *ManagerMock) Update(ctx context.Context, character Character, appearances []Appearance) (Character, error) {
+	args := m.Called(ctx, character, appearances)
+	return args.Get(0).(Character), args.Error(1)
+}
+
+func (m *ManagerMock) GetByBook(ctx context.Context, bookID string) ([]Character, error) {
+	args := m.Called(ctx, bookID)
+	return args.Get(0).([]Character), args.Error(1)
+}
+
+func (m *ManagerMock) List(ctx context.Context, filter Filter, limit int32, cursor string) ([]Character, string, error) {
+	args := m.Called(ctx, filter, limit, cursor)
+	return args.Get(0).([]Character), args.String(1), args.Error(2)
+}
+
+func (m *ManagerMock) Delete(ctx context.Context, characterID string) error {
+	args := m.Called(ctx, characterID)
+	return args.Error(0)
+}
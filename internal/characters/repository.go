@@ -4,46 +4,451 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
 )
 
 type DynamoClient interface {
-	Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string) (string, error)
+	NewID() string
+	Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error)
+	Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error
+	Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error
 	GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error)
 	GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error)
+	BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error)
+	GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error)
+	Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error)
+}
+
+type ActorResolver interface {
+	Resolve(ctx context.Context, actor actors.Actor) (actors.Actor, error)
+}
+
+type Caster interface {
+	AddCast(ctx context.Context, characterID string, bookIDs []string, actorIDs []string) (bool, error)
+}
+
+type Links interface {
+	Writes(owner string, ownerID string, current []relations.Link, links []relations.Link) ([]dynamo.Write, error)
+	Replace(ctx context.Context, owner string, ownerID string, links []relations.Link) error
+	GetByBook(ctx context.Context, owner string, bookID string) ([]relations.Link, error)
+	Apply(ctx context.Context, writes []dynamo.Write) error
+}
+
+type Edges interface {
+	DeleteWrites(ctx context.Context, characterID string) ([]dynamo.Write, error)
+}
+
+const linkOwner = "character"
+const NameIndex = "name-index"
+const characterEntity = "character"
+
+func Defaults() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"entity": &types.AttributeValueMemberS{Value: characterEntity}}
 }
 
 type Repository struct {
-	dynamodb  DynamoClient
-	tableName string
+	dynamodb        DynamoClient
+	tableName       string
+	duplicatePolicy dynamo.DuplicatePolicy
+	links           Links
+	edges           Edges
 }
 
-func NewRepository(dynamoDB DynamoClient, tableName string) *Repository {
-	return &Repository{dynamodb: dynamoDB, tableName: tableName}
+func NewRepository(dynamoDB DynamoClient, tableName string, duplicatePolicy dynamo.DuplicatePolicy, links Links, edges Edges) *Repository {
+	return &Repository{dynamodb: dynamoDB, tableName: tableName, duplicatePolicy: duplicatePolicy, links: links, edges: edges}
 }
 
-func (r *Repository) Save(ctx context.Context, character Character) (Character, error) {
+func (r *Repository) Save(ctx context.Context, character Character) (Character, bool, error) {
+	character.ID = r.dynamodb.NewID()
+
 	characterItem, err := attributevalue.MarshalMap(NewDBCharacter(character))
+	if err != nil {
+		return Character{}, false, fmt.Errorf("failed to marshal character: %w", err)
+	}
+
+	writes, err := r.links.Writes(linkOwner, character.ID, nil, newLinks(character))
+	if err != nil {
+		return Character{}, false, err
+	}
+
+	err = relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		_, err := r.dynamodb.Save(ctx, r.tableName, characterItem, character.Name, writes...)
+		return err
+	})
+	if errors.Is(err, dynamo.ErrDuplicated) {
+		return r.saveDuplicated(ctx, character, err)
+	}
+	if err != nil {
+		return Character{}, false, err
+	}
+
+	character.Version = 1
+
+	return character, true, nil
+}
+
+func (r *Repository) saveDuplicated(ctx context.Context, character Character, duplicatedErr error) (Character, bool, error) {
+	if r.duplicatePolicy == dynamo.DuplicateReject {
+		return Character{}, false, duplicatedErr
+	}
+
+	existingCharacter, err := r.getDuplicated(ctx, character, duplicatedErr)
+	if err != nil {
+		return Character{}, false, err
+	}
+
+	if r.duplicatePolicy == dynamo.DuplicateReturnExisting {
+		return existingCharacter, false, nil
+	}
+
+	character.ID = existingCharacter.ID
+	character.Version = existingCharacter.Version
+
+	updatedCharacter, err := r.Update(ctx, character)
+
+	return updatedCharacter, false, err
+}
+
+func (r *Repository) getDuplicated(ctx context.Context, character Character, duplicatedErr error) (Character, error) {
+	var withID *dynamo.DuplicatedError
+	if errors.As(duplicatedErr, &withID) {
+		return r.GetById(ctx, withID.ID)
+	}
+
+	return r.GetByName(ctx, character.Name)
+}
+
+func (r *Repository) Update(ctx context.Context, character Character) (Character, error) {
+	item, err := r.dynamodb.GetByID(ctx, r.tableName, character.ID)
+	if err != nil {
+		return Character{}, err
+	}
+
+	var currentCharacter DBCharacter
+	err = attributevalue.UnmarshalMap(item, &currentCharacter)
+	if err != nil {
+		return Character{}, fmt.Errorf("failed to unmarshal character: %w", err)
+	}
+
+	if character.Version == dynamo.AnyVersion {
+		character.Version = currentCharacter.Version
+	}
+
+	dbCharacter := NewDBCharacter(character)
+	if character.Books == nil {
+		dbCharacter.Books = currentCharacter.Books
+		dbCharacter.Appearances = currentCharacter.Appearances
+		character.Books = currentCharacter.ToCharacter().Books
+	}
+	dbCharacter.ActorIDs = currentCharacter.ActorIDs
+	dbCharacter.Actors = currentCharacter.Actors
+
+	characterItem, err := attributevalue.MarshalMap(dbCharacter)
 	if err != nil {
 		return Character{}, fmt.Errorf("failed to marshal character: %w", err)
 	}
 
-	id, err := r.dynamodb.Save(ctx, r.tableName, characterItem, character.Name)
+	writes, err := r.links.Writes(linkOwner, character.ID, newLinks(currentCharacter.ToCharacter()), newLinks(character))
 	if err != nil {
-		if errors.Is(err, dynamo.ErrDuplicated) {
-			return r.GetByName(ctx, character.Name)
-		}
 		return Character{}, err
 	}
 
-	character.ID = id
+	err = relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		return r.dynamodb.Update(ctx, r.tableName, character.ID, character.Version, characterItem, currentCharacter.Name, character.Name, writes...)
+	})
+	if err != nil {
+		return Character{}, err
+	}
+
+	character.Version++
 
 	return character, nil
 }
 
+func (r *Repository) Delete(ctx context.Context, characterID string) error {
+	character, err := r.GetById(ctx, characterID)
+	if err != nil {
+		return err
+	}
+
+	writes, err := r.links.Writes(linkOwner, characterID, newLinks(character), nil)
+	if err != nil {
+		return err
+	}
+
+	edgeWrites, err := r.edges.DeleteWrites(ctx, characterID)
+	if err != nil {
+		return err
+	}
+
+	return relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		return r.dynamodb.Delete(ctx, r.tableName, characterID, character.Name, append(writes, edgeWrites...)...)
+	})
+}
+
+func (r *Repository) HasBook(ctx context.Context, bookID string) (bool, error) {
+	links, err := r.links.GetByBook(ctx, linkOwner, bookID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(links) > 0, nil
+}
+
+func (r *Repository) RemoveBook(ctx context.Context, bookID string) error {
+	dbCharacters, err := r.getByBook(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	for _, dbCharacter := range dbCharacters {
+		current := newLinks(dbCharacter.ToCharacter())
+		dbCharacter.Books = slices.DeleteFunc(dbCharacter.Books, func(id string) bool { return id == bookID })
+		dbCharacter.Appearances = slices.DeleteFunc(dbCharacter.Appearances, func(a DBAppearance) bool { return a.BookID == bookID })
+
+		characterItem, err := attributevalue.MarshalMap(dbCharacter)
+		if err != nil {
+			return fmt.Errorf("failed to marshal character: %w", err)
+		}
+
+		writes, err := r.links.Writes(linkOwner, dbCharacter.ID, current, newLinks(dbCharacter.ToCharacter()))
+		if err != nil {
+			return err
+		}
+
+		err = r.dynamodb.Update(ctx, r.tableName, dbCharacter.ID, dbCharacter.Version, characterItem, dbCharacter.Name, dbCharacter.Name, writes...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) RelinkBooks(ctx context.Context) error {
+	characters, err := r.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, character := range characters {
+		err = r.links.Replace(ctx, linkOwner, character.ID, newLinks(character))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) BackfillNameKeys(ctx context.Context) error {
+	dbCharacters, err := r.getAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, dbCharacter := range dbCharacters {
+		if dbCharacter.NameKey != "" {
+			continue
+		}
+
+		character := dbCharacter.ToCharacter()
+		character.Version = dynamo.AnyVersion
+		_, err = r.Update(ctx, character)
+		if err != nil {
+			return fmt.Errorf("%w. character: %s", err, character.ID)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) MigrateCast(ctx context.Context, resolver ActorResolver, caster Caster) error {
+	dbCharacters, err := r.getAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, dbCharacter := range dbCharacters {
+		if len(dbCharacter.ActorIDs) == 0 && len(dbCharacter.Actors) == 0 {
+			continue
+		}
+
+		actorIDs := dbCharacter.ActorIDs
+		for _, dbActor := range dbCharacter.Actors {
+			actor, err := resolver.Resolve(ctx, actors.Actor{Name: dbActor.Name, IMDB: dbActor.IMDB})
+			if err != nil {
+				return fmt.Errorf("%w. character: %s", err, dbCharacter.ID)
+			}
+
+			if !slices.Contains(actorIDs, actor.ID) {
+				actorIDs = append(actorIDs, actor.ID)
+			}
+		}
+
+		placed, err := caster.AddCast(ctx, dbCharacter.ID, dbCharacter.Books, actorIDs)
+		if err != nil {
+			return fmt.Errorf("%w. character: %s", err, dbCharacter.ID)
+		}
+
+		if !placed && len(dbCharacter.Actors) == 0 {
+			continue
+		}
+
+		dbCharacter.ActorIDs = actorIDs
+		if placed {
+			dbCharacter.ActorIDs = nil
+		}
+		dbCharacter.Actors = nil
+
+		characterItem, err := attributevalue.MarshalMap(dbCharacter)
+		if err != nil {
+			return fmt.Errorf("failed to marshal character: %w", err)
+		}
+
+		err = r.dynamodb.Update(ctx, r.tableName, dbCharacter.ID, dbCharacter.Version, characterItem, dbCharacter.Name, dbCharacter.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) GetByBook(ctx context.Context, bookID string) ([]Character, error) {
+	dbCharacters, err := r.getByBook(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	characters := []Character{}
+	for _, dbCharacter := range dbCharacters {
+		characters = append(characters, dbCharacter.ToCharacter())
+	}
+
+	return characters, nil
+}
+
+func (r *Repository) getByBook(ctx context.Context, bookID string) ([]DBCharacter, error) {
+	links, err := r.links.GetByBook(ctx, linkOwner, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	var characterIDs []string
+	for _, link := range links {
+		characterIDs = append(characterIDs, link.OwnerID)
+	}
+
+	items, err := r.dynamodb.BatchGetByIDs(ctx, r.tableName, characterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbCharacters []DBCharacter
+	err = attributevalue.UnmarshalListOfMaps(items, &dbCharacters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal characters: %w", err)
+	}
+
+	return dbCharacters, nil
+}
+
+func (r *Repository) GetPageByName(ctx context.Context, limit int32, afterName string, afterID string) ([]Character, error) {
+	query := dynamo.Query{
+		IndexName: NameIndex,
+		HashKey:   "entity",
+		HashValue: &types.AttributeValueMemberS{Value: characterEntity},
+		RangeKey:  "name_key",
+		Limit:     limit,
+	}
+	if afterID != "" {
+		query.ExclusiveStartKey = map[string]types.AttributeValue{
+			"id":       &types.AttributeValueMemberS{Value: afterID},
+			"entity":   &types.AttributeValueMemberS{Value: characterEntity},
+			"name_key": &types.AttributeValueMemberS{Value: afterName},
+		}
+	}
+
+	items, err := r.dynamodb.Query(ctx, r.tableName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbCharacters []DBCharacter
+	err = attributevalue.UnmarshalListOfMaps(items, &dbCharacters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal characters: %w", err)
+	}
+
+	characters := []Character{}
+	for _, dbCharacter := range dbCharacters {
+		characters = append(characters, dbCharacter.ToCharacter())
+	}
+
+	return characters, nil
+}
+
+func (r *Repository) GetByIds(ctx context.Context, characterIDs []string) ([]Character, error) {
+	items, err := r.dynamodb.BatchGetByIDs(ctx, r.tableName, characterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbCharacters []DBCharacter
+	err = attributevalue.UnmarshalListOfMaps(items, &dbCharacters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal characters: %w", err)
+	}
+
+	var characters []Character
+	for _, dbCharacter := range dbCharacters {
+		characters = append(characters, dbCharacter.ToCharacter())
+	}
+
+	return characters, nil
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]Character, error) {
+	dbCharacters, err := r.getAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var characters []Character
+	for _, dbCharacter := range dbCharacters {
+		characters = append(characters, dbCharacter.ToCharacter())
+	}
+
+	return characters, nil
+}
+
+func (r *Repository) getAll(ctx context.Context) ([]DBCharacter, error) {
+	items, err := r.dynamodb.GetAll(ctx, r.tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbCharacters []DBCharacter
+	err = attributevalue.UnmarshalListOfMaps(items, &dbCharacters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal characters: %w", err)
+	}
+
+	return dbCharacters, nil
+}
+
 func (r *Repository) GetById(ctx context.Context, characterID string) (Character, error) {
 	item, err := r.dynamodb.GetByID(ctx, r.tableName, characterID)
 	if err != nil {
@@ -75,10 +480,21 @@ func (r *Repository) GetByName(ctx context.Context, characterName string) (Chara
 }
 
 type DBCharacter struct {
-	ID     string    `dynamodbav:"id"`
-	Name   string    `dynamodbav:"name"`
-	Books  []string  `dynamodbav:"books"`
-	Actors []DBActor `dynamodbav:"actors"`
+	ID          string         `dynamodbav:"id"`
+	Entity      string         `dynamodbav:"entity,omitempty"`
+	Name        string         `dynamodbav:"name"`
+	NameKey     string         `dynamodbav:"name_key,omitempty"`
+	Books       []string       `dynamodbav:"books"`
+	Appearances []DBAppearance `dynamodbav:"appearances,omitempty"`
+	ActorIDs    []string       `dynamodbav:"actor_ids,omitempty"`
+	Actors      []DBActor      `dynamodbav:"actors,omitempty"`
+	Version     int            `dynamodbav:"version,omitempty"`
+}
+
+type DBAppearance struct {
+	BookID string `dynamodbav:"book_id"`
+	Role   string `dynamodbav:"role,omitempty"`
+	Note   string `dynamodbav:"note,omitempty"`
 }
 
 type DBActor struct {
@@ -88,26 +504,51 @@ type DBActor struct {
 
 func NewDBCharacter(character Character) DBCharacter {
 	bookIds := []string{}
+	var appearances []DBAppearance
 	for _, b := range character.Books {
 		bookIds = append(bookIds, b.ID)
+		if b.Role != "" || b.Note != "" {
+			appearances = append(appearances, DBAppearance{BookID: b.ID, Role: string(b.Role), Note: b.Note})
+		}
 	}
 
-	var actors []DBActor
-	for _, a := range character.Actors {
-		actors = append(actors, DBActor{Name: a.Name, IMDB: a.IMDB})
+	return DBCharacter{
+		ID:          character.ID,
+		Entity:      characterEntity,
+		Name:        character.Name,
+		NameKey:     dynamo.NormalizeKey(character.Name),
+		Books:       bookIds,
+		Appearances: appearances,
+		Version:     character.Version,
 	}
+}
 
-	return DBCharacter{
-		ID:     character.ID,
-		Name:   character.Name,
-		Books:  bookIds,
-		Actors: actors,
+func newLinks(character Character) []relations.Link {
+	var links []relations.Link
+	for _, book := range character.Books {
+		links = append(links, relations.Link{BookID: book.ID})
 	}
+
+	return links
 }
 
 func (d *DBCharacter) ToCharacter() Character {
+	var booksList []Appearance
+	for _, bookID := range d.Books {
+		appearance := Appearance{Book: books.Book{ID: bookID}}
+		for _, a := range d.Appearances {
+			if a.BookID == bookID {
+				appearance.Role = Role(a.Role)
+				appearance.Note = a.Note
+			}
+		}
+		booksList = append(booksList, appearance)
+	}
+
 	return Character{
-		ID:   d.ID,
-		Name: d.Name,
+		ID:      d.ID,
+		Name:    d.Name,
+		Books:   booksList,
+		Version: d.Version,
 	}
 }
@@ -3,62 +3,348 @@ package characters
 import (
 	"context"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
 	"github.com/ggoulart/michael-connelly-api/internal/books"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestRepository_Save(t *testing.T) {
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{}
+	item["id"] = &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}
+	item["entity"] = &types.AttributeValueMemberS{Value: "character"}
+	item["name"] = &types.AttributeValueMemberS{Value: "Harry Bosch"}
+	item["name_key"] = &types.AttributeValueMemberS{Value: "harry bosch"}
+	item["books"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id-1"}, &types.AttributeValueMemberS{Value: "book-id-2"}}}
+	existingItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "name": &types.AttributeValueMemberS{Value: "Harry Bosch"}, "version": &types.AttributeValueMemberN{Value: "2"}}
+	bookLinks := []relations.Link{{BookID: "book-id-1"}, {BookID: "book-id-2"}}
+	writes := []dynamo.Write{dynamo.PutWrite("relations", "character#c6767b2d-438b-4d4c-8b1a-659130a640ca#book-id-1", map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id-1"}})}
+	tests := []struct {
+		name        string
+		policy      dynamo.DuplicatePolicy
+		setup       func(*MockDynamoDBClient, *LinksMock)
+		want        Character
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name:   "when failed to save character because already exists",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("c6767b2d-438b-4d4c-8b1a-659130a640ca").Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "some-table-name", item, "Harry Bosch", writes).Return("", &dynamo.DuplicatedError{ID: "random-id"}).Once()
+			},
+			wantErr: &dynamo.DuplicatedError{ID: "random-id"},
+		},
+		{
+			name:   "when character already exists and policy returns existing",
+			policy: dynamo.DuplicateReturnExisting,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("c6767b2d-438b-4d4c-8b1a-659130a640ca").Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "some-table-name", item, "Harry Bosch", writes).Return("", &dynamo.DuplicatedError{ID: "random-id"}).Once()
+				m.On("GetByID", ctx, "some-table-name", "random-id").Return(existingItem, nil).Once()
+			},
+			want: Character{ID: "random-id", Name: "Harry Bosch", Version: 2},
+		},
+		{
+			name:   "when character already exists without its id and policy returns existing",
+			policy: dynamo.DuplicateReturnExisting,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("c6767b2d-438b-4d4c-8b1a-659130a640ca").Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "some-table-name", item, "Harry Bosch", writes).Return("", dynamo.ErrDuplicated).Once()
+				m.On("GetByUniqueKey", ctx, "some-table-name", "Harry Bosch").Return(existingItem, nil).Once()
+			},
+			want: Character{ID: "random-id", Name: "Harry Bosch", Version: 2},
+		},
+		{
+			name:   "when character already exists and policy upserts",
+			policy: dynamo.DuplicateUpsert,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("c6767b2d-438b-4d4c-8b1a-659130a640ca").Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "some-table-name", item, "Harry Bosch", writes).Return("", &dynamo.DuplicatedError{ID: "random-id"}).Once()
+				m.On("GetByID", ctx, "some-table-name", "random-id").Return(existingItem, nil).Twice()
+				updateItem := maps.Clone(item)
+				updateItem["id"] = &types.AttributeValueMemberS{Value: "random-id"}
+				updateItem["version"] = &types.AttributeValueMemberN{Value: "2"}
+				upsertWrites := []dynamo.Write{dynamo.PutWrite("relations", "character#random-id#book-id-1", map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id-1"}})}
+				l.On("Writes", "character", "random-id", []relations.Link(nil), bookLinks).Return(upsertWrites, nil).Once()
+				m.On("Update", ctx, "some-table-name", "random-id", 2, updateItem, "Harry Bosch", "Harry Bosch", upsertWrites).Return(nil).Once()
+			},
+			want: Character{ID: "random-id", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id-1"}}, {Book: books.Book{ID: "book-id-2"}}}, Version: 3},
+		},
+		{
+			name:   "when failed to build character book link writes",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("c6767b2d-438b-4d4c-8b1a-659130a640ca").Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link(nil), bookLinks).Return([]dynamo.Write(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when failed to save character",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("c6767b2d-438b-4d4c-8b1a-659130a640ca").Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "some-table-name", item, "Harry Bosch", writes).Return("", assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when successfully saved character with its book links",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("c6767b2d-438b-4d4c-8b1a-659130a640ca").Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "some-table-name", item, "Harry Bosch", writes).Return("c6767b2d-438b-4d4c-8b1a-659130a640ca", nil).Once()
+			},
+			want:        Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id-1"}}, {Book: books.Book{ID: "book-id-2"}}}, Version: 1},
+			wantCreated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", tt.policy, links, new(EdgesMock))
+
+			character := Character{Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id-1"}}, {Book: books.Book{ID: "book-id-2"}}}}
+			got, created, err := r.Save(ctx, character)
+
+			assert.Equal(t, got, tt.want)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]types.AttributeValue{
+		"id":        &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"},
+		"name":      &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"books":     &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id-1"}}},
+		"actor_ids": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "actor-id"}}},
+	}
+	tests := []struct {
+		name      string
+		character Character
+		setup     func(*MockDynamoDBClient, *LinksMock)
+		want      Character
+		wantErr   error
+	}{
+		{
+			name:      "when failed to get current character",
+			character: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Hieronymus Bosch"},
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:      "when books are not sent they are kept",
+			character: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Hieronymus Bosch"},
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(current, nil).Once()
+				item := map[string]types.AttributeValue{
+					"id":        &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"},
+					"entity":    &types.AttributeValueMemberS{Value: "character"},
+					"name":      &types.AttributeValueMemberS{Value: "Hieronymus Bosch"},
+					"name_key":  &types.AttributeValueMemberS{Value: "hieronymus bosch"},
+					"books":     current["books"],
+					"actor_ids": current["actor_ids"],
+				}
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link{{BookID: "book-id-1"}}, []relations.Link{{BookID: "book-id-1"}}).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Update", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca", 0, item, "Harry Bosch", "Hieronymus Bosch", []dynamo.Write(nil)).Return(nil).Once()
+			},
+			want: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Hieronymus Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id-1"}}}, Version: 1},
+		},
+		{
+			name:      "when books are replaced and legacy actors not yet migrated are kept",
+			character: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{}},
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(current, nil).Once()
+				item := map[string]types.AttributeValue{
+					"id":        &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"},
+					"entity":    &types.AttributeValueMemberS{Value: "character"},
+					"name":      &types.AttributeValueMemberS{Value: "Harry Bosch"},
+					"name_key":  &types.AttributeValueMemberS{Value: "harry bosch"},
+					"books":     &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+					"actor_ids": current["actor_ids"],
+				}
+				writes := []dynamo.Write{dynamo.DeleteWrite("relations", "character#c6767b2d-438b-4d4c-8b1a-659130a640ca#book-id-1")}
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link{{BookID: "book-id-1"}}, []relations.Link(nil)).Return(writes, nil).Once()
+				m.On("Update", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca", 0, item, "Harry Bosch", "Harry Bosch", writes).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, links, new(EdgesMock))
+
+			got, err := r.Update(ctx, tt.character)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"},
+		"name":  &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+	}
+	linkWrites := []dynamo.Write{dynamo.DeleteWrite("relations", "character#c6767b2d-438b-4d4c-8b1a-659130a640ca#book-id")}
+	edgeWrites := []dynamo.Write{dynamo.DeleteWrite("relationships", "c6767b2d-438b-4d4c-8b1a-659130a640ca#father#maddie-id")}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock, *EdgesMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get current character",
+			setup: func(m *MockDynamoDBClient, _ *LinksMock, _ *EdgesMock) {
+				m.On("GetByID", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to build relationship writes",
+			setup: func(m *MockDynamoDBClient, l *LinksMock, e *EdgesMock) {
+				m.On("GetByID", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(current, nil).Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link{{BookID: "book-id"}}, []relations.Link(nil)).Return(linkWrites, nil).Once()
+				e.On("DeleteWrites", ctx, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return([]dynamo.Write(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully deleted character with its links and relationships",
+			setup: func(m *MockDynamoDBClient, l *LinksMock, e *EdgesMock) {
+				m.On("GetByID", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(current, nil).Once()
+				l.On("Writes", "character", "c6767b2d-438b-4d4c-8b1a-659130a640ca", []relations.Link{{BookID: "book-id"}}, []relations.Link(nil)).Return(linkWrites, nil).Once()
+				e.On("DeleteWrites", ctx, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(edgeWrites, nil).Once()
+				m.On("Delete", ctx, "some-table-name", "c6767b2d-438b-4d4c-8b1a-659130a640ca", "Harry Bosch", append(slices.Clone(linkWrites), edgeWrites...)).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			edges := new(EdgesMock)
+			tt.setup(mockDynamoDBClient, links, edges)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, links, edges)
+
+			err := r.Delete(ctx, "c6767b2d-438b-4d4c-8b1a-659130a640ca")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+			edges.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByIds(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
 		name    string
 		setup   func(*MockDynamoDBClient)
-		want    Character
+		want    []Character
 		wantErr error
 	}{
 		{
-			name: "when failed to save character because already exists",
+			name: "when failed to get characters by ids",
 			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{}
-				item["id"] = &types.AttributeValueMemberS{Value: ""}
-				item["name"] = &types.AttributeValueMemberS{Value: "Harry Bosch"}
-				item["books"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id-1"}, &types.AttributeValueMemberS{Value: "book-id-2"}}}
-				item["actors"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "https://www.imdb.com/name/nm0920038"}}}}}
-				m.On("Save", ctx, "some-table-name", item, "Harry Bosch").Return("", dynamo.ErrDuplicated).Once()
-				output := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "name": &types.AttributeValueMemberS{Value: "Harry Bosch"}}
-				m.On("GetByUniqueKey", ctx, "some-table-name", "Harry Bosch").Return(output, nil).Once()
+				m.On("BatchGetByIDs", ctx, "some-table-name", []string{"character-id", "other-id"}).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
 			},
-			want: Character{ID: "random-id", Name: "Harry Bosch"},
+			wantErr: assert.AnError,
 		},
 		{
-			name: "when failed to save character",
+			name: "when successfully get characters by ids",
 			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{}
-				item["id"] = &types.AttributeValueMemberS{Value: ""}
-				item["name"] = &types.AttributeValueMemberS{Value: "Harry Bosch"}
-				item["books"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id-1"}, &types.AttributeValueMemberS{Value: "book-id-2"}}}
-				item["actors"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "https://www.imdb.com/name/nm0920038"}}}}}
-				m.On("Save", ctx, "some-table-name", item, "Harry Bosch").Return("", assert.AnError).Once()
+				items := []map[string]types.AttributeValue{
+					{"id": &types.AttributeValueMemberS{Value: "character-id"}, "name": &types.AttributeValueMemberS{Value: "Harry Bosch"}},
+					{"id": &types.AttributeValueMemberS{Value: "other-id"}, "name": &types.AttributeValueMemberS{Value: "Mickey Haller"}},
+				}
+				m.On("BatchGetByIDs", ctx, "some-table-name", []string{"character-id", "other-id"}).Return(items, nil).Once()
+			},
+			want: []Character{{ID: "character-id", Name: "Harry Bosch"}, {ID: "other-id", Name: "Mickey Haller"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, new(LinksMock), new(EdgesMock))
+
+			got, err := r.GetByIds(ctx, []string{"character-id", "other-id"})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetAll(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    []Character
+		wantErr error
+	}{
+		{
+			name: "when failed to get all characters",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
 			},
 			wantErr: assert.AnError,
 		},
 		{
-			name: "when successfully saved character",
+			name: "when successfully get all characters",
 			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{}
-				item["id"] = &types.AttributeValueMemberS{Value: ""}
-				item["name"] = &types.AttributeValueMemberS{Value: "Harry Bosch"}
-				item["books"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id-1"}, &types.AttributeValueMemberS{Value: "book-id-2"}}}
-				item["actors"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "https://www.imdb.com/name/nm0920038"}}}}}
-				m.On("Save", ctx, "some-table-name", item, "Harry Bosch").Return("c6767b2d-438b-4d4c-8b1a-659130a640ca", nil)
+				character := map[string]types.AttributeValue{
+					"id":      &types.AttributeValueMemberS{Value: "character-id"},
+					"name":    &types.AttributeValueMemberS{Value: "Harry Bosch"},
+					"books":   &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+					"version": &types.AttributeValueMemberN{Value: "2"},
+				}
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue{character}, nil).Once()
 			},
-			want: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []books.Book{{ID: "book-id-1"}, {ID: "book-id-2"}}, Actors: []Actor{{Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038"}}},
+			want: []Character{{ID: "character-id", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id"}}}, Version: 2}},
 		},
 	}
 	for _, tt := range tests {
@@ -66,14 +352,482 @@ func TestRepository_Save(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
 
-			r := NewRepository(mockDynamoDBClient, "some-table-name")
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, new(LinksMock), new(EdgesMock))
 
-			character := Character{Name: "Harry Bosch", Actors: []Actor{{Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038"}}, Books: []books.Book{{ID: "book-id-1"}, {ID: "book-id-2"}}}
-			got, err := r.Save(ctx, character)
+			got, err := r.GetAll(ctx)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetPageByName(t *testing.T) {
+	ctx := context.Background()
+	character := map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: "character-id"},
+		"entity":   &types.AttributeValueMemberS{Value: "character"},
+		"name":     &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"name_key": &types.AttributeValueMemberS{Value: "harry bosch"},
+		"books":    &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+		"version":  &types.AttributeValueMemberN{Value: "2"},
+	}
+	query := dynamo.Query{
+		IndexName: "name-index",
+		HashKey:   "entity",
+		HashValue: &types.AttributeValueMemberS{Value: "character"},
+		RangeKey:  "name_key",
+		Limit:     21,
+	}
+	startQuery := query
+	startQuery.ExclusiveStartKey = map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: "other-character-id"},
+		"entity":   &types.AttributeValueMemberS{Value: "character"},
+		"name_key": &types.AttributeValueMemberS{Value: "cal pierce"},
+	}
+	tests := []struct {
+		name      string
+		afterName string
+		afterID   string
+		setup     func(*MockDynamoDBClient)
+		want      []Character
+		wantErr   error
+	}{
+		{
+			name: "when failed to query characters",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "some-table-name", query).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get first page",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "some-table-name", query).Return([]map[string]types.AttributeValue{character}, nil).Once()
+			},
+			want: []Character{{ID: "character-id", Name: "Harry Bosch", Version: 2}},
+		},
+		{
+			name:      "when successfully get page after a character",
+			afterName: "cal pierce",
+			afterID:   "other-character-id",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "some-table-name", startQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+			},
+			want: []Character{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, new(LinksMock), new(EdgesMock))
+
+			got, err := r.GetPageByName(ctx, 21, tt.afterName, tt.afterID)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_BackfillNameKeys(t *testing.T) {
+	ctx := context.Background()
+	legacyCharacter := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "character-id"},
+		"name":    &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"books":   &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+		"version": &types.AttributeValueMemberN{Value: "2"},
+	}
+	keyedCharacter := map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: "other-character-id"},
+		"entity":   &types.AttributeValueMemberS{Value: "character"},
+		"name":     &types.AttributeValueMemberS{Value: "Mickey Haller"},
+		"name_key": &types.AttributeValueMemberS{Value: "mickey haller"},
+		"books":    &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+	}
+	item := map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: "character-id"},
+		"entity":   &types.AttributeValueMemberS{Value: "character"},
+		"name":     &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"name_key": &types.AttributeValueMemberS{Value: "harry bosch"},
+		"books":    legacyCharacter["books"],
+		"version":  &types.AttributeValueMemberN{Value: "2"},
+	}
+	bookLinks := []relations.Link{{BookID: "book-id"}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get all characters",
+			setup: func(m *MockDynamoDBClient, _ *LinksMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to update character",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue{legacyCharacter}, nil).Once()
+				m.On("GetByID", ctx, "some-table-name", "character-id").Return(legacyCharacter, nil).Once()
+				l.On("Writes", "character", "character-id", bookLinks, bookLinks).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Update", ctx, "some-table-name", "character-id", 2, item, "Harry Bosch", "Harry Bosch", []dynamo.Write(nil)).Return(assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. character: %s", assert.AnError, "character-id"),
+		},
+		{
+			name: "when successfully backfilled name keys",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue{legacyCharacter, keyedCharacter}, nil).Once()
+				m.On("GetByID", ctx, "some-table-name", "character-id").Return(legacyCharacter, nil).Once()
+				l.On("Writes", "character", "character-id", bookLinks, bookLinks).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Update", ctx, "some-table-name", "character-id", 2, item, "Harry Bosch", "Harry Bosch", []dynamo.Write(nil)).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, links, new(EdgesMock))
+
+			err := r.BackfillNameKeys(ctx)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_HasBook(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		want    bool
+		wantErr error
+	}{
+		{
+			name: "when failed to get book links",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when no character references the book",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link(nil), nil).Once()
+			},
+		},
+		{
+			name: "when a character references the book",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link{{Owner: "character", OwnerID: "character-id", BookID: "book-id"}}, nil).Once()
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, links, new(EdgesMock))
+
+			got, err := r.HasBook(ctx, "book-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_RemoveBook(t *testing.T) {
+	ctx := context.Background()
+	referencing := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "character-id"},
+		"name":    &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"version": &types.AttributeValueMemberN{Value: "4"},
+		"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberS{Value: "book-id"},
+			&types.AttributeValueMemberS{Value: "other-book-id"},
+		}},
+		"appearances": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id"}, "role": &types.AttributeValueMemberS{Value: "cameo"}}},
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "other-book-id"}, "role": &types.AttributeValueMemberS{Value: "protagonist"}}},
+		}},
+	}
+	updated := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "character-id"},
+		"version": &types.AttributeValueMemberN{Value: "4"},
+		"name":    &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"books":   &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "other-book-id"}}},
+		"appearances": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "other-book-id"}, "role": &types.AttributeValueMemberS{Value: "protagonist"}}},
+		}},
+	}
+	currentLinks := []relations.Link{{BookID: "book-id"}, {BookID: "other-book-id"}}
+	remainingLinks := []relations.Link{{BookID: "other-book-id"}}
+	writes := []dynamo.Write{dynamo.DeleteWrite("relations", "character#character-id#book-id")}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get book links",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to update character",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link{{Owner: "character", OwnerID: "character-id", BookID: "book-id"}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "some-table-name", []string{"character-id"}).Return([]map[string]types.AttributeValue{referencing}, nil).Once()
+				l.On("Writes", "character", "character-id", currentLinks, remainingLinks).Return(writes, nil).Once()
+				m.On("Update", ctx, "some-table-name", "character-id", 4, updated, "Harry Bosch", "Harry Bosch", writes).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully removed book from characters",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link{{Owner: "character", OwnerID: "character-id", BookID: "book-id"}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "some-table-name", []string{"character-id"}).Return([]map[string]types.AttributeValue{referencing}, nil).Once()
+				l.On("Writes", "character", "character-id", currentLinks, remainingLinks).Return(writes, nil).Once()
+				m.On("Update", ctx, "some-table-name", "character-id", 4, updated, "Harry Bosch", "Harry Bosch", writes).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, links, new(EdgesMock))
+
+			err := r.RemoveBook(ctx, "book-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByBook(t *testing.T) {
+	ctx := context.Background()
+	character := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "character-id"},
+		"name":  &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		want    []Character
+		wantErr error
+	}{
+		{
+			name: "when failed to get book links",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when no character references the book",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link(nil), nil).Once()
+			},
+			want: []Character{},
+		},
+		{
+			name: "when failed to get characters",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link{{Owner: "character", OwnerID: "character-id", BookID: "book-id"}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "some-table-name", []string{"character-id"}).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get characters by book",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "character", "book-id").Return([]relations.Link{{Owner: "character", OwnerID: "character-id", BookID: "book-id"}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "some-table-name", []string{"character-id"}).Return([]map[string]types.AttributeValue{character}, nil).Once()
+			},
+			want: []Character{{ID: "character-id", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-id"}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, links, new(EdgesMock))
+
+			got, err := r.GetByBook(ctx, "book-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_RelinkBooks(t *testing.T) {
+	ctx := context.Background()
+	character := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "character-id"},
+		"name":  &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get all characters",
+			setup: func(m *MockDynamoDBClient, _ *LinksMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully relinked character books",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue{character}, nil).Once()
+				l.On("Replace", ctx, "character", "character-id", []relations.Link{{BookID: "book-id"}}).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, links, new(EdgesMock))
+
+			err := r.RelinkBooks(ctx)
 
-			assert.Equal(t, got, tt.want)
 			assert.Equal(t, tt.wantErr, err)
 			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_MigrateCast(t *testing.T) {
+	ctx := context.Background()
+	legacyCharacter := map[string]types.AttributeValue{
+		"id":        &types.AttributeValueMemberS{Value: "character-id"},
+		"version":   &types.AttributeValueMemberN{Value: "2"},
+		"name":      &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"books":     &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+		"actor_ids": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "actor-id"}}},
+		"actors":    &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "https://www.imdb.com/name/nm0920038"}}}}},
+	}
+	migratedCharacter := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "other-character-id"},
+		"name":  &types.AttributeValueMemberS{Value: "Mickey Haller"},
+		"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+	}
+	titus := actors.Actor{Name: "Titus Welliver", IMDB: "https://www.imdb.com/name/nm0920038"}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *ActorResolverMock, *CasterMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get all characters",
+			setup: func(m *MockDynamoDBClient, _ *ActorResolverMock, _ *CasterMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to resolve actor",
+			setup: func(m *MockDynamoDBClient, a *ActorResolverMock, _ *CasterMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue{legacyCharacter}, nil).Once()
+				a.On("Resolve", ctx, titus).Return(actors.Actor{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. character: %s", assert.AnError, "character-id"),
+		},
+		{
+			name: "when failed to add cast",
+			setup: func(m *MockDynamoDBClient, a *ActorResolverMock, c *CasterMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue{legacyCharacter}, nil).Once()
+				a.On("Resolve", ctx, titus).Return(actors.Actor{ID: "titus-id"}, nil).Once()
+				c.On("AddCast", ctx, "character-id", []string{"book-id"}, []string{"actor-id", "titus-id"}).Return(false, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. character: %s", assert.AnError, "character-id"),
+		},
+		{
+			name: "when book has no adaptation yet",
+			setup: func(m *MockDynamoDBClient, a *ActorResolverMock, c *CasterMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue{legacyCharacter, migratedCharacter}, nil).Once()
+				a.On("Resolve", ctx, titus).Return(actors.Actor{ID: "titus-id"}, nil).Once()
+				c.On("AddCast", ctx, "character-id", []string{"book-id"}, []string{"actor-id", "titus-id"}).Return(false, nil).Once()
+				item := map[string]types.AttributeValue{
+					"id":        &types.AttributeValueMemberS{Value: "character-id"},
+					"version":   &types.AttributeValueMemberN{Value: "2"},
+					"name":      &types.AttributeValueMemberS{Value: "Harry Bosch"},
+					"books":     &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+					"actor_ids": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "actor-id"}, &types.AttributeValueMemberS{Value: "titus-id"}}},
+				}
+				m.On("Update", ctx, "some-table-name", "character-id", 2, item, "Harry Bosch", "Harry Bosch", []dynamo.Write(nil)).Return(nil).Once()
+			},
+		},
+		{
+			name: "when successfully moved actors to the cast",
+			setup: func(m *MockDynamoDBClient, a *ActorResolverMock, c *CasterMock) {
+				m.On("GetAll", ctx, "some-table-name").Return([]map[string]types.AttributeValue{legacyCharacter, migratedCharacter}, nil).Once()
+				a.On("Resolve", ctx, titus).Return(actors.Actor{ID: "titus-id"}, nil).Once()
+				c.On("AddCast", ctx, "character-id", []string{"book-id"}, []string{"actor-id", "titus-id"}).Return(true, nil).Once()
+				item := map[string]types.AttributeValue{
+					"id":      &types.AttributeValueMemberS{Value: "character-id"},
+					"version": &types.AttributeValueMemberN{Value: "2"},
+					"name":    &types.AttributeValueMemberS{Value: "Harry Bosch"},
+					"books":   &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id"}}},
+				}
+				m.On("Update", ctx, "some-table-name", "character-id", 2, item, "Harry Bosch", "Harry Bosch", []dynamo.Write(nil)).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			resolver := new(ActorResolverMock)
+			caster := new(CasterMock)
+			tt.setup(mockDynamoDBClient, resolver, caster)
+
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, new(LinksMock), new(EdgesMock))
+
+			err := r.MigrateCast(ctx, resolver, caster)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			resolver.AssertExpectations(t)
+			caster.AssertExpectations(t)
 		})
 	}
 }
@@ -102,12 +856,33 @@ func TestRepository_GetById(t *testing.T) {
 			wantErr: fmt.Errorf("failed to unmarshal character: %w", &attributevalue.UnmarshalTypeError{Value: "map", Type: reflect.TypeOf("string")}),
 		},
 		{
-			name: "when success get character",
+			name: "when success get character ignoring legacy actors",
+			setup: func(m *MockDynamoDBClient) {
+				item := map[string]types.AttributeValue{
+					"id":        &types.AttributeValueMemberS{Value: "character-123"},
+					"books":     &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id-1"}}},
+					"actor_ids": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "actor-id"}}},
+					"actors":    &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"name": &types.AttributeValueMemberS{Value: "Titus Welliver"}, "imdb": &types.AttributeValueMemberS{Value: "https://www.imdb.com/name/nm0920038"}}}}},
+				}
+				m.On("GetByID", ctx, "some-table-name", "a-random-character-id").Return(item, nil)
+			},
+			want: Character{ID: "character-123", Books: []Appearance{{Book: books.Book{ID: "book-id-1"}}}},
+		},
+		{
+			name: "when success get character with appearance roles",
 			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "character-123"}}
+				item := map[string]types.AttributeValue{
+					"id":    &types.AttributeValueMemberS{Value: "character-123"},
+					"books": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "book-id-1"}, &types.AttributeValueMemberS{Value: "book-id-2"}}},
+					"appearances": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+						"book_id": &types.AttributeValueMemberS{Value: "book-id-2"},
+						"role":    &types.AttributeValueMemberS{Value: "cameo"},
+						"note":    &types.AttributeValueMemberS{Value: "testifies in court"},
+					}}}},
+				}
 				m.On("GetByID", ctx, "some-table-name", "a-random-character-id").Return(item, nil)
 			},
-			want: Character{ID: "character-123"},
+			want: Character{ID: "character-123", Books: []Appearance{{Book: books.Book{ID: "book-id-1"}}, {Book: books.Book{ID: "book-id-2"}, Role: RoleCameo, Note: "testifies in court"}}},
 		},
 	}
 	for _, tt := range tests {
@@ -115,7 +890,7 @@ func TestRepository_GetById(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
 
-			r := NewRepository(mockDynamoDBClient, "some-table-name")
+			r := NewRepository(mockDynamoDBClient, "some-table-name", dynamo.DuplicateReject, new(LinksMock), new(EdgesMock))
 
 			got, err := r.GetById(ctx, "a-random-character-id")
 
@@ -163,7 +938,7 @@ func TestRepository_GetByName(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
 
-			r := NewRepository(mockDynamoDBClient, "table-name")
+			r := NewRepository(mockDynamoDBClient, "table-name", dynamo.DuplicateReject, new(LinksMock), new(EdgesMock))
 
 			got, err := r.GetByName(ctx, "Harry Bosch")
 
@@ -178,8 +953,13 @@ type MockDynamoDBClient struct {
 	mock.Mock
 }
 
-func (m *MockDynamoDBClient) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string) (string, error) {
-	args := m.Called(ctx, tableName, item, uniqueKey)
+func (m *MockDynamoDBClient) NewID() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockDynamoDBClient) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error) {
+	args := m.Called(ctx, tableName, item, uniqueKey, writes)
 	return args.String(0), args.Error(1)
 }
 
@@ -192,3 +972,79 @@ func (m *MockDynamoDBClient) GetByUniqueKey(ctx context.Context, tableName strin
 	args := m.Called(ctx, tableName, value)
 	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
 }
+
+func (m *MockDynamoDBClient) Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, version, item, oldUniqueKey, newUniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, uniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, ids)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+type ActorResolverMock struct {
+	mock.Mock
+}
+
+func (a *ActorResolverMock) Resolve(ctx context.Context, actor actors.Actor) (actors.Actor, error) {
+	args := a.Called(ctx, actor)
+	return args.Get(0).(actors.Actor), args.Error(1)
+}
+
+type CasterMock struct {
+	mock.Mock
+}
+
+func (c *CasterMock) AddCast(ctx context.Context, characterID string, bookIDs []string, actorIDs []string) (bool, error) {
+	args := c.Called(ctx, characterID, bookIDs, actorIDs)
+	return args.Bool(0), args.Error(1)
+}
+
+type LinksMock struct {
+	mock.Mock
+}
+
+func (l *LinksMock) Writes(owner string, ownerID string, current []relations.Link, links []relations.Link) ([]dynamo.Write, error) {
+	args := l.Called(owner, ownerID, current, links)
+	return args.Get(0).([]dynamo.Write), args.Error(1)
+}
+
+func (l *LinksMock) Replace(ctx context.Context, owner string, ownerID string, links []relations.Link) error {
+	args := l.Called(ctx, owner, ownerID, links)
+	return args.Error(0)
+}
+
+func (l *LinksMock) GetByBook(ctx context.Context, owner string, bookID string) ([]relations.Link, error) {
+	args := l.Called(ctx, owner, bookID)
+	return args.Get(0).([]relations.Link), args.Error(1)
+}
+
+func (l *LinksMock) Apply(ctx context.Context, writes []dynamo.Write) error {
+	args := l.Called(ctx, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, query)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+type EdgesMock struct {
+	mock.Mock
+}
+
+func (e *EdgesMock) DeleteWrites(ctx context.Context, characterID string) ([]dynamo.Write, error) {
+	args := e.Called(ctx, characterID)
+	return args.Get(0).([]dynamo.Write), args.Error(1)
+}
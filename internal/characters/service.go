@@ -1,50 +1,160 @@
 package characters
 
 import (
+	"cmp"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
 
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
 	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/search"
 )
 
+const searchDocumentType = "character"
+
 type StorageCharacter interface {
-	Save(ctx context.Context, character Character) (Character, error)
+	Save(ctx context.Context, character Character) (Character, bool, error)
+	Update(ctx context.Context, character Character) (Character, error)
+	Delete(ctx context.Context, characterID string) error
 	GetById(ctx context.Context, characterID string) (Character, error)
 	GetByName(ctx context.Context, characterName string) (Character, error)
+	GetByBook(ctx context.Context, bookID string) ([]Character, error)
+	GetPageByName(ctx context.Context, limit int32, afterName string, afterID string) ([]Character, error)
+	GetAll(ctx context.Context) ([]Character, error)
 }
 
 type StorageBook interface {
-	GetByTitle(ctx context.Context, bookTitle string) (books.Book, error)
+	GetByIds(ctx context.Context, bookIDs []string) ([]books.Book, error)
+	GetBookListByTitles(ctx context.Context, bookTitles []string) ([]books.Book, error)
+}
+
+type StorageActor interface {
+	GetByIds(ctx context.Context, actorIDs []string) ([]actors.Actor, error)
+}
+
+type Cast interface {
+	GetActorIDs(ctx context.Context, characterIDs []string) (map[string][]string, error)
+	RemoveCharacter(ctx context.Context, characterID string) error
 }
 
 type Service struct {
 	storageCharacter StorageCharacter
 	storageBook      StorageBook
+	storageActor     StorageActor
+	cast             Cast
+	indexer          search.Indexer
 }
 
-func NewService(storageCharacter StorageCharacter, storageBook StorageBook) *Service {
-	return &Service{storageCharacter: storageCharacter, storageBook: storageBook}
+func NewService(storageCharacter StorageCharacter, storageBook StorageBook, storageActor StorageActor, cast Cast, indexer search.Indexer) *Service {
+	return &Service{storageCharacter: storageCharacter, storageBook: storageBook, storageActor: storageActor, cast: cast, indexer: indexer}
 }
 
-func (s *Service) Create(ctx context.Context, character Character, bookTitles []string) (Character, error) {
-	booksList := []books.Book{}
+func (s *Service) Create(ctx context.Context, character Character, appearances []Appearance) (Character, bool, error) {
+	characterBooks, err := s.getBooks(ctx, appearances)
+	if err != nil {
+		return Character{}, false, err
+	}
+
+	character.Books = characterBooks
+
+	savedCharacter, created, err := s.storageCharacter.Save(ctx, character)
+	if err != nil {
+		return Character{}, false, err
+	}
+
+	s.indexer.Put(newSearchDocument(savedCharacter))
+
+	if !created {
+		characters := []Character{savedCharacter}
+		err = s.loadBooks(ctx, characters)
+		if err != nil {
+			return Character{}, false, err
+		}
+
+		err = s.loadActors(ctx, characters)
+		if err != nil {
+			return Character{}, false, err
+		}
+
+		savedCharacter = characters[0]
+	}
+
+	return savedCharacter, created, nil
+}
 
-	for _, bookTitle := range bookTitles {
-		book, err := s.storageBook.GetByTitle(ctx, bookTitle)
+func (s *Service) Update(ctx context.Context, character Character, appearances []Appearance) (Character, error) {
+	if appearances != nil {
+		characterBooks, err := s.getBooks(ctx, appearances)
 		if err != nil {
 			return Character{}, err
 		}
 
-		booksList = append(booksList, book)
+		character.Books = characterBooks
 	}
 
-	character.Books = booksList
+	updatedCharacter, err := s.storageCharacter.Update(ctx, character)
+	if err != nil {
+		return Character{}, err
+	}
 
-	savedCharacter, err := s.storageCharacter.Save(ctx, character)
+	s.indexer.Put(newSearchDocument(updatedCharacter))
+
+	characters := []Character{updatedCharacter}
+	if appearances == nil {
+		err = s.loadBooks(ctx, characters)
+		if err != nil {
+			return Character{}, err
+		}
+	}
+
+	err = s.loadActors(ctx, characters)
 	if err != nil {
 		return Character{}, err
 	}
 
-	return savedCharacter, nil
+	return characters[0], nil
+}
+
+func (s *Service) Delete(ctx context.Context, characterID string) error {
+	err := s.storageCharacter.Delete(ctx, characterID)
+	if err != nil {
+		return err
+	}
+
+	s.indexer.Remove(searchDocumentType, characterID)
+
+	return s.cast.RemoveCharacter(ctx, characterID)
+}
+
+func (s *Service) getBooks(ctx context.Context, appearances []Appearance) ([]Appearance, error) {
+	characterBooks := []Appearance{}
+	if len(appearances) == 0 {
+		return characterBooks, nil
+	}
+
+	var bookTitles []string
+	for _, appearance := range appearances {
+		bookTitles = append(bookTitles, appearance.Title)
+	}
+
+	booksList, err := s.storageBook.GetBookListByTitles(ctx, bookTitles)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, appearance := range appearances {
+		characterBooks = append(characterBooks, Appearance{
+			Book: booksList[i],
+			Role: appearance.Role,
+			Note: appearance.Note,
+		})
+	}
+
+	return characterBooks, nil
 }
 
 func (s *Service) GetById(ctx context.Context, characterID string) (Character, error) {
@@ -53,7 +163,18 @@ func (s *Service) GetById(ctx context.Context, characterID string) (Character, e
 		return Character{}, err
 	}
 
-	return character, nil
+	characters := []Character{character}
+	err = s.loadBooks(ctx, characters)
+	if err != nil {
+		return Character{}, err
+	}
+
+	err = s.loadActors(ctx, characters)
+	if err != nil {
+		return Character{}, err
+	}
+
+	return characters[0], nil
 }
 
 func (s *Service) GetByName(ctx context.Context, characterName string) (Character, error) {
@@ -62,5 +183,248 @@ func (s *Service) GetByName(ctx context.Context, characterName string) (Characte
 		return Character{}, err
 	}
 
-	return character, nil
+	characters := []Character{character}
+	err = s.loadBooks(ctx, characters)
+	if err != nil {
+		return Character{}, err
+	}
+
+	err = s.loadActors(ctx, characters)
+	if err != nil {
+		return Character{}, err
+	}
+
+	return characters[0], nil
+}
+
+func (s *Service) GetByBook(ctx context.Context, bookID string) ([]Character, error) {
+	characters, err := s.storageCharacter.GetByBook(ctx, bookID)
+	if err != nil {
+		return []Character{}, err
+	}
+
+	return characters, nil
+}
+
+func (s *Service) List(ctx context.Context, filter Filter, limit int32, cursor string) ([]Character, string, error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return []Character{}, "", err
+	}
+
+	var page []Character
+	if filter.BookID != "" {
+		page, err = s.listByBook(ctx, filter, after)
+	} else {
+		page, err = s.listByName(ctx, filter, limit, after)
+	}
+	if err != nil {
+		return []Character{}, "", err
+	}
+
+	var nextCursor string
+	if len(page) > int(limit) {
+		page = page[:limit]
+		nextCursor = encodeCursor(newListCursor(page[len(page)-1]))
+	}
+
+	err = s.loadBooks(ctx, page)
+	if err != nil {
+		return []Character{}, "", err
+	}
+
+	return page, nextCursor, nil
+}
+
+func (s *Service) listByBook(ctx context.Context, filter Filter, after listCursor) ([]Character, error) {
+	characters, err := s.storageCharacter.GetByBook(ctx, filter.BookID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.loadActors(ctx, characters)
+	if err != nil {
+		return nil, err
+	}
+
+	page := []Character{}
+	for _, character := range characters {
+		if filter.matches(character) && (after.ID == "" || after.compare(newListCursor(character)) < 0) {
+			page = append(page, character)
+		}
+	}
+
+	slices.SortFunc(page, func(a, b Character) int {
+		return newListCursor(a).compare(newListCursor(b))
+	})
+
+	return page, nil
+}
+
+func (s *Service) listByName(ctx context.Context, filter Filter, limit int32, after listCursor) ([]Character, error) {
+	page := []Character{}
+	for {
+		characters, err := s.storageCharacter.GetPageByName(ctx, limit+1, after.Name, after.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.loadActors(ctx, characters)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, character := range characters {
+			if filter.matches(character) {
+				page = append(page, character)
+			}
+		}
+
+		if len(page) > int(limit) || len(characters) <= int(limit) {
+			return page, nil
+		}
+
+		after = newListCursor(characters[len(characters)-1])
+	}
+}
+
+func (s *Service) Documents(ctx context.Context) ([]search.Document, error) {
+	characters, err := s.storageCharacter.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []search.Document
+	for _, character := range characters {
+		documents = append(documents, newSearchDocument(character))
+	}
+
+	return documents, nil
+}
+
+func (s *Service) loadBooks(ctx context.Context, characters []Character) error {
+	var bookIDs []string
+	for _, character := range characters {
+		for _, book := range character.Books {
+			if !slices.Contains(bookIDs, book.ID) {
+				bookIDs = append(bookIDs, book.ID)
+			}
+		}
+	}
+
+	if len(bookIDs) == 0 {
+		return nil
+	}
+
+	booksList, err := s.storageBook.GetByIds(ctx, bookIDs)
+	if err != nil {
+		return err
+	}
+
+	booksByID := map[string]books.Book{}
+	for _, book := range booksList {
+		booksByID[book.ID] = book
+	}
+
+	for i, character := range characters {
+		var appearances []Appearance
+		for _, appearance := range character.Books {
+			if loaded, ok := booksByID[appearance.ID]; ok {
+				appearance.Book = loaded
+				appearances = append(appearances, appearance)
+			}
+		}
+		characters[i].Books = appearances
+	}
+
+	return nil
+}
+
+func (s *Service) loadActors(ctx context.Context, characters []Character) error {
+	if len(characters) == 0 {
+		return nil
+	}
+
+	var characterIDs []string
+	for _, character := range characters {
+		characterIDs = append(characterIDs, character.ID)
+	}
+
+	actorIDsByCharacter, err := s.cast.GetActorIDs(ctx, characterIDs)
+	if err != nil {
+		return err
+	}
+
+	var actorIDs []string
+	for _, character := range characters {
+		for _, actorID := range actorIDsByCharacter[character.ID] {
+			if !slices.Contains(actorIDs, actorID) {
+				actorIDs = append(actorIDs, actorID)
+			}
+		}
+	}
+
+	if len(actorIDs) == 0 {
+		return nil
+	}
+
+	actorsList, err := s.storageActor.GetByIds(ctx, actorIDs)
+	if err != nil {
+		return err
+	}
+
+	actorsByID := map[string]actors.Actor{}
+	for _, actor := range actorsList {
+		actorsByID[actor.ID] = actor
+	}
+
+	for i, character := range characters {
+		characters[i].Actors = nil
+		for _, actorID := range actorIDsByCharacter[character.ID] {
+			characters[i].Actors = append(characters[i].Actors, actorsByID[actorID])
+		}
+	}
+
+	return nil
+}
+
+type listCursor struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+func newListCursor(character Character) listCursor {
+	return listCursor{Name: dynamo.NormalizeKey(character.Name), ID: character.ID}
+}
+
+func (c listCursor) compare(other listCursor) int {
+	return cmp.Or(cmp.Compare(c.Name, other.Name), cmp.Compare(c.ID, other.ID))
+}
+
+func encodeCursor(cursor listCursor) string {
+	jsonCursor, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(jsonCursor)
+}
+
+func decodeCursor(cursor string) (listCursor, error) {
+	if cursor == "" {
+		return listCursor{}, nil
+	}
+
+	jsonCursor, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return listCursor{}, fmt.Errorf("%w: %s", dynamo.ErrInvalidCursor, cursor)
+	}
+
+	var decoded listCursor
+	err = json.Unmarshal(jsonCursor, &decoded)
+	if err != nil || decoded.ID == "" {
+		return listCursor{}, fmt.Errorf("%w: %s", dynamo.ErrInvalidCursor, cursor)
+	}
+
+	return decoded, nil
+}
+
+func newSearchDocument(character Character) search.Document {
+	return search.Document{Type: searchDocumentType, ID: character.ID, Title: character.Name}
 }
@@ -2,9 +2,14 @@ package characters
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"testing"
 
+	"github.com/ggoulart/michael-connelly-api/internal/actors"
 	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/search"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,52 +17,211 @@ import (
 func TestService_Create(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
-		name    string
-		setup   func(*StorageCharacterMock, *StorageBookMock)
-		want    Character
-		wantErr error
+		name        string
+		setup       func(*StorageCharacterMock, *StorageBookMock, *StorageActorMock, *CastMock, *IndexerMock)
+		want        Character
+		wantCreated bool
+		wantErr     error
 	}{
 		{
 			name: "when failed to get book by title",
-			setup: func(_ *StorageCharacterMock, b *StorageBookMock) {
-				b.On("GetByTitle", ctx, "The Black Echo").Return(books.Book{}, assert.AnError)
+			setup: func(_ *StorageCharacterMock, b *StorageBookMock, _ *StorageActorMock, _ *CastMock, _ *IndexerMock) {
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{}, assert.AnError)
 			},
 			wantErr: assert.AnError,
 		},
 		{
 			name: "failed to save character",
-			setup: func(c *StorageCharacterMock, b *StorageBookMock) {
+			setup: func(c *StorageCharacterMock, b *StorageBookMock, _ *StorageActorMock, _ *CastMock, _ *IndexerMock) {
 				book := books.Book{ID: "random-book-id", Title: "The Black Echo"}
-				b.On("GetByTitle", ctx, "The Black Echo").Return(book, nil)
-				c.On("Save", ctx, Character{Name: "Harry Bosch", Books: []books.Book{book}}).Return(Character{}, assert.AnError)
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{book}, nil)
+				c.On("Save", ctx, Character{Name: "Harry Bosch", Books: []Appearance{{Book: book, Role: RoleProtagonist}}}).Return(Character{}, false, assert.AnError)
 			},
 			wantErr: assert.AnError,
 		},
 		{
 			name: "successfully saved character",
-			setup: func(c *StorageCharacterMock, b *StorageBookMock) {
+			setup: func(c *StorageCharacterMock, b *StorageBookMock, _ *StorageActorMock, _ *CastMock, i *IndexerMock) {
 				book := books.Book{ID: "random-book-id", Title: "The Black Echo"}
-				b.On("GetByTitle", ctx, "The Black Echo").Return(book, nil)
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{book}, nil)
 				savedCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}
-				c.On("Save", ctx, Character{Name: "Harry Bosch", Books: []books.Book{book}}).Return(savedCharacter, nil)
+				c.On("Save", ctx, Character{Name: "Harry Bosch", Books: []Appearance{{Book: book, Role: RoleProtagonist}}}).Return(savedCharacter, true, nil)
+				i.On("Put", search.Document{Type: "character", ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch"})
+			},
+			want:        Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"},
+			wantCreated: true,
+		},
+		{
+			name: "when failed to load actors of existing character",
+			setup: func(c *StorageCharacterMock, b *StorageBookMock, _ *StorageActorMock, cast *CastMock, i *IndexerMock) {
+				book := books.Book{ID: "random-book-id", Title: "The Black Echo"}
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{book}, nil)
+				existingCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}, Role: RoleProtagonist}}}
+				c.On("Save", ctx, Character{Name: "Harry Bosch", Books: []Appearance{{Book: book, Role: RoleProtagonist}}}).Return(existingCharacter, false, nil)
+				i.On("Put", search.Document{Type: "character", ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch"})
+				b.On("GetByIds", ctx, []string{"random-book-id"}).Return([]books.Book{book}, nil)
+				cast.On("GetActorIDs", ctx, []string{"c6767b2d-438b-4d4c-8b1a-659130a640ca"}).Return(map[string][]string{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when character already exists",
+			setup: func(c *StorageCharacterMock, b *StorageBookMock, a *StorageActorMock, cast *CastMock, i *IndexerMock) {
+				book := books.Book{ID: "random-book-id", Title: "The Black Echo"}
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{book}, nil)
+				existingCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}, Role: RoleProtagonist}}}
+				c.On("Save", ctx, Character{Name: "Harry Bosch", Books: []Appearance{{Book: book, Role: RoleProtagonist}}}).Return(existingCharacter, false, nil)
+				i.On("Put", search.Document{Type: "character", ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch"})
+				b.On("GetByIds", ctx, []string{"random-book-id"}).Return([]books.Book{book}, nil)
+				cast.On("GetActorIDs", ctx, []string{"c6767b2d-438b-4d4c-8b1a-659130a640ca"}).Return(map[string][]string{"c6767b2d-438b-4d4c-8b1a-659130a640ca": {"actor-id"}}, nil)
+				a.On("GetByIds", ctx, []string{"actor-id"}).Return([]actors.Actor{{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}}, nil)
+			},
+			want: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id", Title: "The Black Echo"}, Role: RoleProtagonist}}, Actors: []actors.Actor{{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageCharacter := new(StorageCharacterMock)
+			storageBook := new(StorageBookMock)
+			storageActor := new(StorageActorMock)
+			cast := new(CastMock)
+			indexer := new(IndexerMock)
+			tt.setup(storageCharacter, storageBook, storageActor, cast, indexer)
+
+			s := NewService(storageCharacter, storageBook, storageActor, cast, indexer)
+
+			got, created, err := s.Create(ctx, Character{Name: "Harry Bosch"}, []Appearance{{Book: books.Book{Title: "The Black Echo"}, Role: RoleProtagonist}})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			storageCharacter.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+			storageActor.AssertExpectations(t)
+			cast.AssertExpectations(t)
+			indexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		appearances []Appearance
+		setup       func(*StorageCharacterMock, *StorageBookMock, *CastMock, *IndexerMock)
+		want        Character
+		wantErr     error
+	}{
+		{
+			name:        "when failed to get book by title",
+			appearances: []Appearance{{Book: books.Book{Title: "The Black Echo"}}},
+			setup: func(_ *StorageCharacterMock, b *StorageBookMock, _ *CastMock, _ *IndexerMock) {
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to load kept books",
+			setup: func(c *StorageCharacterMock, b *StorageBookMock, _ *CastMock, i *IndexerMock) {
+				character := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}
+				c.On("Update", ctx, character).Return(Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}}}}, nil)
+				i.On("Put", search.Document{Type: "character", ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch"})
+				b.On("GetByIds", ctx, []string{"random-book-id"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when book titles are not sent",
+			setup: func(c *StorageCharacterMock, b *StorageBookMock, cast *CastMock, i *IndexerMock) {
+				character := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}
+				c.On("Update", ctx, character).Return(Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}}}}, nil)
+				i.On("Put", search.Document{Type: "character", ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch"})
+				b.On("GetByIds", ctx, []string{"random-book-id"}).Return([]books.Book{{ID: "random-book-id", Title: "The Black Echo", Year: 1992}}, nil)
+				cast.On("GetActorIDs", ctx, []string{"c6767b2d-438b-4d4c-8b1a-659130a640ca"}).Return(map[string][]string{}, nil)
 			},
-			want: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"},
+			want: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id", Title: "The Black Echo", Year: 1992}}}},
+		},
+		{
+			name:        "when failed to update character",
+			appearances: []Appearance{{Book: books.Book{Title: "The Black Echo"}}},
+			setup: func(c *StorageCharacterMock, b *StorageBookMock, _ *CastMock, _ *IndexerMock) {
+				book := books.Book{ID: "random-book-id", Title: "The Black Echo"}
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{book}, nil)
+				c.On("Update", ctx, Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: book}}}).Return(Character{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			storageCharacter := new(StorageCharacterMock)
 			storageBook := new(StorageBookMock)
-			tt.setup(storageCharacter, storageBook)
+			cast := new(CastMock)
+			indexer := new(IndexerMock)
+			tt.setup(storageCharacter, storageBook, cast, indexer)
 
-			s := NewService(storageCharacter, storageBook)
+			s := NewService(storageCharacter, storageBook, new(StorageActorMock), cast, indexer)
 
-			got, err := s.Create(ctx, Character{Name: "Harry Bosch"}, []string{"The Black Echo"})
+			got, err := s.Update(ctx, Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}, tt.appearances)
 
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
 			storageCharacter.AssertExpectations(t)
 			storageBook.AssertExpectations(t)
+			cast.AssertExpectations(t)
+			indexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageCharacterMock, *CastMock, *IndexerMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to delete character",
+			setup: func(s *StorageCharacterMock, _ *CastMock, _ *IndexerMock) {
+				s.On("Delete", ctx, "a-random-character-id").Return(assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to remove character from cast",
+			setup: func(s *StorageCharacterMock, c *CastMock, i *IndexerMock) {
+				s.On("Delete", ctx, "a-random-character-id").Return(nil)
+				i.On("Remove", "character", "a-random-character-id")
+				c.On("RemoveCharacter", ctx, "a-random-character-id").Return(assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully deleted character",
+			setup: func(s *StorageCharacterMock, c *CastMock, i *IndexerMock) {
+				s.On("Delete", ctx, "a-random-character-id").Return(nil)
+				i.On("Remove", "character", "a-random-character-id")
+				c.On("RemoveCharacter", ctx, "a-random-character-id").Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageCharacter := new(StorageCharacterMock)
+			cast := new(CastMock)
+			indexer := new(IndexerMock)
+			tt.setup(storageCharacter, cast, indexer)
+
+			s := NewService(storageCharacter, nil, new(StorageActorMock), cast, indexer)
+
+			err := s.Delete(ctx, "a-random-character-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			storageCharacter.AssertExpectations(t)
+			cast.AssertExpectations(t)
+			indexer.AssertExpectations(t)
 		})
 	}
 }
@@ -66,37 +230,85 @@ func TestService_GetById(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
 		name    string
-		setup   func(s *StorageCharacterMock)
+		setup   func(*StorageCharacterMock, *StorageBookMock, *StorageActorMock, *CastMock)
 		want    Character
 		wantErr error
 	}{
 		{
 			name: "failed to get character",
-			setup: func(s *StorageCharacterMock) {
+			setup: func(s *StorageCharacterMock, _ *StorageBookMock, _ *StorageActorMock, _ *CastMock) {
 				s.On("GetById", ctx, "a-random-character-id").Return(Character{}, assert.AnError)
 			},
 			wantErr: assert.AnError,
 		},
 		{
-			name: "successfully saved character",
-			setup: func(s *StorageCharacterMock) {
+			name: "failed to get character book",
+			setup: func(s *StorageCharacterMock, b *StorageBookMock, _ *StorageActorMock, _ *CastMock) {
+				returnedCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}}}}
+				s.On("GetById", ctx, "a-random-character-id").Return(returnedCharacter, nil)
+				b.On("GetByIds", ctx, []string{"random-book-id"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "failed to get character cast",
+			setup: func(s *StorageCharacterMock, _ *StorageBookMock, _ *StorageActorMock, c *CastMock) {
+				returnedCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}
+				s.On("GetById", ctx, "a-random-character-id").Return(returnedCharacter, nil)
+				c.On("GetActorIDs", ctx, []string{"c6767b2d-438b-4d4c-8b1a-659130a640ca"}).Return(map[string][]string{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "failed to get character actors",
+			setup: func(s *StorageCharacterMock, _ *StorageBookMock, a *StorageActorMock, c *CastMock) {
 				returnedCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"}
 				s.On("GetById", ctx, "a-random-character-id").Return(returnedCharacter, nil)
+				c.On("GetActorIDs", ctx, []string{"c6767b2d-438b-4d4c-8b1a-659130a640ca"}).Return(map[string][]string{"c6767b2d-438b-4d4c-8b1a-659130a640ca": {"actor-id"}}, nil)
+				a.On("GetByIds", ctx, []string{"actor-id"}).Return([]actors.Actor{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "successfully get character skipping a missing book",
+			setup: func(s *StorageCharacterMock, b *StorageBookMock, _ *StorageActorMock, c *CastMock) {
+				returnedCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "deleted-book-id"}}, {Book: books.Book{ID: "random-book-id"}, Role: RoleProtagonist}}}
+				s.On("GetById", ctx, "a-random-character-id").Return(returnedCharacter, nil)
+				b.On("GetByIds", ctx, []string{"deleted-book-id", "random-book-id"}).Return([]books.Book{{ID: "random-book-id", Title: "The Black Echo", Year: 1992}}, nil)
+				c.On("GetActorIDs", ctx, []string{"c6767b2d-438b-4d4c-8b1a-659130a640ca"}).Return(map[string][]string{}, nil)
 			},
-			want: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch"},
+			want: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id", Title: "The Black Echo", Year: 1992}, Role: RoleProtagonist}}},
+		},
+		{
+			name: "successfully get character",
+			setup: func(s *StorageCharacterMock, b *StorageBookMock, a *StorageActorMock, c *CastMock) {
+				returnedCharacter := Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}}}}
+				s.On("GetById", ctx, "a-random-character-id").Return(returnedCharacter, nil)
+				b.On("GetByIds", ctx, []string{"random-book-id"}).Return([]books.Book{{ID: "random-book-id", Title: "The Black Echo", Year: 1992}}, nil)
+				c.On("GetActorIDs", ctx, []string{"c6767b2d-438b-4d4c-8b1a-659130a640ca"}).Return(map[string][]string{"c6767b2d-438b-4d4c-8b1a-659130a640ca": {"actor-id"}}, nil)
+				a.On("GetByIds", ctx, []string{"actor-id"}).Return([]actors.Actor{{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 1}}, nil)
+			},
+			want: Character{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id", Title: "The Black Echo", Year: 1992}}}, Actors: []actors.Actor{{ID: "actor-id", Name: "Titus Welliver", IMDB: "nm0920038", Version: 1}}},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			storageCharacter := new(StorageCharacterMock)
-			tt.setup(storageCharacter)
+			storageBook := new(StorageBookMock)
+			storageActor := new(StorageActorMock)
+			cast := new(CastMock)
+			tt.setup(storageCharacter, storageBook, storageActor, cast)
 
-			s := NewService(storageCharacter, nil)
+			s := NewService(storageCharacter, storageBook, storageActor, cast, new(IndexerMock))
 
 			got, err := s.GetById(ctx, "a-random-character-id")
 
-			assert.Equal(t, got, tt.want)
+			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
+			storageCharacter.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+			storageActor.AssertExpectations(t)
+			cast.AssertExpectations(t)
 		})
 	}
 }
@@ -105,36 +317,283 @@ func TestService_GetByName(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
 		name    string
-		setup   func(s *StorageCharacterMock)
+		setup   func(*StorageCharacterMock, *StorageBookMock, *CastMock)
 		want    Character
 		wantErr error
 	}{
 		{
 			name: "when failed to get character",
-			setup: func(m *StorageCharacterMock) {
+			setup: func(m *StorageCharacterMock, _ *StorageBookMock, _ *CastMock) {
 				m.On("GetByName", ctx, "Harry Bosch").Return(Character{}, assert.AnError)
 			},
 			wantErr: assert.AnError,
 		},
+		{
+			name: "when failed to get character book",
+			setup: func(m *StorageCharacterMock, b *StorageBookMock, _ *CastMock) {
+				character := Character{ID: "random-id", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}}}}
+				m.On("GetByName", ctx, "Harry Bosch").Return(character, nil)
+				b.On("GetByIds", ctx, []string{"random-book-id"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
 		{
 			name: "successfully get character",
-			setup: func(m *StorageCharacterMock) {
-				character := Character{ID: "random-id", Name: "Harry Bosch"}
+			setup: func(m *StorageCharacterMock, b *StorageBookMock, c *CastMock) {
+				character := Character{ID: "random-id", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}}}}
 				m.On("GetByName", ctx, "Harry Bosch").Return(character, nil)
+				b.On("GetByIds", ctx, []string{"random-book-id"}).Return([]books.Book{{ID: "random-book-id", Title: "The Black Echo", Year: 1992}}, nil)
+				c.On("GetActorIDs", ctx, []string{"random-id"}).Return(map[string][]string{}, nil)
 			},
-			want: Character{ID: "random-id", Name: "Harry Bosch"},
+			want: Character{ID: "random-id", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id", Title: "The Black Echo", Year: 1992}}}},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			storageCharacter := new(StorageCharacterMock)
-			tt.setup(storageCharacter)
+			storageBook := new(StorageBookMock)
+			cast := new(CastMock)
+			tt.setup(storageCharacter, storageBook, cast)
 
-			s := NewService(storageCharacter, nil)
+			s := NewService(storageCharacter, storageBook, new(StorageActorMock), cast, new(IndexerMock))
 			got, err := s.GetByName(ctx, "Harry Bosch")
 
-			assert.Equal(t, got, tt.want)
+			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
+			storageCharacter.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+			cast.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetByBook(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageCharacterMock)
+		want    []Character
+		wantErr error
+	}{
+		{
+			name: "when failed to get characters by book",
+			setup: func(m *StorageCharacterMock) {
+				m.On("GetByBook", ctx, "book-id").Return([]Character{}, assert.AnError)
+			},
+			want:    []Character{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "successfully get characters by book",
+			setup: func(m *StorageCharacterMock) {
+				m.On("GetByBook", ctx, "book-id").Return([]Character{{ID: "random-id", Name: "Harry Bosch"}, {ID: "other-id", Name: "Renée Ballard"}}, nil)
+			},
+			want: []Character{{ID: "random-id", Name: "Harry Bosch"}, {ID: "other-id", Name: "Renée Ballard"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageCharacter := new(StorageCharacterMock)
+			tt.setup(storageCharacter)
+
+			s := NewService(storageCharacter, new(StorageBookMock), new(StorageActorMock), new(CastMock), new(IndexerMock))
+			got, err := s.GetByBook(ctx, "book-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageCharacter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	allCharacters := []Character{
+		{ID: "id-1", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-1"}}, {Book: books.Book{ID: "book-2"}}}},
+		{ID: "id-2", Name: "Mickey Haller", Books: []Appearance{{Book: books.Book{ID: "book-2"}}}},
+		{ID: "id-3", Name: "Renée Ballard"},
+	}
+	castActorIDs := map[string][]string{"id-3": {"maggie-id"}, "id-1": {"titus-id"}, "id-2": {"matthew-id", "manuel-id"}}
+	allActors := []actors.Actor{{ID: "titus-id", Name: "Titus Welliver"}, {ID: "matthew-id", Name: "Matthew McConaughey"}, {ID: "manuel-id", Name: "Manuel Garcia-Rulfo"}, {ID: "maggie-id", Name: "Maggie Q"}}
+	tests := []struct {
+		name           string
+		filter         Filter
+		limit          int32
+		cursor         string
+		setup          func(*StorageCharacterMock, *StorageBookMock, *StorageActorMock, *CastMock)
+		want           []Character
+		wantNextCursor string
+		wantErr        error
+	}{
+		{
+			name:    "when cursor is invalid",
+			limit:   20,
+			cursor:  "not base64!",
+			setup:   func(*StorageCharacterMock, *StorageBookMock, *StorageActorMock, *CastMock) {},
+			want:    []Character{},
+			wantErr: fmt.Errorf("%w: %s", dynamo.ErrInvalidCursor, "not base64!"),
+		},
+		{
+			name:  "when failed to get a page of characters",
+			limit: 20,
+			setup: func(s *StorageCharacterMock, _ *StorageBookMock, _ *StorageActorMock, _ *CastMock) {
+				s.On("GetPageByName", ctx, int32(21), "", "").Return([]Character(nil), assert.AnError)
+			},
+			want:    []Character{},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when failed to get characters by book",
+			filter: Filter{BookID: "book-2"},
+			limit:  20,
+			setup: func(s *StorageCharacterMock, _ *StorageBookMock, _ *StorageActorMock, _ *CastMock) {
+				s.On("GetByBook", ctx, "book-2").Return([]Character{}, assert.AnError)
+			},
+			want:    []Character{},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when failed to load actors",
+			limit: 20,
+			setup: func(s *StorageCharacterMock, _ *StorageBookMock, _ *StorageActorMock, c *CastMock) {
+				s.On("GetPageByName", ctx, int32(21), "", "").Return(cloneCharacters(allCharacters), nil)
+				c.On("GetActorIDs", ctx, []string{"id-1", "id-2", "id-3"}).Return(map[string][]string{}, assert.AnError)
+			},
+			want:    []Character{},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when failed to load books",
+			limit: 20,
+			setup: func(s *StorageCharacterMock, b *StorageBookMock, a *StorageActorMock, c *CastMock) {
+				s.On("GetPageByName", ctx, int32(21), "", "").Return(cloneCharacters(allCharacters), nil)
+				c.On("GetActorIDs", ctx, []string{"id-1", "id-2", "id-3"}).Return(castActorIDs, nil)
+				a.On("GetByIds", ctx, []string{"titus-id", "matthew-id", "manuel-id", "maggie-id"}).Return(allActors, nil)
+				b.On("GetByIds", ctx, []string{"book-1", "book-2"}).Return([]books.Book{}, assert.AnError)
+			},
+			want:    []Character{},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "successfully list first page sorted by name",
+			limit: 2,
+			setup: func(s *StorageCharacterMock, b *StorageBookMock, a *StorageActorMock, c *CastMock) {
+				s.On("GetPageByName", ctx, int32(3), "", "").Return(cloneCharacters(allCharacters), nil)
+				c.On("GetActorIDs", ctx, []string{"id-1", "id-2", "id-3"}).Return(castActorIDs, nil)
+				a.On("GetByIds", ctx, []string{"titus-id", "matthew-id", "manuel-id", "maggie-id"}).Return(allActors, nil)
+				b.On("GetByIds", ctx, []string{"book-1", "book-2"}).Return([]books.Book{{ID: "book-2", Title: "The Brass Verdict"}, {ID: "book-1", Title: "The Black Echo"}}, nil)
+			},
+			want: []Character{
+				{ID: "id-1", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "book-1", Title: "The Black Echo"}}, {Book: books.Book{ID: "book-2", Title: "The Brass Verdict"}}}, Actors: []actors.Actor{{ID: "titus-id", Name: "Titus Welliver"}}},
+				{ID: "id-2", Name: "Mickey Haller", Books: []Appearance{{Book: books.Book{ID: "book-2", Title: "The Brass Verdict"}}}, Actors: []actors.Actor{{ID: "matthew-id", Name: "Matthew McConaughey"}, {ID: "manuel-id", Name: "Manuel Garcia-Rulfo"}}},
+			},
+			wantNextCursor: encodeCursor(listCursor{Name: "mickey haller", ID: "id-2"}),
+		},
+		{
+			name:   "successfully list page after cursor",
+			limit:  2,
+			cursor: encodeCursor(listCursor{Name: "mickey haller", ID: "id-2"}),
+			setup: func(s *StorageCharacterMock, _ *StorageBookMock, a *StorageActorMock, c *CastMock) {
+				s.On("GetPageByName", ctx, int32(3), "mickey haller", "id-2").Return(cloneCharacters(allCharacters[2:]), nil)
+				c.On("GetActorIDs", ctx, []string{"id-3"}).Return(castActorIDs, nil)
+				a.On("GetByIds", ctx, []string{"maggie-id"}).Return(allActors[3:], nil)
+			},
+			want: []Character{{ID: "id-3", Name: "Renée Ballard", Actors: []actors.Actor{{ID: "maggie-id", Name: "Maggie Q"}}}},
+		},
+		{
+			name:   "successfully list characters filtered by book, actor and name",
+			filter: Filter{BookID: "book-2", Actor: "garcia", Name: "haller"},
+			limit:  20,
+			setup: func(s *StorageCharacterMock, b *StorageBookMock, a *StorageActorMock, c *CastMock) {
+				s.On("GetByBook", ctx, "book-2").Return(cloneCharacters(allCharacters[:2]), nil)
+				c.On("GetActorIDs", ctx, []string{"id-1", "id-2"}).Return(castActorIDs, nil)
+				a.On("GetByIds", ctx, []string{"titus-id", "matthew-id", "manuel-id"}).Return(allActors[:3], nil)
+				b.On("GetByIds", ctx, []string{"book-2"}).Return([]books.Book{{ID: "book-2", Title: "The Brass Verdict"}}, nil)
+			},
+			want: []Character{{ID: "id-2", Name: "Mickey Haller", Books: []Appearance{{Book: books.Book{ID: "book-2", Title: "The Brass Verdict"}}}, Actors: []actors.Actor{{ID: "matthew-id", Name: "Matthew McConaughey"}, {ID: "manuel-id", Name: "Manuel Garcia-Rulfo"}}}},
+		},
+		{
+			name:   "successfully list characters by name across index pages",
+			filter: Filter{Name: "ren"},
+			limit:  1,
+			setup: func(s *StorageCharacterMock, _ *StorageBookMock, a *StorageActorMock, c *CastMock) {
+				s.On("GetPageByName", ctx, int32(2), "", "").Return(cloneCharacters(allCharacters[:2]), nil)
+				c.On("GetActorIDs", ctx, []string{"id-1", "id-2"}).Return(castActorIDs, nil)
+				a.On("GetByIds", ctx, []string{"titus-id", "matthew-id", "manuel-id"}).Return(allActors[:3], nil)
+				s.On("GetPageByName", ctx, int32(2), "mickey haller", "id-2").Return(cloneCharacters(allCharacters[2:]), nil)
+				c.On("GetActorIDs", ctx, []string{"id-3"}).Return(castActorIDs, nil)
+				a.On("GetByIds", ctx, []string{"maggie-id"}).Return(allActors[3:], nil)
+			},
+			want: []Character{{ID: "id-3", Name: "Renée Ballard", Actors: []actors.Actor{{ID: "maggie-id", Name: "Maggie Q"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageCharacter := new(StorageCharacterMock)
+			storageBook := new(StorageBookMock)
+			storageActor := new(StorageActorMock)
+			cast := new(CastMock)
+			tt.setup(storageCharacter, storageBook, storageActor, cast)
+
+			s := NewService(storageCharacter, storageBook, storageActor, cast, new(IndexerMock))
+			got, nextCursor, err := s.List(ctx, tt.filter, tt.limit, tt.cursor)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantNextCursor, nextCursor)
+			assert.Equal(t, tt.wantErr, err)
+			storageCharacter.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+			storageActor.AssertExpectations(t)
+			cast.AssertExpectations(t)
+		})
+	}
+}
+
+func cloneCharacters(characters []Character) []Character {
+	var cloned []Character
+	for _, character := range characters {
+		character.Books = slices.Clone(character.Books)
+		cloned = append(cloned, character)
+	}
+
+	return cloned
+}
+
+func TestService_Documents(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageCharacterMock)
+		want    []search.Document
+		wantErr error
+	}{
+		{
+			name: "when failed to get all characters",
+			setup: func(s *StorageCharacterMock) {
+				s.On("GetAll", ctx).Return([]Character(nil), assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get search documents",
+			setup: func(s *StorageCharacterMock) {
+				s.On("GetAll", ctx).Return([]Character{{ID: "random-id", Name: "Harry Bosch", Books: []Appearance{{Book: books.Book{ID: "random-book-id"}}}}}, nil)
+			},
+			want: []search.Document{{Type: "character", ID: "random-id", Title: "Harry Bosch"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageCharacter := new(StorageCharacterMock)
+			tt.setup(storageCharacter)
+
+			s := NewService(storageCharacter, nil, new(StorageActorMock), new(CastMock), new(IndexerMock))
+
+			got, err := s.Documents(ctx)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageCharacter.AssertExpectations(t)
 		})
 	}
 }
@@ -148,21 +607,86 @@ func (s *StorageCharacterMock) GetByName(ctx context.Context, characterName stri
 	return args.Get(0).(Character), args.Error(1)
 }
 
-func (s *StorageCharacterMock) Save(ctx context.Context, character Character) (Character, error) {
+func (s *StorageCharacterMock) Save(ctx context.Context, character Character) (Character, bool, error) {
+	args := s.Called(ctx, character)
+	return args.Get(0).(Character), args.Bool(1), args.Error(2)
+}
+
+func (s *StorageCharacterMock) Update(ctx context.Context, character Character) (Character, error) {
 	args := s.Called(ctx, character)
 	return args.Get(0).(Character), args.Error(1)
 }
 
+func (s *StorageCharacterMock) Delete(ctx context.Context, characterID string) error {
+	args := s.Called(ctx, characterID)
+	return args.Error(0)
+}
+
 func (s *StorageCharacterMock) GetById(ctx context.Context, characterID string) (Character, error) {
 	args := s.Called(ctx, characterID)
 	return args.Get(0).(Character), args.Error(1)
 }
 
+func (s *StorageCharacterMock) GetByBook(ctx context.Context, bookID string) ([]Character, error) {
+	args := s.Called(ctx, bookID)
+	return args.Get(0).([]Character), args.Error(1)
+}
+
+func (s *StorageCharacterMock) GetPageByName(ctx context.Context, limit int32, afterName string, afterID string) ([]Character, error) {
+	args := s.Called(ctx, limit, afterName, afterID)
+	return args.Get(0).([]Character), args.Error(1)
+}
+
+func (s *StorageCharacterMock) GetAll(ctx context.Context) ([]Character, error) {
+	args := s.Called(ctx)
+	return args.Get(0).([]Character), args.Error(1)
+}
+
 type StorageBookMock struct {
 	mock.Mock
 }
 
-func (s *StorageBookMock) GetByTitle(ctx context.Context, bookTitle string) (books.Book, error) {
-	args := s.Called(ctx, bookTitle)
-	return args.Get(0).(books.Book), args.Error(1)
+func (s *StorageBookMock) GetByIds(ctx context.Context, bookIDs []string) ([]books.Book, error) {
+	args := s.Called(ctx, bookIDs)
+	return args.Get(0).([]books.Book), args.Error(1)
+}
+
+func (s *StorageBookMock) GetBookListByTitles(ctx context.Context, bookTitles []string) ([]books.Book, error) {
+	args := s.Called(ctx, bookTitles)
+	return args.Get(0).([]books.Book), args.Error(1)
+}
+
+type StorageActorMock struct {
+	mock.Mock
+}
+
+func (s *StorageActorMock) GetByIds(ctx context.Context, actorIDs []string) ([]actors.Actor, error) {
+	args := s.Called(ctx, actorIDs)
+	return args.Get(0).([]actors.Actor), args.Error(1)
+}
+
+type CastMock struct {
+	mock.Mock
+}
+
+func (c *CastMock) GetActorIDs(ctx context.Context, characterIDs []string) (map[string][]string, error) {
+	args := c.Called(ctx, characterIDs)
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
+
+func (c *CastMock) RemoveCharacter(ctx context.Context, characterID string) error {
+	args := c.Called(ctx, characterID)
+	return args.Error(0)
+}
+
+type IndexerMock struct {
+	mock.Mock
+}
+
+func (i *IndexerMock) Put(document search.Document) {
+	i.Called(document)
+}
+
+func (i *IndexerMock) Remove(documentType string, id string) {
+	i.Called(documentType, id)
 }
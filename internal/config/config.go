@@ -0,0 +1,278 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/spf13/viper"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+const (
+	StorageDynamoDB = "dynamodb"
+	StorageMemory   = "memory"
+
+	CredentialsDefault = "default"
+	CredentialsStatic  = "static"
+)
+
+var RateLimitGroups = []string{"actors", "adaptations", "books", "characters", "series", "search"}
+
+type Config struct {
+	Storage   StorageConfig
+	AWS       AWSConfig
+	Auth      AuthConfig
+	RateLimit RateLimitConfig
+	Search    SearchConfig
+}
+
+type StorageConfig struct {
+	Driver          string
+	DuplicatePolicy dynamo.DuplicatePolicy
+	Tables          TablesConfig
+}
+
+type TablesConfig struct {
+	Actors        string
+	Adaptations   string
+	APIKeys       string
+	Books         string
+	Characters    string
+	Series        string
+	Relations     string
+	Relationships string
+	UniqueKeys    string
+}
+
+func (t TablesConfig) Names() []string {
+	return []string{t.UniqueKeys, t.Actors, t.Adaptations, t.APIKeys, t.Books, t.Characters, t.Series, t.Relations, t.Relationships}
+}
+
+type AWSConfig struct {
+	Region           string
+	CredentialsMode  string
+	AccessKeyID      string
+	SecretAccessKey  string
+	DynamoDBEndpoint string
+}
+
+type AuthConfig struct {
+	JWT JWTConfig
+}
+
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	JWKS     string
+	Secret   string
+}
+
+type RateLimitConfig struct {
+	TrustedHops int
+	IdleTimeout time.Duration
+	Groups      map[string]LimitConfig
+}
+
+type SearchConfig struct {
+	MaxAge time.Duration
+}
+
+type LimitConfig struct {
+	PerMinute int
+	Burst     int
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("aws.region", "us-east-1")
+	v.SetDefault("aws.credentials.mode", CredentialsDefault)
+	if v.GetString("aws.dynamodb.endpoint") != "" {
+		v.SetDefault("aws.credentials.mode", CredentialsStatic)
+	}
+	v.SetDefault("aws.credentials.accessKeyId", "local")
+	v.SetDefault("aws.credentials.secretAccessKey", "local")
+	v.SetDefault("storage.driver", StorageDynamoDB)
+	v.SetDefault("storage.tables.actors", "actors")
+	v.SetDefault("storage.tables.adaptations", "adaptations")
+	v.SetDefault("storage.tables.apiKeys", "api_keys")
+	v.SetDefault("storage.tables.books", "books")
+	v.SetDefault("storage.tables.characters", "characters")
+	v.SetDefault("storage.tables.series", "series")
+	v.SetDefault("storage.tables.relations", "relations")
+	v.SetDefault("storage.tables.relationships", "relationships")
+	v.SetDefault("storage.tables.uniqueKeys", "unique_keys")
+	v.SetDefault("auth.jwt.issuer", "michael-connelly-api")
+	v.SetDefault("auth.jwt.audience", "michael-connelly-api")
+	v.SetDefault("rateLimit.trustedHops", 0)
+	v.SetDefault("rateLimit.idleTimeout", 10*time.Minute)
+	v.SetDefault("rateLimit.default.perMinute", 5)
+	v.SetDefault("rateLimit.default.burst", 10)
+	v.SetDefault("search.maxAge", time.Minute)
+}
+
+func Load(v *viper.Viper) (Config, error) {
+	setDefaults(v)
+
+	var errs []error
+
+	duplicatePolicy, err := dynamo.ParseDuplicatePolicy(v.GetString("storage.duplicatePolicy"))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("storage.duplicatePolicy: %w", err))
+	}
+
+	prefix := v.GetString("storage.tablePrefix")
+	config := Config{
+		Storage: StorageConfig{
+			Driver:          v.GetString("storage.driver"),
+			DuplicatePolicy: duplicatePolicy,
+			Tables: TablesConfig{
+				Actors:        tableName(prefix, v.GetString("storage.tables.actors")),
+				Adaptations:   tableName(prefix, v.GetString("storage.tables.adaptations")),
+				APIKeys:       tableName(prefix, v.GetString("storage.tables.apiKeys")),
+				Books:         tableName(prefix, v.GetString("storage.tables.books")),
+				Characters:    tableName(prefix, v.GetString("storage.tables.characters")),
+				Series:        tableName(prefix, v.GetString("storage.tables.series")),
+				Relations:     tableName(prefix, v.GetString("storage.tables.relations")),
+				Relationships: tableName(prefix, v.GetString("storage.tables.relationships")),
+				UniqueKeys:    tableName(prefix, v.GetString("storage.tables.uniqueKeys")),
+			},
+		},
+		AWS: AWSConfig{
+			Region:           v.GetString("aws.region"),
+			CredentialsMode:  v.GetString("aws.credentials.mode"),
+			AccessKeyID:      v.GetString("aws.credentials.accessKeyId"),
+			SecretAccessKey:  v.GetString("aws.credentials.secretAccessKey"),
+			DynamoDBEndpoint: v.GetString("aws.dynamodb.endpoint"),
+		},
+		Auth: AuthConfig{
+			JWT: JWTConfig{
+				Issuer:   v.GetString("auth.jwt.issuer"),
+				Audience: v.GetString("auth.jwt.audience"),
+				JWKS:     v.GetString("auth.jwt.jwks"),
+				Secret:   v.GetString("auth.jwt.secret"),
+			},
+		},
+		RateLimit: RateLimitConfig{
+			TrustedHops: v.GetInt("rateLimit.trustedHops"),
+			IdleTimeout: v.GetDuration("rateLimit.idleTimeout"),
+			Groups:      map[string]LimitConfig{},
+		},
+		Search: SearchConfig{
+			MaxAge: v.GetDuration("search.maxAge"),
+		},
+	}
+
+	for _, group := range RateLimitGroups {
+		config.RateLimit.Groups[group] = LimitConfig{
+			PerMinute: groupSetting(v, group, "perMinute"),
+			Burst:     groupSetting(v, group, "burst"),
+		}
+	}
+
+	errs = append(errs, config.validate()...)
+	if len(errs) > 0 {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+
+	return config, nil
+}
+
+func (c Config) validate() []error {
+	var errs []error
+
+	if !slices.Contains([]string{StorageDynamoDB, StorageMemory}, c.Storage.Driver) {
+		errs = append(errs, fmt.Errorf("storage.driver: must be one of %s, %s. got: %q", StorageDynamoDB, StorageMemory, c.Storage.Driver))
+	}
+
+	tables := []struct {
+		key  string
+		name string
+	}{
+		{"storage.tables.actors", c.Storage.Tables.Actors},
+		{"storage.tables.adaptations", c.Storage.Tables.Adaptations},
+		{"storage.tables.apiKeys", c.Storage.Tables.APIKeys},
+		{"storage.tables.books", c.Storage.Tables.Books},
+		{"storage.tables.characters", c.Storage.Tables.Characters},
+		{"storage.tables.series", c.Storage.Tables.Series},
+		{"storage.tables.relations", c.Storage.Tables.Relations},
+		{"storage.tables.relationships", c.Storage.Tables.Relationships},
+		{"storage.tables.uniqueKeys", c.Storage.Tables.UniqueKeys},
+	}
+
+	seen := map[string]string{}
+	for _, table := range tables {
+		if table.name == "" {
+			errs = append(errs, fmt.Errorf("%s: is required", table.key))
+			continue
+		}
+		if key, ok := seen[table.name]; ok {
+			errs = append(errs, fmt.Errorf("%s: table %q is already used by %s", table.key, table.name, key))
+			continue
+		}
+		seen[table.name] = table.key
+	}
+
+	if c.Auth.JWT.Issuer == "" {
+		errs = append(errs, errors.New("auth.jwt.issuer: is required"))
+	}
+	if c.Auth.JWT.Audience == "" {
+		errs = append(errs, errors.New("auth.jwt.audience: is required"))
+	}
+
+	if c.RateLimit.TrustedHops < 0 {
+		errs = append(errs, fmt.Errorf("rateLimit.trustedHops: must not be negative. got: %d", c.RateLimit.TrustedHops))
+	}
+	if c.RateLimit.IdleTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("rateLimit.idleTimeout: must be positive. got: %s", c.RateLimit.IdleTimeout))
+	}
+	for _, group := range RateLimitGroups {
+		limit := c.RateLimit.Groups[group]
+		if limit.PerMinute <= 0 || limit.Burst <= 0 {
+			errs = append(errs, fmt.Errorf("rateLimit.groups.%s: perMinute and burst must be positive. got: %d, %d", group, limit.PerMinute, limit.Burst))
+		}
+	}
+
+	if c.Search.MaxAge <= 0 {
+		errs = append(errs, fmt.Errorf("search.maxAge: must be positive. got: %s", c.Search.MaxAge))
+	}
+
+	if c.Storage.Driver != StorageDynamoDB {
+		return errs
+	}
+
+	if c.AWS.Region == "" {
+		errs = append(errs, errors.New("aws.region: is required"))
+	}
+
+	switch c.AWS.CredentialsMode {
+	case CredentialsDefault:
+	case CredentialsStatic:
+		if c.AWS.AccessKeyID == "" || c.AWS.SecretAccessKey == "" {
+			errs = append(errs, errors.New("aws.credentials: accessKeyId and secretAccessKey are required in static mode"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("aws.credentials.mode: must be one of %s, %s. got: %q", CredentialsDefault, CredentialsStatic, c.AWS.CredentialsMode))
+	}
+
+	return errs
+}
+
+func groupSetting(v *viper.Viper, group string, setting string) int {
+	key := "rateLimit.groups." + group + "." + setting
+	if v.IsSet(key) {
+		return v.GetInt(key)
+	}
+
+	return v.GetInt("rateLimit.default." + setting)
+}
+
+func tableName(prefix string, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	return prefix + name
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(*viper.Viper)
+		want    Config
+		wantErr string
+	}{
+		{
+			name:  "when nothing is configured",
+			setup: func(v *viper.Viper) {},
+			want: Config{
+				Storage: StorageConfig{
+					Driver:          StorageDynamoDB,
+					DuplicatePolicy: dynamo.DuplicateReject,
+					Tables:          TablesConfig{Actors: "actors", Adaptations: "adaptations", APIKeys: "api_keys", Books: "books", Characters: "characters", Series: "series", Relations: "relations", Relationships: "relationships", UniqueKeys: "unique_keys"},
+				},
+				AWS:       AWSConfig{Region: "us-east-1", CredentialsMode: CredentialsDefault, AccessKeyID: "local", SecretAccessKey: "local"},
+				Auth:      AuthConfig{JWT: JWTConfig{Issuer: "michael-connelly-api", Audience: "michael-connelly-api"}},
+				RateLimit: defaultRateLimit(),
+				Search:    SearchConfig{MaxAge: time.Minute},
+			},
+		},
+		{
+			name: "when dynamodb endpoint is overridden",
+			setup: func(v *viper.Viper) {
+				v.Set("aws.dynamodb.endpoint", "http://localhost:8000")
+			},
+			want: Config{
+				Storage: StorageConfig{
+					Driver:          StorageDynamoDB,
+					DuplicatePolicy: dynamo.DuplicateReject,
+					Tables:          TablesConfig{Actors: "actors", Adaptations: "adaptations", APIKeys: "api_keys", Books: "books", Characters: "characters", Series: "series", Relations: "relations", Relationships: "relationships", UniqueKeys: "unique_keys"},
+				},
+				AWS:       AWSConfig{Region: "us-east-1", CredentialsMode: CredentialsStatic, AccessKeyID: "local", SecretAccessKey: "local", DynamoDBEndpoint: "http://localhost:8000"},
+				Auth:      AuthConfig{JWT: JWTConfig{Issuer: "michael-connelly-api", Audience: "michael-connelly-api"}},
+				RateLimit: defaultRateLimit(),
+				Search:    SearchConfig{MaxAge: time.Minute},
+			},
+		},
+		{
+			name: "when table prefix and aws settings are configured",
+			setup: func(v *viper.Viper) {
+				v.Set("storage.tablePrefix", "staging_")
+				v.Set("storage.tables.books", "novels")
+				v.Set("storage.duplicatePolicy", "upsert")
+				v.Set("aws.region", "sa-east-1")
+				v.Set("aws.credentials.mode", CredentialsDefault)
+				v.Set("aws.dynamodb.endpoint", "http://localhost:8000")
+				v.Set("auth.jwt.issuer", "https://auth.example.com/")
+				v.Set("auth.jwt.audience", "books-api")
+				v.Set("auth.jwt.jwks", "https://auth.example.com/.well-known/jwks.json")
+				v.Set("auth.jwt.secret", "s3cr3t")
+				v.Set("rateLimit.trustedHops", 1)
+				v.Set("rateLimit.idleTimeout", "5m")
+				v.Set("rateLimit.default.perMinute", 60)
+				v.Set("rateLimit.groups.search.burst", 5)
+				v.Set("search.maxAge", "30s")
+			},
+			want: Config{
+				Storage: StorageConfig{
+					Driver:          StorageDynamoDB,
+					DuplicatePolicy: dynamo.DuplicateUpsert,
+					Tables:          TablesConfig{Actors: "staging_actors", Adaptations: "staging_adaptations", APIKeys: "staging_api_keys", Books: "staging_novels", Characters: "staging_characters", Series: "staging_series", Relations: "staging_relations", Relationships: "staging_relationships", UniqueKeys: "staging_unique_keys"},
+				},
+				AWS:  AWSConfig{Region: "sa-east-1", CredentialsMode: CredentialsDefault, AccessKeyID: "local", SecretAccessKey: "local", DynamoDBEndpoint: "http://localhost:8000"},
+				Auth: AuthConfig{JWT: JWTConfig{Issuer: "https://auth.example.com/", Audience: "books-api", JWKS: "https://auth.example.com/.well-known/jwks.json", Secret: "s3cr3t"}},
+				RateLimit: RateLimitConfig{TrustedHops: 1, IdleTimeout: 5 * time.Minute, Groups: map[string]LimitConfig{
+					"actors": {PerMinute: 60, Burst: 10}, "adaptations": {PerMinute: 60, Burst: 10}, "books": {PerMinute: 60, Burst: 10},
+					"characters": {PerMinute: 60, Burst: 10}, "series": {PerMinute: 60, Burst: 10}, "search": {PerMinute: 60, Burst: 5},
+				}},
+				Search: SearchConfig{MaxAge: 30 * time.Second},
+			},
+		},
+		{
+			name: "when memory driver skips aws validation",
+			setup: func(v *viper.Viper) {
+				v.Set("storage.driver", StorageMemory)
+				v.Set("aws.region", "")
+				v.Set("aws.credentials.mode", "unknown")
+			},
+			want: Config{
+				Storage: StorageConfig{
+					Driver:          StorageMemory,
+					DuplicatePolicy: dynamo.DuplicateReject,
+					Tables:          TablesConfig{Actors: "actors", Adaptations: "adaptations", APIKeys: "api_keys", Books: "books", Characters: "characters", Series: "series", Relations: "relations", Relationships: "relationships", UniqueKeys: "unique_keys"},
+				},
+				AWS:       AWSConfig{CredentialsMode: "unknown", AccessKeyID: "local", SecretAccessKey: "local"},
+				Auth:      AuthConfig{JWT: JWTConfig{Issuer: "michael-connelly-api", Audience: "michael-connelly-api"}},
+				RateLimit: defaultRateLimit(),
+				Search:    SearchConfig{MaxAge: time.Minute},
+			},
+		},
+		{
+			name: "when config is invalid",
+			setup: func(v *viper.Viper) {
+				v.Set("storage.driver", "postgres")
+				v.Set("storage.duplicatePolicy", "merge")
+				v.Set("storage.tables.series", "")
+				v.Set("storage.tables.characters", "books")
+				v.Set("auth.jwt.audience", "")
+				v.Set("rateLimit.idleTimeout", "0s")
+				v.Set("rateLimit.groups.search.burst", 0)
+				v.Set("search.maxAge", "0s")
+			},
+			wantErr: "invalid config: storage.duplicatePolicy: dynamodb: invalid duplicate policy: merge\n" +
+				"storage.driver: must be one of dynamodb, memory. got: \"postgres\"\n" +
+				"storage.tables.characters: table \"books\" is already used by storage.tables.books\n" +
+				"storage.tables.series: is required\n" +
+				"auth.jwt.audience: is required\n" +
+				"rateLimit.idleTimeout: must be positive. got: 0s\n" +
+				"rateLimit.groups.search: perMinute and burst must be positive. got: 5, 0\n" +
+				"search.maxAge: must be positive. got: 0s",
+		},
+		{
+			name: "when aws config is invalid",
+			setup: func(v *viper.Viper) {
+				v.Set("aws.region", "")
+				v.Set("aws.credentials.mode", CredentialsStatic)
+				v.Set("aws.credentials.secretAccessKey", "")
+			},
+			wantErr: "invalid config: aws.region: is required\n" +
+				"aws.credentials: accessKeyId and secretAccessKey are required in static mode",
+		},
+		{
+			name: "when credentials mode is invalid",
+			setup: func(v *viper.Viper) {
+				v.Set("aws.credentials.mode", "iam")
+			},
+			wantErr: "invalid config: aws.credentials.mode: must be one of default, static. got: \"iam\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			tt.setup(v)
+
+			got, err := Load(v)
+
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidConfig)
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func defaultRateLimit() RateLimitConfig {
+	limit := LimitConfig{PerMinute: 5, Burst: 10}
+
+	return RateLimitConfig{
+		IdleTimeout: 10 * time.Minute,
+		Groups:      map[string]LimitConfig{"actors": limit, "adaptations": limit, "books": limit, "characters": limit, "series": limit, "search": limit},
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+func ReadFiles(v *viper.Viper, path string, env string) error {
+	v.SetConfigType("yml")
+	v.AddConfigPath(path)
+	v.SetEnvPrefix("MCAPI")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	v.SetConfigName("config")
+	err := v.MergeInConfig()
+	if err != nil && !isConfigNotFound(err) {
+		return err
+	}
+
+	v.SetConfigName("config." + env)
+	err = v.MergeInConfig()
+	if err != nil && !isConfigNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func isConfigNotFound(err error) bool {
+	var notFound viper.ConfigFileNotFoundError
+	return errors.As(err, &notFound)
+}
+
+func Env() string {
+	if env := os.Getenv("APP_ENV"); env != "" {
+		return env
+	}
+
+	if IsLambda() {
+		return "production"
+	}
+
+	return "local"
+}
+
+func IsLambda() bool {
+	return os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != ""
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		appEnv string
+		lambda string
+		want   string
+	}{
+		{name: "when nothing is set", want: "local"},
+		{name: "when running in lambda", lambda: "michael-connelly-api", want: "production"},
+		{name: "when app env is set", appEnv: "staging", lambda: "michael-connelly-api", want: "staging"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("AWS_LAMBDA_FUNCTION_NAME", tt.lambda)
+
+			assert.Equal(t, tt.want, Env())
+		})
+	}
+}
+
+func TestReadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		env     map[string]string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "when no config file exists",
+			want: map[string]string{"storage.driver": ""},
+		},
+		{
+			name:  "when only base config exists",
+			files: map[string]string{"config.yml": "storage:\n  driver: dynamodb\n  tablePrefix: local_\n"},
+			want:  map[string]string{"storage.driver": "dynamodb", "storage.tablePrefix": "local_"},
+		},
+		{
+			name: "when env config is layered over base config",
+			files: map[string]string{
+				"config.yml":         "storage:\n  driver: dynamodb\n  tablePrefix: local_\n",
+				"config.staging.yml": "storage:\n  tablePrefix: staging_\n",
+			},
+			want: map[string]string{"storage.driver": "dynamodb", "storage.tablePrefix": "staging_"},
+		},
+		{
+			name: "when environment variables override config files",
+			files: map[string]string{
+				"config.yml":         "storage:\n  driver: dynamodb\n  tablePrefix: local_\n",
+				"config.staging.yml": "storage:\n  tablePrefix: staging_\n",
+			},
+			env:  map[string]string{"MCAPI_STORAGE_TABLEPREFIX": "blue_"},
+			want: map[string]string{"storage.driver": "dynamodb", "storage.tablePrefix": "blue_"},
+		},
+		{
+			name:    "when config file is invalid",
+			files:   map[string]string{"config.yml": "storage: [\n"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			v := viper.New()
+
+			err := ReadFiles(v, dir, "staging")
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			for key, value := range tt.want {
+				assert.Equal(t, value, v.GetString(key), key)
+			}
+		})
+	}
+}
@@ -2,8 +2,15 @@ package dynamo
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -15,51 +22,86 @@ import (
 var ErrDynamodb = errors.New("dynamodb: error")
 var ErrNotFound = errors.New("dynamodb: not found")
 var ErrDuplicated = errors.New("dynamodb: duplicated")
+var ErrInvalidCursor = errors.New("dynamodb: invalid cursor")
+var ErrVersionMismatch = errors.New("dynamodb: version mismatch")
+var ErrTooManyWrites = errors.New("dynamodb: too many writes in one transaction")
+var ErrSlugTaken = errors.New("dynamodb: slug taken")
+var ErrReferenced = errors.New("dynamodb: referenced")
+var ErrConditionFailed = errors.New("dynamodb: condition failed")
+
+type DuplicatedError struct {
+	ID string
+}
+
+func (e *DuplicatedError) Error() string {
+	return fmt.Sprintf("%s. id: %s", ErrDuplicated, e.ID)
+}
+
+func (e *DuplicatedError) Unwrap() error {
+	return ErrDuplicated
+}
 
 type Dynamodb interface {
 	GetItem(ctx context.Context, input *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	BatchGetItem(ctx context.Context, input *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
 	UpdateItem(ctx context.Context, input *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
 	TransactWriteItems(ctx context.Context, input *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
 	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
 	ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
 	Scan(ctx context.Context, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	Query(ctx context.Context, input *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
 }
 
-var uniqueKeyTable = "unique_keys"
+const AnyVersion = -1
+
+const batchGetLimit = 100
+const batchGetMaxAttempts = 5
+const TransactWriteLimit = 100
+
+var batchGetBackoff = 50 * time.Millisecond
 
 type Client struct {
-	dynamoDB Dynamodb
-	uuidGen  func() uuid.UUID
+	dynamoDB       Dynamodb
+	uuidGen        func() uuid.UUID
+	uniqueKeyTable string
 }
 
-func NewClient(dynamodb Dynamodb, uuidGen func() uuid.UUID) *Client {
-	return &Client{dynamoDB: dynamodb, uuidGen: uuidGen}
+func NewClient(dynamodb Dynamodb, uuidGen func() uuid.UUID, uniqueKeyTable string) *Client {
+	return &Client{dynamoDB: dynamodb, uuidGen: uuidGen, uniqueKeyTable: uniqueKeyTable}
 }
 
-func (c *Client) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueValue string) (string, error) {
-	tableID := c.uuidGen().String()
+func (c *Client) NewID() string {
+	return c.uuidGen().String()
+}
+
+func (c *Client) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueValue string, writes ...Write) (string, error) {
+	tableID := itemID(item)
+	if tableID == "" {
+		tableID = c.NewID()
+	}
 	item["id"] = &types.AttributeValueMemberS{Value: tableID}
+	item["version"] = &types.AttributeValueMemberN{Value: "1"}
 
-	uniqueTableID := fmt.Sprintf("%s#%s", tableName, uniqueValue)
+	uniqueTableID := UniqueKeyID(tableName, uniqueValue)
 	uniqueKeyItem := map[string]types.AttributeValue{
 		"id":       &types.AttributeValueMemberS{Value: uniqueTableID},
 		"table_id": &types.AttributeValueMemberS{Value: tableID},
 	}
 
-	_, err := c.dynamoDB.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
-			{Put: &types.Put{TableName: aws.String(uniqueKeyTable), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)")}},
-			{Put: &types.Put{TableName: aws.String(tableName), Item: item}},
-		},
-	})
+	err := c.transactWrite(ctx, []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName:                           aws.String(c.uniqueKeyTable),
+			Item:                                uniqueKeyItem,
+			ConditionExpression:                 aws.String("attribute_not_exists(id)"),
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}},
+		{Put: &types.Put{TableName: aws.String(tableName), Item: item}},
+	}, writes)
 
-	var tce *types.TransactionCanceledException
-	if errors.As(err, &tce) {
-		if len(tce.CancellationReasons) > 0 && tce.CancellationReasons[0].Code != nil {
-			if *tce.CancellationReasons[0].Code == "ConditionalCheckFailed" {
-				return "", ErrDuplicated
-			}
-		}
+	if conditionFailedAt(err, 0) {
+		return "", duplicatedError(err, 0)
 	}
 	if err != nil {
 		return "", fmt.Errorf("%w. failed to save character: %w", ErrDynamodb, err)
@@ -68,6 +110,190 @@ func (c *Client) Save(ctx context.Context, tableName string, item map[string]typ
 	return tableID, nil
 }
 
+func (c *Client) Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueValue string, newUniqueValue string, writes ...Write) error {
+	item["id"] = &types.AttributeValueMemberS{Value: id}
+	item["version"] = &types.AttributeValueMemberN{Value: strconv.Itoa(version + 1)}
+
+	put := &types.Put{
+		TableName:                           aws.String(tableName),
+		Item:                                item,
+		ConditionExpression:                 aws.String("attribute_exists(id) AND attribute_not_exists(version)"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+	if version > 0 {
+		put.ConditionExpression = aws.String("attribute_exists(id) AND version = :version")
+		put.ExpressionAttributeValues = map[string]types.AttributeValue{":version": &types.AttributeValueMemberN{Value: strconv.Itoa(version)}}
+	}
+
+	transactItems := c.updateItems(put, tableName, id, oldUniqueValue, newUniqueValue, true)
+	err := c.transactWrite(ctx, transactItems, writes)
+	if len(transactItems) == 3 && conditionFailedAt(err, 1) {
+		transactItems = c.updateItems(put, tableName, id, oldUniqueValue, newUniqueValue, false)
+		err = c.transactWrite(ctx, transactItems, writes)
+	}
+	if conditionFailedAt(err, 0) && existingItemAt(err, 0) {
+		return fmt.Errorf("%w. id: %s, version: %d", ErrVersionMismatch, id, version)
+	}
+	if conditionFailedAt(err, 0) {
+		return fmt.Errorf("%w. id: %s", ErrNotFound, id)
+	}
+	if conditionFailedAt(err, len(transactItems)-1) && len(transactItems) > 1 {
+		return ErrDuplicated
+	}
+	if err != nil {
+		return fmt.Errorf("%w. failed to update item id: %s from table: %s. err: %w", ErrDynamodb, id, tableName, err)
+	}
+
+	return nil
+}
+
+func (c *Client) updateItems(put *types.Put, tableName string, id string, oldUniqueValue string, newUniqueValue string, releaseOldKey bool) []types.TransactWriteItem {
+	transactItems := []types.TransactWriteItem{{Put: put}}
+
+	oldUniqueTableID := UniqueKeyID(tableName, oldUniqueValue)
+	newUniqueTableID := UniqueKeyID(tableName, newUniqueValue)
+	if oldUniqueTableID == newUniqueTableID {
+		return transactItems
+	}
+
+	if releaseOldKey {
+		transactItems = append(transactItems, types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                 aws.String(c.uniqueKeyTable),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: oldUniqueTableID}},
+			ConditionExpression:       aws.String("attribute_not_exists(id) OR table_id = :table_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: id}},
+		}})
+	}
+
+	newUniqueKeyItem := map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: newUniqueTableID},
+		"table_id": &types.AttributeValueMemberS{Value: id},
+	}
+
+	return append(transactItems, types.TransactWriteItem{Put: &types.Put{TableName: aws.String(c.uniqueKeyTable), Item: newUniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)")}})
+}
+
+func (c *Client) Delete(ctx context.Context, tableName string, id string, uniqueValue string, writes ...Write) error {
+	err := c.transactWrite(ctx, []types.TransactWriteItem{
+		{Delete: &types.Delete{
+			TableName:           aws.String(tableName),
+			Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}},
+			ConditionExpression: aws.String("attribute_exists(id)"),
+		}},
+		{Delete: &types.Delete{
+			TableName:                 aws.String(c.uniqueKeyTable),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: UniqueKeyID(tableName, uniqueValue)}},
+			ConditionExpression:       aws.String("table_id = :table_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: id}},
+		}},
+		{Delete: &types.Delete{
+			TableName:           aws.String(c.uniqueKeyTable),
+			Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: ReferencesKeyID(tableName, id)}},
+			ConditionExpression: aws.String("attribute_not_exists(refs)"),
+		}},
+	}, writes)
+	if conditionFailedAt(err, 0) {
+		return fmt.Errorf("%w. id: %s", ErrNotFound, id)
+	}
+	if conditionFailedAt(err, 2) {
+		return fmt.Errorf("%w. id: %s", ErrReferenced, id)
+	}
+	if err != nil {
+		return fmt.Errorf("%w. failed to delete item id: %s from table: %s. err: %w", ErrDynamodb, id, tableName, err)
+	}
+
+	return nil
+}
+
+func (c *Client) WriteItems(ctx context.Context, tableName string, puts []map[string]types.AttributeValue, deleteIDs []string, writes ...Write) error {
+	var itemWrites []Write
+	for _, item := range puts {
+		itemWrites = append(itemWrites, PutWrite(tableName, itemID(item), item))
+	}
+	for _, id := range deleteIDs {
+		itemWrites = append(itemWrites, DeleteWrite(tableName, id))
+	}
+	itemWrites = append(itemWrites, writes...)
+
+	if len(itemWrites) == 0 {
+		return nil
+	}
+
+	err := c.transactWrite(ctx, nil, itemWrites)
+	if err != nil {
+		return fmt.Errorf("%w. failed to write items to table: %s. err: %w", ErrDynamodb, tableName, err)
+	}
+
+	return nil
+}
+
+func (c *Client) SetAttributes(ctx context.Context, tableName string, id string, attributes map[string]types.AttributeValue, conditions map[string]types.AttributeValue) error {
+	names := map[string]string{}
+	values := map[string]types.AttributeValue{}
+
+	var sets []string
+	for i, attribute := range slices.Sorted(maps.Keys(attributes)) {
+		key := fmt.Sprintf("a%d", i)
+		names["#"+key] = attribute
+		values[":"+key] = attributes[attribute]
+		sets = append(sets, fmt.Sprintf("#%s = :%s", key, key))
+	}
+
+	checks := []string{"attribute_exists(id)"}
+	for i, attribute := range slices.Sorted(maps.Keys(conditions)) {
+		key := fmt.Sprintf("c%d", i)
+		names["#"+key] = attribute
+		values[":"+key] = conditions[attribute]
+		checks = append(checks, fmt.Sprintf("#%s = :%s", key, key))
+	}
+
+	_, err := c.dynamoDB.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}},
+		UpdateExpression:          aws.String("SET " + strings.Join(sets, ", ")),
+		ConditionExpression:       aws.String(strings.Join(checks, " AND ")),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w. id: %s", ErrConditionFailed, id)
+	}
+	if err != nil {
+		return fmt.Errorf("%w. failed to set attributes on item id: %s in table: %s. err: %w", ErrDynamodb, id, tableName, err)
+	}
+
+	return nil
+}
+
+func (c *Client) transactWrite(ctx context.Context, transactItems []types.TransactWriteItem, writes []Write) error {
+	owned := len(transactItems)
+	transactItems = slices.Clone(transactItems)
+	writes = MergeReferences(writes)
+	for _, write := range writes {
+		transactItems = append(transactItems, write.transactItem(c.uniqueKeyTable))
+	}
+
+	if len(transactItems) > TransactWriteLimit {
+		return fmt.Errorf("%w. got: %d, limit: %d", ErrTooManyWrites, len(transactItems), TransactWriteLimit)
+	}
+
+	_, err := c.dynamoDB.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
+	for i := range owned {
+		if conditionFailedAt(err, i) {
+			return err
+		}
+	}
+
+	for i, write := range writes {
+		if write.IsSlugClaim() && conditionFailedAt(err, owned+i) {
+			return fmt.Errorf("%w. key: %s. err: %w", ErrSlugTaken, write.ID, err)
+		}
+	}
+
+	return err
+}
+
 func (c *Client) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
 	output, err := c.dynamoDB.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(tableName),
@@ -85,7 +311,15 @@ func (c *Client) GetByID(ctx context.Context, tableName string, id string) (map[
 }
 
 func (c *Client) GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error) {
-	ukItem, err := c.GetByID(ctx, uniqueKeyTable, fmt.Sprintf("%s#%s", tableName, value))
+	return c.getByKey(ctx, tableName, UniqueKeyID(tableName, value), value)
+}
+
+func (c *Client) GetBySlug(ctx context.Context, tableName string, slug string) (map[string]types.AttributeValue, error) {
+	return c.getByKey(ctx, tableName, SlugKeyID(tableName, slug), slug)
+}
+
+func (c *Client) getByKey(ctx context.Context, tableName string, keyID string, value string) (map[string]types.AttributeValue, error) {
+	ukItem, err := c.GetByID(ctx, c.uniqueKeyTable, keyID)
 	if err != nil {
 		return nil, err
 	}
@@ -104,46 +338,267 @@ func (c *Client) GetByUniqueKey(ctx context.Context, tableName string, value str
 	return item, nil
 }
 
-func (c *Client) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
-	output, err := c.dynamoDB.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(tableName),
-	})
+func (c *Client) BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	itemsByID, err := c.batchGetByID(ctx, tableName, ids)
 	if err != nil {
-		return nil, fmt.Errorf("%w. failed to scan books: %w", ErrDynamodb, err)
+		return nil, err
 	}
-	
-	return output.Items, nil
+
+	items := make([]map[string]types.AttributeValue, 0, len(ids))
+	for _, id := range ids {
+		item, ok := itemsByID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w. id: %s", ErrNotFound, id)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
 }
 
-func (c *Client) CreateTables(ctx context.Context) error {
-	tables := []struct {
-		Name     string
-		HashKey  string
-		HashType types.ScalarAttributeType
-	}{
-		{"unique_keys", "id", types.ScalarAttributeTypeS},
-		{"books", "id", types.ScalarAttributeTypeS},
-		{"characters", "id", types.ScalarAttributeTypeS},
-		{"series", "id", types.ScalarAttributeTypeS},
+func (c *Client) BatchGetFound(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	itemsByID, err := c.batchGetByID(ctx, tableName, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]map[string]types.AttributeValue, 0, len(ids))
+	for _, id := range ids {
+		if item, ok := itemsByID[id]; ok {
+			items = append(items, item)
+		}
 	}
 
-	for _, tbl := range tables {
-		_, err := c.dynamoDB.CreateTable(ctx, &dynamodb.CreateTableInput{
-			TableName:            aws.String(tbl.Name),
-			AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String(tbl.HashKey), AttributeType: tbl.HashType}},
-			KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String(tbl.HashKey), KeyType: types.KeyTypeHash}},
-			BillingMode:          types.BillingModePayPerRequest,
-		})
+	return items, nil
+}
+
+func (c *Client) GetReferences(ctx context.Context, tableName string, ids []string) (map[string][]string, error) {
+	keyIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keyIDs = append(keyIDs, ReferencesKeyID(tableName, id))
+	}
+
+	itemsByID, err := c.batchGetByID(ctx, c.uniqueKeyTable, keyIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	references := map[string][]string{}
+	for _, id := range ids {
+		item, ok := itemsByID[ReferencesKeyID(tableName, id)]
+		if !ok {
+			continue
+		}
+
+		var refs References
+		err = attributevalue.UnmarshalMap(item, &refs)
+		if err != nil {
+			return nil, fmt.Errorf("%w. failed to unmarshal references. table: %s. err: %w", ErrDynamodb, tableName, err)
+		}
+
+		if len(refs.Refs) > 0 {
+			references[id] = slices.Sorted(slices.Values(refs.Refs))
+		}
+	}
+
+	return references, nil
+}
+
+func (c *Client) BatchGetByUniqueKeys(ctx context.Context, tableName string, values []string) ([]map[string]types.AttributeValue, error) {
+	uniqueTableIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		uniqueTableIDs = append(uniqueTableIDs, UniqueKeyID(tableName, value))
+	}
+
+	ukItems, err := c.BatchGetByIDs(ctx, c.uniqueKeyTable, uniqueTableIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var uniqueKeys []UniqueKeys
+	err = attributevalue.UnmarshalListOfMaps(ukItems, &uniqueKeys)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to unmarshal. table: %s. err: %w", ErrDynamodb, tableName, err)
+	}
+
+	tableIDs := make([]string, 0, len(uniqueKeys))
+	for _, uk := range uniqueKeys {
+		tableIDs = append(tableIDs, uk.TableID)
+	}
+
+	return c.BatchGetByIDs(ctx, tableName, tableIDs)
+}
+
+func (c *Client) batchGetByID(ctx context.Context, tableName string, ids []string) (map[string]map[string]types.AttributeValue, error) {
+	itemsByID := map[string]map[string]types.AttributeValue{}
+
+	for chunk := range slices.Chunk(uniqueValues(ids), batchGetLimit) {
+		items, err := c.batchGet(ctx, tableName, chunk)
 		if err != nil {
-			var resourceInUse *types.ResourceInUseException
-			if errors.As(err, &resourceInUse) {
-				continue
+			return nil, err
+		}
+
+		for _, item := range items {
+			if id, ok := item["id"].(*types.AttributeValueMemberS); ok {
+				itemsByID[id.Value] = item
 			}
-			return fmt.Errorf("failed to create table %s: %w", tbl.Name, err)
 		}
 	}
 
-	return nil
+	return itemsByID, nil
+}
+
+func (c *Client) batchGet(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	keys := make([]map[string]types.AttributeValue, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}})
+	}
+
+	var items []map[string]types.AttributeValue
+	requestItems := map[string]types.KeysAndAttributes{tableName: {Keys: keys}}
+
+	for attempt := 0; len(requestItems) > 0; attempt++ {
+		if attempt == batchGetMaxAttempts {
+			return nil, fmt.Errorf("%w. failed to get unprocessed keys from table: %s after %d attempts", ErrDynamodb, tableName, attempt)
+		}
+
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("%w. failed to batch get items from table: %s. err: %w", ErrDynamodb, tableName, ctx.Err())
+			case <-time.After(batchGetBackoff << (attempt - 1)):
+			}
+		}
+
+		output, err := c.dynamoDB.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+		if err != nil {
+			return nil, fmt.Errorf("%w. failed to batch get items from table: %s. err: %w", ErrDynamodb, tableName, err)
+		}
+
+		items = append(items, output.Responses[tableName]...)
+		requestItems = output.UnprocessedKeys
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
+	var items []map[string]types.AttributeValue
+	var startKey map[string]types.AttributeValue
+
+	for {
+		output, err := c.dynamoDB.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(tableName),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w. failed to scan books: %w", ErrDynamodb, err)
+		}
+
+		items = append(items, output.Items...)
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		startKey = output.LastEvaluatedKey
+	}
+}
+
+func (c *Client) GetPage(ctx context.Context, tableName string, limit int32, cursor string) ([]map[string]types.AttributeValue, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName:         aws.String(tableName),
+		ExclusiveStartKey: startKey,
+	}
+	if limit > 0 {
+		input.Limit = aws.Int32(limit)
+	}
+
+	output, err := c.dynamoDB.Scan(ctx, input)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w. failed to scan table: %s. err: %w", ErrDynamodb, tableName, err)
+	}
+
+	nextCursor, err := encodeCursor(output.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return output.Items, nextCursor, nil
+}
+
+type Query struct {
+	IndexName         string
+	HashKey           string
+	HashValue         types.AttributeValue
+	RangeKey          string
+	From              types.AttributeValue
+	To                types.AttributeValue
+	Limit             int32
+	ExclusiveStartKey map[string]types.AttributeValue
+}
+
+func (c *Client) Query(ctx context.Context, tableName string, query Query) ([]map[string]types.AttributeValue, error) {
+	keyCondition := "#hash = :hash"
+	names := map[string]string{"#hash": query.HashKey}
+	values := map[string]types.AttributeValue{":hash": query.HashValue}
+
+	switch {
+	case query.From != nil && query.To != nil:
+		keyCondition += " AND #range BETWEEN :from AND :to"
+	case query.From != nil:
+		keyCondition += " AND #range >= :from"
+	case query.To != nil:
+		keyCondition += " AND #range <= :to"
+	}
+	if query.From != nil {
+		values[":from"] = query.From
+	}
+	if query.To != nil {
+		values[":to"] = query.To
+	}
+	if query.From != nil || query.To != nil {
+		names["#range"] = query.RangeKey
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+	if query.IndexName != "" {
+		input.IndexName = aws.String(query.IndexName)
+	}
+	if query.Limit > 0 {
+		input.Limit = aws.Int32(query.Limit)
+	}
+	if len(query.ExclusiveStartKey) > 0 {
+		input.ExclusiveStartKey = query.ExclusiveStartKey
+	}
+
+	items := []map[string]types.AttributeValue{}
+	for {
+		output, err := c.dynamoDB.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("%w. failed to query table: %s. err: %w", ErrDynamodb, tableName, err)
+		}
+
+		items = append(items, output.Items...)
+
+		if query.Limit > 0 && len(items) >= int(query.Limit) {
+			return items[:query.Limit], nil
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
 }
 
 func (c *Client) Ping(ctx context.Context) error {
@@ -160,3 +615,103 @@ type UniqueKeys struct {
 	ID      string `dynamodbav:"id"`
 	TableID string `dynamodbav:"table_id"`
 }
+
+type References struct {
+	ID   string   `dynamodbav:"id"`
+	Refs []string `dynamodbav:"refs,stringset"`
+}
+
+func itemID(item map[string]types.AttributeValue) string {
+	if id, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		return id.Value
+	}
+
+	return ""
+}
+
+func conditionFailedAt(err error, index int) bool {
+	reason, ok := cancellationReasonAt(err, index)
+	return ok && reason.Code != nil && *reason.Code == "ConditionalCheckFailed"
+}
+
+func existingItemAt(err error, index int) bool {
+	reason, ok := cancellationReasonAt(err, index)
+	return ok && len(reason.Item) > 0
+}
+
+func duplicatedError(err error, index int) error {
+	reason, _ := cancellationReasonAt(err, index)
+
+	var uniqueKey UniqueKeys
+	if attributevalue.UnmarshalMap(reason.Item, &uniqueKey) != nil || uniqueKey.TableID == "" {
+		return ErrDuplicated
+	}
+
+	return &DuplicatedError{ID: uniqueKey.TableID}
+}
+
+func cancellationReasonAt(err error, index int) (types.CancellationReason, bool) {
+	var tce *types.TransactionCanceledException
+	if !errors.As(err, &tce) || len(tce.CancellationReasons) <= index {
+		return types.CancellationReason{}, false
+	}
+
+	return tce.CancellationReasons[index], true
+}
+
+func uniqueValues(values []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
+
+func encodeCursor(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	var rawKey map[string]interface{}
+	err := attributevalue.UnmarshalMap(key, &rawKey)
+	if err != nil {
+		return "", fmt.Errorf("%w. failed to unmarshal cursor: %w", ErrDynamodb, err)
+	}
+
+	jsonKey, err := json.Marshal(rawKey)
+	if err != nil {
+		return "", fmt.Errorf("%w. failed to encode cursor: %w", ErrDynamodb, err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(jsonKey), nil
+}
+
+func decodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	jsonKey, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, cursor)
+	}
+
+	var rawKey map[string]interface{}
+	err = json.Unmarshal(jsonKey, &rawKey)
+	if err != nil || len(rawKey) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, cursor)
+	}
+
+	key, err := attributevalue.MarshalMap(rawKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, cursor)
+	}
+
+	return key, nil
+}
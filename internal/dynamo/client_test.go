@@ -24,12 +24,12 @@ func TestClient_Save(t *testing.T) {
 		wantErr error
 	}{
 		{
-			name: "when failed to save because unique key already exists",
+			name: "when failed to save because unique key already exists without returning it",
 			setup: func(m *MockDynamoDBClient) {
-				uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniqueValue"}, "table_id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
-				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniquevalue"}, "table_id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}, "version": &types.AttributeValueMemberN{Value: "1"}}
 				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
-					{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)")}},
+					{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)"), ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld}},
 					{Put: &types.Put{TableName: aws.String("table-name"), Item: item}},
 				}}
 				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}}}
@@ -37,13 +37,28 @@ func TestClient_Save(t *testing.T) {
 			},
 			wantErr: ErrDuplicated,
 		},
+		{
+			name: "when failed to save because unique key already exists",
+			setup: func(m *MockDynamoDBClient) {
+				uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniquevalue"}, "table_id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}, "version": &types.AttributeValueMemberN{Value: "1"}}
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+					{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)"), ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld}},
+					{Put: &types.Put{TableName: aws.String("table-name"), Item: item}},
+				}}
+				existingUniqueKey := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniquevalue"}, "table_id": &types.AttributeValueMemberS{Value: "existing-id"}}
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed"), Item: existingUniqueKey}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: &DuplicatedError{ID: "existing-id"},
+		},
 		{
 			name: "when failed to save",
 			setup: func(m *MockDynamoDBClient) {
-				uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniqueValue"}, "table_id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
-				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniquevalue"}, "table_id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}, "version": &types.AttributeValueMemberN{Value: "1"}}
 				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
-					{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)")}},
+					{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)"), ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld}},
 					{Put: &types.Put{TableName: aws.String("table-name"), Item: item}},
 				}}
 				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, assert.AnError).Once()
@@ -53,10 +68,10 @@ func TestClient_Save(t *testing.T) {
 		{
 			name: "when successfully saved",
 			setup: func(m *MockDynamoDBClient) {
-				uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniqueValue"}, "table_id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
-				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniquevalue"}, "table_id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}, "version": &types.AttributeValueMemberN{Value: "1"}}
 				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
-					{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)")}},
+					{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)"), ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld}},
 					{Put: &types.Put{TableName: aws.String("table-name"), Item: item}},
 				}}
 				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
@@ -68,7 +83,7 @@ func TestClient_Save(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
-			c := NewClient(mockDynamoDBClient, func() uuid.UUID { return uuid.MustParse("c6767b2d-438b-4d4c-8b1a-659130a640ca") })
+			c := NewClient(mockDynamoDBClient, func() uuid.UUID { return uuid.MustParse("c6767b2d-438b-4d4c-8b1a-659130a640ca") }, "unique_keys")
 
 			item := map[string]types.AttributeValue{}
 			got, err := c.Save(ctx, "table-name", item, "uniqueValue")
@@ -80,6 +95,653 @@ func TestClient_Save(t *testing.T) {
 	}
 }
 
+func TestClient_Update(t *testing.T) {
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "version": &types.AttributeValueMemberN{Value: "3"}}
+	itemPut := types.TransactWriteItem{Put: &types.Put{
+		TableName:                           aws.String("table-name"),
+		Item:                                item,
+		ConditionExpression:                 aws.String("attribute_exists(id) AND version = :version"),
+		ExpressionAttributeValues:           map[string]types.AttributeValue{":version": &types.AttributeValueMemberN{Value: "2"}},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}}
+	oldUniqueKeyDelete := types.TransactWriteItem{Delete: &types.Delete{
+		TableName:                 aws.String("unique_keys"),
+		Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#oldvalue"}},
+		ConditionExpression:       aws.String("attribute_not_exists(id) OR table_id = :table_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: "random-id"}},
+	}}
+	newUniqueKeyPut := types.TransactWriteItem{Put: &types.Put{
+		TableName:           aws.String("unique_keys"),
+		Item:                map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#newvalue"}, "table_id": &types.AttributeValueMemberS{Value: "random-id"}},
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	}}
+	tests := []struct {
+		name           string
+		newUniqueValue string
+		setup          func(*MockDynamoDBClient)
+		wantErr        error
+	}{
+		{
+			name:           "when item does not exist",
+			newUniqueValue: "oldValue",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut}}
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s", ErrNotFound, "random-id"),
+		},
+		{
+			name:           "when item version does not match",
+			newUniqueValue: "oldValue",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut}}
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed"), Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s, version: %d", ErrVersionMismatch, "random-id", 2),
+		},
+		{
+			name:           "when new unique key already exists",
+			newUniqueValue: "newValue",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut, oldUniqueKeyDelete, newUniqueKeyPut}}
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: ErrDuplicated,
+		},
+		{
+			name:           "when old unique key belongs to another item",
+			newUniqueValue: "newValue",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut, oldUniqueKeyDelete, newUniqueKeyPut}}
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}, {Code: aws.String("None")}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+				healedInput := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut, newUniqueKeyPut}}
+				m.On("TransactWriteItems", ctx, healedInput, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+		},
+		{
+			name:           "when old unique key belongs to another item and new unique key already exists",
+			newUniqueValue: "newValue",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut, oldUniqueKeyDelete, newUniqueKeyPut}}
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}, {Code: aws.String("ConditionalCheckFailed")}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+				healedInput := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut, newUniqueKeyPut}}
+				healedErr := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}}}
+				m.On("TransactWriteItems", ctx, healedInput, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &healedErr).Once()
+			},
+			wantErr: ErrDuplicated,
+		},
+		{
+			name:           "when failed to update",
+			newUniqueValue: "oldValue",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to update item id: %s from table: %s. err: %w", ErrDynamodb, "random-id", "table-name", assert.AnError),
+		},
+		{
+			name:           "when successfully updated without changing unique key",
+			newUniqueValue: "oldValue",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+		},
+		{
+			name:           "when successfully updated with only case and punctuation changes",
+			newUniqueValue: " OLDVALUE. ",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+		},
+		{
+			name:           "when successfully updated and moved unique key",
+			newUniqueValue: "newValue",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut, oldUniqueKeyDelete, newUniqueKeyPut}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.Update(ctx, "table-name", "random-id", 2, map[string]types.AttributeValue{}, "oldValue", tt.newUniqueValue)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_UpdateUnversionedItem(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "version": &types.AttributeValueMemberN{Value: "1"}}
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{{Put: &types.Put{
+		TableName:                           aws.String("table-name"),
+		Item:                                item,
+		ConditionExpression:                 aws.String("attribute_exists(id) AND attribute_not_exists(version)"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}}}}
+	mockDynamoDBClient.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	err := c.Update(ctx, "table-name", "random-id", 0, map[string]types.AttributeValue{}, "value", "value")
+
+	assert.Nil(t, err)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestClient_Delete(t *testing.T) {
+	ctx := context.Background()
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Delete: &types.Delete{
+			TableName:           aws.String("table-name"),
+			Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}},
+			ConditionExpression: aws.String("attribute_exists(id)"),
+		}},
+		{Delete: &types.Delete{
+			TableName:                 aws.String("unique_keys"),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#value"}},
+			ConditionExpression:       aws.String("table_id = :table_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: "random-id"}},
+		}},
+		{Delete: &types.Delete{
+			TableName:           aws.String("unique_keys"),
+			Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#refs:random-id"}},
+			ConditionExpression: aws.String("attribute_not_exists(refs)"),
+		}},
+	}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when item does not exist",
+			setup: func(m *MockDynamoDBClient) {
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s", ErrNotFound, "random-id"),
+		},
+		{
+			name: "when item is still referenced",
+			setup: func(m *MockDynamoDBClient) {
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s", ErrReferenced, "random-id"),
+		},
+		{
+			name: "when failed to delete",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to delete item id: %s from table: %s. err: %w", ErrDynamodb, "random-id", "table-name", assert.AnError),
+		},
+		{
+			name: "when successfully deleted",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.Delete(ctx, "table-name", "random-id", "value")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_WriteItems(t *testing.T) {
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "new-id"}}
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Put: &types.Put{TableName: aws.String("table-name"), Item: item}},
+		{Delete: &types.Delete{TableName: aws.String("table-name"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "old-id"}}}},
+	}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to write items",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to write items to table: %s. err: %w", ErrDynamodb, "table-name", assert.AnError),
+		},
+		{
+			name: "when successfully wrote items",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.WriteItems(ctx, "table-name", []map[string]types.AttributeValue{item}, []string{"old-id"})
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_WriteItemsWithReferences(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "link-id"}}
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Put: &types.Put{TableName: aws.String("links"), Item: item}},
+		{Update: &types.Update{
+			TableName:                 aws.String("unique_keys"),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#refs:book-id"}},
+			UpdateExpression:          aws.String("ADD refs :refs"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":refs": &types.AttributeValueMemberSS{Value: []string{"link-id"}}},
+		}},
+		{Update: &types.Update{
+			TableName:                 aws.String("unique_keys"),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#refs:other-book-id"}},
+			UpdateExpression:          aws.String("DELETE refs :refs"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":refs": &types.AttributeValueMemberSS{Value: []string{"old-link-id"}}},
+		}},
+	}}
+	mockDynamoDBClient.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	err := c.WriteItems(ctx, "links", []map[string]types.AttributeValue{item}, nil, AddReferencesWrite("books", "book-id", "link-id"), RemoveReferencesWrite("books", "other-book-id", "old-link-id"))
+
+	assert.NoError(t, err)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestClient_WriteItemsMergesReferencesToTheSameKey(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Delete: &types.Delete{TableName: aws.String("links"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "link-id"}}}},
+		{Update: &types.Update{
+			TableName:                 aws.String("unique_keys"),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#refs:book-id"}},
+			UpdateExpression:          aws.String("DELETE refs :refs"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":refs": &types.AttributeValueMemberSS{Value: []string{"link-id", "edge-id"}}},
+		}},
+		{Delete: &types.Delete{TableName: aws.String("edges"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "edge-id"}}}},
+	}}
+	mockDynamoDBClient.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	err := c.WriteItems(ctx, "links", nil, []string{"link-id"},
+		RemoveReferencesWrite("books", "book-id", "link-id"),
+		DeleteWrite("edges", "edge-id"),
+		RemoveReferencesWrite("books", "book-id", "edge-id", "link-id"),
+	)
+
+	assert.NoError(t, err)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestClient_WriteItemsRejectsOversizedTransactions(t *testing.T) {
+	ctx := context.Background()
+	var deleteIDs []string
+	for i := range 101 {
+		deleteIDs = append(deleteIDs, fmt.Sprintf("id-%d", i))
+	}
+
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	err := c.WriteItems(ctx, "table-name", nil, deleteIDs)
+
+	assert.ErrorIs(t, err, ErrTooManyWrites)
+	mockDynamoDBClient.AssertNotCalled(t, "TransactWriteItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestClient_SaveWithWrites(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#uniquevalue"}, "table_id": &types.AttributeValueMemberS{Value: "given-id"}}
+	item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "given-id"}, "version": &types.AttributeValueMemberN{Value: "1"}}
+	linkItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "link-id"}, "book_id": &types.AttributeValueMemberS{Value: "book-id"}}
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)"), ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld}},
+		{Put: &types.Put{TableName: aws.String("table-name"), Item: item}},
+		{Put: &types.Put{TableName: aws.String("links"), Item: linkItem}},
+		{Delete: &types.Delete{TableName: aws.String("links"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "old-link-id"}}}},
+	}}
+	mockDynamoDBClient.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	writes := []Write{
+		PutWrite("links", "link-id", map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id"}}),
+		DeleteWrite("links", "old-link-id"),
+	}
+	got, err := c.Save(ctx, "table-name", map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "given-id"}}, "uniqueValue", writes...)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "given-id", got)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestClient_UpdateWithWrites(t *testing.T) {
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}, "version": &types.AttributeValueMemberN{Value: "3"}}
+	itemPut := types.TransactWriteItem{Put: &types.Put{
+		TableName:                           aws.String("table-name"),
+		Item:                                item,
+		ConditionExpression:                 aws.String("attribute_exists(id) AND version = :version"),
+		ExpressionAttributeValues:           map[string]types.AttributeValue{":version": &types.AttributeValueMemberN{Value: "2"}},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}}
+	linkDelete := types.TransactWriteItem{Delete: &types.Delete{TableName: aws.String("links"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "old-link-id"}}}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when item version does not match",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut, linkDelete}}
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed"), Item: item}, {Code: aws.String("None")}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s, version: %d", ErrVersionMismatch, "random-id", 2),
+		},
+		{
+			name: "when successfully updated with writes",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{itemPut, linkDelete}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.Update(ctx, "table-name", "random-id", 2, map[string]types.AttributeValue{}, "value", "value", DeleteWrite("links", "old-link-id"))
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_DeleteWithWrites(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Delete: &types.Delete{
+			TableName:           aws.String("table-name"),
+			Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}},
+			ConditionExpression: aws.String("attribute_exists(id)"),
+		}},
+		{Delete: &types.Delete{
+			TableName:                 aws.String("unique_keys"),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#value"}},
+			ConditionExpression:       aws.String("table_id = :table_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: "random-id"}},
+		}},
+		{Delete: &types.Delete{
+			TableName:           aws.String("unique_keys"),
+			Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#refs:random-id"}},
+			ConditionExpression: aws.String("attribute_not_exists(refs)"),
+		}},
+		{Delete: &types.Delete{TableName: aws.String("links"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "link-id"}}}},
+	}}
+	mockDynamoDBClient.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	err := c.Delete(ctx, "table-name", "random-id", "value", DeleteWrite("links", "link-id"))
+
+	assert.NoError(t, err)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestClient_SaveClaimingSlug(t *testing.T) {
+	ctx := context.Background()
+	uniqueKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#the black echo"}, "table_id": &types.AttributeValueMemberS{Value: "book-id"}}
+	item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "book-id"}, "version": &types.AttributeValueMemberN{Value: "1"}}
+	slugKeyItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#slug:the-black-echo"}, "table_id": &types.AttributeValueMemberS{Value: "book-id"}}
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Put: &types.Put{TableName: aws.String("unique_keys"), Item: uniqueKeyItem, ConditionExpression: aws.String("attribute_not_exists(id)"), ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld}},
+		{Put: &types.Put{TableName: aws.String("books"), Item: item}},
+		{Put: &types.Put{
+			TableName:                           aws.String("unique_keys"),
+			Item:                                slugKeyItem,
+			ConditionExpression:                 aws.String("attribute_not_exists(id) OR table_id = :table_id"),
+			ExpressionAttributeValues:           map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: "book-id"}},
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}},
+	}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    string
+		wantErr error
+	}{
+		{
+			name: "when title is duplicated",
+			setup: func(m *MockDynamoDBClient) {
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+					{Code: aws.String("ConditionalCheckFailed"), Item: map[string]types.AttributeValue{"table_id": &types.AttributeValueMemberS{Value: "other-book-id"}}},
+					{Code: aws.String("None")},
+					{Code: aws.String("ConditionalCheckFailed")},
+				}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: ErrDuplicated,
+		},
+		{
+			name: "when slug is taken",
+			setup: func(m *MockDynamoDBClient) {
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}}}
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+			},
+			wantErr: ErrSlugTaken,
+		},
+		{
+			name: "when successfully saved with its slug",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+			want: "book-id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			got, err := c.Save(ctx, "books", map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "book-id"}}, "The Black Echo", ClaimSlugWrite("books", "the-black-echo", "book-id"))
+
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_TransactWriteReportsOwnedItemsBeforeSlugs(t *testing.T) {
+	ctx := context.Background()
+	owned := []types.TransactWriteItem{{Put: &types.Put{TableName: aws.String("unique_keys"), Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#the black echo"}}}}}
+	writes := []Write{ClaimSlugWrite("books", "the-black-echo", "book-id")}
+	tests := []struct {
+		name    string
+		reasons []types.CancellationReason
+		wantErr error
+	}{
+		{
+			name:    "when owned item and slug fail",
+			reasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}, {Code: aws.String("ConditionalCheckFailed")}},
+		},
+		{
+			name:    "when only slug fails",
+			reasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}},
+			wantErr: ErrSlugTaken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tce := &types.TransactionCanceledException{CancellationReasons: tt.reasons}
+			mockDynamoDBClient.On("TransactWriteItems", ctx, mock.Anything, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, tce).Once()
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.transactWrite(ctx, owned, writes)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NotErrorIs(t, err, ErrSlugTaken)
+				assert.True(t, conditionFailedAt(err, 0))
+			}
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_DeleteReleasingSlug(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	tableID := map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: "book-id"}}
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Delete: &types.Delete{
+			TableName:           aws.String("books"),
+			Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "book-id"}},
+			ConditionExpression: aws.String("attribute_exists(id)"),
+		}},
+		{Delete: &types.Delete{
+			TableName:                 aws.String("unique_keys"),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#the black echo"}},
+			ConditionExpression:       aws.String("table_id = :table_id"),
+			ExpressionAttributeValues: tableID,
+		}},
+		{Delete: &types.Delete{
+			TableName:           aws.String("unique_keys"),
+			Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#refs:book-id"}},
+			ConditionExpression: aws.String("attribute_not_exists(refs)"),
+		}},
+		{Delete: &types.Delete{
+			TableName:                 aws.String("unique_keys"),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#slug:the-black-echo"}},
+			ConditionExpression:       aws.String("attribute_not_exists(id) OR table_id = :table_id"),
+			ExpressionAttributeValues: tableID,
+		}},
+	}}
+	mockDynamoDBClient.On("TransactWriteItems", ctx, input, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	err := c.Delete(ctx, "books", "book-id", "The Black Echo", ReleaseSlugWrite("books", "the-black-echo", "book-id"))
+
+	assert.NoError(t, err)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestClient_SetAttributes(t *testing.T) {
+	ctx := context.Background()
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String("table-name"),
+		Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}},
+		UpdateExpression:          aws.String("SET #a0 = :a0"),
+		ConditionExpression:       aws.String("attribute_exists(id) AND #c0 = :c0"),
+		ExpressionAttributeNames:  map[string]string{"#a0": "last_used_at", "#c0": "revoked"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":a0": &types.AttributeValueMemberS{Value: "2026-10-02T08:30:00Z"}, ":c0": &types.AttributeValueMemberBOOL{Value: false}},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to update item",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("UpdateItem", ctx, input, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to set attributes on item id: %s in table: %s. err: %w", ErrDynamodb, "random-id", "table-name", assert.AnError),
+		},
+		{
+			name: "when condition failed",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("UpdateItem", ctx, input, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, &types.ConditionalCheckFailedException{}).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s", ErrConditionFailed, "random-id"),
+		},
+		{
+			name: "when successfully set attributes",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("UpdateItem", ctx, input, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.SetAttributes(ctx, "table-name", "random-id",
+				map[string]types.AttributeValue{"last_used_at": &types.AttributeValueMemberS{Value: "2026-10-02T08:30:00Z"}},
+				map[string]types.AttributeValue{"revoked": &types.AttributeValueMemberBOOL{Value: false}},
+			)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_GetBySlug(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	ukInput := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#slug:the-black-echo"}}}
+	ukOutput := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "books#slug:the-black-echo"}, "table_id": &types.AttributeValueMemberS{Value: "book-id"}}}
+	mockDynamoDBClient.On("GetItem", ctx, ukInput, mock.Anything).Return(ukOutput, nil).Once()
+	itemInput := &dynamodb.GetItemInput{TableName: aws.String("books"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "book-id"}}}
+	itemOutput := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "book-id"}}}
+	mockDynamoDBClient.On("GetItem", ctx, itemInput, mock.Anything).Return(itemOutput, nil).Once()
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	got, err := c.GetBySlug(ctx, "books", "The-Black-Echo")
+
+	assert.NoError(t, err)
+	assert.Equal(t, itemOutput.Item, got)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
 func TestClient_GetByID(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
@@ -119,7 +781,7 @@ func TestClient_GetByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
-			c := NewClient(mockDynamoDBClient, nil)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
 
 			got, err := c.GetByID(ctx, "table-name", "random-id")
 
@@ -141,23 +803,23 @@ func TestClient_GetByUniqueKey(t *testing.T) {
 		{
 			name: "when failed to get id by unique key",
 			setup: func(m *MockDynamoDBClient) {
-				input := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#Harry Bosch"}}}
+				input := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#harry bosch"}}}
 				m.On("GetItem", ctx, input, mock.Anything).Return(&dynamodb.GetItemOutput{}, assert.AnError).Once()
 			},
-			wantErr: fmt.Errorf("%w. failed to get item id: %s from table: %s. err: %w", ErrDynamodb, "table-name#Harry Bosch", "unique_keys", assert.AnError),
+			wantErr: fmt.Errorf("%w. failed to get item id: %s from table: %s. err: %w", ErrDynamodb, "table-name#harry bosch", "unique_keys", assert.AnError),
 		},
 		{
 			name: "when unique key not found",
 			setup: func(m *MockDynamoDBClient) {
-				input := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#Harry Bosch"}}}
+				input := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#harry bosch"}}}
 				m.On("GetItem", ctx, input, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
 			},
-			wantErr: fmt.Errorf("%w. id: %s", ErrNotFound, "table-name#Harry Bosch"),
+			wantErr: fmt.Errorf("%w. id: %s", ErrNotFound, "table-name#harry bosch"),
 		},
 		{
 			name: "when failed to unmarshal item",
 			setup: func(m *MockDynamoDBClient) {
-				input := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#Harry Bosch"}}}
+				input := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#harry bosch"}}}
 				output := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}}}
 				m.On("GetItem", ctx, input, mock.Anything).Return(output, nil).Once()
 			},
@@ -166,8 +828,8 @@ func TestClient_GetByUniqueKey(t *testing.T) {
 		{
 			name: "when failed to get item",
 			setup: func(m *MockDynamoDBClient) {
-				ukInput := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#Harry Bosch"}}}
-				ukOutput := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#Harry Bosch"}, "table_id": &types.AttributeValueMemberS{Value: "random-id"}}}
+				ukInput := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#harry bosch"}}}
+				ukOutput := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#harry bosch"}, "table_id": &types.AttributeValueMemberS{Value: "random-id"}}}
 				m.On("GetItem", ctx, ukInput, mock.Anything).Return(ukOutput, nil).Once()
 				itemInput := &dynamodb.GetItemInput{TableName: aws.String("table-name"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}}
 				m.On("GetItem", ctx, itemInput, mock.Anything).Return(&dynamodb.GetItemOutput{}, assert.AnError).Once()
@@ -177,8 +839,8 @@ func TestClient_GetByUniqueKey(t *testing.T) {
 		{
 			name: "when successfully get by unique key",
 			setup: func(m *MockDynamoDBClient) {
-				ukInput := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#Harry Bosch"}}}
-				ukOutput := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#Harry Bosch"}, "table_id": &types.AttributeValueMemberS{Value: "random-id"}}}
+				ukInput := &dynamodb.GetItemInput{TableName: aws.String("unique_keys"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#harry bosch"}}}
+				ukOutput := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#harry bosch"}, "table_id": &types.AttributeValueMemberS{Value: "random-id"}}}
 				m.On("GetItem", ctx, ukInput, mock.Anything).Return(ukOutput, nil).Once()
 				itemInput := &dynamodb.GetItemInput{TableName: aws.String("table-name"), Key: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}}
 				itemOutput := &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}}
@@ -191,7 +853,7 @@ func TestClient_GetByUniqueKey(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
-			c := NewClient(mockDynamoDBClient, nil)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
 
 			got, err := c.GetByUniqueKey(ctx, "table-name", "Harry Bosch")
 
@@ -202,58 +864,218 @@ func TestClient_GetByUniqueKey(t *testing.T) {
 	}
 }
 
-func TestClient_CreateTables(t *testing.T) {
+func TestClient_BatchGetByIDs(t *testing.T) {
 	ctx := context.Background()
+	batchGetBackoff = 0
+	itemA := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "id-a"}}
+	itemB := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "id-b"}}
+	keyA := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "id-a"}}
+	keyB := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "id-b"}}
 	tests := []struct {
 		name    string
+		ids     []string
 		setup   func(*MockDynamoDBClient)
+		want    []map[string]types.AttributeValue
 		wantErr error
 	}{
 		{
-			name: "when failed to create tables",
+			name:  "when there are no ids",
+			setup: func(m *MockDynamoDBClient) {},
+			want:  []map[string]types.AttributeValue{},
+		},
+		{
+			name: "when failed to batch get items",
+			ids:  []string{"id-a"},
 			setup: func(m *MockDynamoDBClient) {
-				input := &dynamodb.CreateTableInput{
-					TableName:            aws.String("unique_keys"),
-					AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS}},
-					KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
-					BillingMode:          types.BillingModePayPerRequest,
-				}
-				m.On("CreateTable", ctx, input, mock.Anything).Return(&dynamodb.CreateTableOutput{}, assert.AnError).Once()
+				input := &dynamodb.BatchGetItemInput{RequestItems: map[string]types.KeysAndAttributes{"table-name": {Keys: []map[string]types.AttributeValue{keyA}}}}
+				m.On("BatchGetItem", ctx, input, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, assert.AnError).Once()
 			},
-			wantErr: fmt.Errorf("failed to create table %s: %w", "unique_keys", assert.AnError),
+			wantErr: fmt.Errorf("%w. failed to batch get items from table: %s. err: %w", ErrDynamodb, "table-name", assert.AnError),
 		},
 		{
-			name: "when all tables already exists",
+			name: "when an item is not found",
+			ids:  []string{"id-a", "id-b"},
 			setup: func(m *MockDynamoDBClient) {
-				input := dynamodb.CreateTableInput{
-					AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS}},
-					KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
-					BillingMode:          types.BillingModePayPerRequest,
-				}
-				uniqueKesyInput := input
-				uniqueKesyInput.TableName = aws.String("unique_keys")
-				err := &types.ResourceInUseException{}
-				m.On("CreateTable", ctx, &uniqueKesyInput, mock.Anything).Return(&dynamodb.CreateTableOutput{}, err).Once()
-				booksInput := input
-				booksInput.TableName = aws.String("books")
-				m.On("CreateTable", ctx, &booksInput, mock.Anything).Return(&dynamodb.CreateTableOutput{}, err).Once()
-				charactersInput := input
-				charactersInput.TableName = aws.String("characters")
-				m.On("CreateTable", ctx, &charactersInput, mock.Anything).Return(&dynamodb.CreateTableOutput{}, err).Once()
-				seriesInput := input
-				seriesInput.TableName = aws.String("series")
-				m.On("CreateTable", ctx, &seriesInput, mock.Anything).Return(&dynamodb.CreateTableOutput{}, err).Once()
+				input := &dynamodb.BatchGetItemInput{RequestItems: map[string]types.KeysAndAttributes{"table-name": {Keys: []map[string]types.AttributeValue{keyA, keyB}}}}
+				output := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"table-name": {itemA}}}
+				m.On("BatchGetItem", ctx, input, mock.Anything).Return(output, nil).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s", ErrNotFound, "id-b"),
+		},
+		{
+			name: "when unprocessed keys are never processed",
+			ids:  []string{"id-a"},
+			setup: func(m *MockDynamoDBClient) {
+				requestItems := map[string]types.KeysAndAttributes{"table-name": {Keys: []map[string]types.AttributeValue{keyA}}}
+				output := &dynamodb.BatchGetItemOutput{UnprocessedKeys: requestItems}
+				m.On("BatchGetItem", ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems}, mock.Anything).Return(output, nil).Times(5)
+			},
+			wantErr: fmt.Errorf("%w. failed to get unprocessed keys from table: %s after %d attempts", ErrDynamodb, "table-name", 5),
+		},
+		{
+			name: "when unprocessed keys are retried",
+			ids:  []string{"id-b", "id-a", "id-b"},
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.BatchGetItemInput{RequestItems: map[string]types.KeysAndAttributes{"table-name": {Keys: []map[string]types.AttributeValue{keyB, keyA}}}}
+				unprocessed := map[string]types.KeysAndAttributes{"table-name": {Keys: []map[string]types.AttributeValue{keyB}}}
+				output := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"table-name": {itemA}}, UnprocessedKeys: unprocessed}
+				m.On("BatchGetItem", ctx, input, mock.Anything).Return(output, nil).Once()
+				retryOutput := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"table-name": {itemB}}}
+				m.On("BatchGetItem", ctx, &dynamodb.BatchGetItemInput{RequestItems: unprocessed}, mock.Anything).Return(retryOutput, nil).Once()
+			},
+			want: []map[string]types.AttributeValue{itemB, itemA, itemB},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			got, err := c.BatchGetByIDs(ctx, "table-name", tt.ids)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_BatchGetFound(t *testing.T) {
+	ctx := context.Background()
+	itemA := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "id-a"}}
+	keyA := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "id-a"}}
+	keyB := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "id-b"}}
+	mockDynamoDBClient := new(MockDynamoDBClient)
+	input := &dynamodb.BatchGetItemInput{RequestItems: map[string]types.KeysAndAttributes{"table-name": {Keys: []map[string]types.AttributeValue{keyB, keyA}}}}
+	output := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"table-name": {itemA}}}
+	mockDynamoDBClient.On("BatchGetItem", ctx, input, mock.Anything).Return(output, nil).Once()
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	got, err := c.BatchGetFound(ctx, "table-name", []string{"id-b", "id-a"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]types.AttributeValue{itemA}, got)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestClient_BatchGetByIDsChunksKeys(t *testing.T) {
+	ctx := context.Background()
+	mockDynamoDBClient := new(MockDynamoDBClient)
+
+	var ids []string
+	var want []map[string]types.AttributeValue
+	for i := range 150 {
+		id := fmt.Sprintf("id-%d", i)
+		ids = append(ids, id)
+		want = append(want, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}})
+	}
+
+	chunkSize := func(size int) func(*dynamodb.BatchGetItemInput) bool {
+		return func(input *dynamodb.BatchGetItemInput) bool {
+			return len(input.RequestItems["table-name"].Keys) == size
+		}
+	}
+	mockDynamoDBClient.On("BatchGetItem", ctx, mock.MatchedBy(chunkSize(100)), mock.Anything).
+		Return(&dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"table-name": want[:100]}}, nil).Once()
+	mockDynamoDBClient.On("BatchGetItem", ctx, mock.MatchedBy(chunkSize(50)), mock.Anything).
+		Return(&dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"table-name": want[100:]}}, nil).Once()
+
+	c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+	got, err := c.BatchGetByIDs(ctx, "table-name", ids)
+
+	assert.Equal(t, want, got)
+	assert.Nil(t, err)
+	mockDynamoDBClient.AssertExpectations(t)
+}
+
+func TestClient_BatchGetByUniqueKeys(t *testing.T) {
+	ctx := context.Background()
+	ukInput := &dynamodb.BatchGetItemInput{RequestItems: map[string]types.KeysAndAttributes{"unique_keys": {Keys: []map[string]types.AttributeValue{
+		{"id": &types.AttributeValueMemberS{Value: "table-name#the black echo"}},
+	}}}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    []map[string]types.AttributeValue
+		wantErr error
+	}{
+		{
+			name: "when unique key is not found",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("BatchGetItem", ctx, ukInput, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s", ErrNotFound, "table-name#the black echo"),
+		},
+		{
+			name: "when successfully get items by unique keys",
+			setup: func(m *MockDynamoDBClient) {
+				ukItem := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "table-name#the black echo"}, "table_id": &types.AttributeValueMemberS{Value: "book-id"}}
+				m.On("BatchGetItem", ctx, ukInput, mock.Anything).Return(&dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"unique_keys": {ukItem}}}, nil).Once()
+				input := &dynamodb.BatchGetItemInput{RequestItems: map[string]types.KeysAndAttributes{"table-name": {Keys: []map[string]types.AttributeValue{{"id": &types.AttributeValueMemberS{Value: "book-id"}}}}}}
+				item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "book-id"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}}
+				m.On("BatchGetItem", ctx, input, mock.Anything).Return(&dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"table-name": {item}}}, nil).Once()
+			},
+			want: []map[string]types.AttributeValue{{"id": &types.AttributeValueMemberS{Value: "book-id"}, "title": &types.AttributeValueMemberS{Value: "The Black Echo"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			got, err := c.BatchGetByUniqueKeys(ctx, "table-name", []string{"The Black Echo"})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_GetReferences(t *testing.T) {
+	ctx := context.Background()
+	input := &dynamodb.BatchGetItemInput{RequestItems: map[string]types.KeysAndAttributes{"unique_keys": {Keys: []map[string]types.AttributeValue{
+		{"id": &types.AttributeValueMemberS{Value: "books#refs:book-1"}},
+		{"id": &types.AttributeValueMemberS{Value: "books#refs:book-2"}},
+		{"id": &types.AttributeValueMemberS{Value: "books#refs:book-3"}},
+	}}}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    map[string][]string
+		wantErr error
+	}{
+		{
+			name: "when failed to get references",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("BatchGetItem", ctx, input, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, assert.AnError).Once()
 			},
+			wantErr: fmt.Errorf("%w. failed to batch get items from table: %s. err: %w", ErrDynamodb, "unique_keys", assert.AnError),
+		},
+		{
+			name: "when successfully got references in one batch",
+			setup: func(m *MockDynamoDBClient) {
+				output := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"unique_keys": {
+					{"id": &types.AttributeValueMemberS{Value: "books#refs:book-1"}, "refs": &types.AttributeValueMemberSS{Value: []string{"series#s#book-1", "adaptation#a#book-1"}}},
+					{"id": &types.AttributeValueMemberS{Value: "books#refs:book-2"}},
+				}}}
+				m.On("BatchGetItem", ctx, input, mock.Anything).Return(output, nil).Once()
+			},
+			want: map[string][]string{"book-1": {"adaptation#a#book-1", "series#s#book-1"}},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
-			c := NewClient(mockDynamoDBClient, nil)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
 
-			err := c.CreateTables(ctx)
+			got, err := c.GetReferences(ctx, "books", []string{"book-1", "book-2", "book-3"})
 
+			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
 			mockDynamoDBClient.AssertExpectations(t)
 		})
@@ -290,7 +1112,7 @@ func TestClient_Ping(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
-			c := NewClient(mockDynamoDBClient, nil)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
 
 			err := c.Ping(ctx)
 
@@ -325,12 +1147,25 @@ func TestClient_GetAll(t *testing.T) {
 			},
 			want: []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}},
 		},
+		{
+			name: "when Scan returns more than one page",
+			setup: func(m *MockDynamoDBClient) {
+				lastKey := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}
+				firstInput := &dynamodb.ScanInput{TableName: aws.String("table-name")}
+				firstOutput := &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}}, LastEvaluatedKey: lastKey}
+				m.On("Scan", ctx, firstInput, mock.Anything).Return(firstOutput, nil).Once()
+				secondInput := &dynamodb.ScanInput{TableName: aws.String("table-name"), ExclusiveStartKey: lastKey}
+				secondOutput := &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Ice"}}}}
+				m.On("Scan", ctx, secondInput, mock.Anything).Return(secondOutput, nil).Once()
+			},
+			want: []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}, {"title": &types.AttributeValueMemberS{Value: "The Black Ice"}}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
-			c := NewClient(mockDynamoDBClient, nil)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
 
 			got, err := c.GetAll(ctx, "table-name")
 
@@ -341,6 +1176,171 @@ func TestClient_GetAll(t *testing.T) {
 	}
 }
 
+func TestClient_Query(t *testing.T) {
+	ctx := context.Background()
+	hashValue := &types.AttributeValueMemberS{Value: "book"}
+	from := &types.AttributeValueMemberN{Value: "2000"}
+	to := &types.AttributeValueMemberN{Value: "2009"}
+	blackEcho := map[string]types.AttributeValue{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}
+	blackIce := map[string]types.AttributeValue{"title": &types.AttributeValueMemberS{Value: "The Black Ice"}}
+	tests := []struct {
+		name    string
+		query   Query
+		setup   func(*MockDynamoDBClient)
+		want    []map[string]types.AttributeValue
+		wantErr error
+	}{
+		{
+			name:  "when failed to query",
+			query: Query{HashKey: "entity", HashValue: hashValue},
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to query table: %s. err: %w", ErrDynamodb, "table-name", assert.AnError),
+		},
+		{
+			name:  "when successfully query a range",
+			query: Query{IndexName: "year-index", HashKey: "entity", HashValue: hashValue, RangeKey: "year", From: from, To: to},
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.QueryInput{
+					TableName:                 aws.String("table-name"),
+					IndexName:                 aws.String("year-index"),
+					KeyConditionExpression:    aws.String("#hash = :hash AND #range BETWEEN :from AND :to"),
+					ExpressionAttributeNames:  map[string]string{"#hash": "entity", "#range": "year"},
+					ExpressionAttributeValues: map[string]types.AttributeValue{":hash": hashValue, ":from": from, ":to": to},
+				}
+				m.On("Query", ctx, input, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{blackEcho}}, nil).Once()
+			},
+			want: []map[string]types.AttributeValue{blackEcho},
+		},
+		{
+			name:  "when successfully query from a lower bound",
+			query: Query{HashKey: "entity", HashValue: hashValue, RangeKey: "year", From: from},
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.QueryInput{
+					TableName:                 aws.String("table-name"),
+					KeyConditionExpression:    aws.String("#hash = :hash AND #range >= :from"),
+					ExpressionAttributeNames:  map[string]string{"#hash": "entity", "#range": "year"},
+					ExpressionAttributeValues: map[string]types.AttributeValue{":hash": hashValue, ":from": from},
+				}
+				m.On("Query", ctx, input, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+			},
+			want: []map[string]types.AttributeValue{},
+		},
+		{
+			name:  "when query returns more than one page",
+			query: Query{HashKey: "entity", HashValue: hashValue, RangeKey: "year", To: to},
+			setup: func(m *MockDynamoDBClient) {
+				lastKey := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}
+				firstInput := &dynamodb.QueryInput{
+					TableName:                 aws.String("table-name"),
+					KeyConditionExpression:    aws.String("#hash = :hash AND #range <= :to"),
+					ExpressionAttributeNames:  map[string]string{"#hash": "entity", "#range": "year"},
+					ExpressionAttributeValues: map[string]types.AttributeValue{":hash": hashValue, ":to": to},
+				}
+				m.On("Query", ctx, firstInput, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{blackEcho}, LastEvaluatedKey: lastKey}, nil).Once()
+				secondInput := *firstInput
+				secondInput.ExclusiveStartKey = lastKey
+				m.On("Query", ctx, &secondInput, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{blackIce}}, nil).Once()
+			},
+			want: []map[string]types.AttributeValue{blackEcho, blackIce},
+		},
+		{
+			name:  "when query is limited and starts after a key",
+			query: Query{IndexName: "name-index", HashKey: "entity", HashValue: hashValue, Limit: 1, ExclusiveStartKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "start-id"}}},
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.QueryInput{
+					TableName:                 aws.String("table-name"),
+					IndexName:                 aws.String("name-index"),
+					KeyConditionExpression:    aws.String("#hash = :hash"),
+					ExpressionAttributeNames:  map[string]string{"#hash": "entity"},
+					ExpressionAttributeValues: map[string]types.AttributeValue{":hash": hashValue},
+					Limit:                     aws.Int32(1),
+					ExclusiveStartKey:         map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "start-id"}},
+				}
+				lastKey := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}
+				m.On("Query", ctx, input, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{blackIce}, LastEvaluatedKey: lastKey}, nil).Once()
+			},
+			want: []map[string]types.AttributeValue{blackIce},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			got, err := c.Query(ctx, "table-name", tt.query)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_GetPage(t *testing.T) {
+	ctx := context.Background()
+	lastKey := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "random-id"}}
+	tests := []struct {
+		name       string
+		cursor     string
+		setup      func(*MockDynamoDBClient)
+		want       []map[string]types.AttributeValue
+		wantCursor string
+		wantErr    error
+	}{
+		{
+			name:    "when cursor is invalid",
+			cursor:  "not a cursor",
+			setup:   func(m *MockDynamoDBClient) {},
+			wantErr: fmt.Errorf("%w: %s", ErrInvalidCursor, "not a cursor"),
+		},
+		{
+			name: "when failed to Scan",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.ScanInput{TableName: aws.String("table-name"), Limit: aws.Int32(2)}
+				m.On("Scan", ctx, input, mock.Anything).Return(&dynamodb.ScanOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to scan table: %s. err: %w", ErrDynamodb, "table-name", assert.AnError),
+		},
+		{
+			name: "when there are more pages",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.ScanInput{TableName: aws.String("table-name"), Limit: aws.Int32(2)}
+				output := &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}}, LastEvaluatedKey: lastKey}
+				m.On("Scan", ctx, input, mock.Anything).Return(output, nil).Once()
+			},
+			want:       []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Echo"}}},
+			wantCursor: "eyJpZCI6InJhbmRvbS1pZCJ9",
+		},
+		{
+			name:   "when cursor is the last page",
+			cursor: "eyJpZCI6InJhbmRvbS1pZCJ9",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.ScanInput{TableName: aws.String("table-name"), Limit: aws.Int32(2), ExclusiveStartKey: lastKey}
+				output := &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Ice"}}}}
+				m.On("Scan", ctx, input, mock.Anything).Return(output, nil).Once()
+			},
+			want: []map[string]types.AttributeValue{{"title": &types.AttributeValueMemberS{Value: "The Black Ice"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			got, gotCursor, err := c.GetPage(ctx, "table-name", 2, tt.cursor)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCursor, gotCursor)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
 type MockDynamoDBClient struct {
 	Dynamodb
 	mock.Mock
@@ -361,6 +1361,11 @@ func (m *MockDynamoDBClient) GetItem(ctx context.Context, params *dynamodb.GetIt
 	return args.Get(0).(*dynamodb.GetItemOutput), args.Error(1)
 }
 
+func (m *MockDynamoDBClient) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	return args.Get(0).(*dynamodb.BatchGetItemOutput), args.Error(1)
+}
+
 func (m *MockDynamoDBClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
 	args := m.Called(ctx, params, optFns)
 	return args.Get(0).(*dynamodb.UpdateItemOutput), args.Error(1)
@@ -381,6 +1386,16 @@ func (m *MockDynamoDBClient) CreateTable(ctx context.Context, params *dynamodb.C
 	return args.Get(0).(*dynamodb.CreateTableOutput), args.Error(1)
 }
 
+func (m *MockDynamoDBClient) UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	return args.Get(0).(*dynamodb.UpdateTableOutput), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	return args.Get(0).(*dynamodb.DescribeTableOutput), args.Error(1)
+}
+
 func (m *MockDynamoDBClient) Scan(ctx context.Context, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
 	args := m.Called(ctx, input, optFns)
 	return args.Get(0).(*dynamodb.ScanOutput), args.Error(1)
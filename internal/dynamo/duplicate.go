@@ -0,0 +1,27 @@
+package dynamo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidDuplicatePolicy = errors.New("dynamodb: invalid duplicate policy")
+
+type DuplicatePolicy string
+
+const (
+	DuplicateReject         DuplicatePolicy = "reject"
+	DuplicateReturnExisting DuplicatePolicy = "return-existing"
+	DuplicateUpsert         DuplicatePolicy = "upsert"
+)
+
+func ParseDuplicatePolicy(policy string) (DuplicatePolicy, error) {
+	switch DuplicatePolicy(policy) {
+	case "", DuplicateReject:
+		return DuplicateReject, nil
+	case DuplicateReturnExisting, DuplicateUpsert:
+		return DuplicatePolicy(policy), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidDuplicatePolicy, policy)
+	}
+}
@@ -0,0 +1,31 @@
+package dynamo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    DuplicatePolicy
+		wantErr error
+	}{
+		{name: "when policy is empty", policy: "", want: DuplicateReject},
+		{name: "when policy is reject", policy: "reject", want: DuplicateReject},
+		{name: "when policy is return-existing", policy: "return-existing", want: DuplicateReturnExisting},
+		{name: "when policy is upsert", policy: "upsert", want: DuplicateUpsert},
+		{name: "when policy is unknown", policy: "ignore", wantErr: fmt.Errorf("%w: %s", ErrInvalidDuplicatePolicy, "ignore")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDuplicatePolicy(tt.policy)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package dynamo
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const slugKeyPrefix = "slug:"
+const referencesKeyPrefix = "refs:"
+const slugAttempts = 10
+
+var keyFolder = cases.Fold()
+
+func NormalizeKey(value string) string {
+	folded := keyFolder.String(norm.NFKC.String(value))
+
+	words := strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r)
+	})
+
+	return strings.Join(words, " ")
+}
+
+func UniqueKeyID(tableName string, value string) string {
+	return tableName + "#" + NormalizeKey(value)
+}
+
+func Slug(value string) string {
+	return strings.ReplaceAll(NormalizeKey(value), " ", "-")
+}
+
+func SlugKeyID(tableName string, slug string) string {
+	return tableName + "#" + slugKeyPrefix + Slug(slug)
+}
+
+func ReferencesKeyID(tableName string, id string) string {
+	return tableName + "#" + referencesKeyPrefix + id
+}
+
+func SlugCandidates(current string, title string, fallback string) []string {
+	if current != "" {
+		return []string{current}
+	}
+
+	base := cmp.Or(Slug(title), Slug(fallback))
+	candidates := []string{base}
+	for n := 2; n <= slugAttempts; n++ {
+		candidates = append(candidates, base+"-"+strconv.Itoa(n))
+	}
+
+	return candidates
+}
+
+func isReservedKey(value string) bool {
+	return strings.HasPrefix(value, slugKeyPrefix) || strings.HasPrefix(value, referencesKeyPrefix)
+}
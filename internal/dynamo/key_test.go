@@ -0,0 +1,65 @@
+package dynamo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "when value is already normalized", value: "the black echo", want: "the black echo"},
+		{name: "when value has mixed case", value: "The Black ECHO", want: "the black echo"},
+		{name: "when value has surrounding and repeated whitespace", value: "  The \t Black\n\nEcho ", want: "the black echo"},
+		{name: "when value has punctuation", value: "The Black Echo!", want: "the black echo"},
+		{name: "when value has punctuation between words", value: "Bosch: Legacy -- Season 1", want: "bosch legacy season 1"},
+		{name: "when value has compatibility characters", value: "ＴＨＥ ＤＲＯＰ", want: "the drop"},
+		{name: "when value needs case folding", value: "STRASSE Straße", want: "strasse strasse"},
+		{name: "when value has accents", value: "Renée Ballard", want: "renée ballard"},
+		{name: "when value has only punctuation", value: " ?! ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeKey(tt.value))
+		})
+	}
+}
+
+func TestUniqueKeyID(t *testing.T) {
+	assert.Equal(t, "books#the black echo", UniqueKeyID("books", " The Black  Echo "))
+	assert.Equal(t, UniqueKeyID("books", "the black echo"), UniqueKeyID("books", "THE BLACK ECHO."))
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "the-black-echo", Slug(" The Black  Echo "))
+	assert.Equal(t, "bosch-legacy-season-1", Slug("Bosch: Legacy -- Season 1"))
+	assert.Equal(t, "the-black-echo-2", Slug("The-Black-Echo-2"))
+}
+
+func TestSlugKeyID(t *testing.T) {
+	assert.Equal(t, "books#slug:the-black-echo", SlugKeyID("books", "The-Black-Echo"))
+	assert.NotEqual(t, UniqueKeyID("books", "slug the black echo"), SlugKeyID("books", "the-black-echo"))
+}
+
+func TestSlugCandidates(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		title    string
+		fallback string
+		want     []string
+	}{
+		{name: "when slug is already stored", current: "the-black-echo", title: "The Black Echo (Reissue)", want: []string{"the-black-echo"}},
+		{name: "when slug is generated from the title", title: "The Black Echo", want: []string{"the-black-echo", "the-black-echo-2", "the-black-echo-3", "the-black-echo-4", "the-black-echo-5", "the-black-echo-6", "the-black-echo-7", "the-black-echo-8", "the-black-echo-9", "the-black-echo-10"}},
+		{name: "when title has no letters or digits", title: "?!", fallback: "Book-ID", want: []string{"book-id", "book-id-2", "book-id-3", "book-id-4", "book-id-5", "book-id-6", "book-id-7", "book-id-8", "book-id-9", "book-id-10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SlugCandidates(tt.current, tt.title, tt.fallback))
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package dynamo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var ErrTableNotFound = errors.New("dynamodb: table not found")
+var ErrTableNotActive = errors.New("dynamodb: table not active")
+
+const migrateMaxAttempts = 60
+
+var migratePollInterval = time.Second
+
+type Key struct {
+	Name string
+	Type types.ScalarAttributeType
+}
+
+type IndexSchema struct {
+	Name     string
+	HashKey  Key
+	RangeKey *Key
+}
+
+type TableSchema struct {
+	Name     string
+	HashKey  Key
+	Indexes  []IndexSchema
+	Defaults map[string]types.AttributeValue
+}
+
+func (c *Client) Migrate(ctx context.Context, schemas []TableSchema) error {
+	for _, schema := range schemas {
+		err := c.migrateTable(ctx, schema)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) VerifyTables(ctx context.Context, tableNames ...string) error {
+	for _, tableName := range tableNames {
+		_, err := c.describeTable(ctx, tableName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) RekeyUniqueKeys(ctx context.Context) error {
+	items, err := c.GetAll(ctx, c.uniqueKeyTable)
+	if err != nil {
+		return err
+	}
+
+	var uniqueKeys []UniqueKeys
+	err = attributevalue.UnmarshalListOfMaps(items, &uniqueKeys)
+	if err != nil {
+		return fmt.Errorf("%w. failed to unmarshal unique keys. err: %w", ErrDynamodb, err)
+	}
+
+	var errs []error
+	for _, uniqueKey := range uniqueKeys {
+		tableName, value, ok := strings.Cut(uniqueKey.ID, "#")
+		if !ok || isReservedKey(value) || UniqueKeyID(tableName, value) == uniqueKey.ID {
+			continue
+		}
+
+		err = c.rekeyUniqueKey(ctx, uniqueKey, UniqueKeyID(tableName, value))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Client) rekeyUniqueKey(ctx context.Context, uniqueKey UniqueKeys, newID string) error {
+	deleteOld := types.TransactWriteItem{Delete: &types.Delete{
+		TableName:                 aws.String(c.uniqueKeyTable),
+		Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: uniqueKey.ID}},
+		ConditionExpression:       aws.String("table_id = :table_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: uniqueKey.TableID}},
+	}}
+	putNew := types.TransactWriteItem{Put: &types.Put{
+		TableName: aws.String(c.uniqueKeyTable),
+		Item: map[string]types.AttributeValue{
+			"id":       &types.AttributeValueMemberS{Value: newID},
+			"table_id": &types.AttributeValueMemberS{Value: uniqueKey.TableID},
+		},
+		ConditionExpression:                 aws.String("attribute_not_exists(id) OR table_id = :table_id"),
+		ExpressionAttributeValues:           map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: uniqueKey.TableID}},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}}
+
+	_, err := c.dynamoDB.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{putNew, deleteOld}})
+	if conditionFailedAt(err, 0) {
+		return fmt.Errorf("failed to rekey %s to %s: %w", uniqueKey.ID, newID, duplicatedError(err, 0))
+	}
+	if err != nil {
+		return fmt.Errorf("%w. failed to rekey %s to %s. err: %w", ErrDynamodb, uniqueKey.ID, newID, err)
+	}
+
+	return nil
+}
+
+func (c *Client) migrateTable(ctx context.Context, schema TableSchema) error {
+	table, err := c.describeTable(ctx, schema.Name)
+	if errors.Is(err, ErrTableNotFound) {
+		return c.createTable(ctx, schema)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isActive(table) {
+		err = c.waitActive(ctx, schema.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.migrateIndexes(ctx, schema, table)
+	if err != nil {
+		return err
+	}
+
+	return c.backfillDefaults(ctx, schema)
+}
+
+func (c *Client) migrateIndexes(ctx context.Context, schema TableSchema, table *types.TableDescription) error {
+	for _, index := range schema.Indexes {
+		if slices.ContainsFunc(table.GlobalSecondaryIndexes, func(i types.GlobalSecondaryIndexDescription) bool { return aws.ToString(i.IndexName) == index.Name }) {
+			continue
+		}
+
+		_, err := c.dynamoDB.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+			TableName:                   aws.String(schema.Name),
+			AttributeDefinitions:        attributeDefinitions(schema),
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{Create: createIndexAction(index)}},
+		})
+		if err != nil {
+			return fmt.Errorf("%w. failed to create index %s on table %s. err: %w", ErrDynamodb, index.Name, schema.Name, err)
+		}
+
+		err = c.waitActive(ctx, schema.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) backfillDefaults(ctx context.Context, schema TableSchema) error {
+	if len(schema.Defaults) == 0 {
+		return nil
+	}
+
+	items, err := c.GetAll(ctx, schema.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		for _, attribute := range slices.Sorted(maps.Keys(schema.Defaults)) {
+			if _, ok := item[attribute]; ok {
+				continue
+			}
+
+			_, err := c.dynamoDB.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:                 aws.String(schema.Name),
+				Key:                       map[string]types.AttributeValue{"id": item["id"]},
+				UpdateExpression:          aws.String("SET #attribute = :value"),
+				ConditionExpression:       aws.String("attribute_exists(id) AND attribute_not_exists(#attribute)"),
+				ExpressionAttributeNames:  map[string]string{"#attribute": attribute},
+				ExpressionAttributeValues: map[string]types.AttributeValue{":value": schema.Defaults[attribute]},
+			})
+			var conditionFailed *types.ConditionalCheckFailedException
+			if err != nil && !errors.As(err, &conditionFailed) {
+				return fmt.Errorf("%w. failed to backfill %s on table %s. err: %w", ErrDynamodb, attribute, schema.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) createTable(ctx context.Context, schema TableSchema) error {
+	input := &dynamodb.CreateTableInput{
+		TableName:            aws.String(schema.Name),
+		AttributeDefinitions: attributeDefinitions(schema),
+		KeySchema:            keySchema(schema.HashKey, nil),
+		BillingMode:          types.BillingModePayPerRequest,
+	}
+	for _, index := range schema.Indexes {
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, types.GlobalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  keySchema(index.HashKey, index.RangeKey),
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+
+	_, err := c.dynamoDB.CreateTable(ctx, input)
+	var resourceInUse *types.ResourceInUseException
+	if err != nil && !errors.As(err, &resourceInUse) {
+		return fmt.Errorf("%w. failed to create table %s. err: %w", ErrDynamodb, schema.Name, err)
+	}
+
+	return c.waitActive(ctx, schema.Name)
+}
+
+func (c *Client) waitActive(ctx context.Context, tableName string) error {
+	for attempt := 1; attempt <= migrateMaxAttempts; attempt++ {
+		table, err := c.describeTable(ctx, tableName)
+		if err != nil && !errors.Is(err, ErrTableNotFound) {
+			return err
+		}
+		if err == nil && isActive(table) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(migratePollInterval):
+		}
+	}
+
+	return fmt.Errorf("%w: %s after %d attempts", ErrTableNotActive, tableName, migrateMaxAttempts)
+}
+
+func (c *Client) describeTable(ctx context.Context, tableName string) (*types.TableDescription, error) {
+	output, err := c.dynamoDB.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return nil, fmt.Errorf("%w: %s", ErrTableNotFound, tableName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to describe table %s. err: %w", ErrDynamodb, tableName, err)
+	}
+
+	return output.Table, nil
+}
+
+func isActive(table *types.TableDescription) bool {
+	if table == nil || table.TableStatus != types.TableStatusActive {
+		return false
+	}
+
+	for _, index := range table.GlobalSecondaryIndexes {
+		if index.IndexStatus != types.IndexStatusActive {
+			return false
+		}
+	}
+
+	return true
+}
+
+func createIndexAction(index IndexSchema) *types.CreateGlobalSecondaryIndexAction {
+	return &types.CreateGlobalSecondaryIndexAction{
+		IndexName:  aws.String(index.Name),
+		KeySchema:  keySchema(index.HashKey, index.RangeKey),
+		Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+	}
+}
+
+func keySchema(hashKey Key, rangeKey *Key) []types.KeySchemaElement {
+	elements := []types.KeySchemaElement{{AttributeName: aws.String(hashKey.Name), KeyType: types.KeyTypeHash}}
+	if rangeKey != nil {
+		elements = append(elements, types.KeySchemaElement{AttributeName: aws.String(rangeKey.Name), KeyType: types.KeyTypeRange})
+	}
+
+	return elements
+}
+
+func attributeDefinitions(schema TableSchema) []types.AttributeDefinition {
+	keys := []Key{schema.HashKey}
+	for _, index := range schema.Indexes {
+		keys = append(keys, index.HashKey)
+		if index.RangeKey != nil {
+			keys = append(keys, *index.RangeKey)
+		}
+	}
+
+	var definitions []types.AttributeDefinition
+	for _, key := range keys {
+		if slices.ContainsFunc(definitions, func(d types.AttributeDefinition) bool { return aws.ToString(d.AttributeName) == key.Name }) {
+			continue
+		}
+
+		definitions = append(definitions, types.AttributeDefinition{AttributeName: aws.String(key.Name), AttributeType: key.Type})
+	}
+
+	return definitions
+}
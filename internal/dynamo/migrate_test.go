@@ -0,0 +1,338 @@
+package dynamo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClient_Migrate(t *testing.T) {
+	migratePollInterval = 0
+	ctx := context.Background()
+	id := Key{Name: "id", Type: types.ScalarAttributeTypeS}
+	year := Key{Name: "year", Type: types.ScalarAttributeTypeN}
+	title := Key{Name: "title", Type: types.ScalarAttributeTypeS}
+	schema := TableSchema{Name: "books", HashKey: id, Indexes: []IndexSchema{{Name: "year-index", HashKey: year, RangeKey: &title}}}
+	describeInput := &dynamodb.DescribeTableInput{TableName: aws.String("books")}
+	attributeDefinitions := []types.AttributeDefinition{
+		{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+		{AttributeName: aws.String("year"), AttributeType: types.ScalarAttributeTypeN},
+		{AttributeName: aws.String("title"), AttributeType: types.ScalarAttributeTypeS},
+	}
+	indexKeySchema := []types.KeySchemaElement{
+		{AttributeName: aws.String("year"), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String("title"), KeyType: types.KeyTypeRange},
+	}
+	activeIndex := types.GlobalSecondaryIndexDescription{IndexName: aws.String("year-index"), IndexStatus: types.IndexStatusActive}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to describe table",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to describe table %s. err: %w", ErrDynamodb, "books", assert.AnError),
+		},
+		{
+			name: "when failed to create table",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, &types.ResourceNotFoundException{}).Once()
+				m.On("CreateTable", ctx, mock.Anything, mock.Anything).Return(&dynamodb.CreateTableOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to create table %s. err: %w", ErrDynamodb, "books", assert.AnError),
+		},
+		{
+			name: "when table is created and becomes active",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.CreateTableInput{
+					TableName:            aws.String("books"),
+					AttributeDefinitions: attributeDefinitions,
+					KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
+					BillingMode:          types.BillingModePayPerRequest,
+					GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+						IndexName:  aws.String("year-index"),
+						KeySchema:  indexKeySchema,
+						Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+					}},
+				}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, &types.ResourceNotFoundException{}).Once()
+				m.On("CreateTable", ctx, input, mock.Anything).Return(&dynamodb.CreateTableOutput{}, nil).Once()
+				creating := &types.TableDescription{TableStatus: types.TableStatusCreating}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: creating}, nil).Once()
+				active := &types.TableDescription{TableStatus: types.TableStatusActive, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{activeIndex}}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: active}, nil).Once()
+			},
+		},
+		{
+			name: "when table never becomes active",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, &types.ResourceNotFoundException{}).Once()
+				m.On("CreateTable", ctx, mock.Anything, mock.Anything).Return(&dynamodb.CreateTableOutput{}, &types.ResourceInUseException{}).Once()
+				creating := &types.TableDescription{TableStatus: types.TableStatusCreating}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: creating}, nil).Times(migrateMaxAttempts)
+			},
+			wantErr: fmt.Errorf("%w: %s after %d attempts", ErrTableNotActive, "books", migrateMaxAttempts),
+		},
+		{
+			name: "when table exists without index",
+			setup: func(m *MockDynamoDBClient) {
+				input := &dynamodb.UpdateTableInput{
+					TableName:            aws.String("books"),
+					AttributeDefinitions: attributeDefinitions,
+					GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{Create: &types.CreateGlobalSecondaryIndexAction{
+						IndexName:  aws.String("year-index"),
+						KeySchema:  indexKeySchema,
+						Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+					}}},
+				}
+				table := &types.TableDescription{TableStatus: types.TableStatusActive}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: table}, nil).Once()
+				m.On("UpdateTable", ctx, input, mock.Anything).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+				backfilling := &types.TableDescription{TableStatus: types.TableStatusActive, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{{IndexName: aws.String("year-index"), IndexStatus: types.IndexStatusCreating}}}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: backfilling}, nil).Once()
+				active := &types.TableDescription{TableStatus: types.TableStatusActive, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{activeIndex}}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: active}, nil).Once()
+			},
+		},
+		{
+			name: "when failed to create index",
+			setup: func(m *MockDynamoDBClient) {
+				table := &types.TableDescription{TableStatus: types.TableStatusActive}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: table}, nil).Once()
+				m.On("UpdateTable", ctx, mock.Anything, mock.Anything).Return(&dynamodb.UpdateTableOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to create index %s on table %s. err: %w", ErrDynamodb, "year-index", "books", assert.AnError),
+		},
+		{
+			name: "when table exists and is still creating",
+			setup: func(m *MockDynamoDBClient) {
+				creating := &types.TableDescription{TableStatus: types.TableStatusCreating, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{{IndexName: aws.String("year-index"), IndexStatus: types.IndexStatusCreating}}}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: creating}, nil).Twice()
+				active := &types.TableDescription{TableStatus: types.TableStatusActive, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{activeIndex}}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: active}, nil).Once()
+			},
+		},
+		{
+			name: "when table exists and never becomes active",
+			setup: func(m *MockDynamoDBClient) {
+				updating := &types.TableDescription{TableStatus: types.TableStatusUpdating, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{activeIndex}}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: updating}, nil).Times(migrateMaxAttempts + 1)
+			},
+			wantErr: fmt.Errorf("%w: %s after %d attempts", ErrTableNotActive, "books", migrateMaxAttempts),
+		},
+		{
+			name: "when table is up to date",
+			setup: func(m *MockDynamoDBClient) {
+				table := &types.TableDescription{TableStatus: types.TableStatusActive, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{activeIndex}}
+				m.On("DescribeTable", ctx, describeInput, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: table}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.Migrate(ctx, []TableSchema{schema})
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_MigrateBackfillsDefaults(t *testing.T) {
+	ctx := context.Background()
+	entity := &types.AttributeValueMemberS{Value: "book"}
+	schema := TableSchema{Name: "books", HashKey: Key{Name: "id", Type: types.ScalarAttributeTypeS}, Defaults: map[string]types.AttributeValue{"entity": entity}}
+	backfill := func(id string) *dynamodb.UpdateItemInput {
+		return &dynamodb.UpdateItemInput{
+			TableName:                 aws.String("books"),
+			Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}},
+			UpdateExpression:          aws.String("SET #attribute = :value"),
+			ConditionExpression:       aws.String("attribute_exists(id) AND attribute_not_exists(#attribute)"),
+			ExpressionAttributeNames:  map[string]string{"#attribute": "entity"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{":value": entity},
+		}
+	}
+	items := []map[string]types.AttributeValue{
+		{"id": &types.AttributeValueMemberS{Value: "missing-id"}},
+		{"id": &types.AttributeValueMemberS{Value: "deleted-id"}},
+		{"id": &types.AttributeValueMemberS{Value: "tagged-id"}, "entity": entity},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to backfill",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Scan", ctx, mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+				m.On("UpdateItem", ctx, backfill("missing-id"), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to backfill %s on table %s. err: %w", ErrDynamodb, "entity", "books", assert.AnError),
+		},
+		{
+			name: "when successfully backfilled items missing defaults",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Scan", ctx, mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+				m.On("UpdateItem", ctx, backfill("missing-id"), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				m.On("UpdateItem", ctx, backfill("deleted-id"), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, &types.ConditionalCheckFailedException{}).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			mockDynamoDBClient.On("DescribeTable", ctx, mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusActive}}, nil).Once()
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.Migrate(ctx, []TableSchema{schema})
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_VerifyTables(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when table is missing",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{TableName: aws.String("unique_keys")}, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{}}, nil).Once()
+				m.On("DescribeTable", ctx, &dynamodb.DescribeTableInput{TableName: aws.String("books")}, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, &types.ResourceNotFoundException{}).Once()
+			},
+			wantErr: fmt.Errorf("%w: %s", ErrTableNotFound, "books"),
+		},
+		{
+			name: "when all tables exist",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("DescribeTable", ctx, mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{}}, nil).Twice()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.VerifyTables(ctx, "unique_keys", "books")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_RekeyUniqueKeys(t *testing.T) {
+	ctx := context.Background()
+	uniqueKey := func(id string, tableID string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}, "table_id": &types.AttributeValueMemberS{Value: tableID}}
+	}
+	rekeyInput := func(oldID string, newID string, tableID string) *dynamodb.TransactWriteItemsInput {
+		tableIDValue := map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: tableID}}
+		return &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName:                           aws.String("unique_keys"),
+				Item:                                uniqueKey(newID, tableID),
+				ConditionExpression:                 aws.String("attribute_not_exists(id) OR table_id = :table_id"),
+				ExpressionAttributeValues:           tableIDValue,
+				ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+			}},
+			{Delete: &types.Delete{
+				TableName:                 aws.String("unique_keys"),
+				Key:                       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: oldID}},
+				ConditionExpression:       aws.String("table_id = :table_id"),
+				ExpressionAttributeValues: tableIDValue,
+			}},
+		}}
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to scan unique keys",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Scan", ctx, &dynamodb.ScanInput{TableName: aws.String("unique_keys")}, mock.Anything).Return(&dynamodb.ScanOutput{}, assert.AnError).Once()
+			},
+			wantErr: fmt.Errorf("%w. failed to scan books: %w", ErrDynamodb, assert.AnError),
+		},
+		{
+			name: "when keys are already normalized",
+			setup: func(m *MockDynamoDBClient) {
+				items := []map[string]types.AttributeValue{uniqueKey("books#the black echo", "book-id")}
+				m.On("Scan", ctx, &dynamodb.ScanInput{TableName: aws.String("unique_keys")}, mock.Anything).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+			},
+		},
+		{
+			name: "when keys are slugs",
+			setup: func(m *MockDynamoDBClient) {
+				items := []map[string]types.AttributeValue{uniqueKey("books#slug:the-black-echo", "book-id")}
+				m.On("Scan", ctx, &dynamodb.ScanInput{TableName: aws.String("unique_keys")}, mock.Anything).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+			},
+		},
+		{
+			name: "when keys are references",
+			setup: func(m *MockDynamoDBClient) {
+				items := []map[string]types.AttributeValue{{
+					"id":   &types.AttributeValueMemberS{Value: "books#refs:book-id"},
+					"refs": &types.AttributeValueMemberSS{Value: []string{"series#series-id#book-id"}},
+				}}
+				m.On("Scan", ctx, &dynamodb.ScanInput{TableName: aws.String("unique_keys")}, mock.Anything).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+			},
+		},
+		{
+			name: "when normalized key belongs to another item",
+			setup: func(m *MockDynamoDBClient) {
+				items := []map[string]types.AttributeValue{uniqueKey("books#The Black Echo", "book-id"), uniqueKey("books#The Black Ice", "other-book-id")}
+				m.On("Scan", ctx, &dynamodb.ScanInput{TableName: aws.String("unique_keys")}, mock.Anything).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+				err := types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed"), Item: uniqueKey("books#the black echo", "duplicated-book-id")}}}
+				m.On("TransactWriteItems", ctx, rekeyInput("books#The Black Echo", "books#the black echo", "book-id"), mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, &err).Once()
+				m.On("TransactWriteItems", ctx, rekeyInput("books#The Black Ice", "books#the black ice", "other-book-id"), mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+			wantErr: errors.Join(fmt.Errorf("failed to rekey %s to %s: %w", "books#The Black Echo", "books#the black echo", &DuplicatedError{ID: "duplicated-book-id"})),
+		},
+		{
+			name: "when successfully rekeyed",
+			setup: func(m *MockDynamoDBClient) {
+				items := []map[string]types.AttributeValue{uniqueKey("characters#  Harry BOSCH! ", "character-id"), uniqueKey("books#the black echo", "book-id")}
+				m.On("Scan", ctx, &dynamodb.ScanInput{TableName: aws.String("unique_keys")}, mock.Anything).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+				m.On("TransactWriteItems", ctx, rekeyInput("characters#  Harry BOSCH! ", "characters#harry bosch", "character-id"), mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+			c := NewClient(mockDynamoDBClient, nil, "unique_keys")
+
+			err := c.RekeyUniqueKeys(ctx)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package dynamo
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type writeKind int
+
+const (
+	putWrite writeKind = iota
+	deleteWrite
+	claimSlugWrite
+	releaseSlugWrite
+	addReferencesWrite
+	removeReferencesWrite
+)
+
+type Write struct {
+	TableName string
+	ID        string
+	Item      map[string]types.AttributeValue
+	TableID   string
+	Refs      []string
+	kind      writeKind
+}
+
+func PutWrite(tableName string, id string, item map[string]types.AttributeValue) Write {
+	return Write{TableName: tableName, ID: id, Item: item, kind: putWrite}
+}
+
+func DeleteWrite(tableName string, id string) Write {
+	return Write{TableName: tableName, ID: id, kind: deleteWrite}
+}
+
+func ClaimSlugWrite(tableName string, slug string, tableID string) Write {
+	return Write{TableName: tableName, ID: SlugKeyID(tableName, slug), TableID: tableID, kind: claimSlugWrite}
+}
+
+func ReleaseSlugWrite(tableName string, slug string, tableID string) Write {
+	return Write{TableName: tableName, ID: SlugKeyID(tableName, slug), TableID: tableID, kind: releaseSlugWrite}
+}
+
+func AddReferencesWrite(tableName string, id string, refs ...string) Write {
+	return Write{TableName: tableName, ID: ReferencesKeyID(tableName, id), Refs: refs, kind: addReferencesWrite}
+}
+
+func RemoveReferencesWrite(tableName string, id string, refs ...string) Write {
+	return Write{TableName: tableName, ID: ReferencesKeyID(tableName, id), Refs: refs, kind: removeReferencesWrite}
+}
+
+func (w Write) IsPut() bool {
+	return w.kind == putWrite
+}
+
+func (w Write) IsDelete() bool {
+	return w.kind == deleteWrite
+}
+
+func (w Write) IsSlugClaim() bool {
+	return w.kind == claimSlugWrite
+}
+
+func (w Write) IsSlugRelease() bool {
+	return w.kind == releaseSlugWrite
+}
+
+func (w Write) IsReferencesAdd() bool {
+	return w.kind == addReferencesWrite
+}
+
+func (w Write) IsReferencesRemove() bool {
+	return w.kind == removeReferencesWrite
+}
+
+func (w Write) Undo() (Write, bool) {
+	switch w.kind {
+	case putWrite:
+		return DeleteWrite(w.TableName, w.ID), true
+	case addReferencesWrite:
+		w.kind = removeReferencesWrite
+		return w, true
+	case removeReferencesWrite:
+		w.kind = addReferencesWrite
+		return w, true
+	}
+
+	return Write{}, false
+}
+
+func MergeReferences(writes []Write) []Write {
+	var merged []Write
+	for _, write := range writes {
+		if !write.IsReferencesAdd() && !write.IsReferencesRemove() {
+			merged = append(merged, write)
+			continue
+		}
+
+		i := slices.IndexFunc(merged, func(m Write) bool { return m.kind == write.kind && m.ID == write.ID })
+		if i < 0 {
+			merged = append(merged, write)
+			continue
+		}
+
+		refs := slices.Clone(merged[i].Refs)
+		for _, ref := range write.Refs {
+			if !slices.Contains(refs, ref) {
+				refs = append(refs, ref)
+			}
+		}
+		merged[i].Refs = refs
+	}
+
+	return merged
+}
+
+func (w Write) transactItem(uniqueKeyTable string) types.TransactWriteItem {
+	key := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: w.ID}}
+	ownedBy := map[string]types.AttributeValue{":table_id": &types.AttributeValueMemberS{Value: w.TableID}}
+
+	switch w.kind {
+	case deleteWrite:
+		return types.TransactWriteItem{Delete: &types.Delete{TableName: aws.String(w.TableName), Key: key}}
+	case claimSlugWrite:
+		return types.TransactWriteItem{Put: &types.Put{
+			TableName:                           aws.String(uniqueKeyTable),
+			Item:                                map[string]types.AttributeValue{"id": key["id"], "table_id": ownedBy[":table_id"]},
+			ConditionExpression:                 aws.String("attribute_not_exists(id) OR table_id = :table_id"),
+			ExpressionAttributeValues:           ownedBy,
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}}
+	case releaseSlugWrite:
+		return types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                 aws.String(uniqueKeyTable),
+			Key:                       key,
+			ConditionExpression:       aws.String("attribute_not_exists(id) OR table_id = :table_id"),
+			ExpressionAttributeValues: ownedBy,
+		}}
+	case addReferencesWrite, removeReferencesWrite:
+		action := "ADD"
+		if w.kind == removeReferencesWrite {
+			action = "DELETE"
+		}
+
+		return types.TransactWriteItem{Update: &types.Update{
+			TableName:                 aws.String(uniqueKeyTable),
+			Key:                       key,
+			UpdateExpression:          aws.String(action + " refs :refs"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":refs": &types.AttributeValueMemberSS{Value: w.Refs}},
+		}}
+	}
+
+	item := maps.Clone(w.Item)
+	item["id"] = key["id"]
+
+	return types.TransactWriteItem{Put: &types.Put{TableName: aws.String(w.TableName), Item: item}}
+}
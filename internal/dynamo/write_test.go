@@ -0,0 +1,52 @@
+package dynamo
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite_Undo(t *testing.T) {
+	tests := []struct {
+		name   string
+		write  Write
+		want   Write
+		wantOk bool
+	}{
+		{
+			name:   "when undoing a put",
+			write:  PutWrite("links", "link-id", map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "link-id"}}),
+			want:   DeleteWrite("links", "link-id"),
+			wantOk: true,
+		},
+		{
+			name:   "when undoing a references add",
+			write:  AddReferencesWrite("books", "book-id", "link-id"),
+			want:   RemoveReferencesWrite("books", "book-id", "link-id"),
+			wantOk: true,
+		},
+		{
+			name:   "when undoing a references remove",
+			write:  RemoveReferencesWrite("books", "book-id", "link-id"),
+			want:   AddReferencesWrite("books", "book-id", "link-id"),
+			wantOk: true,
+		},
+		{
+			name:  "when undoing a delete",
+			write: DeleteWrite("links", "link-id"),
+		},
+		{
+			name:  "when undoing a slug claim",
+			write: ClaimSlugWrite("books", "the-black-echo", "book-id"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.write.Undo()
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package etag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+)
+
+var ErrMissing = errors.New("etag: missing If-Match header")
+var ErrInvalid = errors.New("etag: invalid If-Match header")
+
+func Format(version int) string {
+	return fmt.Sprintf("%q", strconv.Itoa(version))
+}
+
+func Parse(ifMatch string) (int, error) {
+	if ifMatch == "" {
+		return 0, ErrMissing
+	}
+
+	if strings.TrimSpace(ifMatch) == "*" {
+		return dynamo.AnyVersion, nil
+	}
+
+	value := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalid, ifMatch)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,60 @@
+package etag
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, `"3"`, Format(3))
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+		wantErr error
+	}{
+		{
+			name:    "when header is missing",
+			wantErr: ErrMissing,
+		},
+		{
+			name:    "when header is not a version",
+			ifMatch: `"abc"`,
+			wantErr: fmt.Errorf("%w: %s", ErrInvalid, `"abc"`),
+		},
+		{
+			name:    "when header is a negative version",
+			ifMatch: `"-1"`,
+			wantErr: fmt.Errorf("%w: %s", ErrInvalid, `"-1"`),
+		},
+		{
+			name:    "when header matches any version",
+			ifMatch: "*",
+			want:    dynamo.AnyVersion,
+		},
+		{
+			name:    "when header is a strong etag",
+			ifMatch: `"3"`,
+			want:    3,
+		},
+		{
+			name:    "when header is a weak etag",
+			ifMatch: `W/"3"`,
+			want:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.ifMatch)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
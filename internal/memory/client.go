@@ -0,0 +1,562 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/google/uuid"
+)
+
+type Client struct {
+	mu         sync.RWMutex
+	tables     map[string]map[string]map[string]types.AttributeValue
+	uniqueKeys map[string]string
+	references map[string][]string
+	uuidGen    func() uuid.UUID
+}
+
+func NewClient(uuidGen func() uuid.UUID) *Client {
+	return &Client{
+		tables:     map[string]map[string]map[string]types.AttributeValue{},
+		uniqueKeys: map[string]string{},
+		references: map[string][]string{},
+		uuidGen:    uuidGen,
+	}
+}
+
+func (c *Client) NewID() string {
+	return c.uuidGen().String()
+}
+
+func (c *Client) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueValue string, writes ...dynamo.Write) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableID := ""
+	if id, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		tableID = id.Value
+	}
+	if tableID == "" {
+		tableID = c.NewID()
+	}
+
+	err := checkWrites(2, tableName, tableID, writes)
+	if err != nil {
+		return "", err
+	}
+
+	uniqueKey := dynamo.UniqueKeyID(tableName, uniqueValue)
+	if existingID, ok := c.uniqueKeys[uniqueKey]; ok {
+		return "", &dynamo.DuplicatedError{ID: existingID}
+	}
+
+	err = c.checkSlugWrites(writes)
+	if err != nil {
+		return "", err
+	}
+
+	item["id"] = &types.AttributeValueMemberS{Value: tableID}
+	item["version"] = &types.AttributeValueMemberN{Value: "1"}
+
+	c.uniqueKeys[uniqueKey] = tableID
+	c.table(tableName)[tableID] = maps.Clone(item)
+	c.applyWrites(writes)
+
+	return tableID, nil
+}
+
+func (c *Client) Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueValue string, newUniqueValue string, writes ...dynamo.Write) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	oldUniqueKey := dynamo.UniqueKeyID(tableName, oldUniqueValue)
+	newUniqueKey := dynamo.UniqueKeyID(tableName, newUniqueValue)
+	owned := 1
+	if oldUniqueKey != newUniqueKey {
+		owned = 3
+	}
+
+	err := checkWrites(owned, tableName, id, writes)
+	if err != nil {
+		return err
+	}
+
+	currentItem, ok := c.table(tableName)[id]
+	if !ok {
+		return fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, id)
+	}
+
+	currentVersion, err := itemVersion(currentItem)
+	if err != nil {
+		return err
+	}
+	if currentVersion != version {
+		return fmt.Errorf("%w. id: %s, version: %d", dynamo.ErrVersionMismatch, id, version)
+	}
+
+	if _, ok := c.uniqueKeys[newUniqueKey]; ok && oldUniqueKey != newUniqueKey {
+		return dynamo.ErrDuplicated
+	}
+
+	err = c.checkSlugWrites(writes)
+	if err != nil {
+		return err
+	}
+
+	if oldUniqueKey != newUniqueKey {
+		if c.uniqueKeys[oldUniqueKey] == id {
+			delete(c.uniqueKeys, oldUniqueKey)
+		}
+		c.uniqueKeys[newUniqueKey] = id
+	}
+
+	item["id"] = &types.AttributeValueMemberS{Value: id}
+	item["version"] = &types.AttributeValueMemberN{Value: strconv.Itoa(version + 1)}
+
+	c.table(tableName)[id] = maps.Clone(item)
+	c.applyWrites(writes)
+
+	return nil
+}
+
+func (c *Client) Delete(ctx context.Context, tableName string, id string, uniqueValue string, writes ...dynamo.Write) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := checkWrites(3, tableName, id, writes)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := c.table(tableName)[id]; !ok {
+		return fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, id)
+	}
+
+	referencesKey := dynamo.ReferencesKeyID(tableName, id)
+	if len(c.references[referencesKey]) > 0 {
+		return fmt.Errorf("%w. id: %s", dynamo.ErrReferenced, id)
+	}
+
+	err = c.checkSlugWrites(writes)
+	if err != nil {
+		return err
+	}
+
+	delete(c.table(tableName), id)
+	delete(c.references, referencesKey)
+
+	uniqueKey := dynamo.UniqueKeyID(tableName, uniqueValue)
+	if c.uniqueKeys[uniqueKey] == id {
+		delete(c.uniqueKeys, uniqueKey)
+	}
+	c.applyWrites(writes)
+
+	return nil
+}
+
+func (c *Client) WriteItems(ctx context.Context, tableName string, puts []map[string]types.AttributeValue, deleteIDs []string, writes ...dynamo.Write) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var itemWrites []dynamo.Write
+	for _, item := range puts {
+		id, ok := item["id"].(*types.AttributeValueMemberS)
+		if !ok {
+			return fmt.Errorf("%w. item without id for table: %s", dynamo.ErrDynamodb, tableName)
+		}
+
+		itemWrites = append(itemWrites, dynamo.PutWrite(tableName, id.Value, item))
+	}
+	for _, id := range deleteIDs {
+		itemWrites = append(itemWrites, dynamo.DeleteWrite(tableName, id))
+	}
+	itemWrites = append(itemWrites, writes...)
+
+	err := checkWrites(0, "", "", itemWrites)
+	if err != nil {
+		return err
+	}
+
+	c.applyWrites(itemWrites)
+
+	return nil
+}
+
+func (c *Client) SetAttributes(ctx context.Context, tableName string, id string, attributes map[string]types.AttributeValue, conditions map[string]types.AttributeValue) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.table(tableName)[id]
+	if !ok {
+		return fmt.Errorf("%w. id: %s", dynamo.ErrConditionFailed, id)
+	}
+
+	for attribute, value := range conditions {
+		if c, ok := compare(item[attribute], value); !ok || c != 0 {
+			return fmt.Errorf("%w. id: %s", dynamo.ErrConditionFailed, id)
+		}
+	}
+
+	maps.Copy(item, attributes)
+
+	return nil
+}
+
+func (c *Client) checkSlugWrites(writes []dynamo.Write) error {
+	for _, write := range writes {
+		tableID, ok := c.uniqueKeys[write.ID]
+		if !ok || tableID == write.TableID {
+			continue
+		}
+
+		if write.IsSlugClaim() {
+			return fmt.Errorf("%w. key: %s", dynamo.ErrSlugTaken, write.ID)
+		}
+		if write.IsSlugRelease() {
+			return fmt.Errorf("%w. key: %s belongs to: %s", dynamo.ErrDynamodb, write.ID, tableID)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) applyWrites(writes []dynamo.Write) {
+	for _, write := range writes {
+		switch {
+		case write.IsSlugClaim():
+			c.uniqueKeys[write.ID] = write.TableID
+			continue
+		case write.IsSlugRelease():
+			delete(c.uniqueKeys, write.ID)
+			continue
+		case write.IsReferencesAdd():
+			for _, ref := range write.Refs {
+				if !slices.Contains(c.references[write.ID], ref) {
+					c.references[write.ID] = append(c.references[write.ID], ref)
+				}
+			}
+			continue
+		case write.IsReferencesRemove():
+			c.references[write.ID] = slices.DeleteFunc(c.references[write.ID], func(ref string) bool { return slices.Contains(write.Refs, ref) })
+			continue
+		case write.IsDelete():
+			delete(c.table(write.TableName), write.ID)
+			continue
+		}
+
+		item := maps.Clone(write.Item)
+		item["id"] = &types.AttributeValueMemberS{Value: write.ID}
+		c.table(write.TableName)[write.ID] = item
+	}
+}
+
+func (c *Client) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.getByID(tableName, id)
+}
+
+func (c *Client) GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tableID, err := c.uniqueKeyTableID(tableName, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getByID(tableName, tableID)
+}
+
+func (c *Client) GetBySlug(ctx context.Context, tableName string, slug string) (map[string]types.AttributeValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tableID, err := c.keyTableID(dynamo.SlugKeyID(tableName, slug))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getByID(tableName, tableID)
+}
+
+func (c *Client) BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := make([]map[string]types.AttributeValue, 0, len(ids))
+	for _, id := range ids {
+		item, err := c.getByID(tableName, id)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (c *Client) BatchGetFound(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := make([]map[string]types.AttributeValue, 0, len(ids))
+	for _, id := range ids {
+		if item, err := c.getByID(tableName, id); err == nil {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetReferences(ctx context.Context, tableName string, ids []string) (map[string][]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	references := map[string][]string{}
+	for _, id := range ids {
+		if refs := c.references[dynamo.ReferencesKeyID(tableName, id)]; len(refs) > 0 {
+			references[id] = slices.Sorted(slices.Values(refs))
+		}
+	}
+
+	return references, nil
+}
+
+func (c *Client) BatchGetByUniqueKeys(ctx context.Context, tableName string, values []string) ([]map[string]types.AttributeValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := make([]map[string]types.AttributeValue, 0, len(values))
+	for _, value := range values {
+		tableID, err := c.uniqueKeyTableID(tableName, value)
+		if err != nil {
+			return nil, err
+		}
+
+		item, err := c.getByID(tableName, tableID)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := []map[string]types.AttributeValue{}
+	for _, id := range c.sortedIDs(tableName) {
+		items = append(items, maps.Clone(c.tables[tableName][id]))
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetPage(ctx context.Context, tableName string, limit int32, cursor string) ([]map[string]types.AttributeValue, string, error) {
+	startID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ids := c.sortedIDs(tableName)
+	start, _ := slices.BinarySearch(ids, startID)
+	if startID != "" && start < len(ids) && ids[start] == startID {
+		start++
+	}
+
+	end := len(ids)
+	if limit > 0 && start+int(limit) < end {
+		end = start + int(limit)
+	}
+
+	items := []map[string]types.AttributeValue{}
+	for _, id := range ids[start:end] {
+		items = append(items, maps.Clone(c.tables[tableName][id]))
+	}
+
+	nextCursor := ""
+	if end < len(ids) {
+		nextCursor = encodeCursor(ids[end-1])
+	}
+
+	return items, nextCursor, nil
+}
+
+func (c *Client) Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := []map[string]types.AttributeValue{}
+	for _, id := range c.sortedIDs(tableName) {
+		item := c.tables[tableName][id]
+		if matches(item, query) {
+			items = append(items, maps.Clone(item))
+		}
+	}
+
+	if query.RangeKey != "" {
+		slices.SortStableFunc(items, func(a, b map[string]types.AttributeValue) int {
+			c, _ := compare(a[query.RangeKey], b[query.RangeKey])
+			return c
+		})
+	}
+
+	if startID, ok := query.ExclusiveStartKey["id"]; ok {
+		start := slices.IndexFunc(items, func(item map[string]types.AttributeValue) bool {
+			c, ok := compare(item["id"], startID)
+			return ok && c == 0
+		})
+		items = items[start+1:]
+	}
+	if query.Limit > 0 && len(items) > int(query.Limit) {
+		items = items[:query.Limit]
+	}
+
+	return items, nil
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	return nil
+}
+
+func checkWrites(owned int, tableName string, id string, writes []dynamo.Write) error {
+	writes = dynamo.MergeReferences(writes)
+	if owned+len(writes) > dynamo.TransactWriteLimit {
+		return fmt.Errorf("%w. got: %d, limit: %d", dynamo.ErrTooManyWrites, owned+len(writes), dynamo.TransactWriteLimit)
+	}
+
+	seen := map[string]bool{tableName + "#" + id: owned > 0}
+	for _, write := range writes {
+		key := write.TableName + "#" + write.ID
+		if seen[key] {
+			return fmt.Errorf("%w. transaction has multiple operations on one item. table: %s, id: %s", dynamo.ErrDynamodb, write.TableName, write.ID)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+func (c *Client) table(tableName string) map[string]map[string]types.AttributeValue {
+	table, ok := c.tables[tableName]
+	if !ok {
+		table = map[string]map[string]types.AttributeValue{}
+		c.tables[tableName] = table
+	}
+
+	return table
+}
+
+func (c *Client) getByID(tableName string, id string) (map[string]types.AttributeValue, error) {
+	item, ok := c.tables[tableName][id]
+	if !ok {
+		return nil, fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, id)
+	}
+
+	return maps.Clone(item), nil
+}
+
+func (c *Client) uniqueKeyTableID(tableName string, value string) (string, error) {
+	return c.keyTableID(dynamo.UniqueKeyID(tableName, value))
+}
+
+func (c *Client) keyTableID(key string) (string, error) {
+	tableID, ok := c.uniqueKeys[key]
+	if !ok {
+		return "", fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, key)
+	}
+
+	return tableID, nil
+}
+
+func (c *Client) sortedIDs(tableName string) []string {
+	return slices.Sorted(maps.Keys(c.tables[tableName]))
+}
+
+func compare(a types.AttributeValue, b types.AttributeValue) (int, bool) {
+	switch a := a.(type) {
+	case *types.AttributeValueMemberS:
+		if b, ok := b.(*types.AttributeValueMemberS); ok {
+			return strings.Compare(a.Value, b.Value), true
+		}
+	case *types.AttributeValueMemberN:
+		if b, ok := b.(*types.AttributeValueMemberN); ok {
+			x, errA := strconv.ParseFloat(a.Value, 64)
+			y, errB := strconv.ParseFloat(b.Value, 64)
+			if errA == nil && errB == nil {
+				return cmp.Compare(x, y), true
+			}
+		}
+	case *types.AttributeValueMemberBOOL:
+		if b, ok := b.(*types.AttributeValueMemberBOOL); ok {
+			return cmp.Compare(strconv.FormatBool(a.Value), strconv.FormatBool(b.Value)), true
+		}
+	}
+
+	return 0, false
+}
+
+func matches(item map[string]types.AttributeValue, query dynamo.Query) bool {
+	if c, ok := compare(item[query.HashKey], query.HashValue); !ok || c != 0 {
+		return false
+	}
+	if c, ok := compare(item[query.RangeKey], query.From); query.From != nil && (!ok || c < 0) {
+		return false
+	}
+	if c, ok := compare(item[query.RangeKey], query.To); query.To != nil && (!ok || c > 0) {
+		return false
+	}
+
+	return true
+}
+
+func itemVersion(item map[string]types.AttributeValue) (int, error) {
+	version, ok := item["version"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+
+	v, err := strconv.Atoi(version.Value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse version: %w", err)
+	}
+
+	return v, nil
+}
+
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(id) == 0 {
+		return "", fmt.Errorf("%w: %s", dynamo.ErrInvalidCursor, cursor)
+	}
+
+	return string(id), nil
+}
@@ -0,0 +1,538 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var firstID = uuid.MustParse("c6767b2d-438b-4d4c-8b1a-659130a640ca")
+var secondID = uuid.MustParse("d6767b2d-438b-4d4c-8b1a-659130a640ca")
+
+func sequentialUUIDs(ids ...uuid.UUID) func() uuid.UUID {
+	i := 0
+	return func() uuid.UUID {
+		id := ids[i]
+		i++
+		return id
+	}
+}
+
+func titleItem(title string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"title": &types.AttributeValueMemberS{Value: title}}
+}
+
+func storedItem(id string, title string, version string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: id},
+		"title":   &types.AttributeValueMemberS{Value: title},
+		"version": &types.AttributeValueMemberN{Value: version},
+	}
+}
+
+func TestClient_Save(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*Client)
+		want    string
+		wantErr error
+	}{
+		{
+			name: "when unique key already exists",
+			setup: func(c *Client) {
+				c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+			},
+			wantErr: &dynamo.DuplicatedError{ID: firstID.String()},
+		},
+		{
+			name: "when unique key already exists with different case and spacing",
+			setup: func(c *Client) {
+				c.Save(ctx, "table-name", titleItem("the black echo "), "the black echo ")
+			},
+			wantErr: &dynamo.DuplicatedError{ID: firstID.String()},
+		},
+		{
+			name: "when unique key exists in another table",
+			setup: func(c *Client) {
+				c.Save(ctx, "other-table", titleItem("The Black Echo"), "The Black Echo")
+			},
+			want: secondID.String(),
+		},
+		{
+			name:  "when successfully saved",
+			setup: func(c *Client) {},
+			want:  firstID.String(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(sequentialUUIDs(firstID, secondID))
+			tt.setup(c)
+
+			got, err := c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				item, _ := c.GetByID(ctx, "table-name", tt.want)
+				assert.Equal(t, storedItem(tt.want, "The Black Echo", "1"), item)
+			}
+		})
+	}
+}
+
+func TestClient_Update(t *testing.T) {
+	ctx := context.Background()
+	id := firstID.String()
+	tests := []struct {
+		name     string
+		setup    func(*Client)
+		version  int
+		newTitle string
+		wantErr  error
+		want     map[string]types.AttributeValue
+	}{
+		{
+			name:     "when item not found",
+			setup:    func(c *Client) {},
+			version:  1,
+			newTitle: "The Black Echo",
+			wantErr:  fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, id),
+		},
+		{
+			name: "when version mismatch",
+			setup: func(c *Client) {
+				c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+			},
+			version:  2,
+			newTitle: "The Black Echo",
+			wantErr:  fmt.Errorf("%w. id: %s, version: %d", dynamo.ErrVersionMismatch, id, 2),
+		},
+		{
+			name: "when new unique key already exists",
+			setup: func(c *Client) {
+				c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+				c.Save(ctx, "table-name", titleItem("The Black Ice"), "The Black Ice")
+			},
+			version:  1,
+			newTitle: "The Black Ice",
+			wantErr:  dynamo.ErrDuplicated,
+		},
+		{
+			name: "when successfully updated unversioned item",
+			setup: func(c *Client) {
+				c.tables["table-name"] = map[string]map[string]types.AttributeValue{id: {"id": &types.AttributeValueMemberS{Value: id}}}
+				c.uniqueKeys["table-name#the black echo"] = id
+			},
+			version:  0,
+			newTitle: "The Black Echo",
+			want:     storedItem(id, "The Black Echo", "1"),
+		},
+		{
+			name: "when successfully updated with new unique key",
+			setup: func(c *Client) {
+				c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+			},
+			version:  1,
+			newTitle: "The Black Ice",
+			want:     storedItem(id, "The Black Ice", "2"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(sequentialUUIDs(firstID, secondID))
+			tt.setup(c)
+
+			err := c.Update(ctx, "table-name", id, tt.version, titleItem(tt.newTitle), "The Black Echo", tt.newTitle)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				item, err := c.GetByUniqueKey(ctx, "table-name", tt.newTitle)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, item)
+			}
+		})
+	}
+}
+
+func TestClient_Delete(t *testing.T) {
+	ctx := context.Background()
+	id := firstID.String()
+	tests := []struct {
+		name    string
+		setup   func(*Client)
+		wantErr error
+	}{
+		{
+			name:    "when item not found",
+			setup:   func(c *Client) {},
+			wantErr: fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, id),
+		},
+		{
+			name: "when successfully deleted",
+			setup: func(c *Client) {
+				c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(sequentialUUIDs(firstID, secondID))
+			tt.setup(c)
+
+			err := c.Delete(ctx, "table-name", id, "The Black Echo")
+
+			assert.Equal(t, tt.wantErr, err)
+			_, err = c.GetByUniqueKey(ctx, "table-name", "The Black Echo")
+			assert.ErrorIs(t, err, dynamo.ErrNotFound)
+			_, err = c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_GetByUniqueKey(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*Client)
+		want    map[string]types.AttributeValue
+		wantErr error
+	}{
+		{
+			name:    "when unique key not found",
+			setup:   func(c *Client) {},
+			wantErr: fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, "table-name#the black echo"),
+		},
+		{
+			name: "when successfully get by unique key",
+			setup: func(c *Client) {
+				c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+			},
+			want: storedItem(firstID.String(), "The Black Echo", "1"),
+		},
+		{
+			name: "when successfully get by unique key saved with different case",
+			setup: func(c *Client) {
+				c.Save(ctx, "table-name", titleItem("THE BLACK ECHO"), "THE BLACK ECHO")
+			},
+			want: storedItem(firstID.String(), "THE BLACK ECHO", "1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(sequentialUUIDs(firstID, secondID))
+			tt.setup(c)
+
+			got, err := c.GetByUniqueKey(ctx, "table-name", "The Black Echo")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestClient_BatchGet(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(sequentialUUIDs(firstID, secondID))
+	c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+	c.Save(ctx, "table-name", titleItem("The Black Ice"), "The Black Ice")
+	blackEcho := storedItem(firstID.String(), "The Black Echo", "1")
+	blackIce := storedItem(secondID.String(), "The Black Ice", "1")
+
+	got, err := c.BatchGetByIDs(ctx, "table-name", []string{secondID.String(), firstID.String(), secondID.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]types.AttributeValue{blackIce, blackEcho, blackIce}, got)
+
+	_, err = c.BatchGetByIDs(ctx, "table-name", []string{firstID.String(), "random-id"})
+	assert.Equal(t, fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, "random-id"), err)
+
+	got, err = c.BatchGetFound(ctx, "table-name", []string{"random-id", firstID.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]types.AttributeValue{blackEcho}, got)
+
+	got, err = c.BatchGetByUniqueKeys(ctx, "table-name", []string{"The Black Ice", "The Black Echo"})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]types.AttributeValue{blackIce, blackEcho}, got)
+
+	_, err = c.BatchGetByUniqueKeys(ctx, "table-name", []string{"The Concrete Blonde"})
+	assert.Equal(t, fmt.Errorf("%w. id: %s", dynamo.ErrNotFound, "table-name#the concrete blonde"), err)
+}
+
+func TestClient_GetPage(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(sequentialUUIDs(secondID, firstID))
+	c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+	c.Save(ctx, "table-name", titleItem("The Black Ice"), "The Black Ice")
+	blackEcho := storedItem(secondID.String(), "The Black Echo", "1")
+	blackIce := storedItem(firstID.String(), "The Black Ice", "1")
+
+	tests := []struct {
+		name       string
+		limit      int32
+		cursor     string
+		want       []map[string]types.AttributeValue
+		wantCursor string
+		wantErr    error
+	}{
+		{
+			name:    "when cursor is invalid",
+			limit:   1,
+			cursor:  "not base64!",
+			wantErr: fmt.Errorf("%w: %s", dynamo.ErrInvalidCursor, "not base64!"),
+		},
+		{
+			name:       "when first page",
+			limit:      1,
+			want:       []map[string]types.AttributeValue{blackIce},
+			wantCursor: encodeCursor(firstID.String()),
+		},
+		{
+			name:   "when last page",
+			limit:  1,
+			cursor: encodeCursor(firstID.String()),
+			want:   []map[string]types.AttributeValue{blackEcho},
+		},
+		{
+			name: "when no limit",
+			want: []map[string]types.AttributeValue{blackIce, blackEcho},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotCursor, err := c.GetPage(ctx, "table-name", tt.limit, tt.cursor)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCursor, gotCursor)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	all, err := c.GetAll(ctx, "table-name")
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]types.AttributeValue{blackIce, blackEcho}, all)
+}
+
+func TestClient_Query(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(uuid.New)
+	for title, year := range map[string]string{"The Black Echo": "1992", "The Closers": "2005", "The Overlook": "2007", "The Drop": "2011"} {
+		c.Save(ctx, "table-name", map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: title}, "entity": &types.AttributeValueMemberS{Value: "book"}, "title": &types.AttributeValueMemberS{Value: title}, "year": &types.AttributeValueMemberN{Value: year}}, title)
+	}
+	c.Save(ctx, "table-name", titleItem("Untyped"), "Untyped")
+	hashValue := &types.AttributeValueMemberS{Value: "book"}
+
+	tests := []struct {
+		name  string
+		query dynamo.Query
+		want  []string
+	}{
+		{
+			name:  "when querying a range",
+			query: dynamo.Query{HashKey: "entity", HashValue: hashValue, RangeKey: "year", From: &types.AttributeValueMemberN{Value: "2000"}, To: &types.AttributeValueMemberN{Value: "2009"}},
+			want:  []string{"The Closers", "The Overlook"},
+		},
+		{
+			name:  "when querying an upper bound",
+			query: dynamo.Query{HashKey: "entity", HashValue: hashValue, RangeKey: "year", To: &types.AttributeValueMemberN{Value: "2005"}},
+			want:  []string{"The Black Echo", "The Closers"},
+		},
+		{
+			name:  "when querying only the hash key",
+			query: dynamo.Query{HashKey: "entity", HashValue: hashValue, RangeKey: "year"},
+			want:  []string{"The Black Echo", "The Closers", "The Overlook", "The Drop"},
+		},
+		{
+			name:  "when query is limited and starts after a key",
+			query: dynamo.Query{HashKey: "entity", HashValue: hashValue, RangeKey: "year", Limit: 2, ExclusiveStartKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "The Black Echo"}}},
+			want:  []string{"The Closers", "The Overlook"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := c.Query(ctx, "table-name", tt.query)
+
+			var got []string
+			for _, item := range items {
+				got = append(got, item["title"].(*types.AttributeValueMemberS).Value)
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_WriteItems(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(uuid.New)
+	c.WriteItems(ctx, "table-name", []map[string]types.AttributeValue{storedItem("first", "The Black Echo", "1"), storedItem("second", "The Black Ice", "1")}, nil)
+
+	err := c.WriteItems(ctx, "table-name", []map[string]types.AttributeValue{storedItem("third", "The Concrete Blonde", "1")}, []string{"first", "missing"})
+	assert.NoError(t, err)
+
+	items, err := c.GetAll(ctx, "table-name")
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]types.AttributeValue{storedItem("second", "The Black Ice", "1"), storedItem("third", "The Concrete Blonde", "1")}, items)
+
+	err = c.WriteItems(ctx, "table-name", []map[string]types.AttributeValue{titleItem("No ID")}, nil)
+	assert.ErrorIs(t, err, dynamo.ErrDynamodb)
+}
+
+func TestClient_SetAttributes(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(uuid.New)
+	revoked := map[string]types.AttributeValue{"revoked": &types.AttributeValueMemberBOOL{Value: false}}
+	lastUsed := map[string]types.AttributeValue{"last_used_at": &types.AttributeValueMemberS{Value: "2026-10-02T08:30:00Z"}}
+	key := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "key-id"}, "revoked": &types.AttributeValueMemberBOOL{Value: false}}
+	c.WriteItems(ctx, "api-keys", []map[string]types.AttributeValue{key}, nil)
+
+	err := c.SetAttributes(ctx, "api-keys", "missing", lastUsed, revoked)
+	assert.ErrorIs(t, err, dynamo.ErrConditionFailed)
+
+	err = c.SetAttributes(ctx, "api-keys", "key-id", lastUsed, revoked)
+	assert.NoError(t, err)
+	item, _ := c.GetByID(ctx, "api-keys", "key-id")
+	assert.Equal(t, lastUsed["last_used_at"], item["last_used_at"])
+
+	err = c.SetAttributes(ctx, "api-keys", "key-id", map[string]types.AttributeValue{"revoked": &types.AttributeValueMemberBOOL{Value: true}}, nil)
+	assert.NoError(t, err)
+
+	err = c.SetAttributes(ctx, "api-keys", "key-id", lastUsed, revoked)
+	assert.ErrorIs(t, err, dynamo.ErrConditionFailed)
+}
+
+func TestClient_Writes(t *testing.T) {
+	ctx := context.Background()
+	link := func(bookID string) dynamo.Write {
+		return dynamo.PutWrite("links", "series#"+bookID, map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: bookID}})
+	}
+	c := NewClient(uuid.New)
+
+	id, err := c.Save(ctx, "table-name", storedItem("given-id", "The Black Echo", "0"), "The Black Echo", link("book-1"), link("book-2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "given-id", id)
+	links, _ := c.GetAll(ctx, "links")
+	assert.Len(t, links, 2)
+
+	err = c.Update(ctx, "table-name", id, 1, titleItem("The Black Echo"), "The Black Echo", "The Black Echo", link("book-3"), link("book-3"))
+	assert.ErrorIs(t, err, dynamo.ErrDynamodb)
+	item, _ := c.GetByID(ctx, "table-name", id)
+	assert.Equal(t, storedItem(id, "The Black Echo", "1"), item)
+
+	var tooMany []dynamo.Write
+	for i := range dynamo.TransactWriteLimit {
+		tooMany = append(tooMany, link(fmt.Sprint(i)))
+	}
+	err = c.Update(ctx, "table-name", id, 1, titleItem("The Black Echo"), "The Black Echo", "The Black Echo", tooMany...)
+	assert.ErrorIs(t, err, dynamo.ErrTooManyWrites)
+
+	err = c.Update(ctx, "table-name", id, 1, titleItem("The Black Echo"), "The Black Echo", "The Black Echo", link("book-3"), dynamo.DeleteWrite("links", "series#book-1"))
+	assert.NoError(t, err)
+	links, _ = c.GetAll(ctx, "links")
+	assert.Len(t, links, 2)
+
+	err = c.Delete(ctx, "table-name", id, "The Black Echo", dynamo.DeleteWrite("links", "series#book-2"), dynamo.DeleteWrite("links", "series#book-3"))
+	assert.NoError(t, err)
+	links, _ = c.GetAll(ctx, "links")
+	assert.Empty(t, links)
+}
+
+func TestClient_Slugs(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(uuid.New)
+
+	id, err := c.Save(ctx, "books", storedItem("book-id", "The Black Echo", "0"), "The Black Echo", dynamo.ClaimSlugWrite("books", "the-black-echo", "book-id"))
+	assert.NoError(t, err)
+
+	_, err = c.Save(ctx, "books", storedItem("other-book-id", "The Black Echo Returns", "0"), "The Black Echo Returns", dynamo.ClaimSlugWrite("books", "the-black-echo", "other-book-id"))
+	assert.ErrorIs(t, err, dynamo.ErrSlugTaken)
+	_, err = c.GetByID(ctx, "books", "other-book-id")
+	assert.ErrorIs(t, err, dynamo.ErrNotFound)
+
+	err = c.Update(ctx, "books", id, 1, titleItem("The Black Echo Reissued"), "The Black Echo", "The Black Echo Reissued", dynamo.ClaimSlugWrite("books", "the-black-echo", id))
+	assert.NoError(t, err)
+	item, err := c.GetBySlug(ctx, "books", "The-Black-Echo")
+	assert.NoError(t, err)
+	assert.Equal(t, storedItem(id, "The Black Echo Reissued", "2"), item)
+
+	err = c.Delete(ctx, "books", id, "The Black Echo Reissued", dynamo.ReleaseSlugWrite("books", "the-black-echo", id))
+	assert.NoError(t, err)
+	_, err = c.GetBySlug(ctx, "books", "the-black-echo")
+	assert.ErrorIs(t, err, dynamo.ErrNotFound)
+}
+
+func TestClient_References(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(uuid.New)
+	link := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "series#series-id#book-id"}}
+
+	id, err := c.Save(ctx, "books", storedItem("book-id", "The Black Echo", "0"), "The Black Echo")
+	assert.NoError(t, err)
+
+	err = c.WriteItems(ctx, "links", []map[string]types.AttributeValue{link}, nil, dynamo.AddReferencesWrite("books", id, "series#series-id#book-id"))
+	assert.NoError(t, err)
+
+	err = c.Delete(ctx, "books", id, "The Black Echo")
+	assert.ErrorIs(t, err, dynamo.ErrReferenced)
+	_, err = c.GetByID(ctx, "books", id)
+	assert.NoError(t, err)
+
+	references, err := c.GetReferences(ctx, "books", []string{id, "missing-id"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{id: {"series#series-id#book-id"}}, references)
+
+	err = c.WriteItems(ctx, "links", nil, []string{"series#series-id#book-id"}, dynamo.RemoveReferencesWrite("books", id, "series#series-id#book-id"))
+	assert.NoError(t, err)
+
+	references, err = c.GetReferences(ctx, "books", []string{id})
+	assert.NoError(t, err)
+	assert.Empty(t, references)
+
+	err = c.Delete(ctx, "books", id, "The Black Echo")
+	assert.NoError(t, err)
+
+	err = c.WriteItems(ctx, "links", nil, nil, dynamo.AddReferencesWrite("books", "book-1", "series#first#book-1"), dynamo.AddReferencesWrite("books", "book-1", "series#second#book-1"))
+	assert.NoError(t, err)
+	references, err = c.GetReferences(ctx, "books", []string{"book-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"book-1": {"series#first#book-1", "series#second#book-1"}}, references)
+
+	err = c.WriteItems(ctx, "links", nil, nil, dynamo.AddReferencesWrite("books", "book-1", "series#third#book-1"), dynamo.RemoveReferencesWrite("books", "book-1", "series#first#book-1"))
+	assert.ErrorIs(t, err, dynamo.ErrDynamodb)
+}
+
+func TestClient_ConcurrentSave(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(uuid.New)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Save(ctx, "table-name", titleItem("The Black Echo"), "The Black Echo")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	saved := 0
+	for err := range errs {
+		if err == nil {
+			saved++
+			continue
+		}
+		assert.ErrorIs(t, err, dynamo.ErrDuplicated)
+	}
+
+	assert.Equal(t, 1, saved)
+}
@@ -1,56 +1,92 @@
 package middleware
 
 import (
-	"net"
+	"errors"
 	"net/http"
 	"strings"
-	"sync"
-	"time"
 
+	"github.com/ggoulart/michael-connelly-api/internal/auth"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
-var adminToken = "meu_token_secreto"
-var visitors = make(map[string]*rate.Limiter)
-var mu sync.Mutex
+const (
+	SubjectKey   = "subject"
+	ScopesKey    = "scopes"
+	PrincipalKey = "principal"
+)
 
-func Admin() gin.HandlerFunc {
+func APIKey(authenticator auth.Authenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid token"})
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			c.Next()
 			return
 		}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token != adminToken {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "unauthorized"})
+		principal, err := authenticator.Authenticate(c, key)
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+			return
+		}
+		if err != nil {
+			c.Error(err)
+			c.Abort()
 			return
 		}
 
+		setPrincipal(c, principal)
 		c.Next()
 	}
 }
 
-func RateLimit() gin.HandlerFunc {
+func Authorize(authenticator auth.Authenticator, scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip, _, _ := net.SplitHostPort(c.Request.RemoteAddr)
+		principal, ok := principalFrom(c)
+		if !ok {
+			authHeader := c.GetHeader("Authorization")
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				c.Header("WWW-Authenticate", "Bearer")
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid token"})
+				return
+			}
 
-		mu.Lock()
-		defer mu.Unlock()
-
-		limiter, exists := visitors[ip]
-		if !exists {
-			// 5 requisições por minuto, estouro máximo de 10
-			limiter = rate.NewLimiter(rate.Every(time.Minute/5), 10)
-			visitors[ip] = limiter
+			var err error
+			principal, err = authenticator.Authenticate(c, strings.TrimPrefix(authHeader, "Bearer "))
+			if errors.Is(err, auth.ErrUnauthenticated) {
+				c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid token"})
+				return
+			}
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
 		}
 
-		if !limiter.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+		if !principal.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient scope", "scope": scope})
 			return
 		}
+
+		setPrincipal(c, principal)
 		c.Next()
 	}
 }
+
+func setPrincipal(c *gin.Context, principal auth.Principal) {
+	c.Set(PrincipalKey, principal)
+	c.Set(SubjectKey, principal.Subject)
+	c.Set(ScopesKey, principal.Scopes)
+}
+
+func principalFrom(c *gin.Context) (auth.Principal, bool) {
+	value, ok := c.Get(PrincipalKey)
+	if !ok {
+		return auth.Principal{}, false
+	}
+
+	principal, ok := value.(auth.Principal)
+
+	return principal, ok
+}
@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ggoulart/michael-connelly-api/internal/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name            string
+		header          string
+		setup           func(*AuthenticatorMock)
+		expectedStatus  int
+		expectedBody    string
+		expectedSubject string
+	}{
+		{
+			name:           "when authorization header is missing",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"missing or invalid token"}`,
+		},
+		{
+			name:           "when authorization header is not a bearer token",
+			header:         "Basic dXNlcjpwYXNz",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"missing or invalid token"}`,
+		},
+		{
+			name:   "when token is invalid",
+			header: "Bearer invalid",
+			setup: func(a *AuthenticatorMock) {
+				a.On("Authenticate", mock.Anything, "invalid").Return(auth.Principal{}, auth.ErrUnauthenticated).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"missing or invalid token"}`,
+		},
+		{
+			name:   "when token lacks the route scope",
+			header: "Bearer valid",
+			setup: func(a *AuthenticatorMock) {
+				a.On("Authenticate", mock.Anything, "valid").Return(auth.Principal{Subject: "partner", Scopes: []string{"series:write"}}, nil).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"insufficient scope","scope":"books:write"}`,
+		},
+		{
+			name:   "when authenticator fails unexpectedly",
+			header: "Bearer valid",
+			setup: func(a *AuthenticatorMock) {
+				a.On("Authenticate", mock.Anything, "valid").Return(auth.Principal{}, assert.AnError).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "",
+		},
+		{
+			name:   "when token has the route scope",
+			header: "Bearer valid",
+			setup: func(a *AuthenticatorMock) {
+				a.On("Authenticate", mock.Anything, "valid").Return(auth.Principal{Subject: "partner", Scopes: []string{"books:write"}}, nil).Once()
+			},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "partner",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := new(AuthenticatorMock)
+			if tt.setup != nil {
+				tt.setup(authenticator)
+			}
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/books", nil)
+			if tt.header != "" {
+				ctx.Request.Header.Set("Authorization", tt.header)
+			}
+
+			Authorize(authenticator, "books:write")(ctx)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Equal(t, tt.expectedBody, recorder.Body.String())
+			assert.Equal(t, tt.expectedSubject, ctx.GetString(SubjectKey))
+			authenticator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthorize_WithAPIKeyPrincipal(t *testing.T) {
+	tests := []struct {
+		name           string
+		scopes         []string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "when api key lacks the route scope",
+			scopes:         []string{"series:write"},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"insufficient scope","scope":"books:write"}`,
+		},
+		{
+			name:           "when api key has the route scope",
+			scopes:         []string{"books:write"},
+			expectedStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := new(AuthenticatorMock)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/books", nil)
+			ctx.Set(PrincipalKey, auth.Principal{Subject: "partner", Scopes: tt.scopes, KeyID: "key-id", Tier: "partner"})
+
+			Authorize(authenticator, "books:write")(ctx)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Equal(t, tt.expectedBody, recorder.Body.String())
+			authenticator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	tests := []struct {
+		name              string
+		header            string
+		setup             func(*AuthenticatorMock)
+		expectedStatus    int
+		expectedBody      string
+		expectedPrincipal any
+		expectedErr       error
+	}{
+		{
+			name:           "when api key header is missing",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "when api key is invalid",
+			header: "key-id.wrong",
+			setup: func(a *AuthenticatorMock) {
+				a.On("Authenticate", mock.Anything, "key-id.wrong").Return(auth.Principal{}, auth.ErrUnauthenticated).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"invalid api key"}`,
+		},
+		{
+			name:   "when authenticator fails unexpectedly",
+			header: "key-id.secret",
+			setup: func(a *AuthenticatorMock) {
+				a.On("Authenticate", mock.Anything, "key-id.secret").Return(auth.Principal{}, assert.AnError).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedErr:    assert.AnError,
+		},
+		{
+			name:   "when api key is valid",
+			header: "key-id.secret",
+			setup: func(a *AuthenticatorMock) {
+				a.On("Authenticate", mock.Anything, "key-id.secret").Return(auth.Principal{Subject: "partner", Scopes: []string{"books:write"}, KeyID: "key-id", Tier: "partner"}, nil).Once()
+			},
+			expectedStatus:    http.StatusOK,
+			expectedPrincipal: auth.Principal{Subject: "partner", Scopes: []string{"books:write"}, KeyID: "key-id", Tier: "partner"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := new(AuthenticatorMock)
+			if tt.setup != nil {
+				tt.setup(authenticator)
+			}
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+			if tt.header != "" {
+				ctx.Request.Header.Set("X-API-Key", tt.header)
+			}
+
+			APIKey(authenticator)(ctx)
+
+			principal, _ := ctx.Get(PrincipalKey)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Equal(t, tt.expectedBody, recorder.Body.String())
+			assert.Equal(t, tt.expectedPrincipal, principal)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, ctx.Errors.Last(), tt.expectedErr)
+			}
+			authenticator.AssertExpectations(t)
+		})
+	}
+}
+
+type AuthenticatorMock struct {
+	mock.Mock
+}
+
+func (m *AuthenticatorMock) Authenticate(ctx context.Context, token string) (auth.Principal, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(auth.Principal), args.Error(1)
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
@@ -22,12 +24,35 @@ func Error() gin.HandlerFunc {
 		var validationErrs validator.ValidationErrors
 		var jsonSyntaxError *json.SyntaxError
 		var jsonUnmarshalTypeError *json.UnmarshalTypeError
+		var duplicatedErr *dynamo.DuplicatedError
+		var referencedErr *apperr.ReferencedError
+		var invalidErr *apperr.InvalidError
 
 		//slog.Error(err.Error())
 
 		switch {
 		case errors.Is(err, dynamo.ErrNotFound):
 			ctx.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		case errors.As(err, &duplicatedErr):
+			ctx.AbortWithStatusJSON(409, gin.H{"error": "duplicated", "id": duplicatedErr.ID})
+		case errors.Is(err, dynamo.ErrDuplicated):
+			ctx.AbortWithStatusJSON(409, gin.H{"error": "duplicated"})
+		case errors.Is(err, dynamo.ErrSlugTaken):
+			ctx.AbortWithStatusJSON(409, gin.H{"error": "slug taken"})
+		case errors.As(err, &referencedErr):
+			ctx.AbortWithStatusJSON(409, gin.H{"error": referencedErr.Resource + " is referenced"})
+		case errors.Is(err, dynamo.ErrVersionMismatch):
+			ctx.AbortWithStatusJSON(412, gin.H{"error": "version mismatch"})
+		case errors.Is(err, etag.ErrMissing):
+			ctx.AbortWithStatusJSON(428, gin.H{"error": "missing If-Match header"})
+		case errors.Is(err, etag.ErrInvalid):
+			ctx.AbortWithStatusJSON(400, gin.H{"error": "invalid If-Match header"})
+		case errors.Is(err, dynamo.ErrInvalidCursor):
+			ctx.AbortWithStatusJSON(400, gin.H{"error": "invalid cursor"})
+		case errors.Is(err, dynamo.ErrTooManyWrites):
+			ctx.AbortWithStatusJSON(400, gin.H{"error": "too many related items"})
+		case errors.As(err, &invalidErr):
+			ctx.AbortWithStatusJSON(400, gin.H{"error": invalidErr.Reason})
 		case errors.As(err, &validationErrs) || errors.As(err, &jsonSyntaxError) || errors.As(err, &jsonUnmarshalTypeError):
 			ctx.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
 		default:
@@ -2,12 +2,15 @@ package middleware
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
 
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
@@ -31,6 +34,70 @@ func TestError(t *testing.T) {
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   `{"error":"not found"}`,
 		},
+		{
+			name:           "when error is dynamo.ErrDuplicated",
+			setup:          func(ctx *gin.Context) { ctx.Error(dynamo.ErrDuplicated) },
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"duplicated"}`,
+		},
+		{
+			name:           "when error is dynamo.DuplicatedError",
+			setup:          func(ctx *gin.Context) { ctx.Error(&dynamo.DuplicatedError{ID: "existing-id"}) },
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"duplicated","id":"existing-id"}`,
+		},
+		{
+			name: "when error is dynamo.ErrSlugTaken",
+			setup: func(ctx *gin.Context) {
+				ctx.Error(fmt.Errorf("%w. key: books#slug:the-black-echo", dynamo.ErrSlugTaken))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"slug taken"}`,
+		},
+		{
+			name:           "when error is apperr.ReferencedError",
+			setup:          func(ctx *gin.Context) { ctx.Error(&apperr.ReferencedError{Resource: "book", ID: "a-book-id"}) },
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"book is referenced"}`,
+		},
+		{
+			name:           "when error is dynamo.ErrVersionMismatch",
+			setup:          func(ctx *gin.Context) { ctx.Error(dynamo.ErrVersionMismatch) },
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody:   `{"error":"version mismatch"}`,
+		},
+		{
+			name:           "when error is etag.ErrMissing",
+			setup:          func(ctx *gin.Context) { ctx.Error(etag.ErrMissing) },
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedBody:   `{"error":"missing If-Match header"}`,
+		},
+		{
+			name:           "when error is etag.ErrInvalid",
+			setup:          func(ctx *gin.Context) { ctx.Error(etag.ErrInvalid) },
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid If-Match header"}`,
+		},
+		{
+			name:           "when error is dynamo.ErrInvalidCursor",
+			setup:          func(ctx *gin.Context) { ctx.Error(dynamo.ErrInvalidCursor) },
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid cursor"}`,
+		},
+		{
+			name:           "when error is dynamo.ErrTooManyWrites",
+			setup:          func(ctx *gin.Context) { ctx.Error(dynamo.ErrTooManyWrites) },
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"too many related items"}`,
+		},
+		{
+			name: "when error is apperr.InvalidError",
+			setup: func(ctx *gin.Context) {
+				ctx.Error(fmt.Errorf("%w. id: some-id", &apperr.InvalidError{Reason: "character cannot be related to itself"}))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"character cannot be related to itself"}`,
+		},
 		{
 			name:           "when error is validator.ValidationErrors",
 			setup:          func(ctx *gin.Context) { ctx.Error(validator.ValidationErrors{}) },
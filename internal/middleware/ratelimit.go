@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+	"github.com/ggoulart/michael-connelly-api/internal/apikeys"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+var tierLimits = map[string]Limit{
+	apikeys.TierFree:     {PerMinute: 30, Burst: 60},
+	apikeys.TierStandard: {PerMinute: 120, Burst: 240},
+	apikeys.TierPartner:  {PerMinute: 600, Burst: 1200},
+}
+
+type RateLimiter struct {
+	limit       Limit
+	trustedHops int
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	visitors  map[string]*visitor
+	lastSweep time.Time
+}
+
+type visitor struct {
+	limit    Limit
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func NewRateLimiter(limit Limit, trustedHops int, idleTimeout time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, trustedHops: trustedHops, idleTimeout: idleTimeout, now: time.Now, visitors: map[string]*visitor{}}
+}
+
+func (l *RateLimiter) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, limit := "ip:"+l.clientIP(c), l.limit
+		if principal, ok := principalFrom(c); ok && principal.KeyID != "" {
+			key, limit = "key:"+principal.KeyID, l.tierLimit(principal.Tier)
+		}
+
+		now := l.now()
+
+		l.mu.Lock()
+		l.evict(now)
+		limiter := l.limiter(key, limit, now)
+		reservation := limiter.ReserveN(now, 1)
+		delay := reservation.DelayFrom(now)
+		if delay > 0 {
+			reservation.CancelAt(now)
+		}
+		remaining := int(math.Max(0, math.Floor(limiter.TokensAt(now))))
+		l.mu.Unlock()
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if delay > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func (l *RateLimiter) tierLimit(tier string) Limit {
+	if limit, ok := tierLimits[tier]; ok {
+		return limit
+	}
+
+	return l.limit
+}
+
+func (l *RateLimiter) limiter(key string, limit Limit, now time.Time) *rate.Limiter {
+	v, ok := l.visitors[key]
+	if !ok || v.limit != limit {
+		v = &visitor{limit: limit, limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(limit.PerMinute)), limit.Burst)}
+		l.visitors[key] = v
+	}
+	v.lastSeen = now
+
+	return v.limiter
+}
+
+func (l *RateLimiter) evict(now time.Time) {
+	if now.Sub(l.lastSweep) < l.idleTimeout {
+		return
+	}
+
+	for key, v := range l.visitors {
+		if now.Sub(v.lastSeen) >= l.idleTimeout {
+			delete(l.visitors, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+func (l *RateLimiter) clientIP(c *gin.Context) string {
+	if requestContext, ok := core.GetAPIGatewayContextFromContext(c.Request.Context()); ok && requestContext.Identity.SourceIP != "" {
+		return requestContext.Identity.SourceIP
+	}
+
+	if l.trustedHops > 0 {
+		var hops []string
+		for _, header := range c.Request.Header.Values("X-Forwarded-For") {
+			for _, hop := range strings.Split(header, ",") {
+				hops = append(hops, strings.TrimSpace(hop))
+			}
+		}
+
+		if len(hops) >= l.trustedHops && hops[len(hops)-l.trustedHops] != "" {
+			return hops[len(hops)-l.trustedHops]
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+	if err != nil {
+		return c.Request.RemoteAddr
+	}
+
+	return ip
+}
@@ -0,0 +1,221 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+	"github.com/ggoulart/michael-connelly-api/internal/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Handler(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		allowed   int
+	}{
+		{
+			name:    "when caller is anonymous",
+			allowed: 3,
+		},
+		{
+			name:      "when caller uses a free api key",
+			principal: &auth.Principal{Subject: "free-team", KeyID: "free-key", Tier: "free"},
+			allowed:   60,
+		},
+		{
+			name:      "when caller uses an api key with an unknown tier",
+			principal: &auth.Principal{Subject: "legacy-team", KeyID: "legacy-key", Tier: "gold"},
+			allowed:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+			limiter := NewRateLimiter(Limit{PerMinute: 6, Burst: 3}, 0, 10*time.Minute)
+			limiter.now = func() time.Time { return now }
+			handler := limiter.Handler()
+
+			var recorders []*httptest.ResponseRecorder
+			for range tt.allowed + 1 {
+				recorder := httptest.NewRecorder()
+				ctx, _ := gin.CreateTestContext(recorder)
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+				if tt.principal != nil {
+					ctx.Set(PrincipalKey, *tt.principal)
+				}
+
+				handler(ctx)
+
+				recorders = append(recorders, recorder)
+			}
+
+			first, last := recorders[0], recorders[tt.allowed]
+			assert.Equal(t, http.StatusOK, first.Code)
+			assert.Equal(t, strconv.Itoa(tt.allowed), first.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, strconv.Itoa(tt.allowed-1), first.Header().Get("RateLimit-Remaining"))
+			assert.Empty(t, first.Header().Get("Retry-After"))
+
+			assert.Equal(t, http.StatusOK, recorders[tt.allowed-1].Code)
+			assert.Equal(t, "0", recorders[tt.allowed-1].Header().Get("RateLimit-Remaining"))
+
+			assert.Equal(t, http.StatusTooManyRequests, last.Code)
+			assert.Equal(t, `{"error":"too many requests"}`, last.Body.String())
+			assert.Equal(t, "0", last.Header().Get("RateLimit-Remaining"))
+			assert.NotEmpty(t, last.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestRateLimiter_RetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(Limit{PerMinute: 2, Burst: 1}, 0, 10*time.Minute)
+	limiter.now = func() time.Time { return now }
+	handler := limiter.Handler()
+
+	request := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+		handler(ctx)
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, request().Code)
+
+	limited := request()
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "30", limited.Header().Get("Retry-After"))
+
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, "20", request().Header().Get("Retry-After"))
+
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, http.StatusOK, request().Code)
+}
+
+func TestRateLimiter_TierChange(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(Limit{PerMinute: 6, Burst: 3}, 0, 10*time.Minute)
+	limiter.now = func() time.Time { return now }
+	handler := limiter.Handler()
+
+	request := func(tier string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+		ctx.Set(PrincipalKey, auth.Principal{Subject: "partner-team", KeyID: "partner-key", Tier: tier})
+		handler(ctx)
+		return recorder
+	}
+
+	for range 60 {
+		assert.Equal(t, http.StatusOK, request("free").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, request("free").Code)
+
+	upgraded := request("partner")
+	assert.Equal(t, http.StatusOK, upgraded.Code)
+	assert.Equal(t, "1200", upgraded.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1199", upgraded.Header().Get("RateLimit-Remaining"))
+	assert.Len(t, limiter.visitors, 1)
+}
+
+func TestRateLimiter_Evict(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(Limit{PerMinute: 5, Burst: 10}, 0, time.Minute)
+	limiter.now = func() time.Time { return now }
+	handler := limiter.Handler()
+
+	request := func(remoteAddr string) {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+		ctx.Request.RemoteAddr = remoteAddr
+		handler(ctx)
+	}
+
+	request("203.0.113.1:1234")
+	now = now.Add(30 * time.Second)
+	request("203.0.113.2:1234")
+	assert.Len(t, limiter.visitors, 2)
+
+	now = now.Add(45 * time.Second)
+	request("203.0.113.2:1234")
+	assert.Len(t, limiter.visitors, 1)
+	assert.Contains(t, limiter.visitors, "ip:203.0.113.2")
+}
+
+func TestRateLimiter_ClientIP(t *testing.T) {
+	tests := []struct {
+		name        string
+		trustedHops int
+		request     func(*testing.T) *http.Request
+		want        string
+	}{
+		{
+			name: "when request comes through api gateway",
+			request: func(t *testing.T) *http.Request {
+				event := events.APIGatewayProxyRequest{
+					HTTPMethod:     http.MethodGet,
+					Path:           "/books",
+					Headers:        map[string]string{"X-Forwarded-For": "198.51.100.9"},
+					RequestContext: events.APIGatewayProxyRequestContext{Identity: events.APIGatewayRequestIdentity{SourceIP: "203.0.113.7"}},
+				}
+				req, err := (&core.RequestAccessor{}).EventToRequestWithContext(context.Background(), event)
+				require.NoError(t, err)
+				return req
+			},
+			want: "203.0.113.7",
+		},
+		{
+			name:        "when forwarded hops are trusted",
+			trustedHops: 2,
+			request: func(*testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/books", nil)
+				req.Header.Add("X-Forwarded-For", "198.51.100.9, 203.0.113.7")
+				req.Header.Add("X-Forwarded-For", "10.0.0.1")
+				return req
+			},
+			want: "203.0.113.7",
+		},
+		{
+			name:        "when there are fewer forwarded hops than trusted",
+			trustedHops: 2,
+			request: func(*testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/books", nil)
+				req.Header.Set("X-Forwarded-For", "198.51.100.9")
+				req.RemoteAddr = "10.0.0.1:4321"
+				return req
+			},
+			want: "10.0.0.1",
+		},
+		{
+			name: "when forwarded hops are not trusted",
+			request: func(*testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/books", nil)
+				req.Header.Set("X-Forwarded-For", "198.51.100.9")
+				req.RemoteAddr = "10.0.0.1:4321"
+				return req
+			},
+			want: "10.0.0.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(Limit{PerMinute: 5, Burst: 10}, tt.trustedHops, time.Minute)
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = tt.request(t)
+
+			assert.Equal(t, tt.want, limiter.clientIP(ctx))
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package relations
+
+type Link struct {
+	Owner   string
+	OwnerID string
+	BookID  string
+	Order   int
+}
+
+type Cast struct {
+	AdaptationID string
+	CharacterID  string
+	ActorID      string
+}
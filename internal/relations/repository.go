@@ -0,0 +1,325 @@
+package relations
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+)
+
+type DynamoDBClient interface {
+	WriteItems(ctx context.Context, tableName string, puts []map[string]types.AttributeValue, deleteIDs []string, writes ...dynamo.Write) error
+	Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error)
+	GetReferences(ctx context.Context, tableName string, ids []string) (map[string][]string, error)
+}
+
+const OwnerIndex = "owner-index"
+const BookIndex = "book-index"
+const ActorIndex = "actor-index"
+const castPrefix = "cast"
+
+type Repository struct {
+	dynamoDBClient DynamoDBClient
+	tableName      string
+	booksTableName string
+}
+
+func NewRepository(dynamoDBClient DynamoDBClient, tableName string, booksTableName string) *Repository {
+	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName, booksTableName: booksTableName}
+}
+
+func (r *Repository) Replace(ctx context.Context, owner string, ownerID string, links []Link) error {
+	current, err := r.query(ctx, dynamo.Query{
+		IndexName: OwnerIndex,
+		HashKey:   "owner",
+		HashValue: &types.AttributeValueMemberS{Value: ownerKey(owner, ownerID)},
+	})
+	if err != nil {
+		return err
+	}
+
+	var references, puts, deletes, removed []dynamo.Write
+	var keepIDs []string
+	for _, link := range uniqueLinks(links) {
+		link.Owner = owner
+		link.OwnerID = ownerID
+
+		dbLink := NewDBLink(link)
+		item, err := attributevalue.MarshalMap(dbLink)
+		if err != nil {
+			return fmt.Errorf("failed to marshal link: %w", err)
+		}
+
+		keepIDs = append(keepIDs, dbLink.ID)
+		references = append(references, dynamo.AddReferencesWrite(r.booksTableName, dbLink.BookID, dbLink.ID))
+		puts = append(puts, dynamo.PutWrite(r.tableName, dbLink.ID, item))
+	}
+
+	for _, dbLink := range current {
+		if !slices.Contains(keepIDs, dbLink.ID) {
+			deletes = append(deletes, dynamo.DeleteWrite(r.tableName, dbLink.ID))
+			removed = append(removed, dynamo.RemoveReferencesWrite(r.booksTableName, dbLink.BookID, dbLink.ID))
+		}
+	}
+
+	return r.Apply(ctx, slices.Concat(references, puts, deletes, removed))
+}
+
+func (r *Repository) Writes(owner string, ownerID string, current []Link, links []Link) ([]dynamo.Write, error) {
+	currentLinks := uniqueLinks(current)
+	newLinks := uniqueLinks(links)
+
+	var writes []dynamo.Write
+	for _, link := range newLinks {
+		link.Owner = owner
+		link.OwnerID = ownerID
+		if slices.ContainsFunc(currentLinks, func(l Link) bool { return l.BookID == link.BookID && l.Order == link.Order }) {
+			continue
+		}
+
+		dbLink := NewDBLink(link)
+		item, err := attributevalue.MarshalMap(dbLink)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal link: %w", err)
+		}
+
+		writes = append(writes, dynamo.PutWrite(r.tableName, dbLink.ID, item))
+		if !slices.ContainsFunc(currentLinks, func(l Link) bool { return l.BookID == link.BookID }) {
+			writes = append(writes, dynamo.AddReferencesWrite(r.booksTableName, link.BookID, dbLink.ID))
+		}
+	}
+
+	for _, link := range currentLinks {
+		if slices.ContainsFunc(newLinks, func(l Link) bool { return l.BookID == link.BookID }) {
+			continue
+		}
+
+		link.Owner = owner
+		link.OwnerID = ownerID
+		dbLink := NewDBLink(link)
+		writes = append(writes, dynamo.DeleteWrite(r.tableName, dbLink.ID), dynamo.RemoveReferencesWrite(r.booksTableName, link.BookID, dbLink.ID))
+	}
+
+	return writes, nil
+}
+
+func (r *Repository) GetByBook(ctx context.Context, owner string, bookID string) ([]Link, error) {
+	dbLinks, err := r.query(ctx, dynamo.Query{
+		IndexName: BookIndex,
+		HashKey:   "book_id",
+		HashValue: &types.AttributeValueMemberS{Value: bookID},
+		RangeKey:  "owner_type",
+		From:      &types.AttributeValueMemberS{Value: owner},
+		To:        &types.AttributeValueMemberS{Value: owner},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var links []Link
+	for _, dbLink := range dbLinks {
+		links = append(links, dbLink.ToLink())
+	}
+
+	return links, nil
+}
+
+func (r *Repository) GetByBooks(ctx context.Context, owner string, bookIDs []string) (map[string][]Link, error) {
+	references, err := r.dynamoDBClient.GetReferences(ctx, r.booksTableName, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	linksByBook := map[string][]Link{}
+	for bookID, refs := range references {
+		for _, ref := range refs {
+			ownerID, ok := strings.CutPrefix(ref, owner+"#")
+			if !ok {
+				continue
+			}
+
+			ownerID, ok = strings.CutSuffix(ownerID, "#"+bookID)
+			if ok {
+				linksByBook[bookID] = append(linksByBook[bookID], Link{Owner: owner, OwnerID: ownerID, BookID: bookID})
+			}
+		}
+	}
+
+	return linksByBook, nil
+}
+
+func (r *Repository) CastWrites(current []Cast, cast []Cast) ([]dynamo.Write, error) {
+	var writes []dynamo.Write
+	for _, member := range cast {
+		if slices.Contains(current, member) {
+			continue
+		}
+
+		dbCast := NewDBCast(member)
+		item, err := attributevalue.MarshalMap(dbCast)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal cast: %w", err)
+		}
+
+		writes = append(writes, dynamo.PutWrite(r.tableName, dbCast.ID, item), dynamo.AddReferencesWrite(r.tableName, member.CharacterID, dbCast.ID))
+	}
+
+	for _, member := range current {
+		if !slices.Contains(cast, member) {
+			castID := NewDBCast(member).ID
+			writes = append(writes, dynamo.DeleteWrite(r.tableName, castID), dynamo.RemoveReferencesWrite(r.tableName, member.CharacterID, castID))
+		}
+	}
+
+	return writes, nil
+}
+
+func (r *Repository) PutCast(ctx context.Context, cast []Cast) error {
+	var references, puts []dynamo.Write
+	for _, member := range cast {
+		dbCast := NewDBCast(member)
+		item, err := attributevalue.MarshalMap(dbCast)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cast: %w", err)
+		}
+
+		references = append(references, dynamo.AddReferencesWrite(r.tableName, member.CharacterID, dbCast.ID))
+		puts = append(puts, dynamo.PutWrite(r.tableName, dbCast.ID, item))
+	}
+
+	return r.Apply(ctx, slices.Concat(references, puts))
+}
+
+func (r *Repository) GetCastByCharacters(ctx context.Context, characterIDs []string) (map[string][]Cast, error) {
+	references, err := r.dynamoDBClient.GetReferences(ctx, r.tableName, characterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	castByCharacter := map[string][]Cast{}
+	for characterID, refs := range references {
+		for _, ref := range refs {
+			parts := strings.Split(ref, "#")
+			if len(parts) == 4 && parts[0] == castPrefix && parts[2] == characterID {
+				castByCharacter[characterID] = append(castByCharacter[characterID], Cast{AdaptationID: parts[1], CharacterID: characterID, ActorID: parts[3]})
+			}
+		}
+	}
+
+	return castByCharacter, nil
+}
+
+func (r *Repository) GetCastByActor(ctx context.Context, actorID string) ([]Cast, error) {
+	return r.queryCast(ctx, dynamo.Query{
+		IndexName: ActorIndex,
+		HashKey:   "actor_id",
+		HashValue: &types.AttributeValueMemberS{Value: actorID},
+	})
+}
+
+func (r *Repository) queryCast(ctx context.Context, query dynamo.Query) ([]Cast, error) {
+	items, err := r.dynamoDBClient.Query(ctx, r.tableName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbCast []DBCast
+	err = attributevalue.UnmarshalListOfMaps(items, &dbCast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cast: %w", err)
+	}
+
+	var cast []Cast
+	for _, member := range dbCast {
+		cast = append(cast, member.ToCast())
+	}
+
+	return cast, nil
+}
+
+func (r *Repository) query(ctx context.Context, query dynamo.Query) ([]DBLink, error) {
+	items, err := r.dynamoDBClient.Query(ctx, r.tableName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbLinks []DBLink
+	err = attributevalue.UnmarshalListOfMaps(items, &dbLinks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal links: %w", err)
+	}
+
+	return dbLinks, nil
+}
+
+type DBLink struct {
+	ID        string `dynamodbav:"id"`
+	Owner     string `dynamodbav:"owner"`
+	OwnerType string `dynamodbav:"owner_type"`
+	OwnerID   string `dynamodbav:"owner_id"`
+	BookID    string `dynamodbav:"book_id"`
+	Order     int    `dynamodbav:"order,omitempty"`
+}
+
+func NewDBLink(link Link) DBLink {
+	return DBLink{
+		ID:        ownerKey(link.Owner, link.OwnerID) + "#" + link.BookID,
+		Owner:     ownerKey(link.Owner, link.OwnerID),
+		OwnerType: link.Owner,
+		OwnerID:   link.OwnerID,
+		BookID:    link.BookID,
+		Order:     link.Order,
+	}
+}
+
+func (d *DBLink) ToLink() Link {
+	return Link{
+		Owner:   d.OwnerType,
+		OwnerID: d.OwnerID,
+		BookID:  d.BookID,
+		Order:   d.Order,
+	}
+}
+
+type DBCast struct {
+	ID           string `dynamodbav:"id"`
+	AdaptationID string `dynamodbav:"adaptation_id"`
+	CharacterID  string `dynamodbav:"character_id"`
+	ActorID      string `dynamodbav:"actor_id"`
+}
+
+func NewDBCast(cast Cast) DBCast {
+	return DBCast{
+		ID:           castPrefix + "#" + cast.AdaptationID + "#" + cast.CharacterID + "#" + cast.ActorID,
+		AdaptationID: cast.AdaptationID,
+		CharacterID:  cast.CharacterID,
+		ActorID:      cast.ActorID,
+	}
+}
+
+func (d *DBCast) ToCast() Cast {
+	return Cast{
+		AdaptationID: d.AdaptationID,
+		CharacterID:  d.CharacterID,
+		ActorID:      d.ActorID,
+	}
+}
+
+func uniqueLinks(links []Link) []Link {
+	var unique []Link
+	for _, link := range links {
+		if !slices.ContainsFunc(unique, func(l Link) bool { return l.BookID == link.BookID }) {
+			unique = append(unique, link)
+		}
+	}
+
+	return unique
+}
+
+func ownerKey(owner string, ownerID string) string {
+	return owner + "#" + ownerID
+}
@@ -0,0 +1,453 @@
+package relations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func linkItem(id string, ownerID string, bookID string, order int) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: id},
+		"owner":      &types.AttributeValueMemberS{Value: "series#" + ownerID},
+		"owner_type": &types.AttributeValueMemberS{Value: "series"},
+		"owner_id":   &types.AttributeValueMemberS{Value: ownerID},
+		"book_id":    &types.AttributeValueMemberS{Value: bookID},
+	}
+	if order > 0 {
+		item["order"] = &types.AttributeValueMemberN{Value: fmt.Sprint(order)}
+	}
+
+	return item
+}
+
+func TestRepository_Replace(t *testing.T) {
+	ctx := context.Background()
+	ownerQuery := dynamo.Query{IndexName: OwnerIndex, HashKey: "owner", HashValue: &types.AttributeValueMemberS{Value: "series#series-id"}}
+	tests := []struct {
+		name    string
+		links   []Link
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name:  "when failed to query current links",
+			links: []Link{{BookID: "book-1", Order: 1}},
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", ownerQuery).Return([]map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when failed to unmarshal current links",
+			links: []Link{{BookID: "book-1", Order: 1}},
+			setup: func(m *MockDynamoDBClient) {
+				output := []map[string]types.AttributeValue{{"id": &types.AttributeValueMemberM{}}}
+				m.On("Query", ctx, "table-name", ownerQuery).Return(output, nil).Once()
+			},
+			wantErr: fmt.Errorf("failed to unmarshal links: %w", &attributevalue.UnmarshalTypeError{Value: "map", Type: reflect.TypeOf("string")}),
+		},
+		{
+			name:  "when successfully replaced links",
+			links: []Link{{BookID: "book-1", Order: 1}, {BookID: "book-2", Order: 2}},
+			setup: func(m *MockDynamoDBClient) {
+				current := []map[string]types.AttributeValue{linkItem("series#series-id#book-1", "series-id", "book-1", 3), linkItem("series#series-id#book-3", "series-id", "book-3", 1)}
+				m.On("Query", ctx, "table-name", ownerQuery).Return(current, nil).Once()
+				writes := []dynamo.Write{
+					dynamo.AddReferencesWrite("books", "book-1", "series#series-id#book-1"),
+					dynamo.AddReferencesWrite("books", "book-2", "series#series-id#book-2"),
+					dynamo.PutWrite("table-name", "series#series-id#book-1", linkItem("series#series-id#book-1", "series-id", "book-1", 1)),
+					dynamo.PutWrite("table-name", "series#series-id#book-2", linkItem("series#series-id#book-2", "series-id", "book-2", 2)),
+					dynamo.DeleteWrite("table-name", "series#series-id#book-3"),
+					dynamo.RemoveReferencesWrite("books", "book-3", "series#series-id#book-3"),
+				}
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue(nil), []string(nil), writes).Return(nil).Once()
+			},
+		},
+		{
+			name:  "when there is nothing to write",
+			links: nil,
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", ownerQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", "books")
+			err := r.Replace(ctx, "series", "series-id", tt.links)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Writes(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []Link
+		links   []Link
+		want    []dynamo.Write
+	}{
+		{
+			name:  "when owner is created",
+			links: []Link{{BookID: "book-1", Order: 1}, {BookID: "book-2", Order: 2}},
+			want: []dynamo.Write{
+				dynamo.PutWrite("table-name", "series#series-id#book-1", linkItem("series#series-id#book-1", "series-id", "book-1", 1)),
+				dynamo.AddReferencesWrite("books", "book-1", "series#series-id#book-1"),
+				dynamo.PutWrite("table-name", "series#series-id#book-2", linkItem("series#series-id#book-2", "series-id", "book-2", 2)),
+				dynamo.AddReferencesWrite("books", "book-2", "series#series-id#book-2"),
+			},
+		},
+		{
+			name:  "when the same book is linked twice",
+			links: []Link{{BookID: "book-1", Order: 1}, {BookID: "book-1", Order: 2}},
+			want: []dynamo.Write{
+				dynamo.PutWrite("table-name", "series#series-id#book-1", linkItem("series#series-id#book-1", "series-id", "book-1", 1)),
+				dynamo.AddReferencesWrite("books", "book-1", "series#series-id#book-1"),
+			},
+		},
+		{
+			name:    "when links change",
+			current: []Link{{BookID: "book-1", Order: 1}, {BookID: "book-2", Order: 2}, {BookID: "book-3", Order: 3}},
+			links:   []Link{{BookID: "book-1", Order: 1}, {BookID: "book-2", Order: 3}, {BookID: "book-4", Order: 4}},
+			want: []dynamo.Write{
+				dynamo.PutWrite("table-name", "series#series-id#book-2", linkItem("series#series-id#book-2", "series-id", "book-2", 3)),
+				dynamo.PutWrite("table-name", "series#series-id#book-4", linkItem("series#series-id#book-4", "series-id", "book-4", 4)),
+				dynamo.AddReferencesWrite("books", "book-4", "series#series-id#book-4"),
+				dynamo.DeleteWrite("table-name", "series#series-id#book-3"),
+				dynamo.RemoveReferencesWrite("books", "book-3", "series#series-id#book-3"),
+			},
+		},
+		{
+			name:    "when owner is deleted",
+			current: []Link{{BookID: "book-1", Order: 1}},
+			want:    []dynamo.Write{dynamo.DeleteWrite("table-name", "series#series-id#book-1"), dynamo.RemoveReferencesWrite("books", "book-1", "series#series-id#book-1")},
+		},
+		{
+			name:    "when nothing changes",
+			current: []Link{{BookID: "book-1", Order: 1}},
+			links:   []Link{{BookID: "book-1", Order: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(new(MockDynamoDBClient), "table-name", "books")
+
+			got, err := r.Writes("series", "series-id", tt.current, tt.links)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRepository_GetByBook(t *testing.T) {
+	ctx := context.Background()
+	bookQuery := dynamo.Query{
+		IndexName: BookIndex,
+		HashKey:   "book_id",
+		HashValue: &types.AttributeValueMemberS{Value: "book-1"},
+		RangeKey:  "owner_type",
+		From:      &types.AttributeValueMemberS{Value: "series"},
+		To:        &types.AttributeValueMemberS{Value: "series"},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    []Link
+		wantErr error
+	}{
+		{
+			name: "when failed to query links",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", bookQuery).Return([]map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get links by book",
+			setup: func(m *MockDynamoDBClient) {
+				output := []map[string]types.AttributeValue{linkItem("series#series-1#book-1", "series-1", "book-1", 1), linkItem("series#series-2#book-1", "series-2", "book-1", 4)}
+				m.On("Query", ctx, "table-name", bookQuery).Return(output, nil).Once()
+			},
+			want: []Link{{Owner: "series", OwnerID: "series-1", BookID: "book-1", Order: 1}, {Owner: "series", OwnerID: "series-2", BookID: "book-1", Order: 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", "books")
+			got, err := r.GetByBook(ctx, "series", "book-1")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func castItem(adaptationID string, characterID string, actorID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id":            &types.AttributeValueMemberS{Value: "cast#" + adaptationID + "#" + characterID + "#" + actorID},
+		"adaptation_id": &types.AttributeValueMemberS{Value: adaptationID},
+		"character_id":  &types.AttributeValueMemberS{Value: characterID},
+		"actor_id":      &types.AttributeValueMemberS{Value: actorID},
+	}
+}
+
+func TestRepository_CastWrites(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []Cast
+		cast    []Cast
+		want    []dynamo.Write
+	}{
+		{
+			name: "when cast is added",
+			cast: []Cast{{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-1"}},
+			want: []dynamo.Write{
+				dynamo.PutWrite("table-name", "cast#adaptation-id#character-1#actor-1", castItem("adaptation-id", "character-1", "actor-1")),
+				dynamo.AddReferencesWrite("table-name", "character-1", "cast#adaptation-id#character-1#actor-1"),
+			},
+		},
+		{
+			name:    "when cast changes",
+			current: []Cast{{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-1"}, {AdaptationID: "adaptation-id", CharacterID: "character-2", ActorID: "actor-2"}},
+			cast:    []Cast{{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-1"}, {AdaptationID: "adaptation-id", CharacterID: "character-3", ActorID: "actor-3"}},
+			want: []dynamo.Write{
+				dynamo.PutWrite("table-name", "cast#adaptation-id#character-3#actor-3", castItem("adaptation-id", "character-3", "actor-3")),
+				dynamo.AddReferencesWrite("table-name", "character-3", "cast#adaptation-id#character-3#actor-3"),
+				dynamo.DeleteWrite("table-name", "cast#adaptation-id#character-2#actor-2"),
+				dynamo.RemoveReferencesWrite("table-name", "character-2", "cast#adaptation-id#character-2#actor-2"),
+			},
+		},
+		{
+			name:    "when cast is unchanged",
+			current: []Cast{{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-1"}},
+			cast:    []Cast{{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(new(MockDynamoDBClient), "table-name", "books")
+
+			got, err := r.CastWrites(tt.current, tt.cast)
+
+			assert.Equal(t, tt.want, got)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestRepository_PutCast(t *testing.T) {
+	ctx := context.Background()
+	castWrites := []dynamo.Write{
+		dynamo.AddReferencesWrite("table-name", "character-1", "cast#adaptation-id#character-1#actor-1"),
+		dynamo.PutWrite("table-name", "cast#adaptation-id#character-1#actor-1", castItem("adaptation-id", "character-1", "actor-1")),
+	}
+	tests := []struct {
+		name    string
+		cast    []Cast
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name:  "when there is no cast",
+			setup: func(m *MockDynamoDBClient) {},
+		},
+		{
+			name: "when failed to write cast",
+			cast: []Cast{{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-1"}},
+			setup: func(m *MockDynamoDBClient) {
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue(nil), []string(nil), castWrites).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully wrote cast",
+			cast: []Cast{{AdaptationID: "adaptation-id", CharacterID: "character-1", ActorID: "actor-1"}},
+			setup: func(m *MockDynamoDBClient) {
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue(nil), []string(nil), castWrites).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", "books")
+			err := r.PutCast(ctx, tt.cast)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByBooks(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    map[string][]Link
+		wantErr error
+	}{
+		{
+			name: "when failed to get book references",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetReferences", ctx, "books", []string{"book-1", "book-2"}).Return(map[string][]string(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get links by books",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetReferences", ctx, "books", []string{"book-1", "book-2"}).Return(map[string][]string{
+					"book-1": {"adaptation#adaptation-1#book-1", "relationship#edge-1", "series#series-1#book-1"},
+					"book-2": {"adaptation#adaptation-1#book-2", "adaptation#adaptation-2#book-2"},
+				}, nil).Once()
+			},
+			want: map[string][]Link{
+				"book-1": {{Owner: "adaptation", OwnerID: "adaptation-1", BookID: "book-1"}},
+				"book-2": {{Owner: "adaptation", OwnerID: "adaptation-1", BookID: "book-2"}, {Owner: "adaptation", OwnerID: "adaptation-2", BookID: "book-2"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", "books")
+			got, err := r.GetByBooks(ctx, "adaptation", []string{"book-1", "book-2"})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetCastByCharacters(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    map[string][]Cast
+		wantErr error
+	}{
+		{
+			name: "when failed to get character references",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetReferences", ctx, "table-name", []string{"character-1", "character-2"}).Return(map[string][]string(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully got character cast",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetReferences", ctx, "table-name", []string{"character-1", "character-2"}).Return(map[string][]string{
+					"character-1": {"cast#adaptation-1#character-1#actor-1", "cast#adaptation-2#character-1#actor-2", "cast#adaptation-3#character-2#actor-3"},
+				}, nil).Once()
+			},
+			want: map[string][]Cast{
+				"character-1": {{AdaptationID: "adaptation-1", CharacterID: "character-1", ActorID: "actor-1"}, {AdaptationID: "adaptation-2", CharacterID: "character-1", ActorID: "actor-2"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", "books")
+			got, err := r.GetCastByCharacters(ctx, []string{"character-1", "character-2"})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetCastByActor(t *testing.T) {
+	ctx := context.Background()
+	actorQuery := dynamo.Query{IndexName: ActorIndex, HashKey: "actor_id", HashValue: &types.AttributeValueMemberS{Value: "actor-1"}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    []Cast
+		wantErr error
+	}{
+		{
+			name: "when failed to query actor cast",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", actorQuery).Return([]map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to unmarshal cast",
+			setup: func(m *MockDynamoDBClient) {
+				output := []map[string]types.AttributeValue{{"id": &types.AttributeValueMemberM{}}}
+				m.On("Query", ctx, "table-name", actorQuery).Return(output, nil).Once()
+			},
+			wantErr: fmt.Errorf("failed to unmarshal cast: %w", &attributevalue.UnmarshalTypeError{Value: "map", Type: reflect.TypeOf("string")}),
+		},
+		{
+			name: "when successfully got actor cast",
+			setup: func(m *MockDynamoDBClient) {
+				output := []map[string]types.AttributeValue{castItem("adaptation-1", "character-1", "actor-1"), castItem("adaptation-2", "character-2", "actor-1")}
+				m.On("Query", ctx, "table-name", actorQuery).Return(output, nil).Once()
+			},
+			want: []Cast{{AdaptationID: "adaptation-1", CharacterID: "character-1", ActorID: "actor-1"}, {AdaptationID: "adaptation-2", CharacterID: "character-2", ActorID: "actor-1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", "books")
+			got, err := r.GetCastByActor(ctx, "actor-1")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+type MockDynamoDBClient struct {
+	mock.Mock
+}
+
+func (m *MockDynamoDBClient) WriteItems(ctx context.Context, tableName string, puts []map[string]types.AttributeValue, deleteIDs []string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, puts, deleteIDs, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, query)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetReferences(ctx context.Context, tableName string, ids []string) (map[string][]string, error) {
+	args := m.Called(ctx, tableName, ids)
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
@@ -0,0 +1,95 @@
+package relations
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+)
+
+type Applier interface {
+	Apply(ctx context.Context, writes []dynamo.Write) error
+}
+
+func (r *Repository) Apply(ctx context.Context, writes []dynamo.Write) error {
+	for chunk := range slices.Chunk(writes, dynamo.TransactWriteLimit) {
+		err := r.dynamoDBClient.WriteItems(ctx, r.tableName, nil, nil, chunk...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WriteOwner(ctx context.Context, links Applier, writes []dynamo.Write, write func(writes []dynamo.Write) error) error {
+	if !conflictingReferences(writes) {
+		err := write(writes)
+		if !errors.Is(err, dynamo.ErrTooManyWrites) {
+			return err
+		}
+	}
+
+	before, after := splitWrites(writes)
+	err := links.Apply(ctx, before)
+	if err == nil {
+		err = write(nil)
+	}
+	if err != nil {
+		undoErr := links.Apply(ctx, undoWrites(before))
+		if undoErr != nil {
+			return errors.Join(err, undoErr)
+		}
+
+		return err
+	}
+
+	return links.Apply(ctx, after)
+}
+
+func conflictingReferences(writes []dynamo.Write) bool {
+	for _, added := range writes {
+		if added.IsReferencesAdd() && slices.ContainsFunc(writes, func(removed dynamo.Write) bool { return removed.IsReferencesRemove() && removed.ID == added.ID }) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitWrites(writes []dynamo.Write) ([]dynamo.Write, []dynamo.Write) {
+	var added []string
+	for _, write := range writes {
+		if write.IsReferencesAdd() {
+			added = append(added, write.Refs...)
+		}
+	}
+
+	var references, puts, changes, removed []dynamo.Write
+	for _, write := range writes {
+		switch {
+		case write.IsReferencesAdd():
+			references = append(references, write)
+		case write.IsPut() && slices.Contains(added, write.ID):
+			puts = append(puts, write)
+		case write.IsReferencesRemove():
+			removed = append(removed, write)
+		default:
+			changes = append(changes, write)
+		}
+	}
+
+	return append(references, puts...), append(changes, removed...)
+}
+
+func undoWrites(writes []dynamo.Write) []dynamo.Write {
+	var undo []dynamo.Write
+	for _, write := range slices.Backward(writes) {
+		if undoWrite, ok := write.Undo(); ok {
+			undo = append(undo, undoWrite)
+		}
+	}
+
+	return undo
+}
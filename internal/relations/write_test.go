@@ -0,0 +1,179 @@
+package relations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRepository_Apply(t *testing.T) {
+	ctx := context.Background()
+	var writes []dynamo.Write
+	for i := range dynamo.TransactWriteLimit + 1 {
+		writes = append(writes, dynamo.DeleteWrite("table-name", fmt.Sprintf("series#series-id#book-%d", i)))
+	}
+	tests := []struct {
+		name    string
+		writes  []dynamo.Write
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name:  "when there is nothing to write",
+			setup: func(m *MockDynamoDBClient) {},
+		},
+		{
+			name:   "when failed to write a chunk",
+			writes: writes,
+			setup: func(m *MockDynamoDBClient) {
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue(nil), []string(nil), writes[:dynamo.TransactWriteLimit]).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when writes are split into transaction sized chunks",
+			writes: writes,
+			setup: func(m *MockDynamoDBClient) {
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue(nil), []string(nil), writes[:dynamo.TransactWriteLimit]).Return(nil).Once()
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue(nil), []string(nil), writes[dynamo.TransactWriteLimit:]).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "table-name", "books")
+			err := r.Apply(ctx, tt.writes)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWriteOwner(t *testing.T) {
+	ctx := context.Background()
+	newLink := dynamo.PutWrite("table-name", "series#series-id#book-2", linkItem("series#series-id#book-2", "series-id", "book-2", 2))
+	newReference := dynamo.AddReferencesWrite("books", "book-2", "series#series-id#book-2")
+	movedLink := dynamo.PutWrite("table-name", "series#series-id#book-1", linkItem("series#series-id#book-1", "series-id", "book-1", 3))
+	oldLink := dynamo.DeleteWrite("table-name", "series#series-id#book-3")
+	oldReference := dynamo.RemoveReferencesWrite("books", "book-3", "series#series-id#book-3")
+	writes := []dynamo.Write{movedLink, newLink, newReference, oldLink, oldReference}
+	before := []dynamo.Write{newReference, newLink}
+	after := []dynamo.Write{movedLink, oldLink, oldReference}
+	swappedCast := []dynamo.Write{
+		dynamo.DeleteWrite("table-name", "cast#adaptation-id#character-1#actor-1"),
+		dynamo.RemoveReferencesWrite("table-name", "character-1", "cast#adaptation-id#character-1#actor-1"),
+		dynamo.PutWrite("table-name", "cast#adaptation-id#character-1#actor-2", castItem("adaptation-id", "character-1", "actor-2")),
+		dynamo.AddReferencesWrite("table-name", "character-1", "cast#adaptation-id#character-1#actor-2"),
+	}
+	undo := []dynamo.Write{dynamo.DeleteWrite("table-name", "series#series-id#book-2"), dynamo.RemoveReferencesWrite("books", "book-2", "series#series-id#book-2")}
+	tests := []struct {
+		name       string
+		writes     []dynamo.Write
+		ownerErrs  []error
+		setup      func(*MockApplier)
+		wantWrites [][]dynamo.Write
+		wantErr    error
+	}{
+		{
+			name:       "when owner is written in one transaction",
+			ownerErrs:  []error{nil},
+			setup:      func(m *MockApplier) {},
+			wantWrites: [][]dynamo.Write{writes},
+		},
+		{
+			name:       "when owner write fails",
+			ownerErrs:  []error{assert.AnError},
+			setup:      func(m *MockApplier) {},
+			wantWrites: [][]dynamo.Write{writes},
+			wantErr:    assert.AnError,
+		},
+		{
+			name:      "when failed to write new links",
+			ownerErrs: []error{dynamo.ErrTooManyWrites},
+			setup: func(m *MockApplier) {
+				m.On("Apply", ctx, before).Return(assert.AnError).Once()
+				m.On("Apply", ctx, undo).Return(nil).Once()
+			},
+			wantWrites: [][]dynamo.Write{writes},
+			wantErr:    assert.AnError,
+		},
+		{
+			name:      "when owner write fails after new links are written",
+			ownerErrs: []error{dynamo.ErrTooManyWrites, dynamo.ErrVersionMismatch},
+			setup: func(m *MockApplier) {
+				m.On("Apply", ctx, before).Return(nil).Once()
+				m.On("Apply", ctx, undo).Return(nil).Once()
+			},
+			wantWrites: [][]dynamo.Write{writes, nil},
+			wantErr:    dynamo.ErrVersionMismatch,
+		},
+		{
+			name:      "when failed to undo new links",
+			ownerErrs: []error{dynamo.ErrTooManyWrites, dynamo.ErrVersionMismatch},
+			setup: func(m *MockApplier) {
+				m.On("Apply", ctx, before).Return(nil).Once()
+				m.On("Apply", ctx, undo).Return(assert.AnError).Once()
+			},
+			wantWrites: [][]dynamo.Write{writes, nil},
+			wantErr:    errors.Join(dynamo.ErrVersionMismatch, assert.AnError),
+		},
+		{
+			name:      "when links are written around the owner",
+			ownerErrs: []error{dynamo.ErrTooManyWrites, nil},
+			setup: func(m *MockApplier) {
+				m.On("Apply", ctx, before).Return(nil).Once()
+				m.On("Apply", ctx, after).Return(nil).Once()
+			},
+			wantWrites: [][]dynamo.Write{writes, nil},
+		},
+		{
+			name:      "when references are added and removed on one item",
+			writes:    swappedCast,
+			ownerErrs: []error{nil},
+			setup: func(m *MockApplier) {
+				m.On("Apply", ctx, []dynamo.Write{swappedCast[3], swappedCast[2]}).Return(nil).Once()
+				m.On("Apply", ctx, []dynamo.Write{swappedCast[0], swappedCast[1]}).Return(nil).Once()
+			},
+			wantWrites: [][]dynamo.Write{nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockApplier := new(MockApplier)
+			tt.setup(mockApplier)
+
+			if tt.writes == nil {
+				tt.writes = writes
+			}
+
+			var gotWrites [][]dynamo.Write
+			err := WriteOwner(ctx, mockApplier, tt.writes, func(writes []dynamo.Write) error {
+				gotWrites = append(gotWrites, writes)
+				return tt.ownerErrs[len(gotWrites)-1]
+			})
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantWrites, gotWrites)
+			mockApplier.AssertExpectations(t)
+		})
+	}
+}
+
+type MockApplier struct {
+	mock.Mock
+}
+
+func (m *MockApplier) Apply(ctx context.Context, writes []dynamo.Write) error {
+	args := m.Called(ctx, writes)
+	return args.Error(0)
+}
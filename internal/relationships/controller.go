@@ -0,0 +1,156 @@
+package relationships
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/gin-gonic/gin"
+)
+
+type Manager interface {
+	Create(ctx context.Context, from string, to string, relationship Relationship) (Relationship, bool, error)
+	Graph(ctx context.Context, character string, depth int) (Graph, error)
+}
+
+const defaultDepth = 1
+
+type Controller struct {
+	manager Manager
+}
+
+func NewController(manager Manager) *Controller {
+	return &Controller{manager: manager}
+}
+
+func (c *Controller) Create(ctx *gin.Context) {
+	var characterRequest CharacterRequest
+	if err := ctx.BindUri(&characterRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var relationshipDTO RelationshipDTO
+	if err := ctx.BindJSON(&relationshipDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	createdRelationship, created, err := c.manager.Create(ctx, characterRequest.Character, relationshipDTO.To, relationshipDTO.ToRelationship())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	ctx.JSON(status, NewEdgeDTO(createdRelationship))
+}
+
+func (c *Controller) Graph(ctx *gin.Context) {
+	var characterRequest CharacterRequest
+	if err := ctx.BindUri(&characterRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var graphRequest GraphRequest
+	if err := ctx.BindQuery(&graphRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	depth := graphRequest.Depth
+	if depth == 0 {
+		depth = defaultDepth
+	}
+
+	graph, err := c.manager.Graph(ctx, characterRequest.Character, depth)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewGraphDTO(graph))
+}
+
+type RelationshipDTO struct {
+	To        string `json:"to" binding:"required"`
+	Type      string `json:"type" binding:"required"`
+	StartBook string `json:"startBook"`
+	EndBook   string `json:"endBook"`
+}
+
+func (r *RelationshipDTO) ToRelationship() Relationship {
+	return Relationship{
+		Type:      r.Type,
+		StartBook: books.Book{Title: r.StartBook},
+		EndBook:   books.Book{Title: r.EndBook},
+	}
+}
+
+type GraphDTO struct {
+	Nodes []NodeDTO `json:"nodes"`
+	Edges []EdgeDTO `json:"edges"`
+}
+
+type NodeDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type EdgeDTO struct {
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Type      string       `json:"type"`
+	StartBook *EdgeBookDTO `json:"startBook,omitempty"`
+	EndBook   *EdgeBookDTO `json:"endBook,omitempty"`
+}
+
+type EdgeBookDTO struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+func NewGraphDTO(graph Graph) GraphDTO {
+	nodesDTO := []NodeDTO{}
+	for _, node := range graph.Nodes {
+		nodesDTO = append(nodesDTO, NodeDTO{ID: node.ID, Name: node.Name})
+	}
+
+	edgesDTO := []EdgeDTO{}
+	for _, edge := range graph.Edges {
+		edgesDTO = append(edgesDTO, NewEdgeDTO(edge))
+	}
+
+	return GraphDTO{Nodes: nodesDTO, Edges: edgesDTO}
+}
+
+func NewEdgeDTO(relationship Relationship) EdgeDTO {
+	return EdgeDTO{
+		From:      relationship.FromID,
+		To:        relationship.ToID,
+		Type:      relationship.Type,
+		StartBook: newEdgeBookDTO(relationship.StartBook),
+		EndBook:   newEdgeBookDTO(relationship.EndBook),
+	}
+}
+
+func newEdgeBookDTO(book books.Book) *EdgeBookDTO {
+	if book.ID == "" {
+		return nil
+	}
+
+	return &EdgeBookDTO{ID: book.ID, Title: book.Title}
+}
+
+type CharacterRequest struct {
+	Character string `uri:"character" binding:"required"`
+}
+
+type GraphRequest struct {
+	Depth int `form:"depth" binding:"omitempty,gte=1,lte=3"`
+}
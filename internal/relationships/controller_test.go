@@ -0,0 +1,182 @@
+package relationships
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestController_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when request body is an invalid json",
+			reqBody: `}`,
+			setup:   func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var syntaxErr *json.SyntaxError
+				assert.True(t, errors.As(err, &syntaxErr))
+			},
+		},
+		{
+			name:    "when request body misses the type",
+			reqBody: `{"to":"Mickey Haller"}`,
+			setup:   func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when create relationship service fails",
+			reqBody: `{"to":"Mickey Haller","type":"half-brother"}`,
+			setup: func(m *ManagerMock) {
+				m.On("Create", mock.Anything, "harry-bosch", "Mickey Haller", Relationship{Type: "half-brother"}).Return(Relationship{}, false, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when create relationship is successful",
+			reqBody: `{"to":"Mickey Haller","type":"half-brother","startBook":"The Brass Verdict"}`,
+			setup: func(m *ManagerMock) {
+				reqRelationship := Relationship{Type: "half-brother", StartBook: books.Book{Title: "The Brass Verdict"}}
+				respRelationship := Relationship{FromID: "bosch-id", ToID: "haller-id", Type: "half-brother", StartBook: books.Book{ID: "book-id", Title: "The Brass Verdict"}}
+				m.On("Create", mock.Anything, "harry-bosch", "Mickey Haller", reqRelationship).Return(respRelationship, true, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, `{"from":"bosch-id","to":"haller-id","type":"half-brother","startBook":{"id":"book-id","title":"The Brass Verdict"}}`, r.Body.String())
+			},
+		},
+		{
+			name:    "when relationship already exists",
+			reqBody: `{"to":"Mickey Haller","type":"half-brother"}`,
+			setup: func(m *ManagerMock) {
+				respRelationship := Relationship{FromID: "bosch-id", ToID: "haller-id", Type: "half-brother"}
+				m.On("Create", mock.Anything, "harry-bosch", "Mickey Haller", Relationship{Type: "half-brother"}).Return(respRelationship, false, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"from":"bosch-id","to":"haller-id","type":"half-brother"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/characters/harry-bosch/relationships", strings.NewReader(tt.reqBody))
+			ctx.Params = gin.Params{{Key: "character", Value: "harry-bosch"}}
+
+			tt.setup(m)
+
+			c.Create(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Graph(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when depth is out of range",
+			query: "?depth=4",
+			setup: func(*ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when get graph service fails",
+			query: "",
+			setup: func(m *ManagerMock) {
+				m.On("Graph", mock.Anything, "harry-bosch", 1).Return(Graph{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:  "when get graph is successful",
+			query: "?depth=2",
+			setup: func(m *ManagerMock) {
+				graph := Graph{
+					Nodes: []characters.Character{{ID: "bosch-id", Name: "Harry Bosch"}, {ID: "maddie-id", Name: "Maddie Bosch"}},
+					Edges: []Relationship{{FromID: "maddie-id", ToID: "bosch-id", Type: "daughter"}},
+				}
+				m.On("Graph", mock.Anything, "harry-bosch", 2).Return(graph, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"nodes":[{"id":"bosch-id","name":"Harry Bosch"},{"id":"maddie-id","name":"Maddie Bosch"}],"edges":[{"from":"maddie-id","to":"bosch-id","type":"daughter"}]}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/characters/harry-bosch/graph"+tt.query, nil)
+			ctx.Params = gin.Params{{Key: "character", Value: "harry-bosch"}}
+
+			tt.setup(m)
+
+			c.Graph(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+type ManagerMock struct {
+	mock.Mock
+}
+
+func (m *ManagerMock) Create(ctx context.Context, from string, to string, relationship Relationship) (Relationship, bool, error) {
+	args := m.Called(ctx, from, to, relationship)
+	return args.Get(0).(Relationship), args.Bool(1), args.Error(2)
+}
+
+func (m *ManagerMock) Graph(ctx context.Context, character string, depth int) (Graph, error) {
+	args := m.Called(ctx, character, depth)
+	return args.Get(0).(Graph), args.Error(1)
+}
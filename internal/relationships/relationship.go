@@ -0,0 +1,19 @@
+package relationships
+
+import (
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+)
+
+type Relationship struct {
+	FromID    string
+	ToID      string
+	Type      string
+	StartBook books.Book
+	EndBook   books.Book
+}
+
+type Graph struct {
+	Nodes []characters.Character
+	Edges []Relationship
+}
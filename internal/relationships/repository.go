@@ -0,0 +1,277 @@
+package relationships
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+)
+
+type DynamoDBClient interface {
+	GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error)
+	WriteItems(ctx context.Context, tableName string, puts []map[string]types.AttributeValue, deleteIDs []string, writes ...dynamo.Write) error
+	Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error)
+	GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error)
+}
+
+const FromIndex = "from-index"
+const ToIndex = "to-index"
+const StartBookIndex = "start-book-index"
+const EndBookIndex = "end-book-index"
+
+type Repository struct {
+	dynamoDBClient DynamoDBClient
+	tableName      string
+	booksTableName string
+}
+
+func NewRepository(dynamoDBClient DynamoDBClient, tableName string, booksTableName string) *Repository {
+	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName, booksTableName: booksTableName}
+}
+
+func (r *Repository) Save(ctx context.Context, relationship Relationship) (Relationship, bool, error) {
+	dbRelationship := NewDBRelationship(relationship)
+
+	var current DBRelationship
+	currentItem, err := r.dynamoDBClient.GetByID(ctx, r.tableName, dbRelationship.ID)
+	if err != nil && !errors.Is(err, dynamo.ErrNotFound) {
+		return Relationship{}, false, err
+	}
+	created := err != nil
+	if !created {
+		err = attributevalue.UnmarshalMap(currentItem, &current)
+		if err != nil {
+			return Relationship{}, false, fmt.Errorf("failed to unmarshal relationship: %w", err)
+		}
+	}
+
+	item, err := attributevalue.MarshalMap(dbRelationship)
+	if err != nil {
+		return Relationship{}, false, fmt.Errorf("failed to marshal relationship: %w", err)
+	}
+
+	var writes []dynamo.Write
+	for _, bookID := range dbRelationship.bookIDs() {
+		writes = append(writes, dynamo.AddReferencesWrite(r.booksTableName, bookID, dbRelationship.reference()))
+	}
+	for _, bookID := range current.bookIDs() {
+		if !slices.Contains(dbRelationship.bookIDs(), bookID) {
+			writes = append(writes, dynamo.RemoveReferencesWrite(r.booksTableName, bookID, current.reference()))
+		}
+	}
+
+	err = r.dynamoDBClient.WriteItems(ctx, r.tableName, []map[string]types.AttributeValue{item}, nil, writes...)
+	if err != nil {
+		return Relationship{}, false, err
+	}
+
+	return relationship, created, nil
+}
+
+func (r *Repository) GetByCharacter(ctx context.Context, characterID string) ([]Relationship, error) {
+	outgoing, err := r.query(ctx, dynamo.Query{
+		IndexName: FromIndex,
+		HashKey:   "from_id",
+		HashValue: &types.AttributeValueMemberS{Value: characterID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	incoming, err := r.query(ctx, dynamo.Query{
+		IndexName: ToIndex,
+		HashKey:   "to_id",
+		HashValue: &types.AttributeValueMemberS{Value: characterID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var relationships []Relationship
+	for _, dbRelationship := range outgoing {
+		relationships = append(relationships, dbRelationship.ToRelationship())
+	}
+	for _, dbRelationship := range incoming {
+		if dbRelationship.FromID != characterID {
+			relationships = append(relationships, dbRelationship.ToRelationship())
+		}
+	}
+
+	return relationships, nil
+}
+
+func (r *Repository) DeleteWrites(ctx context.Context, characterID string) ([]dynamo.Write, error) {
+	relationships, err := r.GetByCharacter(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var writes []dynamo.Write
+	for _, relationship := range relationships {
+		dbRelationship := NewDBRelationship(relationship)
+		writes = append(writes, dynamo.DeleteWrite(r.tableName, dbRelationship.ID))
+		for _, bookID := range dbRelationship.bookIDs() {
+			writes = append(writes, dynamo.RemoveReferencesWrite(r.booksTableName, bookID, dbRelationship.reference()))
+		}
+	}
+
+	return writes, nil
+}
+
+func (r *Repository) HasBook(ctx context.Context, bookID string) (bool, error) {
+	dbRelationships, err := r.getByBook(ctx, bookID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(dbRelationships) > 0, nil
+}
+
+func (r *Repository) RemoveBook(ctx context.Context, bookID string) error {
+	dbRelationships, err := r.getByBook(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	for _, dbRelationship := range dbRelationships {
+		if dbRelationship.StartBookID == bookID {
+			dbRelationship.StartBookID = ""
+		}
+		if dbRelationship.EndBookID == bookID {
+			dbRelationship.EndBookID = ""
+		}
+
+		item, err := attributevalue.MarshalMap(dbRelationship)
+		if err != nil {
+			return fmt.Errorf("failed to marshal relationship: %w", err)
+		}
+
+		err = r.dynamoDBClient.WriteItems(ctx, r.tableName, []map[string]types.AttributeValue{item}, nil, dynamo.RemoveReferencesWrite(r.booksTableName, bookID, dbRelationship.reference()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) RelinkBooks(ctx context.Context) error {
+	items, err := r.dynamoDBClient.GetAll(ctx, r.tableName)
+	if err != nil {
+		return err
+	}
+
+	var dbRelationships []DBRelationship
+	err = attributevalue.UnmarshalListOfMaps(items, &dbRelationships)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal relationships: %w", err)
+	}
+
+	for _, dbRelationship := range dbRelationships {
+		var writes []dynamo.Write
+		for _, bookID := range dbRelationship.bookIDs() {
+			writes = append(writes, dynamo.AddReferencesWrite(r.booksTableName, bookID, dbRelationship.reference()))
+		}
+
+		err = r.dynamoDBClient.WriteItems(ctx, r.tableName, nil, nil, writes...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) getByBook(ctx context.Context, bookID string) ([]DBRelationship, error) {
+	starting, err := r.query(ctx, dynamo.Query{
+		IndexName: StartBookIndex,
+		HashKey:   "start_book_id",
+		HashValue: &types.AttributeValueMemberS{Value: bookID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ending, err := r.query(ctx, dynamo.Query{
+		IndexName: EndBookIndex,
+		HashKey:   "end_book_id",
+		HashValue: &types.AttributeValueMemberS{Value: bookID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbRelationships := starting
+	for _, dbRelationship := range ending {
+		if dbRelationship.StartBookID != bookID {
+			dbRelationships = append(dbRelationships, dbRelationship)
+		}
+	}
+
+	return dbRelationships, nil
+}
+
+func (r *Repository) query(ctx context.Context, query dynamo.Query) ([]DBRelationship, error) {
+	items, err := r.dynamoDBClient.Query(ctx, r.tableName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbRelationships []DBRelationship
+	err = attributevalue.UnmarshalListOfMaps(items, &dbRelationships)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal relationships: %w", err)
+	}
+
+	return dbRelationships, nil
+}
+
+type DBRelationship struct {
+	ID          string `dynamodbav:"id"`
+	FromID      string `dynamodbav:"from_id"`
+	ToID        string `dynamodbav:"to_id"`
+	Type        string `dynamodbav:"type"`
+	StartBookID string `dynamodbav:"start_book_id,omitempty"`
+	EndBookID   string `dynamodbav:"end_book_id,omitempty"`
+}
+
+func NewDBRelationship(relationship Relationship) DBRelationship {
+	return DBRelationship{
+		ID:          relationship.FromID + "#" + relationship.Type + "#" + relationship.ToID,
+		FromID:      relationship.FromID,
+		ToID:        relationship.ToID,
+		Type:        relationship.Type,
+		StartBookID: relationship.StartBook.ID,
+		EndBookID:   relationship.EndBook.ID,
+	}
+}
+
+func (d *DBRelationship) bookIDs() []string {
+	var bookIDs []string
+	for _, bookID := range []string{d.StartBookID, d.EndBookID} {
+		if bookID != "" && !slices.Contains(bookIDs, bookID) {
+			bookIDs = append(bookIDs, bookID)
+		}
+	}
+
+	return bookIDs
+}
+
+func (d *DBRelationship) reference() string {
+	return "relationship#" + d.ID
+}
+
+func (d *DBRelationship) ToRelationship() Relationship {
+	return Relationship{
+		FromID:    d.FromID,
+		ToID:      d.ToID,
+		Type:      d.Type,
+		StartBook: books.Book{ID: d.StartBookID},
+		EndBook:   books.Book{ID: d.EndBookID},
+	}
+}
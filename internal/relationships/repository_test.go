@@ -0,0 +1,380 @@
+package relationships
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func relationshipItem(fromID string, relationshipType string, toID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: fromID + "#" + relationshipType + "#" + toID},
+		"from_id": &types.AttributeValueMemberS{Value: fromID},
+		"to_id":   &types.AttributeValueMemberS{Value: toID},
+		"type":    &types.AttributeValueMemberS{Value: relationshipType},
+	}
+}
+
+func TestRepository_Save(t *testing.T) {
+	ctx := context.Background()
+	relationship := Relationship{FromID: "haller-id", ToID: "bosch-id", Type: "half-brother", StartBook: books.Book{ID: "book-id"}}
+	item := relationshipItem("haller-id", "half-brother", "bosch-id")
+	item["start_book_id"] = &types.AttributeValueMemberS{Value: "book-id"}
+	current := relationshipItem("haller-id", "half-brother", "bosch-id")
+	current["start_book_id"] = &types.AttributeValueMemberS{Value: "book-id"}
+	current["end_book_id"] = &types.AttributeValueMemberS{Value: "other-book-id"}
+	addBook := dynamo.AddReferencesWrite("books", "book-id", "relationship#haller-id#half-brother#bosch-id")
+	removeOtherBook := dynamo.RemoveReferencesWrite("books", "other-book-id", "relationship#haller-id#half-brother#bosch-id")
+	tests := []struct {
+		name        string
+		setup       func(*MockDynamoDBClient)
+		want        Relationship
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name: "when failed to get current relationship",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "haller-id#half-brother#bosch-id").Return(map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to write relationship",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "haller-id#half-brother#bosch-id").Return(map[string]types.AttributeValue(nil), dynamo.ErrNotFound).Once()
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue{item}, []string(nil), []dynamo.Write{addBook}).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully created relationship",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "haller-id#half-brother#bosch-id").Return(map[string]types.AttributeValue(nil), dynamo.ErrNotFound).Once()
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue{item}, []string(nil), []dynamo.Write{addBook}).Return(nil).Once()
+			},
+			want:        relationship,
+			wantCreated: true,
+		},
+		{
+			name: "when successfully replaced existing relationship",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetByID", ctx, "table-name", "haller-id#half-brother#bosch-id").Return(current, nil).Once()
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue{item}, []string(nil), []dynamo.Write{addBook, removeOtherBook}).Return(nil).Once()
+			},
+			want: relationship,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name", "books")
+			got, created, err := r.Save(ctx, relationship)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByCharacter(t *testing.T) {
+	ctx := context.Background()
+	fromQuery := dynamo.Query{IndexName: FromIndex, HashKey: "from_id", HashValue: &types.AttributeValueMemberS{Value: "bosch-id"}}
+	toQuery := dynamo.Query{IndexName: ToIndex, HashKey: "to_id", HashValue: &types.AttributeValueMemberS{Value: "bosch-id"}}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    []Relationship
+		wantErr error
+	}{
+		{
+			name: "when failed to query outgoing relationships",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", fromQuery).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to unmarshal incoming relationships",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", fromQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+				m.On("Query", ctx, "table-name", toQuery).Return([]map[string]types.AttributeValue{{"id": &types.AttributeValueMemberM{}}}, nil).Once()
+			},
+			wantErr: fmt.Errorf("failed to unmarshal relationships: %w", &attributevalue.UnmarshalTypeError{Value: "map", Type: reflect.TypeOf("string")}),
+		},
+		{
+			name: "when successfully get outgoing and incoming relationships",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", fromQuery).Return([]map[string]types.AttributeValue{relationshipItem("bosch-id", "father", "maddie-id")}, nil).Once()
+				m.On("Query", ctx, "table-name", toQuery).Return([]map[string]types.AttributeValue{relationshipItem("ballard-id", "partner", "bosch-id")}, nil).Once()
+			},
+			want: []Relationship{
+				{FromID: "bosch-id", ToID: "maddie-id", Type: "father"},
+				{FromID: "ballard-id", ToID: "bosch-id", Type: "partner"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name", "books")
+			got, err := r.GetByCharacter(ctx, "bosch-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_DeleteWrites(t *testing.T) {
+	ctx := context.Background()
+	fromQuery := dynamo.Query{IndexName: FromIndex, HashKey: "from_id", HashValue: &types.AttributeValueMemberS{Value: "bosch-id"}}
+	toQuery := dynamo.Query{IndexName: ToIndex, HashKey: "to_id", HashValue: &types.AttributeValueMemberS{Value: "bosch-id"}}
+	partner := relationshipItem("ballard-id", "partner", "bosch-id")
+	partner["start_book_id"] = &types.AttributeValueMemberS{Value: "book-id"}
+	partner["end_book_id"] = &types.AttributeValueMemberS{Value: "book-id"}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    []dynamo.Write
+		wantErr error
+	}{
+		{
+			name: "when failed to get relationships",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", fromQuery).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when character has no relationships",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", fromQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+				m.On("Query", ctx, "table-name", toQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+			},
+		},
+		{
+			name: "when successfully built relationship deletes",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", fromQuery).Return([]map[string]types.AttributeValue{relationshipItem("bosch-id", "father", "maddie-id")}, nil).Once()
+				m.On("Query", ctx, "table-name", toQuery).Return([]map[string]types.AttributeValue{partner}, nil).Once()
+			},
+			want: []dynamo.Write{
+				dynamo.DeleteWrite("table-name", "bosch-id#father#maddie-id"),
+				dynamo.DeleteWrite("table-name", "ballard-id#partner#bosch-id"),
+				dynamo.RemoveReferencesWrite("books", "book-id", "relationship#ballard-id#partner#bosch-id"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name", "books")
+			got, err := r.DeleteWrites(ctx, "bosch-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_HasBook(t *testing.T) {
+	ctx := context.Background()
+	startQuery := dynamo.Query{IndexName: StartBookIndex, HashKey: "start_book_id", HashValue: &types.AttributeValueMemberS{Value: "book-id"}}
+	endQuery := dynamo.Query{IndexName: EndBookIndex, HashKey: "end_book_id", HashValue: &types.AttributeValueMemberS{Value: "book-id"}}
+	ending := relationshipItem("haller-id", "half-brother", "bosch-id")
+	ending["end_book_id"] = &types.AttributeValueMemberS{Value: "book-id"}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    bool
+		wantErr error
+	}{
+		{
+			name: "when failed to query relationships starting in the book",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", startQuery).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to query relationships ending in the book",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", startQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+				m.On("Query", ctx, "table-name", endQuery).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when no relationship references the book",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", startQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+				m.On("Query", ctx, "table-name", endQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+			},
+		},
+		{
+			name: "when a relationship ends in the book",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", startQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+				m.On("Query", ctx, "table-name", endQuery).Return([]map[string]types.AttributeValue{ending}, nil).Once()
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name", "books")
+			got, err := r.HasBook(ctx, "book-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_RemoveBook(t *testing.T) {
+	ctx := context.Background()
+	startQuery := dynamo.Query{IndexName: StartBookIndex, HashKey: "start_book_id", HashValue: &types.AttributeValueMemberS{Value: "book-id"}}
+	endQuery := dynamo.Query{IndexName: EndBookIndex, HashKey: "end_book_id", HashValue: &types.AttributeValueMemberS{Value: "book-id"}}
+	referencing := relationshipItem("haller-id", "half-brother", "bosch-id")
+	referencing["start_book_id"] = &types.AttributeValueMemberS{Value: "book-id"}
+	referencing["end_book_id"] = &types.AttributeValueMemberS{Value: "other-book-id"}
+	updated := relationshipItem("haller-id", "half-brother", "bosch-id")
+	updated["end_book_id"] = &types.AttributeValueMemberS{Value: "other-book-id"}
+	removeBook := dynamo.RemoveReferencesWrite("books", "book-id", "relationship#haller-id#half-brother#bosch-id")
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to get relationships",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", startQuery).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to write relationship",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", startQuery).Return([]map[string]types.AttributeValue{referencing}, nil).Once()
+				m.On("Query", ctx, "table-name", endQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue{updated}, []string(nil), []dynamo.Write{removeBook}).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully removed book from relationships",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("Query", ctx, "table-name", startQuery).Return([]map[string]types.AttributeValue{referencing}, nil).Once()
+				m.On("Query", ctx, "table-name", endQuery).Return([]map[string]types.AttributeValue{}, nil).Once()
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue{updated}, []string(nil), []dynamo.Write{removeBook}).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name", "books")
+			err := r.RemoveBook(ctx, "book-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_RelinkBooks(t *testing.T) {
+	ctx := context.Background()
+	referencing := relationshipItem("haller-id", "half-brother", "bosch-id")
+	referencing["start_book_id"] = &types.AttributeValueMemberS{Value: "book-id"}
+	addBook := dynamo.AddReferencesWrite("books", "book-id", "relationship#haller-id#half-brother#bosch-id")
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		wantErr error
+	}{
+		{
+			name: "when failed to get all relationships",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to write book references",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{referencing}, nil).Once()
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue(nil), []string(nil), []dynamo.Write{addBook}).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully relinked relationship books",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetAll", ctx, "table-name").Return([]map[string]types.AttributeValue{referencing}, nil).Once()
+				m.On("WriteItems", ctx, "table-name", []map[string]types.AttributeValue(nil), []string(nil), []dynamo.Write{addBook}).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockDynamoDBClient)
+			tt.setup(m)
+
+			r := NewRepository(m, "table-name", "books")
+			err := r.RelinkBooks(ctx)
+
+			assert.Equal(t, tt.wantErr, err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+type MockDynamoDBClient struct {
+	mock.Mock
+}
+
+func (m *MockDynamoDBClient) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, id)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) WriteItems(ctx context.Context, tableName string, puts []map[string]types.AttributeValue, deleteIDs []string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, puts, deleteIDs, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) Query(ctx context.Context, tableName string, query dynamo.Query) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, query)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
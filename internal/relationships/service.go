@@ -0,0 +1,210 @@
+package relationships
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ggoulart/michael-connelly-api/internal/apperr"
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/google/uuid"
+)
+
+var ErrSelfReference = &apperr.InvalidError{Reason: "character cannot be related to itself"}
+
+type StorageRelationship interface {
+	Save(ctx context.Context, relationship Relationship) (Relationship, bool, error)
+	GetByCharacter(ctx context.Context, characterID string) ([]Relationship, error)
+}
+
+type StorageCharacter interface {
+	GetById(ctx context.Context, characterID string) (characters.Character, error)
+	GetByName(ctx context.Context, characterName string) (characters.Character, error)
+	GetByIds(ctx context.Context, characterIDs []string) ([]characters.Character, error)
+}
+
+type StorageBook interface {
+	GetByIds(ctx context.Context, bookIDs []string) ([]books.Book, error)
+	GetBookListByTitles(ctx context.Context, bookTitles []string) ([]books.Book, error)
+}
+
+type Service struct {
+	storageRelationship StorageRelationship
+	storageCharacter    StorageCharacter
+	storageBook         StorageBook
+}
+
+func NewService(storageRelationship StorageRelationship, storageCharacter StorageCharacter, storageBook StorageBook) *Service {
+	return &Service{storageRelationship: storageRelationship, storageCharacter: storageCharacter, storageBook: storageBook}
+}
+
+func (s *Service) Create(ctx context.Context, from string, to string, relationship Relationship) (Relationship, bool, error) {
+	fromCharacter, err := s.getCharacter(ctx, from)
+	if err != nil {
+		return Relationship{}, false, err
+	}
+
+	toCharacter, err := s.getCharacter(ctx, to)
+	if err != nil {
+		return Relationship{}, false, err
+	}
+
+	if fromCharacter.ID == toCharacter.ID {
+		return Relationship{}, false, fmt.Errorf("%w. id: %s", ErrSelfReference, fromCharacter.ID)
+	}
+
+	relationship.FromID = fromCharacter.ID
+	relationship.ToID = toCharacter.ID
+	relationship.Type = dynamo.Slug(relationship.Type)
+
+	relationship.StartBook, relationship.EndBook, err = s.getBooks(ctx, relationship.StartBook.Title, relationship.EndBook.Title)
+	if err != nil {
+		return Relationship{}, false, err
+	}
+
+	return s.storageRelationship.Save(ctx, relationship)
+}
+
+func (s *Service) Graph(ctx context.Context, character string, depth int) (Graph, error) {
+	root, err := s.getCharacter(ctx, character)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	visited := map[string]bool{root.ID: true}
+	seenEdges := map[string]bool{}
+	var nodeIDs []string
+	edges := []Relationship{}
+
+	frontier := []string{root.ID}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		var next []string
+		for _, characterID := range frontier {
+			relationships, err := s.storageRelationship.GetByCharacter(ctx, characterID)
+			if err != nil {
+				return Graph{}, err
+			}
+
+			for _, relationship := range relationships {
+				edgeID := NewDBRelationship(relationship).ID
+				if seenEdges[edgeID] {
+					continue
+				}
+				seenEdges[edgeID] = true
+				edges = append(edges, relationship)
+
+				for _, neighbourID := range []string{relationship.FromID, relationship.ToID} {
+					if !visited[neighbourID] {
+						visited[neighbourID] = true
+						nodeIDs = append(nodeIDs, neighbourID)
+						next = append(next, neighbourID)
+					}
+				}
+			}
+		}
+		frontier = next
+	}
+
+	nodes, err := s.getNodes(ctx, root, nodeIDs)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	found := map[string]bool{}
+	for _, node := range nodes {
+		found[node.ID] = true
+	}
+	edges = slices.DeleteFunc(edges, func(r Relationship) bool { return !found[r.FromID] || !found[r.ToID] })
+
+	err = s.loadBooks(ctx, edges)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	return Graph{Nodes: nodes, Edges: edges}, nil
+}
+
+func (s *Service) getCharacter(ctx context.Context, character string) (characters.Character, error) {
+	characterID, err := uuid.Parse(character)
+	if err != nil {
+		return s.storageCharacter.GetByName(ctx, character)
+	}
+
+	return s.storageCharacter.GetById(ctx, characterID.String())
+}
+
+func (s *Service) getNodes(ctx context.Context, root characters.Character, nodeIDs []string) ([]characters.Character, error) {
+	nodes := []characters.Character{root}
+	if len(nodeIDs) == 0 {
+		return nodes, nil
+	}
+
+	characterList, err := s.storageCharacter.GetByIds(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(nodes, characterList...), nil
+}
+
+func (s *Service) getBooks(ctx context.Context, startTitle string, endTitle string) (books.Book, books.Book, error) {
+	var bookTitles []string
+	for _, title := range []string{startTitle, endTitle} {
+		if title != "" {
+			bookTitles = append(bookTitles, title)
+		}
+	}
+
+	if len(bookTitles) == 0 {
+		return books.Book{}, books.Book{}, nil
+	}
+
+	booksList, err := s.storageBook.GetBookListByTitles(ctx, bookTitles)
+	if err != nil {
+		return books.Book{}, books.Book{}, err
+	}
+
+	var startBook, endBook books.Book
+	if startTitle != "" {
+		startBook, booksList = booksList[0], booksList[1:]
+	}
+	if endTitle != "" {
+		endBook = booksList[0]
+	}
+
+	return startBook, endBook, nil
+}
+
+func (s *Service) loadBooks(ctx context.Context, relationships []Relationship) error {
+	var bookIDs []string
+	for _, relationship := range relationships {
+		for _, bookID := range []string{relationship.StartBook.ID, relationship.EndBook.ID} {
+			if bookID != "" && !slices.Contains(bookIDs, bookID) {
+				bookIDs = append(bookIDs, bookID)
+			}
+		}
+	}
+
+	if len(bookIDs) == 0 {
+		return nil
+	}
+
+	booksList, err := s.storageBook.GetByIds(ctx, bookIDs)
+	if err != nil {
+		return err
+	}
+
+	booksByID := map[string]books.Book{}
+	for _, book := range booksList {
+		booksByID[book.ID] = book
+	}
+
+	for i := range relationships {
+		relationships[i].StartBook = booksByID[relationships[i].StartBook.ID]
+		relationships[i].EndBook = booksByID[relationships[i].EndBook.ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package relationships
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/characters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const boschID = "c6767b2d-438b-4d4c-8b1a-659130a640ca"
+
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+	bosch := characters.Character{ID: boschID, Name: "Harry Bosch"}
+	haller := characters.Character{ID: "haller-id", Name: "Mickey Haller"}
+	brassVerdict := books.Book{ID: "book-id", Title: "The Brass Verdict"}
+	tests := []struct {
+		name         string
+		to           string
+		relationship Relationship
+		setup        func(*StorageRelationshipMock, *StorageCharacterMock, *StorageBookMock)
+		want         Relationship
+		wantCreated  bool
+		wantErr      error
+	}{
+		{
+			name:         "when failed to get from character",
+			to:           "Mickey Haller",
+			relationship: Relationship{Type: "half-brother"},
+			setup: func(_ *StorageRelationshipMock, c *StorageCharacterMock, _ *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(characters.Character{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:         "when failed to get to character",
+			to:           "Mickey Haller",
+			relationship: Relationship{Type: "half-brother"},
+			setup: func(_ *StorageRelationshipMock, c *StorageCharacterMock, _ *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				c.On("GetByName", ctx, "Mickey Haller").Return(characters.Character{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:         "when character is related to itself",
+			to:           "Harry Bosch",
+			relationship: Relationship{Type: "nemesis"},
+			setup: func(_ *StorageRelationshipMock, c *StorageCharacterMock, _ *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				c.On("GetByName", ctx, "Harry Bosch").Return(bosch, nil).Once()
+			},
+			wantErr: fmt.Errorf("%w. id: %s", ErrSelfReference, boschID),
+		},
+		{
+			name:         "when failed to get books",
+			to:           "Mickey Haller",
+			relationship: Relationship{Type: "half-brother", StartBook: books.Book{Title: "The Brass Verdict"}},
+			setup: func(_ *StorageRelationshipMock, c *StorageCharacterMock, b *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				c.On("GetByName", ctx, "Mickey Haller").Return(haller, nil).Once()
+				b.On("GetBookListByTitles", ctx, []string{"The Brass Verdict"}).Return([]books.Book(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:         "when successfully created relationship",
+			to:           "Mickey Haller",
+			relationship: Relationship{Type: "Half Brother", StartBook: books.Book{Title: "The Brass Verdict"}},
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, b *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				c.On("GetByName", ctx, "Mickey Haller").Return(haller, nil).Once()
+				b.On("GetBookListByTitles", ctx, []string{"The Brass Verdict"}).Return([]books.Book{brassVerdict}, nil).Once()
+				relationship := Relationship{FromID: boschID, ToID: "haller-id", Type: "half-brother", StartBook: brassVerdict}
+				r.On("Save", ctx, relationship).Return(relationship, true, nil).Once()
+			},
+			want:        Relationship{FromID: boschID, ToID: "haller-id", Type: "half-brother", StartBook: brassVerdict},
+			wantCreated: true,
+		},
+		{
+			name:         "when successfully created relationship with only an end book",
+			to:           "Mickey Haller",
+			relationship: Relationship{Type: "partner", EndBook: books.Book{Title: "The Brass Verdict"}},
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, b *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				c.On("GetByName", ctx, "Mickey Haller").Return(haller, nil).Once()
+				b.On("GetBookListByTitles", ctx, []string{"The Brass Verdict"}).Return([]books.Book{brassVerdict}, nil).Once()
+				relationship := Relationship{FromID: boschID, ToID: "haller-id", Type: "partner", EndBook: brassVerdict}
+				r.On("Save", ctx, relationship).Return(relationship, false, nil).Once()
+			},
+			want: Relationship{FromID: boschID, ToID: "haller-id", Type: "partner", EndBook: brassVerdict},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageRelationship := new(StorageRelationshipMock)
+			storageCharacter := new(StorageCharacterMock)
+			storageBook := new(StorageBookMock)
+			tt.setup(storageRelationship, storageCharacter, storageBook)
+
+			s := NewService(storageRelationship, storageCharacter, storageBook)
+			got, created, err := s.Create(ctx, boschID, tt.to, tt.relationship)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
+			assert.Equal(t, tt.wantErr, err)
+			storageRelationship.AssertExpectations(t)
+			storageCharacter.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Graph(t *testing.T) {
+	ctx := context.Background()
+	bosch := characters.Character{ID: boschID, Name: "Harry Bosch"}
+	haller := characters.Character{ID: "haller-id", Name: "Mickey Haller"}
+	maddie := characters.Character{ID: "maddie-id", Name: "Maddie Bosch"}
+	ballard := characters.Character{ID: "ballard-id", Name: "Renée Ballard"}
+	halfBrother := Relationship{FromID: "haller-id", ToID: boschID, Type: "half-brother", StartBook: books.Book{ID: "book-id"}}
+	daughter := Relationship{FromID: "maddie-id", ToID: boschID, Type: "daughter"}
+	partner := Relationship{FromID: "ballard-id", ToID: "maddie-id", Type: "partner"}
+	tests := []struct {
+		name    string
+		depth   int
+		setup   func(*StorageRelationshipMock, *StorageCharacterMock, *StorageBookMock)
+		want    Graph
+		wantErr error
+	}{
+		{
+			name:  "when failed to get character",
+			depth: 1,
+			setup: func(_ *StorageRelationshipMock, c *StorageCharacterMock, _ *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(characters.Character{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when failed to get relationships",
+			depth: 1,
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, _ *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				r.On("GetByCharacter", ctx, boschID).Return([]Relationship(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when character has no relationships",
+			depth: 2,
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, _ *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				r.On("GetByCharacter", ctx, boschID).Return([]Relationship(nil), nil).Once()
+			},
+			want: Graph{Nodes: []characters.Character{bosch}, Edges: []Relationship{}},
+		},
+		{
+			name:  "when failed to get nodes",
+			depth: 1,
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, _ *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				r.On("GetByCharacter", ctx, boschID).Return([]Relationship{halfBrother, daughter}, nil).Once()
+				c.On("GetByIds", ctx, []string{"haller-id", "maddie-id"}).Return([]characters.Character(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when failed to load books",
+			depth: 1,
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, b *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				r.On("GetByCharacter", ctx, boschID).Return([]Relationship{halfBrother, daughter}, nil).Once()
+				c.On("GetByIds", ctx, []string{"haller-id", "maddie-id"}).Return([]characters.Character{haller, maddie}, nil).Once()
+				b.On("GetByIds", ctx, []string{"book-id"}).Return([]books.Book(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:  "when successfully get graph with depth 1",
+			depth: 1,
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, b *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				r.On("GetByCharacter", ctx, boschID).Return([]Relationship{halfBrother, daughter}, nil).Once()
+				c.On("GetByIds", ctx, []string{"haller-id", "maddie-id"}).Return([]characters.Character{haller, maddie}, nil).Once()
+				b.On("GetByIds", ctx, []string{"book-id"}).Return([]books.Book{{ID: "book-id", Title: "The Brass Verdict"}}, nil).Once()
+			},
+			want: Graph{
+				Nodes: []characters.Character{bosch, haller, maddie},
+				Edges: []Relationship{
+					{FromID: "haller-id", ToID: boschID, Type: "half-brother", StartBook: books.Book{ID: "book-id", Title: "The Brass Verdict"}},
+					daughter,
+				},
+			},
+		},
+		{
+			name:  "when successfully get graph with depth 2",
+			depth: 2,
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, b *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				r.On("GetByCharacter", ctx, boschID).Return([]Relationship{halfBrother, daughter}, nil).Once()
+				r.On("GetByCharacter", ctx, "haller-id").Return([]Relationship{halfBrother}, nil).Once()
+				r.On("GetByCharacter", ctx, "maddie-id").Return([]Relationship{daughter, partner}, nil).Once()
+				c.On("GetByIds", ctx, []string{"haller-id", "maddie-id", "ballard-id"}).Return([]characters.Character{haller, maddie, ballard}, nil).Once()
+				b.On("GetByIds", ctx, []string{"book-id"}).Return([]books.Book{{ID: "book-id", Title: "The Brass Verdict"}}, nil).Once()
+			},
+			want: Graph{
+				Nodes: []characters.Character{bosch, haller, maddie, ballard},
+				Edges: []Relationship{
+					{FromID: "haller-id", ToID: boschID, Type: "half-brother", StartBook: books.Book{ID: "book-id", Title: "The Brass Verdict"}},
+					daughter,
+					partner,
+				},
+			},
+		},
+		{
+			name:  "when graph skips missing characters and books",
+			depth: 2,
+			setup: func(r *StorageRelationshipMock, c *StorageCharacterMock, b *StorageBookMock) {
+				c.On("GetById", ctx, boschID).Return(bosch, nil).Once()
+				r.On("GetByCharacter", ctx, boschID).Return([]Relationship{halfBrother, daughter}, nil).Once()
+				r.On("GetByCharacter", ctx, "haller-id").Return([]Relationship{halfBrother}, nil).Once()
+				r.On("GetByCharacter", ctx, "maddie-id").Return([]Relationship{daughter, partner}, nil).Once()
+				c.On("GetByIds", ctx, []string{"haller-id", "maddie-id", "ballard-id"}).Return([]characters.Character{haller, maddie}, nil).Once()
+				b.On("GetByIds", ctx, []string{"book-id"}).Return([]books.Book{}, nil).Once()
+			},
+			want: Graph{
+				Nodes: []characters.Character{bosch, haller, maddie},
+				Edges: []Relationship{{FromID: "haller-id", ToID: boschID, Type: "half-brother"}, daughter},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageRelationship := new(StorageRelationshipMock)
+			storageCharacter := new(StorageCharacterMock)
+			storageBook := new(StorageBookMock)
+			tt.setup(storageRelationship, storageCharacter, storageBook)
+
+			s := NewService(storageRelationship, storageCharacter, storageBook)
+			got, err := s.Graph(ctx, boschID, tt.depth)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageRelationship.AssertExpectations(t)
+			storageCharacter.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+		})
+	}
+}
+
+type StorageRelationshipMock struct {
+	mock.Mock
+}
+
+func (s *StorageRelationshipMock) Save(ctx context.Context, relationship Relationship) (Relationship, bool, error) {
+	args := s.Called(ctx, relationship)
+	return args.Get(0).(Relationship), args.Bool(1), args.Error(2)
+}
+
+func (s *StorageRelationshipMock) GetByCharacter(ctx context.Context, characterID string) ([]Relationship, error) {
+	args := s.Called(ctx, characterID)
+	return args.Get(0).([]Relationship), args.Error(1)
+}
+
+type StorageCharacterMock struct {
+	mock.Mock
+}
+
+func (s *StorageCharacterMock) GetById(ctx context.Context, characterID string) (characters.Character, error) {
+	args := s.Called(ctx, characterID)
+	return args.Get(0).(characters.Character), args.Error(1)
+}
+
+func (s *StorageCharacterMock) GetByName(ctx context.Context, characterName string) (characters.Character, error) {
+	args := s.Called(ctx, characterName)
+	return args.Get(0).(characters.Character), args.Error(1)
+}
+
+func (s *StorageCharacterMock) GetByIds(ctx context.Context, characterIDs []string) ([]characters.Character, error) {
+	args := s.Called(ctx, characterIDs)
+	return args.Get(0).([]characters.Character), args.Error(1)
+}
+
+type StorageBookMock struct {
+	mock.Mock
+}
+
+func (s *StorageBookMock) GetByIds(ctx context.Context, bookIDs []string) ([]books.Book, error) {
+	args := s.Called(ctx, bookIDs)
+	return args.Get(0).([]books.Book), args.Error(1)
+}
+
+func (s *StorageBookMock) GetBookListByTitles(ctx context.Context, bookTitles []string) ([]books.Book, error) {
+	args := s.Called(ctx, bookTitles)
+	return args.Get(0).([]books.Book), args.Error(1)
+}
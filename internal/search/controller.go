@@ -0,0 +1,73 @@
+package search
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Searcher interface {
+	Search(ctx context.Context, query string, limit int) ([]Hit, error)
+}
+
+const defaultLimit = 20
+
+type Controller struct {
+	searcher Searcher
+}
+
+func NewController(searcher Searcher) *Controller {
+	return &Controller{searcher: searcher}
+}
+
+func (c *Controller) Search(ctx *gin.Context) {
+	var searchRequest SearchRequest
+	if err := ctx.BindQuery(&searchRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	limit := searchRequest.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	hits, err := c.searcher.Search(ctx, searchRequest.Q, limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	hitsDTO := []HitDTO{}
+	for _, hit := range hits {
+		hitsDTO = append(hitsDTO, NewHitDTO(hit))
+	}
+
+	ctx.JSON(http.StatusOK, SearchResultDTO{Items: hitsDTO})
+}
+
+type SearchRequest struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+type SearchResultDTO struct {
+	Items []HitDTO `json:"items"`
+}
+
+type HitDTO struct {
+	Type  string  `json:"type"`
+	ID    string  `json:"id"`
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+func NewHitDTO(hit Hit) HitDTO {
+	return HitDTO{
+		Type:  hit.Type,
+		ID:    hit.ID,
+		Title: hit.Title,
+		Score: hit.Score,
+	}
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestController_Search(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(*SearcherMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when query is missing",
+			query: "",
+			setup: func(m *SearcherMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when limit is out of range",
+			query: "?q=dollmaker&limit=101",
+			setup: func(m *SearcherMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when search fails",
+			query: "?q=dollmaker",
+			setup: func(m *SearcherMock) {
+				m.On("Search", mock.Anything, "dollmaker", 20).Return([]Hit(nil), assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+			},
+		},
+		{
+			name:  "when nothing matches",
+			query: "?q=lincoln",
+			setup: func(m *SearcherMock) {
+				m.On("Search", mock.Anything, "lincoln", 20).Return([]Hit{}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"items":[]}`, r.Body.String())
+			},
+		},
+		{
+			name:  "when search is successful",
+			query: "?q=dollmaker&limit=5",
+			setup: func(m *SearcherMock) {
+				m.On("Search", mock.Anything, "dollmaker", 5).Return([]Hit{{Type: "book", ID: "123", Title: "The Concrete Blonde", Score: 1.609}}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"items":[{"type":"book","id":"123","title":"The Concrete Blonde","score":1.609}]}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(SearcherMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/search"+tt.query, nil)
+
+			tt.setup(m)
+
+			c.Search(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+type SearcherMock struct {
+	mock.Mock
+}
+
+func (m *SearcherMock) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	args := m.Called(ctx, query, limit)
+	return args.Get(0).([]Hit), args.Error(1)
+}
@@ -0,0 +1,266 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const titleWeight = 3.0
+const bodyWeight = 1.0
+
+type Document struct {
+	Type  string
+	ID    string
+	Title string
+	Body  string
+}
+
+type Hit struct {
+	Type  string
+	ID    string
+	Title string
+	Score float64
+}
+
+type Source interface {
+	Documents(ctx context.Context) ([]Document, error)
+}
+
+type Indexer interface {
+	Put(document Document)
+	Remove(documentType string, id string)
+}
+
+type Index struct {
+	mu        sync.RWMutex
+	buildMu   sync.Mutex
+	refreshes sync.WaitGroup
+	sources   []Source
+	maxAge    time.Duration
+	now       func() time.Time
+	builtAt   time.Time
+	snapshot  *snapshot
+	pending   map[string]change
+}
+
+type snapshot struct {
+	documents map[string]Document
+	terms     map[string]map[string]float64
+	postings  map[string]map[string]float64
+}
+
+type change struct {
+	document Document
+	removed  bool
+}
+
+func NewIndex(maxAge time.Duration, now func() time.Time) *Index {
+	return &Index{maxAge: maxAge, now: now, snapshot: newSnapshot()}
+}
+
+func newSnapshot() *snapshot {
+	return &snapshot{
+		documents: map[string]Document{},
+		terms:     map[string]map[string]float64{},
+		postings:  map[string]map[string]float64{},
+	}
+}
+
+func (i *Index) Register(sources ...Source) {
+	i.buildMu.Lock()
+	defer i.buildMu.Unlock()
+
+	i.sources = append(i.sources, sources...)
+}
+
+func (i *Index) Rebuild(ctx context.Context) error {
+	i.buildMu.Lock()
+	defer i.buildMu.Unlock()
+
+	return i.rebuild(ctx)
+}
+
+func (i *Index) rebuild(ctx context.Context) error {
+	builtAt := i.now()
+
+	i.mu.Lock()
+	i.pending = map[string]change{}
+	i.mu.Unlock()
+
+	next := newSnapshot()
+	for _, source := range i.sources {
+		documents, err := source.Documents(ctx)
+		if err != nil {
+			i.mu.Lock()
+			i.pending = nil
+			i.mu.Unlock()
+			return err
+		}
+
+		for _, document := range documents {
+			next.put(document)
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for key, change := range i.pending {
+		next.remove(key)
+		if !change.removed {
+			next.put(change.document)
+		}
+	}
+	i.snapshot = next
+	i.pending = nil
+	i.builtAt = builtAt
+
+	return nil
+}
+
+func (i *Index) refresh(ctx context.Context) error {
+	i.mu.RLock()
+	builtAt := i.builtAt
+	i.mu.RUnlock()
+
+	if builtAt.IsZero() {
+		i.buildMu.Lock()
+		defer i.buildMu.Unlock()
+
+		if !i.builtAt.IsZero() {
+			return nil
+		}
+
+		return i.rebuild(ctx)
+	}
+
+	if i.now().Sub(builtAt) < i.maxAge || !i.buildMu.TryLock() {
+		return nil
+	}
+
+	i.refreshes.Add(1)
+	go func() {
+		defer i.refreshes.Done()
+		defer i.buildMu.Unlock()
+
+		_ = i.rebuild(context.WithoutCancel(ctx))
+	}()
+
+	return nil
+}
+
+func (i *Index) Put(document Document) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	key := documentKey(document.Type, document.ID)
+	i.snapshot.remove(key)
+	i.snapshot.put(document)
+	if i.pending != nil {
+		i.pending[key] = change{document: document}
+	}
+}
+
+func (i *Index) Remove(documentType string, id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	key := documentKey(documentType, id)
+	i.snapshot.remove(key)
+	if i.pending != nil {
+		i.pending[key] = change{removed: true}
+	}
+}
+
+func (i *Index) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	queryTerms := slices.Compact(slices.Sorted(slices.Values(Tokenize(query))))
+	if len(queryTerms) == 0 {
+		return []Hit{}, nil
+	}
+
+	err := i.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	scores := map[string]float64{}
+	matches := map[string]int{}
+	for _, term := range queryTerms {
+		postings := i.snapshot.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		idf := math.Log(1 + float64(len(i.snapshot.documents))/float64(len(postings)))
+		for key, weight := range postings {
+			scores[key] += (1 + math.Log(weight)) * idf
+			matches[key]++
+		}
+	}
+
+	hits := []Hit{}
+	for key, score := range scores {
+		document := i.snapshot.documents[key]
+		coverage := float64(matches[key]) / float64(len(queryTerms))
+		hits = append(hits, Hit{
+			Type:  document.Type,
+			ID:    document.ID,
+			Title: document.Title,
+			Score: math.Round(score*coverage*1000) / 1000,
+		})
+	}
+
+	slices.SortFunc(hits, func(a, b Hit) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Type, b.Type), cmp.Compare(a.Title, b.Title), cmp.Compare(a.ID, b.ID))
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
+
+func (s *snapshot) put(document Document) {
+	key := documentKey(document.Type, document.ID)
+
+	weights := map[string]float64{}
+	for _, term := range Tokenize(document.Title) {
+		weights[term] += titleWeight
+	}
+	for _, term := range Tokenize(document.Body) {
+		weights[term] += bodyWeight
+	}
+
+	s.documents[key] = document
+	s.terms[key] = weights
+	for term, weight := range weights {
+		if s.postings[term] == nil {
+			s.postings[term] = map[string]float64{}
+		}
+		s.postings[term][key] = weight
+	}
+}
+
+func (s *snapshot) remove(key string) {
+	for term := range s.terms[key] {
+		delete(s.postings[term], key)
+		if len(s.postings[term]) == 0 {
+			delete(s.postings, term)
+		}
+	}
+
+	delete(s.terms, key)
+	delete(s.documents, key)
+}
+
+func documentKey(documentType string, id string) string {
+	return documentType + "#" + id
+}
@@ -0,0 +1,271 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var concreteBlonde = Document{Type: "book", ID: "the-concrete-blonde-id", Title: "The Concrete Blonde", Body: "Bosch is on trial for killing the Dollmaker, a serial killer who murdered eleven women."}
+var blackEcho = Document{Type: "book", ID: "the-black-echo-id", Title: "The Black Echo", Body: "Bosch investigates the murder of a fellow Vietnam veteran."}
+var harryBoschCharacter = Document{Type: "character", ID: "harry-bosch-id", Title: "Harry Bosch"}
+var harryBoschSeries = Document{Type: "series", ID: "harry-bosch-series-id", Title: "Harry Bosch"}
+
+func hitKeys(hits []Hit) []string {
+	var keys []string
+	for _, hit := range hits {
+		keys = append(keys, documentKey(hit.Type, hit.ID))
+	}
+	return keys
+}
+
+var builtAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fixedNow() time.Time {
+	return builtAt
+}
+
+func TestIndex_Search(t *testing.T) {
+	ctx := context.Background()
+	source := new(SourceMock)
+	source.On("Documents", ctx).Return([]Document{concreteBlonde, blackEcho, harryBoschCharacter, harryBoschSeries}, nil).Once()
+	i := NewIndex(time.Minute, fixedNow)
+	i.Register(source)
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "when query has no searchable terms",
+			query: "the, of!",
+		},
+		{
+			name:  "when nothing matches",
+			query: "lincoln",
+		},
+		{
+			name:  "when matching a blurb term",
+			query: "Dollmaker",
+			want:  []string{"book#the-concrete-blonde-id"},
+		},
+		{
+			name:  "when matching a stemmed term",
+			query: "murders",
+			want:  []string{"book#the-black-echo-id", "book#the-concrete-blonde-id"},
+		},
+		{
+			name:  "when title matches rank above blurb matches",
+			query: "bosch",
+			want:  []string{"character#harry-bosch-id", "series#harry-bosch-series-id", "book#the-black-echo-id", "book#the-concrete-blonde-id"},
+		},
+		{
+			name:  "when documents matching every term rank first",
+			query: "bosch trial",
+			want:  []string{"book#the-concrete-blonde-id", "character#harry-bosch-id", "series#harry-bosch-series-id", "book#the-black-echo-id"},
+		},
+		{
+			name:  "when limited",
+			query: "bosch",
+			limit: 2,
+			want:  []string{"character#harry-bosch-id", "series#harry-bosch-series-id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := i.Search(ctx, tt.query, tt.limit)
+
+			assert.NoError(t, err)
+			assert.NotNil(t, got)
+			assert.Equal(t, tt.want, hitKeys(got))
+		})
+	}
+	source.AssertExpectations(t)
+}
+
+func TestIndex_SearchRefreshes(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		setup     func(*SourceMock)
+		elapsed   time.Duration
+		want      []string
+		wantAfter []string
+	}{
+		{
+			name: "when index is fresh",
+			setup: func(s *SourceMock) {
+				s.On("Documents", ctx).Return([]Document{blackEcho}, nil).Once()
+			},
+			elapsed:   59 * time.Second,
+			want:      []string{"book#the-black-echo-id"},
+			wantAfter: []string{"book#the-black-echo-id"},
+		},
+		{
+			name: "when index is stale",
+			setup: func(s *SourceMock) {
+				s.On("Documents", ctx).Return([]Document{blackEcho}, nil).Once()
+				s.On("Documents", mock.Anything).Return([]Document{harryBoschCharacter}, nil).Once()
+			},
+			elapsed:   time.Minute,
+			want:      []string{"book#the-black-echo-id"},
+			wantAfter: []string{"character#harry-bosch-id"},
+		},
+		{
+			name: "when failed to refresh a stale index",
+			setup: func(s *SourceMock) {
+				s.On("Documents", ctx).Return([]Document{blackEcho}, nil).Once()
+				s.On("Documents", mock.Anything).Return([]Document(nil), assert.AnError).Once()
+				s.On("Documents", mock.Anything).Return([]Document{harryBoschCharacter}, nil).Once()
+			},
+			elapsed:   time.Minute,
+			want:      []string{"book#the-black-echo-id"},
+			wantAfter: []string{"book#the-black-echo-id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := new(SourceMock)
+			tt.setup(source)
+			now := builtAt
+			i := NewIndex(time.Minute, func() time.Time { return now })
+			i.Register(source)
+			_, err := i.Search(ctx, "bosch", 0)
+			assert.NoError(t, err)
+
+			now = now.Add(tt.elapsed)
+			got, err := i.Search(ctx, "bosch", 0)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, hitKeys(got))
+
+			i.refreshes.Wait()
+			got, err = i.Search(ctx, "bosch", 0)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAfter, hitKeys(got))
+
+			i.refreshes.Wait()
+			source.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIndex_SearchBuildsOnFirstUse(t *testing.T) {
+	ctx := context.Background()
+	source := new(SourceMock)
+	source.On("Documents", ctx).Return([]Document(nil), assert.AnError).Once()
+	i := NewIndex(time.Minute, fixedNow)
+	i.Register(source)
+
+	got, err := i.Search(ctx, "bosch", 0)
+
+	assert.Nil(t, got)
+	assert.Equal(t, assert.AnError, err)
+	source.AssertExpectations(t)
+}
+
+func TestIndex_RebuildKeepsConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	blackIce := Document{Type: "book", ID: "the-black-ice-id", Title: "The Black Ice"}
+	source := new(SourceMock)
+	i := NewIndex(time.Minute, fixedNow)
+	i.Register(source)
+	source.On("Documents", ctx).Return([]Document{blackEcho, concreteBlonde}, nil).Run(func(mock.Arguments) {
+		i.Put(blackIce)
+		i.Remove("book", "the-concrete-blonde-id")
+	}).Once()
+
+	err := i.Rebuild(ctx)
+
+	assert.NoError(t, err)
+	hits, err := i.Search(ctx, "black", 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"book#the-black-echo-id", "book#the-black-ice-id"}, hitKeys(hits))
+	hits, err = i.Search(ctx, "dollmaker", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	assert.Nil(t, i.pending)
+	source.AssertExpectations(t)
+}
+
+func TestIndex_PutAndRemove(t *testing.T) {
+	ctx := context.Background()
+	source := new(SourceMock)
+	source.On("Documents", ctx).Return([]Document{blackEcho}, nil).Once()
+	i := NewIndex(time.Minute, fixedNow)
+	i.Register(source)
+	assert.NoError(t, i.Rebuild(ctx))
+
+	i.Put(Document{Type: "book", ID: "the-black-echo-id", Title: "The Black Ice", Body: "Bosch investigates the death of a narcotics officer."})
+
+	hits, err := i.Search(ctx, "echo", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	hits, err = i.Search(ctx, "black ice", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []Hit{{Type: "book", ID: "the-black-echo-id", Title: "The Black Ice", Score: 2.909}}, hits)
+
+	i.Remove("book", "the-black-echo-id")
+
+	hits, err = i.Search(ctx, "black ice", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	assert.Empty(t, i.snapshot.postings)
+	source.AssertExpectations(t)
+}
+
+func TestIndex_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*SourceMock)
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "when failed to load documents",
+			setup: func(s *SourceMock) {
+				s.On("Documents", ctx).Return([]Document(nil), assert.AnError).Once()
+			},
+			want:    []string{"book#the-black-echo-id"},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully rebuilt",
+			setup: func(s *SourceMock) {
+				s.On("Documents", ctx).Return([]Document{harryBoschCharacter}, nil).Once()
+			},
+			want: []string{"character#harry-bosch-id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := new(SourceMock)
+			tt.setup(source)
+			i := NewIndex(time.Minute, fixedNow)
+			i.Register(source)
+			i.builtAt = builtAt
+			i.Put(blackEcho)
+
+			err := i.Rebuild(ctx)
+
+			assert.Equal(t, tt.wantErr, err)
+			hits, _ := i.Search(ctx, "bosch", 0)
+			assert.Equal(t, tt.want, hitKeys(hits))
+			source.AssertExpectations(t)
+		})
+	}
+}
+
+type SourceMock struct {
+	mock.Mock
+}
+
+func (s *SourceMock) Documents(ctx context.Context) ([]Document, error) {
+	args := s.Called(ctx)
+	return args.Get(0).([]Document), args.Error(1)
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "has": true, "he": true, "her": true, "his": true, "in": true, "is": true,
+	"it": true, "its": true, "of": true, "on": true, "or": true, "she": true, "that": true, "the": true,
+	"their": true, "this": true, "to": true, "was": true, "were": true, "who": true, "will": true, "with": true,
+}
+
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var tokens []string
+	for _, field := range fields {
+		if len([]rune(field)) < 2 || stopWords[field] {
+			continue
+		}
+
+		tokens = append(tokens, Stem(field))
+	}
+
+	return tokens
+}
+
+func Stem(word string) string {
+	return stemSuffix(stemPlural(word))
+}
+
+func stemPlural(word string) string {
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "oes") && len(word) > 4:
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && len(word) > 3:
+		return strings.TrimSuffix(word, "s")
+	}
+
+	return word
+}
+
+func stemSuffix(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ing") && hasVowel(strings.TrimSuffix(word, "ing")) && len(word) > 5:
+		return undouble(strings.TrimSuffix(word, "ing"))
+	case strings.HasSuffix(word, "ed") && hasVowel(strings.TrimSuffix(word, "ed")) && len(word) > 4:
+		return undouble(strings.TrimSuffix(word, "ed"))
+	case strings.HasSuffix(word, "ly") && len(word) > 4:
+		return strings.TrimSuffix(word, "ly")
+	}
+
+	return word
+}
+
+func hasVowel(word string) bool {
+	return strings.ContainsAny(word, "aeiouy")
+}
+
+func undouble(word string) string {
+	n := len(word)
+	if n < 2 || word[n-1] != word[n-2] || strings.ContainsRune("lsz", rune(word[n-1])) {
+		return word
+	}
+
+	return word[:n-1]
+}
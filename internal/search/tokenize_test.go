@@ -0,0 +1,68 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "when text is empty",
+			text: "",
+		},
+		{
+			name: "when text has only stop words and punctuation",
+			text: "The, and... of it!",
+		},
+		{
+			name: "when text has mixed case, punctuation and stop words",
+			text: "Harry Bosch hunts the Dollmaker's copycat.",
+			want: []string{"harry", "bosch", "hunt", "dollmaker", "copycat"},
+		},
+		{
+			name: "when text has digits and single letters",
+			text: "Case 9 of 1992: a LAPD file",
+			want: []string{"case", "1992", "lapd", "file"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tokenize(tt.text)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "killings", want: "kill"},
+		{word: "killing", want: "kill"},
+		{word: "running", want: "run"},
+		{word: "murdered", want: "murder"},
+		{word: "stopped", want: "stop"},
+		{word: "mysteries", want: "mystery"},
+		{word: "witnesses", want: "witness"},
+		{word: "heroes", want: "hero"},
+		{word: "quickly", want: "quick"},
+		{word: "detectives", want: "detective"},
+		{word: "boss", want: "boss"},
+		{word: "corpus", want: "corpus"},
+		{word: "sing", want: "sing"},
+		{word: "red", want: "red"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			assert.Equal(t, tt.want, Stem(tt.word))
+		})
+	}
+}
@@ -2,17 +2,30 @@ package series
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type Manager interface {
-	Create(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, error)
+	Create(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, bool, error)
+	Update(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, error)
+	Delete(ctx context.Context, seriesID string) error
+	GetById(ctx context.Context, seriesID string) (Series, error)
+	GetBySlug(ctx context.Context, slug string) (Series, error)
+	GetByTitle(ctx context.Context, title string) (Series, error)
+	GetByBook(ctx context.Context, bookID string) ([]Series, error)
 	GetAll(ctx context.Context) ([]Series, error)
+	GetPage(ctx context.Context, limit int32, cursor string) ([]Series, string, error)
 }
 
+const defaultPageLimit = 20
+
 type Controller struct {
 	manager Manager
 }
@@ -28,16 +41,176 @@ func (c *Controller) Create(ctx *gin.Context) {
 		return
 	}
 
-	createdSeries, err := c.manager.Create(ctx, seriesDTO.ToSeries(), seriesDTO.ToBooksOrderList())
+	createdSeries, created, err := c.manager.Create(ctx, seriesDTO.ToSeries(), seriesDTO.ToBooksOrderList())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	ctx.Header("ETag", etag.Format(createdSeries.Version))
+	ctx.JSON(status, NewSeriesDTO(createdSeries))
+}
+
+func (c *Controller) Update(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	version, err := etag.Parse(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var seriesDTO SeriesDTO
+	if err := ctx.BindJSON(&seriesDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	series := seriesDTO.ToSeries()
+	series.ID = idRequest.SeriesID
+	series.Version = version
+
+	booksOrderList := seriesDTO.ToBooksOrderList()
+	if booksOrderList == nil {
+		booksOrderList = []BooksOrder{}
+	}
+
+	updatedSeries, err := c.manager.Update(ctx, series, booksOrderList)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, NewSeriesDTO(createdSeries))
+	ctx.Header("ETag", etag.Format(updatedSeries.Version))
+	ctx.JSON(http.StatusOK, NewSeriesDTO(updatedSeries))
+}
+
+func (c *Controller) Patch(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	version, err := etag.Parse(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var patchSeriesDTO PatchSeriesDTO
+	if err := ctx.BindJSON(&patchSeriesDTO); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	series, err := c.manager.GetById(ctx, idRequest.SeriesID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	series, booksOrderList := patchSeriesDTO.ApplyTo(series)
+	series.Version = version
+
+	updatedSeries, err := c.manager.Update(ctx, series, booksOrderList)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(updatedSeries.Version))
+	ctx.JSON(http.StatusOK, NewSeriesDTO(updatedSeries))
+}
+
+func (c *Controller) Delete(ctx *gin.Context) {
+	var idRequest IDRequest
+	if err := ctx.BindUri(&idRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err := c.manager.Delete(ctx, idRequest.SeriesID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *Controller) GetBy(ctx *gin.Context) {
+	var getByRequest GetByRequest
+	if err := ctx.BindUri(&getByRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var series Series
+	seriesID, err := uuid.Parse(getByRequest.Series)
+	if err != nil {
+		series, err = c.manager.GetBySlug(ctx, getByRequest.Series)
+		if errors.Is(err, dynamo.ErrNotFound) {
+			series, err = c.manager.GetByTitle(ctx, getByRequest.Series)
+		}
+	} else {
+		series, err = c.manager.GetById(ctx, seriesID.String())
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag.Format(series.Version))
+	ctx.JSON(http.StatusOK, NewSeriesDTO(series))
+}
+
+func (c *Controller) GetByBook(ctx *gin.Context) {
+	var bookRequest BookRequest
+	if err := ctx.BindUri(&bookRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	series, err := c.manager.GetByBook(ctx, bookRequest.BookID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	bookSeriesDTO := []BookSeriesDTO{}
+	for _, s := range series {
+		bookSeriesDTO = append(bookSeriesDTO, NewBookSeriesDTO(s, bookRequest.BookID))
+	}
+
+	ctx.JSON(http.StatusOK, bookSeriesDTO)
 }
 
 func (c *Controller) GetAll(ctx *gin.Context) {
+	var getAllRequest GetAllRequest
+	if err := ctx.BindQuery(&getAllRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if getAllRequest.Limit == 0 && getAllRequest.Cursor == "" {
+		c.getAll(ctx)
+		return
+	}
+
+	c.getPage(ctx, getAllRequest)
+}
+
+func (c *Controller) getAll(ctx *gin.Context) {
 	series, err := c.manager.GetAll(ctx)
 	if err != nil {
 		ctx.Error(err)
@@ -53,12 +226,56 @@ func (c *Controller) GetAll(ctx *gin.Context) {
 
 }
 
+func (c *Controller) getPage(ctx *gin.Context, getAllRequest GetAllRequest) {
+	limit := getAllRequest.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	series, nextCursor, err := c.manager.GetPage(ctx, limit, getAllRequest.Cursor)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	seriesDTO := []SeriesDTO{}
+	for _, s := range series {
+		seriesDTO = append(seriesDTO, NewSeriesDTO(s))
+	}
+
+	ctx.JSON(http.StatusOK, SeriesPageDTO{Items: seriesDTO, NextCursor: nextCursor})
+}
+
 type SeriesDTO struct {
 	ID    string          `json:"id"`
+	Slug  string          `json:"slug,omitempty"`
 	Title string          `json:"title" binding:"required"`
 	Books []BooksOrderDTO `json:"books"`
 }
 
+type BookSeriesDTO struct {
+	ID    string `json:"id"`
+	Slug  string `json:"slug,omitempty"`
+	Title string `json:"title"`
+	Order int    `json:"order"`
+}
+
+func NewBookSeriesDTO(series Series, bookID string) BookSeriesDTO {
+	bookSeriesDTO := BookSeriesDTO{ID: series.ID, Slug: series.Slug, Title: series.Title}
+	for _, b := range series.Books {
+		if b.Book.ID == bookID {
+			bookSeriesDTO.Order = b.Order
+		}
+	}
+
+	return bookSeriesDTO
+}
+
+type SeriesPageDTO struct {
+	Items      []SeriesDTO `json:"items"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
 type BooksOrderDTO struct {
 	ID        string `json:"id,omitempty"`
 	BookTitle string `json:"title" binding:"required"`
@@ -77,6 +294,7 @@ func NewSeriesDTO(series Series) SeriesDTO {
 
 	return SeriesDTO{
 		ID:    series.ID,
+		Slug:  series.Slug,
 		Title: series.Title,
 		Books: bookTitles,
 	}
@@ -102,3 +320,41 @@ func (r *SeriesDTO) ToBooksOrderList() []BooksOrder {
 
 	return booksOrderList
 }
+
+type GetAllRequest struct {
+	Limit  int32  `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor string `form:"cursor"`
+}
+
+type PatchSeriesDTO struct {
+	Title *string          `json:"title" binding:"omitempty,min=1"`
+	Books *[]BooksOrderDTO `json:"books"`
+}
+
+func (r *PatchSeriesDTO) ApplyTo(series Series) (Series, []BooksOrder) {
+	if r.Title != nil {
+		series.Title = *r.Title
+	}
+
+	series.Books = nil
+
+	var booksOrderList []BooksOrder
+	if r.Books != nil {
+		seriesDTO := SeriesDTO{Books: *r.Books}
+		booksOrderList = append([]BooksOrder{}, seriesDTO.ToBooksOrderList()...)
+	}
+
+	return series, booksOrderList
+}
+
+type IDRequest struct {
+	SeriesID string `uri:"series" binding:"required,uuid"`
+}
+
+type GetByRequest struct {
+	Series string `uri:"series" binding:"required"`
+}
+
+type BookRequest struct {
+	BookID string `uri:"bookID" binding:"required,uuid"`
+}
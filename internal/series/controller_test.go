@@ -10,7 +10,10 @@ import (
 	"testing"
 
 	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/etag"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -37,7 +40,7 @@ func TestController_Create(t *testing.T) {
 			setup: func(m *ManagerMock) {
 				reqSeries := Series{Title: "The Harry Bosch"}
 				reqOrder := []BooksOrder{{Order: 1, Book: books.Book{Title: "The Black Echo"}}}
-				m.On("Create", mock.Anything, reqSeries, reqOrder).Return(Series{}, assert.AnError).Once()
+				m.On("Create", mock.Anything, reqSeries, reqOrder).Return(Series{}, false, assert.AnError).Once()
 			},
 			expected: func(_ *httptest.ResponseRecorder, err error) {
 				assert.True(t, errors.Is(err, assert.AnError))
@@ -51,7 +54,7 @@ func TestController_Create(t *testing.T) {
 				reqOrder := []BooksOrder{{Order: 1, Book: books.Book{Title: "The Black Echo"}}}
 				outputBook := books.Book{ID: "the-black-echo-book-id", Title: "The Black Echo", Year: 1992, Blurb: "a random blurb"}
 				outputSeries := Series{ID: "the-harry-bosch-series-id", Title: "The Harry Bosch", Books: []BooksOrder{{Order: 1, Book: outputBook}}}
-				m.On("Create", mock.Anything, reqSeries, reqOrder).Return(outputSeries, nil).Once()
+				m.On("Create", mock.Anything, reqSeries, reqOrder).Return(outputSeries, true, nil).Once()
 			},
 			expected: func(r *httptest.ResponseRecorder, err error) {
 				assert.Nil(t, err)
@@ -59,6 +62,22 @@ func TestController_Create(t *testing.T) {
 				assert.Equal(t, `{"id":"the-harry-bosch-series-id","title":"The Harry Bosch","books":[{"id":"the-black-echo-book-id","title":"The Black Echo","order":1}]}`, r.Body.String())
 			},
 		},
+		{
+			name:    "when create series returns an existing series",
+			reqBody: `{"title":"The Harry Bosch", "books":[{"title":"The Black Echo", "order": 1}]}`,
+			setup: func(m *ManagerMock) {
+				reqSeries := Series{Title: "The Harry Bosch"}
+				reqOrder := []BooksOrder{{Order: 1, Book: books.Book{Title: "The Black Echo"}}}
+				outputSeries := Series{ID: "the-harry-bosch-series-id", Title: "The Harry Bosch", Version: 2}
+				m.On("Create", mock.Anything, reqSeries, reqOrder).Return(outputSeries, false, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"2"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"the-harry-bosch-series-id","title":"The Harry Bosch","books":null}`, r.Body.String())
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -130,17 +149,520 @@ func TestController_GetAll(t *testing.T) {
 	}
 }
 
+func TestController_GetAllPaginated(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(*ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when limit is above maximum",
+			query: "?limit=101",
+			setup: func(m *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:  "when failed to get page of series",
+			query: "?cursor=a-cursor",
+			setup: func(m *ManagerMock) {
+				m.On("GetPage", mock.Anything, int32(20), "a-cursor").Return([]Series{}, "", assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:  "when successful to get page of series",
+			query: "?limit=1",
+			setup: func(m *ManagerMock) {
+				series := []Series{{ID: "the-harry-bosch-series-id", Title: "The Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "the-black-echo-id", Title: "The Black Echo", Year: 1992, Blurb: "Blurb"}}}}}
+				m.On("GetPage", mock.Anything, int32(1), "").Return(series, "next-cursor", nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `{"items":[{"id":"the-harry-bosch-series-id","title":"The Harry Bosch","books":[{"id":"the-black-echo-id","title":"The Black Echo","order":1}]}],"nextCursor":"next-cursor"}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/series"+tt.query, nil)
+
+			tt.setup(m)
+
+			c.GetAll(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  string
+		ifMatch  string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when series id is not a uuid",
+			reqBody: `{"title":"Harry Bosch"}`,
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "Harry Bosch"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name:    "when If-Match header is missing",
+			reqBody: `{"title":"Harry Bosch"}`,
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, etag.ErrMissing))
+			},
+		},
+		{
+			name:    "when update series service fails",
+			ifMatch: `"2"`,
+			reqBody: `{"title":"Harry Bosch"}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("Update", mock.Anything, Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch", Version: 2}, []BooksOrder{}).Return(Series{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when update series is successful",
+			ifMatch: `"2"`,
+			reqBody: `{"title":"Harry Bosch","books":[{"title":"The Black Echo","order":1}]}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				booksOrderList := []BooksOrder{{Order: 1, Book: books.Book{Title: "The Black Echo"}}}
+				respSeries := Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "the-black-echo-id", Title: "The Black Echo"}}}, Version: 3}
+				m.On("Update", mock.Anything, Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch", Version: 2}, booksOrderList).Return(respSeries, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","title":"Harry Bosch","books":[{"id":"the-black-echo-id","title":"The Black Echo","order":1}]}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/series/c6767b2d-438b-4d4c-8b1a-659130a640ca", strings.NewReader(tt.reqBody))
+
+			ctx.Request.Header.Set("If-Match", tt.ifMatch)
+
+			tt.setup(ctx, m)
+
+			c.Update(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Patch(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  string
+		ifMatch  string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:    "when get series service fails",
+			ifMatch: `"2"`,
+			reqBody: `{"title":"Harry Bosch"}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Series{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name:    "when patch only changes the title",
+			ifMatch: `"2"`,
+			reqBody: `{"title":"Bosch"}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				currentSeries := Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "the-black-echo-id", Title: "The Black Echo"}}}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(currentSeries, nil).Once()
+				respSeries := Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Bosch", Books: currentSeries.Books, Version: 3}
+				m.On("Update", mock.Anything, Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Bosch", Version: 2}, []BooksOrder(nil)).Return(respSeries, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","title":"Bosch","books":[{"id":"the-black-echo-id","title":"The Black Echo","order":1}]}`, r.Body.String())
+			},
+		},
+		{
+			name:    "when patch replaces the books",
+			ifMatch: `"2"`,
+			reqBody: `{"books":[]}`,
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch"}, nil).Once()
+				m.On("Update", mock.Anything, Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch", Version: 2}, []BooksOrder{}).Return(Series{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/series/c6767b2d-438b-4d4c-8b1a-659130a640ca", strings.NewReader(tt.reqBody))
+
+			ctx.Request.Header.Set("If-Match", tt.ifMatch)
+
+			tt.setup(ctx, m)
+
+			c.Patch(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetBy(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name:  "when missing series id",
+			setup: func(_ *gin.Context, _ *ManagerMock) {},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name: "when get series service fails",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(Series{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name: "when get series service is successful",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				respSeries := Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Title: "Harry Bosch", Version: 5}
+				m.On("GetById", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(respSeries, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"5"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","title":"Harry Bosch","books":null}`, r.Body.String())
+			},
+		},
+		{
+			name: "when get series by slug service fails",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "harry-bosch"}}
+				m.On("GetBySlug", mock.Anything, "harry-bosch").Return(Series{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name: "when get series by title service fails",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "Harry Bosch"}}
+				m.On("GetBySlug", mock.Anything, "Harry Bosch").Return(Series{}, dynamo.ErrNotFound).Once()
+				m.On("GetByTitle", mock.Anything, "Harry Bosch").Return(Series{}, dynamo.ErrNotFound).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, dynamo.ErrNotFound))
+			},
+		},
+		{
+			name: "when get renamed series by title is successful",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "Harry Bosch Novels"}}
+				respSeries := Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "harry-bosch", Title: "Harry Bosch Novels", Version: 3}
+				m.On("GetBySlug", mock.Anything, "Harry Bosch Novels").Return(Series{}, dynamo.ErrNotFound).Once()
+				m.On("GetByTitle", mock.Anything, "Harry Bosch Novels").Return(respSeries, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","slug":"harry-bosch","title":"Harry Bosch Novels","books":null}`, r.Body.String())
+			},
+		},
+		{
+			name: "when get series by slug service is successful",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "harry-bosch"}}
+				respSeries := Series{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "harry-bosch", Title: "Harry Bosch", Version: 2}
+				m.On("GetBySlug", mock.Anything, "harry-bosch").Return(respSeries, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"2"`, r.Header().Get("ETag"))
+				assert.Equal(t, `{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","slug":"harry-bosch","title":"Harry Bosch","books":null}`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/series/c6767b2d-438b-4d4c-8b1a-659130a640ca", nil)
+
+			tt.setup(ctx, m)
+
+			c.GetBy(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_GetByBook(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name: "when book id is not a uuid",
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "the-black-echo"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name: "when get series by book service fails",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetByBook", mock.Anything, "a7767b2d-438b-4d4c-8b1a-659130a640ca").Return([]Series{}, assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name: "when no series contains the book",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("GetByBook", mock.Anything, "a7767b2d-438b-4d4c-8b1a-659130a640ca").Return([]Series{}, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[]`, r.Body.String())
+			},
+		},
+		{
+			name: "when get series by book service is successful",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "bookID", Value: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				respSeries := []Series{
+					{ID: "c6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "harry-bosch", Title: "Harry Bosch", Books: []BooksOrder{
+						{Order: 1, Book: books.Book{ID: "b1767b2d-438b-4d4c-8b1a-659130a640ca"}},
+						{Order: 2, Book: books.Book{ID: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}},
+					}},
+					{ID: "d6767b2d-438b-4d4c-8b1a-659130a640ca", Slug: "the-lincoln-lawyer", Title: "The Lincoln Lawyer", Books: []BooksOrder{
+						{Order: 5, Book: books.Book{ID: "a7767b2d-438b-4d4c-8b1a-659130a640ca"}},
+					}},
+				}
+				m.On("GetByBook", mock.Anything, "a7767b2d-438b-4d4c-8b1a-659130a640ca").Return(respSeries, nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `[{"id":"c6767b2d-438b-4d4c-8b1a-659130a640ca","slug":"harry-bosch","title":"Harry Bosch","order":2},{"id":"d6767b2d-438b-4d4c-8b1a-659130a640ca","slug":"the-lincoln-lawyer","title":"The Lincoln Lawyer","order":5}]`, r.Body.String())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/books/a7767b2d-438b-4d4c-8b1a-659130a640ca/series", nil)
+
+			tt.setup(ctx, m)
+
+			c.GetByBook(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_Delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*gin.Context, *ManagerMock)
+		expected func(*httptest.ResponseRecorder, error)
+	}{
+		{
+			name: "when series id is not a uuid",
+			setup: func(ctx *gin.Context, _ *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "Harry Bosch"}}
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				var validationErrs validator.ValidationErrors
+				assert.True(t, errors.As(err, &validationErrs))
+			},
+		},
+		{
+			name: "when delete series service fails",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("Delete", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(assert.AnError).Once()
+			},
+			expected: func(_ *httptest.ResponseRecorder, err error) {
+				assert.True(t, errors.Is(err, assert.AnError))
+			},
+		},
+		{
+			name: "when delete series is successful",
+			setup: func(ctx *gin.Context, m *ManagerMock) {
+				ctx.Params = gin.Params{{Key: "series", Value: "c6767b2d-438b-4d4c-8b1a-659130a640ca"}}
+				m.On("Delete", mock.Anything, "c6767b2d-438b-4d4c-8b1a-659130a640ca").Return(nil).Once()
+			},
+			expected: func(r *httptest.ResponseRecorder, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusNoContent, r.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(ManagerMock)
+			c := NewController(m)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/series/c6767b2d-438b-4d4c-8b1a-659130a640ca", nil)
+
+			tt.setup(ctx, m)
+
+			c.Delete(ctx)
+
+			ctx.Writer.WriteHeaderNow()
+
+			tt.expected(recorder, ctx.Errors.Last())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
 type ManagerMock struct {
 	Manager
 	mock.Mock
 }
 
-func (m *ManagerMock) Create(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, error) {
+func (m *ManagerMock) Create(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, bool, error) {
 	args := m.Called(ctx, series, booksOrderList)
-	return args.Get(0).(Series), args.Error(1)
+	return args.Get(0).(Series), args.Bool(1), args.Error(2)
 }
 
 func (m *ManagerMock) GetAll(ctx context.Context) ([]Series, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]Series), args.Error(1)
 }
+
+func (m *ManagerMock) GetPage(ctx context.Context, limit int32, cursor string) ([]Series, string, error) {
+	args := m.Called(ctx, limit, cursor)
+	return args.Get(0).([]Series), args.String(1), args.Error(2)
+}
+
+func (m *ManagerMock) Update(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, error) {
+	args := m.Called(ctx, series, booksOrderList)
+	return args.Get(0).(Series), args.Error(1)
+}
+
+func (m *ManagerMock) GetById(ctx context.Context, seriesID string) (Series, error) {
+	args := m.Called(ctx, seriesID)
+	return args.Get(0).(Series), args.Error(1)
+}
+
+func (m *ManagerMock) GetBySlug(ctx context.Context, slug string) (Series, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(Series), args.Error(1)
+}
+
+func (m *ManagerMock) GetByTitle(ctx context.Context, title string) (Series, error) {
+	args := m.Called(ctx, title)
+	return args.Get(0).(Series), args.Error(1)
+}
+
+func (m *ManagerMock) GetByBook(ctx context.Context, bookID string) ([]Series, error) {
+	args := m.Called(ctx, bookID)
+	return args.Get(0).([]Series), args.Error(1)
+}
+
+func (m *ManagerMock) Delete(ctx context.Context, seriesID string) error {
+	args := m.Called(ctx, seriesID)
+	return args.Error(0)
+}
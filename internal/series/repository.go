@@ -4,47 +4,314 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/ggoulart/michael-connelly-api/internal/books"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
 )
 
 type DynamoDBClient interface {
-	Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string) (string, error)
+	NewID() string
+	Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error)
+	Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error
+	Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error
+	GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error)
 	GetByUniqueKey(ctx context.Context, tableName string, value string) (map[string]types.AttributeValue, error)
+	GetBySlug(ctx context.Context, tableName string, slug string) (map[string]types.AttributeValue, error)
+	BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error)
 	GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error)
+	GetPage(ctx context.Context, tableName string, limit int32, cursor string) ([]map[string]types.AttributeValue, string, error)
 }
 
+type Links interface {
+	Writes(owner string, ownerID string, current []relations.Link, links []relations.Link) ([]dynamo.Write, error)
+	Replace(ctx context.Context, owner string, ownerID string, links []relations.Link) error
+	GetByBook(ctx context.Context, owner string, bookID string) ([]relations.Link, error)
+	Apply(ctx context.Context, writes []dynamo.Write) error
+}
+
+const linkOwner = "series"
+
 type Repository struct {
-	dynamoDBClient DynamoDBClient
-	tableName      string
+	dynamoDBClient  DynamoDBClient
+	tableName       string
+	duplicatePolicy dynamo.DuplicatePolicy
+	links           Links
 }
 
-func NewRepository(dynamoDBClient DynamoDBClient, tableName string) *Repository {
-	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName}
+func NewRepository(dynamoDBClient DynamoDBClient, tableName string, duplicatePolicy dynamo.DuplicatePolicy, links Links) *Repository {
+	return &Repository{dynamoDBClient: dynamoDBClient, tableName: tableName, duplicatePolicy: duplicatePolicy, links: links}
 }
 
-func (r *Repository) Save(ctx context.Context, series Series) (Series, error) {
+func (r *Repository) Save(ctx context.Context, series Series) (Series, bool, error) {
+	series.ID = r.dynamoDBClient.NewID()
+
+	writes, err := r.links.Writes(linkOwner, series.ID, nil, newLinks(series))
+	if err != nil {
+		return Series{}, false, err
+	}
+
+	for _, slug := range dynamo.SlugCandidates("", series.Title, series.ID) {
+		series.Slug = slug
+		err = r.save(ctx, series, writes)
+		if !errors.Is(err, dynamo.ErrSlugTaken) {
+			break
+		}
+	}
+	if errors.Is(err, dynamo.ErrDuplicated) {
+		return r.saveDuplicated(ctx, series, err)
+	}
+	if err != nil {
+		return Series{}, false, err
+	}
+
+	series.Version = 1
+
+	return series, true, nil
+}
+
+func (r *Repository) save(ctx context.Context, series Series, writes []dynamo.Write) error {
 	seriesItem, err := attributevalue.MarshalMap(NewDBSeries(series))
 	if err != nil {
-		return Series{}, fmt.Errorf("failed to marshal series: %w", err)
+		return fmt.Errorf("failed to marshal series: %w", err)
+	}
+
+	return relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		_, err := r.dynamoDBClient.Save(ctx, r.tableName, seriesItem, series.Title, append(writes, dynamo.ClaimSlugWrite(r.tableName, series.Slug, series.ID))...)
+		return err
+	})
+}
+
+func (r *Repository) saveDuplicated(ctx context.Context, series Series, duplicatedErr error) (Series, bool, error) {
+	if r.duplicatePolicy == dynamo.DuplicateReject {
+		return Series{}, false, duplicatedErr
+	}
+
+	existingSeries, err := r.getDuplicated(ctx, series, duplicatedErr)
+	if err != nil {
+		return Series{}, false, err
+	}
+
+	if r.duplicatePolicy == dynamo.DuplicateReturnExisting {
+		return existingSeries, false, nil
+	}
+
+	series.ID = existingSeries.ID
+	series.Version = existingSeries.Version
+
+	updatedSeries, err := r.Update(ctx, series)
+
+	return updatedSeries, false, err
+}
+
+func (r *Repository) getDuplicated(ctx context.Context, series Series, duplicatedErr error) (Series, error) {
+	var withID *dynamo.DuplicatedError
+	if errors.As(duplicatedErr, &withID) {
+		return r.GetById(ctx, withID.ID)
+	}
+
+	return r.GetByTitle(ctx, series.Title)
+}
+
+func (r *Repository) Update(ctx context.Context, series Series) (Series, error) {
+	item, err := r.dynamoDBClient.GetByID(ctx, r.tableName, series.ID)
+	if err != nil {
+		return Series{}, err
+	}
+
+	var currentSeries DBSeries
+	err = attributevalue.UnmarshalMap(item, &currentSeries)
+	if err != nil {
+		return Series{}, fmt.Errorf("failed to unmarshal series: %w", err)
+	}
+
+	if series.Version == dynamo.AnyVersion {
+		series.Version = currentSeries.Version
+	}
+
+	if series.Books == nil {
+		series.Books = currentSeries.ToSeries().Books
 	}
 
-	id, err := r.dynamoDBClient.Save(ctx, r.tableName, seriesItem, series.Title)
+	writes, err := r.links.Writes(linkOwner, series.ID, newLinks(currentSeries.ToSeries()), newLinks(series))
 	if err != nil {
-		if errors.Is(err, dynamo.ErrDuplicated) {
-			return r.GetByTitle(ctx, series.Title)
+		return Series{}, err
+	}
+
+	for _, slug := range dynamo.SlugCandidates(currentSeries.Slug, series.Title, series.ID) {
+		series.Slug = slug
+		err = r.update(ctx, series, currentSeries.Title, writes)
+		if !errors.Is(err, dynamo.ErrSlugTaken) {
+			break
 		}
+	}
+	if err != nil {
 		return Series{}, err
 	}
 
-	series.ID = id
+	series.Version++
 
 	return series, nil
 }
 
+func (r *Repository) update(ctx context.Context, series Series, currentTitle string, writes []dynamo.Write) error {
+	seriesItem, err := attributevalue.MarshalMap(NewDBSeries(series))
+	if err != nil {
+		return fmt.Errorf("failed to marshal series: %w", err)
+	}
+
+	return relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		return r.dynamoDBClient.Update(ctx, r.tableName, series.ID, series.Version, seriesItem, currentTitle, series.Title, append(writes, dynamo.ClaimSlugWrite(r.tableName, series.Slug, series.ID))...)
+	})
+}
+
+func (r *Repository) Delete(ctx context.Context, seriesID string) error {
+	series, err := r.GetById(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	writes, err := r.links.Writes(linkOwner, seriesID, newLinks(series), nil)
+	if err != nil {
+		return err
+	}
+
+	return relations.WriteOwner(ctx, r.links, writes, func(writes []dynamo.Write) error {
+		if series.Slug != "" {
+			writes = append(writes, dynamo.ReleaseSlugWrite(r.tableName, series.Slug, seriesID))
+		}
+
+		return r.dynamoDBClient.Delete(ctx, r.tableName, seriesID, series.Title, writes...)
+	})
+}
+
+func (r *Repository) HasBook(ctx context.Context, bookID string) (bool, error) {
+	links, err := r.links.GetByBook(ctx, linkOwner, bookID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(links) > 0, nil
+}
+
+func (r *Repository) RemoveBook(ctx context.Context, bookID string) error {
+	seriesList, err := r.GetByBook(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	for _, series := range seriesList {
+		current := newLinks(series)
+		series.Books = slices.DeleteFunc(series.Books, func(b BooksOrder) bool { return b.ID == bookID })
+
+		seriesItem, err := attributevalue.MarshalMap(NewDBSeries(series))
+		if err != nil {
+			return fmt.Errorf("failed to marshal series: %w", err)
+		}
+
+		writes, err := r.links.Writes(linkOwner, series.ID, current, newLinks(series))
+		if err != nil {
+			return err
+		}
+
+		err = r.dynamoDBClient.Update(ctx, r.tableName, series.ID, series.Version, seriesItem, series.Title, series.Title, writes...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) BackfillSlugs(ctx context.Context) error {
+	seriesList, err := r.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, series := range seriesList {
+		if series.Slug != "" {
+			continue
+		}
+
+		series.Version = dynamo.AnyVersion
+		_, err = r.Update(ctx, series)
+		if err != nil {
+			return fmt.Errorf("%w. series: %s", err, series.ID)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) RelinkBooks(ctx context.Context) error {
+	seriesList, err := r.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, series := range seriesList {
+		err = r.links.Replace(ctx, linkOwner, series.ID, newLinks(series))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) GetByBook(ctx context.Context, bookID string) ([]Series, error) {
+	links, err := r.links.GetByBook(ctx, linkOwner, bookID)
+	if err != nil {
+		return []Series{}, err
+	}
+
+	if len(links) == 0 {
+		return []Series{}, nil
+	}
+
+	var seriesIDs []string
+	for _, link := range links {
+		seriesIDs = append(seriesIDs, link.OwnerID)
+	}
+
+	items, err := r.dynamoDBClient.BatchGetByIDs(ctx, r.tableName, seriesIDs)
+	if err != nil {
+		return []Series{}, err
+	}
+
+	var seriesList []Series
+	for _, item := range items {
+		var dbSeries DBSeries
+		err = attributevalue.UnmarshalMap(item, &dbSeries)
+		if err != nil {
+			return []Series{}, fmt.Errorf("failed to unmarshal series: %w", err)
+		}
+
+		seriesList = append(seriesList, dbSeries.ToSeries())
+	}
+
+	return seriesList, nil
+}
+
+func (r *Repository) GetById(ctx context.Context, seriesID string) (Series, error) {
+	item, err := r.dynamoDBClient.GetByID(ctx, r.tableName, seriesID)
+	if err != nil {
+		return Series{}, err
+	}
+
+	var dbSeries DBSeries
+	err = attributevalue.UnmarshalMap(item, &dbSeries)
+	if err != nil {
+		return Series{}, fmt.Errorf("failed to unmarshal series: %w", err)
+	}
+
+	return dbSeries.ToSeries(), nil
+}
+
 func (r *Repository) GetByTitle(ctx context.Context, title string) (Series, error) {
 	item, err := r.dynamoDBClient.GetByUniqueKey(ctx, r.tableName, title)
 	if err != nil {
@@ -60,6 +327,21 @@ func (r *Repository) GetByTitle(ctx context.Context, title string) (Series, erro
 	return dbSeries.ToSeries(), nil
 }
 
+func (r *Repository) GetBySlug(ctx context.Context, slug string) (Series, error) {
+	item, err := r.dynamoDBClient.GetBySlug(ctx, r.tableName, slug)
+	if err != nil {
+		return Series{}, err
+	}
+
+	var dbSeries DBSeries
+	err = attributevalue.UnmarshalMap(item, &dbSeries)
+	if err != nil {
+		return Series{}, fmt.Errorf("failed to unmarshal series: %w", err)
+	}
+
+	return dbSeries.ToSeries(), nil
+}
+
 func (r *Repository) GetAll(ctx context.Context) ([]Series, error) {
 	items, err := r.dynamoDBClient.GetAll(ctx, r.tableName)
 	if err != nil {
@@ -80,10 +362,32 @@ func (r *Repository) GetAll(ctx context.Context) ([]Series, error) {
 	return seriesList, nil
 }
 
+func (r *Repository) GetPage(ctx context.Context, limit int32, cursor string) ([]Series, string, error) {
+	items, nextCursor, err := r.dynamoDBClient.GetPage(ctx, r.tableName, limit, cursor)
+	if err != nil {
+		return []Series{}, "", err
+	}
+
+	var seriesList []Series
+	for _, item := range items {
+		var dbSeries DBSeries
+		err = attributevalue.UnmarshalMap(item, &dbSeries)
+		if err != nil {
+			return []Series{}, "", fmt.Errorf("failed to unmarshal series: %w", err)
+		}
+
+		seriesList = append(seriesList, dbSeries.ToSeries())
+	}
+
+	return seriesList, nextCursor, nil
+}
+
 type DBSeries struct {
 	ID         string         `dynamodbav:"id"`
+	Slug       string         `dynamodbav:"slug,omitempty"`
 	Title      string         `dynamodbav:"title"`
 	BooksOrder []DBBooksOrder `dynamodbav:"booksOrder"`
+	Version    int            `dynamodbav:"version,omitempty"`
 }
 
 type DBBooksOrder struct {
@@ -103,11 +407,22 @@ func NewDBSeries(series Series) DBSeries {
 
 	return DBSeries{
 		ID:         series.ID,
+		Slug:       series.Slug,
 		Title:      series.Title,
 		BooksOrder: booksOrderList,
+		Version:    series.Version,
 	}
 }
 
+func newLinks(series Series) []relations.Link {
+	var links []relations.Link
+	for _, book := range series.Books {
+		links = append(links, relations.Link{BookID: book.ID, Order: book.Order})
+	}
+
+	return links
+}
+
 func (d *DBSeries) ToSeries() Series {
 	var booksList []BooksOrder
 
@@ -119,8 +434,10 @@ func (d *DBSeries) ToSeries() Series {
 	}
 
 	return Series{
-		ID:    d.ID,
-		Title: d.Title,
-		Books: booksList,
+		ID:      d.ID,
+		Slug:    d.Slug,
+		Title:   d.Title,
+		Books:   booksList,
+		Version: d.Version,
 	}
 }
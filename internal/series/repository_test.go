@@ -3,103 +3,159 @@ package series
 import (
 	"context"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/ggoulart/michael-connelly-api/internal/books"
 	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/ggoulart/michael-connelly-api/internal/memory"
+	"github.com/ggoulart/michael-connelly-api/internal/relations"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestRepository_Save(t *testing.T) {
 	ctx := context.Background()
+	item := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "new-series-id"},
+		"slug":  &types.AttributeValueMemberS{Value: "harry-bosch"},
+		"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"book_id": &types.AttributeValueMemberS{Value: "book-id-1"},
+				"order":   &types.AttributeValueMemberN{Value: "1"},
+			}},
+		}},
+	}
+	existingItem := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "series-id-1"},
+		"slug":    &types.AttributeValueMemberS{Value: "harry-bosch"},
+		"title":   &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"version": &types.AttributeValueMemberN{Value: "2"},
+		"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"book_id": &types.AttributeValueMemberS{Value: "book-id-1"},
+				"order":   &types.AttributeValueMemberN{Value: "1"},
+			}},
+		}},
+	}
+	bookLinks := []relations.Link{{BookID: "book-id-1", Order: 1}}
+	writes := []dynamo.Write{dynamo.DeleteWrite("relations", "link-id")}
+	saveWrites := append(slices.Clone(writes), dynamo.ClaimSlugWrite("series-table", "harry-bosch", "new-series-id"))
 	tests := []struct {
-		name    string
-		setup   func(*MockDynamoDBClient)
-		want    Series
-		wantErr error
+		name        string
+		policy      dynamo.DuplicatePolicy
+		setup       func(*MockDynamoDBClient, *LinksMock)
+		want        Series
+		wantCreated bool
+		wantErr     error
 	}{
 		{
-			name: "when failed to save series because already exists",
-			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{
-					"id":    &types.AttributeValueMemberS{Value: ""},
-					"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
-					"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
-						&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
-							"book_id": &types.AttributeValueMemberS{Value: "book-id-1"},
-							"order":   &types.AttributeValueMemberN{Value: "1"},
-						}},
-					}},
-				}
-				m.On("Save", ctx, "series-table", item, "Harry Bosch").Return("", dynamo.ErrDuplicated).Once()
-				output := map[string]types.AttributeValue{
-					"id":    &types.AttributeValueMemberS{Value: "series-id-1"},
-					"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
-					"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
-						&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
-							"book_id": &types.AttributeValueMemberS{Value: "book-id-1"},
-							"order":   &types.AttributeValueMemberN{Value: "1"},
-						}},
-					}},
-				}
-				m.On("GetByUniqueKey", ctx, "series-table", "Harry Bosch").Return(output, nil).Once()
+			name:   "when failed to save series because already exists",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("new-series-id").Once()
+				l.On("Writes", "series", "new-series-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "series-table", item, "Harry Bosch", saveWrites).Return("", &dynamo.DuplicatedError{ID: "series-id-1"}).Once()
 			},
-			want: Series{ID: "series-id-1", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id-1"}}}},
+			wantErr: &dynamo.DuplicatedError{ID: "series-id-1"},
 		},
 		{
-			name: "when failed to save series",
-			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{
-					"id":    &types.AttributeValueMemberS{Value: ""},
-					"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
-					"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
-						&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
-							"book_id": &types.AttributeValueMemberS{Value: "book-id-1"},
-							"order":   &types.AttributeValueMemberN{Value: "1"},
-						}},
-					}},
-				}
-				m.On("Save", ctx, "series-table", item, "Harry Bosch").Return("", assert.AnError).Once()
+			name:   "when series already exists and policy returns existing",
+			policy: dynamo.DuplicateReturnExisting,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("new-series-id").Once()
+				l.On("Writes", "series", "new-series-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "series-table", item, "Harry Bosch", saveWrites).Return("", &dynamo.DuplicatedError{ID: "series-id-1"}).Once()
+				m.On("GetByID", ctx, "series-table", "series-id-1").Return(existingItem, nil).Once()
+			},
+			want: Series{ID: "series-id-1", Slug: "harry-bosch", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id-1"}}}, Version: 2},
+		},
+		{
+			name:   "when series already exists and policy upserts",
+			policy: dynamo.DuplicateUpsert,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("new-series-id").Once()
+				l.On("Writes", "series", "new-series-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "series-table", item, "Harry Bosch", saveWrites).Return("", &dynamo.DuplicatedError{ID: "series-id-1"}).Once()
+				m.On("GetByID", ctx, "series-table", "series-id-1").Return(existingItem, nil).Twice()
+				updateItem := maps.Clone(item)
+				updateItem["id"] = &types.AttributeValueMemberS{Value: "series-id-1"}
+				updateItem["version"] = &types.AttributeValueMemberN{Value: "2"}
+				l.On("Writes", "series", "series-id-1", bookLinks, bookLinks).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Update", ctx, "series-table", "series-id-1", 2, updateItem, "Harry Bosch", "Harry Bosch", []dynamo.Write{dynamo.ClaimSlugWrite("series-table", "harry-bosch", "series-id-1")}).Return(nil).Once()
+			},
+			want: Series{ID: "series-id-1", Slug: "harry-bosch", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id-1", Title: "The Black Echo"}}}, Version: 3},
+		},
+		{
+			name:   "when failed to build link writes",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("new-series-id").Once()
+				l.On("Writes", "series", "new-series-id", []relations.Link(nil), bookLinks).Return([]dynamo.Write(nil), assert.AnError).Once()
 			},
 			wantErr: assert.AnError,
 		},
 		{
-			name: "when successfully saved series",
-			setup: func(m *MockDynamoDBClient) {
-				item := map[string]types.AttributeValue{
-					"id":    &types.AttributeValueMemberS{Value: ""},
-					"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
-					"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
-						&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
-							"book_id": &types.AttributeValueMemberS{Value: "book-id-1"},
-							"order":   &types.AttributeValueMemberN{Value: "1"},
-						}},
-					}},
-				}
-				m.On("Save", ctx, "series-table", item, "Harry Bosch").Return("series-id-1", nil).Once()
+			name:   "when slug is taken it is suffixed",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("new-series-id").Once()
+				l.On("Writes", "series", "new-series-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "series-table", item, "Harry Bosch", saveWrites).Return("", dynamo.ErrSlugTaken).Once()
+				suffixedItem := maps.Clone(item)
+				suffixedItem["slug"] = &types.AttributeValueMemberS{Value: "harry-bosch-2"}
+				suffixedWrites := append(slices.Clone(writes), dynamo.ClaimSlugWrite("series-table", "harry-bosch-2", "new-series-id"))
+				m.On("Save", ctx, "series-table", suffixedItem, "Harry Bosch", suffixedWrites).Return("new-series-id", nil).Once()
+			},
+			want:        Series{ID: "new-series-id", Slug: "harry-bosch-2", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id-1", Title: "The Black Echo"}}}, Version: 1},
+			wantCreated: true,
+		},
+		{
+			name:   "when failed to save series",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("new-series-id").Once()
+				l.On("Writes", "series", "new-series-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "series-table", item, "Harry Bosch", saveWrites).Return("", assert.AnError).Once()
 			},
-			want: Series{ID: "series-id-1", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id-1", Title: "The Black Echo"}}}},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when successfully saved series with its book links",
+			policy: dynamo.DuplicateReject,
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("NewID").Return("new-series-id").Once()
+				l.On("Writes", "series", "new-series-id", []relations.Link(nil), bookLinks).Return(writes, nil).Once()
+				m.On("Save", ctx, "series-table", item, "Harry Bosch", saveWrites).Return("new-series-id", nil).Once()
+			},
+			want:        Series{ID: "new-series-id", Slug: "harry-bosch", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id-1", Title: "The Black Echo"}}}, Version: 1},
+			wantCreated: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
-			tt.setup(mockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
 
-			r := NewRepository(mockDynamoDBClient, "series-table")
+			r := NewRepository(mockDynamoDBClient, "series-table", tt.policy, links)
 
-			got, err := r.Save(ctx, Series{
+			got, created, err := r.Save(ctx, Series{
 				Title: "Harry Bosch",
 				Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id-1", Title: "The Black Echo"}}},
 			})
 
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
 			assert.Equal(t, tt.wantErr, err)
 			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
 		})
 	}
 }
@@ -150,7 +206,7 @@ func TestRepository_GetByTitle(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
 
-			r := NewRepository(mockDynamoDBClient, "series-table")
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, new(LinksMock))
 
 			got, err := r.GetByTitle(ctx, "Harry Bosch")
 
@@ -161,6 +217,50 @@ func TestRepository_GetByTitle(t *testing.T) {
 	}
 }
 
+func TestRepository_GetBySlug(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient)
+		want    Series
+		wantErr error
+	}{
+		{
+			name: "when failed to get series by slug",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetBySlug", ctx, "series-table", "harry-bosch").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get series by slug",
+			setup: func(m *MockDynamoDBClient) {
+				output := map[string]types.AttributeValue{
+					"id":    &types.AttributeValueMemberS{Value: "series-id-1"},
+					"slug":  &types.AttributeValueMemberS{Value: "harry-bosch"},
+					"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
+				}
+				m.On("GetBySlug", ctx, "series-table", "harry-bosch").Return(output, nil).Once()
+			},
+			want: Series{ID: "series-id-1", Slug: "harry-bosch", Title: "Harry Bosch"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, new(LinksMock))
+
+			got, err := r.GetBySlug(ctx, "harry-bosch")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
 func TestRepository_GetAll(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
@@ -209,7 +309,7 @@ func TestRepository_GetAll(t *testing.T) {
 			mockDynamoDBClient := new(MockDynamoDBClient)
 			tt.setup(mockDynamoDBClient)
 
-			r := NewRepository(mockDynamoDBClient, "series-table")
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, new(LinksMock))
 
 			got, err := r.GetAll(ctx)
 
@@ -220,13 +320,501 @@ func TestRepository_GetAll(t *testing.T) {
 	}
 }
 
+func TestRepository_GetPage(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		setup      func(*MockDynamoDBClient)
+		want       []Series
+		wantCursor string
+		wantErr    error
+	}{
+		{
+			name: "when failed to get page of series",
+			setup: func(m *MockDynamoDBClient) {
+				m.On("GetPage", ctx, "series-table", int32(10), "a-cursor").Return([]map[string]types.AttributeValue(nil), "", assert.AnError).Once()
+			},
+			want:    []Series{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to unmarshal series",
+			setup: func(m *MockDynamoDBClient) {
+				output := map[string]types.AttributeValue{"title": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}}}
+				m.On("GetPage", ctx, "series-table", int32(10), "a-cursor").Return([]map[string]types.AttributeValue{output}, "", nil).Once()
+			},
+			want:    []Series{},
+			wantErr: fmt.Errorf("failed to unmarshal series: %w", &attributevalue.UnmarshalTypeError{Value: "map", Type: reflect.TypeOf("string")}),
+		},
+		{
+			name: "when sucessfull get page of series",
+			setup: func(m *MockDynamoDBClient) {
+				series1 := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "1"}, "title": &types.AttributeValueMemberS{Value: "Series One"}}
+				m.On("GetPage", ctx, "series-table", int32(10), "a-cursor").Return([]map[string]types.AttributeValue{series1}, "next-cursor", nil).Once()
+			},
+			want:       []Series{{ID: "1", Title: "Series One"}},
+			wantCursor: "next-cursor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			tt.setup(mockDynamoDBClient)
+
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, new(LinksMock))
+
+			got, gotCursor, err := r.GetPage(ctx, 10, "a-cursor")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCursor, gotCursor)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "the-harry-bosch-id"},
+		"slug":  &types.AttributeValueMemberS{Value: "harry-bosch"},
+		"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"book_id": &types.AttributeValueMemberS{Value: "the-black-echo-id"},
+			"order":   &types.AttributeValueMemberN{Value: "1"},
+		}}}},
+	}
+	claim := dynamo.ClaimSlugWrite("series-table", "harry-bosch", "the-harry-bosch-id")
+	tests := []struct {
+		name    string
+		series  Series
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		want    Series
+		wantErr error
+	}{
+		{
+			name:   "when failed to get current series",
+			series: Series{ID: "the-harry-bosch-id", Title: "Bosch"},
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "series-table", "the-harry-bosch-id").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when books are not sent they are kept",
+			series: Series{ID: "the-harry-bosch-id", Title: "Bosch"},
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "series-table", "the-harry-bosch-id").Return(current, nil).Once()
+				item := map[string]types.AttributeValue{
+					"id":         &types.AttributeValueMemberS{Value: "the-harry-bosch-id"},
+					"slug":       &types.AttributeValueMemberS{Value: "harry-bosch"},
+					"title":      &types.AttributeValueMemberS{Value: "Bosch"},
+					"booksOrder": current["booksOrder"],
+				}
+				bookLinks := []relations.Link{{BookID: "the-black-echo-id", Order: 1}}
+				l.On("Writes", "series", "the-harry-bosch-id", bookLinks, bookLinks).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Update", ctx, "series-table", "the-harry-bosch-id", 0, item, "Harry Bosch", "Bosch", []dynamo.Write{claim}).Return(nil).Once()
+			},
+			want: Series{ID: "the-harry-bosch-id", Slug: "harry-bosch", Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "the-black-echo-id"}}}, Version: 1},
+		},
+		{
+			name:   "when failed to update series",
+			series: Series{ID: "the-harry-bosch-id", Title: "Harry Bosch", Books: []BooksOrder{}},
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "series-table", "the-harry-bosch-id").Return(current, nil).Once()
+				item := map[string]types.AttributeValue{
+					"id":         &types.AttributeValueMemberS{Value: "the-harry-bosch-id"},
+					"slug":       &types.AttributeValueMemberS{Value: "harry-bosch"},
+					"title":      &types.AttributeValueMemberS{Value: "Harry Bosch"},
+					"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+				}
+				writes := []dynamo.Write{dynamo.DeleteWrite("relations", "series#the-harry-bosch-id#the-black-echo-id")}
+				l.On("Writes", "series", "the-harry-bosch-id", []relations.Link{{BookID: "the-black-echo-id", Order: 1}}, []relations.Link(nil)).Return(writes, nil).Once()
+				m.On("Update", ctx, "series-table", "the-harry-bosch-id", 0, item, "Harry Bosch", "Harry Bosch", append(slices.Clone(writes), claim)).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:   "when failed to build link writes",
+			series: Series{ID: "the-harry-bosch-id", Title: "Harry Bosch", Books: []BooksOrder{}},
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "series-table", "the-harry-bosch-id").Return(current, nil).Once()
+				l.On("Writes", "series", "the-harry-bosch-id", []relations.Link{{BookID: "the-black-echo-id", Order: 1}}, []relations.Link(nil)).Return([]dynamo.Write(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, links)
+
+			got, err := r.Update(ctx, tt.series)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get current series",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetByID", ctx, "series-table", "the-harry-bosch-id").Return(map[string]types.AttributeValue{}, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully deleted series",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				current := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "the-harry-bosch-id"}, "slug": &types.AttributeValueMemberS{Value: "harry-bosch"}, "title": &types.AttributeValueMemberS{Value: "Harry Bosch"}}
+				m.On("GetByID", ctx, "series-table", "the-harry-bosch-id").Return(current, nil).Once()
+				l.On("Writes", "series", "the-harry-bosch-id", []relations.Link(nil), []relations.Link(nil)).Return([]dynamo.Write(nil), nil).Once()
+				m.On("Delete", ctx, "series-table", "the-harry-bosch-id", "Harry Bosch", []dynamo.Write{dynamo.ReleaseSlugWrite("series-table", "harry-bosch", "the-harry-bosch-id")}).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, links)
+
+			err := r.Delete(ctx, "the-harry-bosch-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_HasBook(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		want    bool
+		wantErr error
+	}{
+		{
+			name: "when failed to get book links",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when no series references the book",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link(nil), nil).Once()
+			},
+		},
+		{
+			name: "when a series references the book",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link{{Owner: "series", OwnerID: "series-id", BookID: "book-id", Order: 1}}, nil).Once()
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, links)
+
+			got, err := r.HasBook(ctx, "book-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_RemoveBook(t *testing.T) {
+	ctx := context.Background()
+	series := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "series-id"},
+		"title":   &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"version": &types.AttributeValueMemberN{Value: "4"},
+		"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id"}, "order": &types.AttributeValueMemberN{Value: "1"}}},
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "other-book-id"}, "order": &types.AttributeValueMemberN{Value: "2"}}},
+		}},
+	}
+	updated := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "series-id"},
+		"title":   &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"version": &types.AttributeValueMemberN{Value: "4"},
+		"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "other-book-id"}, "order": &types.AttributeValueMemberN{Value: "2"}}},
+		}},
+	}
+	currentLinks := []relations.Link{{BookID: "book-id", Order: 1}, {BookID: "other-book-id", Order: 2}}
+	remainingLinks := []relations.Link{{BookID: "other-book-id", Order: 2}}
+	writes := []dynamo.Write{dynamo.DeleteWrite("relations", "series#series-id#book-id")}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get series by book",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to update series",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link{{Owner: "series", OwnerID: "series-id", BookID: "book-id", Order: 1}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "series-table", []string{"series-id"}).Return([]map[string]types.AttributeValue{series}, nil).Once()
+				l.On("Writes", "series", "series-id", currentLinks, remainingLinks).Return(writes, nil).Once()
+				m.On("Update", ctx, "series-table", "series-id", 4, updated, "Harry Bosch", "Harry Bosch", writes).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully removed book from series",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link{{Owner: "series", OwnerID: "series-id", BookID: "book-id", Order: 1}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "series-table", []string{"series-id"}).Return([]map[string]types.AttributeValue{series}, nil).Once()
+				l.On("Writes", "series", "series-id", currentLinks, remainingLinks).Return(writes, nil).Once()
+				m.On("Update", ctx, "series-table", "series-id", 4, updated, "Harry Bosch", "Harry Bosch", writes).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, links)
+
+			err := r.RemoveBook(ctx, "book-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_RelinkBooks(t *testing.T) {
+	ctx := context.Background()
+	seriesItem := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "series-id"},
+		"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id"}, "order": &types.AttributeValueMemberN{Value: "1"}}},
+		}},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to get all series",
+			setup: func(m *MockDynamoDBClient, _ *LinksMock) {
+				m.On("GetAll", ctx, "series-table").Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to link series books",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetAll", ctx, "series-table").Return([]map[string]types.AttributeValue{seriesItem}, nil).Once()
+				l.On("Replace", ctx, "series", "series-id", []relations.Link{{BookID: "book-id", Order: 1}}).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully relinked series books",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				m.On("GetAll", ctx, "series-table").Return([]map[string]types.AttributeValue{seriesItem}, nil).Once()
+				l.On("Replace", ctx, "series", "series-id", []relations.Link{{BookID: "book-id", Order: 1}}).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, links)
+
+			err := r.RelinkBooks(ctx)
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_GetByBook(t *testing.T) {
+	ctx := context.Background()
+	seriesItem := map[string]types.AttributeValue{
+		"id":    &types.AttributeValueMemberS{Value: "series-id"},
+		"title": &types.AttributeValueMemberS{Value: "Harry Bosch"},
+		"booksOrder": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: "book-id"}, "order": &types.AttributeValueMemberN{Value: "3"}}},
+		}},
+	}
+	tests := []struct {
+		name    string
+		setup   func(*MockDynamoDBClient, *LinksMock)
+		want    []Series
+		wantErr error
+	}{
+		{
+			name: "when failed to get book links",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link(nil), assert.AnError).Once()
+			},
+			want:    []Series{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when no series references the book",
+			setup: func(_ *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link(nil), nil).Once()
+			},
+			want: []Series{},
+		},
+		{
+			name: "when failed to get series",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link{{Owner: "series", OwnerID: "series-id", BookID: "book-id", Order: 3}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "series-table", []string{"series-id"}).Return([]map[string]types.AttributeValue(nil), assert.AnError).Once()
+			},
+			want:    []Series{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successfully get series by book",
+			setup: func(m *MockDynamoDBClient, l *LinksMock) {
+				l.On("GetByBook", ctx, "series", "book-id").Return([]relations.Link{{Owner: "series", OwnerID: "series-id", BookID: "book-id", Order: 3}}, nil).Once()
+				m.On("BatchGetByIDs", ctx, "series-table", []string{"series-id"}).Return([]map[string]types.AttributeValue{seriesItem}, nil).Once()
+			},
+			want: []Series{{ID: "series-id", Title: "Harry Bosch", Books: []BooksOrder{{Order: 3, Book: books.Book{ID: "book-id"}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamoDBClient := new(MockDynamoDBClient)
+			links := new(LinksMock)
+			tt.setup(mockDynamoDBClient, links)
+
+			r := NewRepository(mockDynamoDBClient, "series-table", dynamo.DuplicateReject, links)
+
+			got, err := r.GetByBook(ctx, "book-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			mockDynamoDBClient.AssertExpectations(t)
+			links.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_LinkWritesAtTransactionLimit(t *testing.T) {
+	ctx := context.Background()
+	seriesBooks := func(count int) []BooksOrder {
+		var booksOrder []BooksOrder
+		for i := range count {
+			booksOrder = append(booksOrder, BooksOrder{Order: i + 1, Book: books.Book{ID: fmt.Sprintf("book-%d", i)}})
+		}
+		return booksOrder
+	}
+	bookIDs := func(booksOrder []BooksOrder) []string {
+		var ids []string
+		for _, book := range booksOrder {
+			ids = append(ids, book.ID)
+		}
+		return ids
+	}
+	tests := []struct {
+		name    string
+		books   int
+		updated int
+	}{
+		{name: "when links fit in the owner transaction", books: 49, updated: 48},
+		{name: "when links are one past the owner transaction", books: 50, updated: 49},
+		{name: "when links span several transactions", books: 120, updated: 130},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := memory.NewClient(uuid.New)
+			r := NewRepository(client, "series", dynamo.DuplicateReject, relations.NewRepository(client, "relations", "books"))
+
+			series, _, err := r.Save(ctx, Series{Title: "Harry Bosch", Books: seriesBooks(tt.books)})
+			assert.NoError(t, err)
+			references, err := client.GetReferences(ctx, "books", bookIDs(seriesBooks(tt.books)))
+			assert.NoError(t, err)
+			assert.Len(t, references, tt.books)
+
+			series.Books = seriesBooks(tt.updated)
+			slices.Reverse(series.Books)
+			_, err = r.Update(ctx, series)
+			assert.NoError(t, err)
+			references, err = client.GetReferences(ctx, "books", bookIDs(seriesBooks(max(tt.books, tt.updated))))
+			assert.NoError(t, err)
+			assert.Len(t, references, tt.updated)
+			got, err := r.GetById(ctx, series.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, series.Books, got.Books)
+			hasBook, err := r.HasBook(ctx, fmt.Sprintf("book-%d", tt.updated-1))
+			assert.NoError(t, err)
+			assert.True(t, hasBook)
+
+			err = r.Delete(ctx, series.ID)
+			assert.NoError(t, err)
+			references, err = client.GetReferences(ctx, "books", bookIDs(seriesBooks(max(tt.books, tt.updated))))
+			assert.NoError(t, err)
+			assert.Empty(t, references)
+			hasBook, err = r.HasBook(ctx, "book-0")
+			assert.NoError(t, err)
+			assert.False(t, hasBook)
+		})
+	}
+}
+
 type MockDynamoDBClient struct {
 	DynamoDBClient
 	mock.Mock
 }
 
-func (m *MockDynamoDBClient) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string) (string, error) {
-	args := m.Called(ctx, tableName, item, uniqueKey)
+func (m *MockDynamoDBClient) NewID() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockDynamoDBClient) Save(ctx context.Context, tableName string, item map[string]types.AttributeValue, uniqueKey string, writes ...dynamo.Write) (string, error) {
+	args := m.Called(ctx, tableName, item, uniqueKey, writes)
 	return args.String(0), args.Error(1)
 }
 
@@ -235,7 +823,61 @@ func (m *MockDynamoDBClient) GetByUniqueKey(ctx context.Context, tableName strin
 	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
 }
 
+func (m *MockDynamoDBClient) GetBySlug(ctx context.Context, tableName string, slug string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, slug)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
 func (m *MockDynamoDBClient) GetAll(ctx context.Context, tableName string) ([]map[string]types.AttributeValue, error) {
 	args := m.Called(ctx, tableName)
 	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
 }
+
+func (m *MockDynamoDBClient) GetPage(ctx context.Context, tableName string, limit int32, cursor string) ([]map[string]types.AttributeValue, string, error) {
+	args := m.Called(ctx, tableName, limit, cursor)
+	return args.Get(0).([]map[string]types.AttributeValue), args.String(1), args.Error(2)
+}
+
+func (m *MockDynamoDBClient) Update(ctx context.Context, tableName string, id string, version int, item map[string]types.AttributeValue, oldUniqueKey string, newUniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, version, item, oldUniqueKey, newUniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) GetByID(ctx context.Context, tableName string, id string) (map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, id)
+	return args.Get(0).(map[string]types.AttributeValue), args.Error(1)
+}
+
+func (m *MockDynamoDBClient) Delete(ctx context.Context, tableName string, id string, uniqueKey string, writes ...dynamo.Write) error {
+	args := m.Called(ctx, tableName, id, uniqueKey, writes)
+	return args.Error(0)
+}
+
+func (m *MockDynamoDBClient) BatchGetByIDs(ctx context.Context, tableName string, ids []string) ([]map[string]types.AttributeValue, error) {
+	args := m.Called(ctx, tableName, ids)
+	return args.Get(0).([]map[string]types.AttributeValue), args.Error(1)
+}
+
+type LinksMock struct {
+	mock.Mock
+}
+
+func (l *LinksMock) Writes(owner string, ownerID string, current []relations.Link, links []relations.Link) ([]dynamo.Write, error) {
+	args := l.Called(owner, ownerID, current, links)
+	return args.Get(0).([]dynamo.Write), args.Error(1)
+}
+
+func (l *LinksMock) Replace(ctx context.Context, owner string, ownerID string, links []relations.Link) error {
+	args := l.Called(ctx, owner, ownerID, links)
+	return args.Error(0)
+}
+
+func (l *LinksMock) GetByBook(ctx context.Context, owner string, bookID string) ([]relations.Link, error) {
+	args := l.Called(ctx, owner, bookID)
+	return args.Get(0).([]relations.Link), args.Error(1)
+}
+
+func (l *LinksMock) Apply(ctx context.Context, writes []dynamo.Write) error {
+	args := l.Called(ctx, writes)
+	return args.Error(0)
+}
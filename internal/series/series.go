@@ -3,9 +3,11 @@ package series
 import "github.com/ggoulart/michael-connelly-api/internal/books"
 
 type Series struct {
-	ID    string
-	Title string
-	Books []BooksOrder
+	ID      string
+	Slug    string
+	Title   string
+	Books   []BooksOrder
+	Version int
 }
 
 type BooksOrder struct {
@@ -4,47 +4,155 @@ import (
 	"context"
 
 	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/search"
 )
 
+const searchDocumentType = "series"
+
 type StorageSeries interface {
-	Save(ctx context.Context, series Series) (Series, error)
+	Save(ctx context.Context, series Series) (Series, bool, error)
+	Update(ctx context.Context, series Series) (Series, error)
+	Delete(ctx context.Context, seriesID string) error
+	GetById(ctx context.Context, seriesID string) (Series, error)
 	GetByTitle(ctx context.Context, title string) (Series, error)
+	GetBySlug(ctx context.Context, slug string) (Series, error)
+	GetByBook(ctx context.Context, bookID string) ([]Series, error)
 	GetAll(ctx context.Context) ([]Series, error)
+	GetPage(ctx context.Context, limit int32, cursor string) ([]Series, string, error)
 }
 
 type StorageBook interface {
-	GetById(ctx context.Context, bookID string) (books.Book, error)
-	GetByTitle(ctx context.Context, bookTitle string) (books.Book, error)
+	GetByIds(ctx context.Context, bookIDs []string) ([]books.Book, error)
+	GetBookListByTitles(ctx context.Context, bookTitles []string) ([]books.Book, error)
 }
 
 type Service struct {
 	storageSeries StorageSeries
 	storageBook   StorageBook
+	indexer       search.Indexer
 }
 
-func NewService(storageSeries StorageSeries, storageBook StorageBook) *Service {
-	return &Service{storageSeries: storageSeries, storageBook: storageBook}
+func NewService(storageSeries StorageSeries, storageBook StorageBook, indexer search.Indexer) *Service {
+	return &Service{storageSeries: storageSeries, storageBook: storageBook, indexer: indexer}
 }
 
-func (s *Service) Create(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, error) {
-	for _, bookOrder := range booksOrderList {
-		book, err := s.storageBook.GetByTitle(ctx, bookOrder.Book.Title)
+func (s *Service) Create(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, bool, error) {
+	seriesBooks, err := s.getBooks(ctx, booksOrderList)
+	if err != nil {
+		return Series{}, false, err
+	}
+
+	series.Books = append(series.Books, seriesBooks...)
+
+	savedSeries, created, err := s.storageSeries.Save(ctx, series)
+	if err != nil {
+		return Series{}, false, err
+	}
+
+	s.indexer.Put(newSearchDocument(savedSeries))
+
+	if !created {
+		seriesList := []Series{savedSeries}
+		err = s.loadBooks(ctx, seriesList)
+		if err != nil {
+			return Series{}, false, err
+		}
+
+		savedSeries = seriesList[0]
+	}
+
+	return savedSeries, created, nil
+}
+
+func (s *Service) Update(ctx context.Context, series Series, booksOrderList []BooksOrder) (Series, error) {
+	if booksOrderList != nil {
+		seriesBooks, err := s.getBooks(ctx, booksOrderList)
 		if err != nil {
 			return Series{}, err
 		}
 
-		series.Books = append(series.Books, BooksOrder{
-			Order: bookOrder.Order,
-			Book:  book,
-		})
+		series.Books = seriesBooks
+	}
+
+	updatedSeries, err := s.storageSeries.Update(ctx, series)
+	if err != nil {
+		return Series{}, err
+	}
+
+	s.indexer.Put(newSearchDocument(updatedSeries))
+
+	seriesList := []Series{updatedSeries}
+	err = s.loadBooks(ctx, seriesList)
+	if err != nil {
+		return Series{}, err
+	}
+
+	return seriesList[0], nil
+}
+
+func (s *Service) Delete(ctx context.Context, seriesID string) error {
+	err := s.storageSeries.Delete(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	s.indexer.Remove(searchDocumentType, seriesID)
+
+	return nil
+}
+
+func (s *Service) GetById(ctx context.Context, seriesID string) (Series, error) {
+	series, err := s.storageSeries.GetById(ctx, seriesID)
+	if err != nil {
+		return Series{}, err
+	}
+
+	seriesList := []Series{series}
+	err = s.loadBooks(ctx, seriesList)
+	if err != nil {
+		return Series{}, err
+	}
+
+	return seriesList[0], nil
+}
+
+func (s *Service) GetBySlug(ctx context.Context, slug string) (Series, error) {
+	series, err := s.storageSeries.GetBySlug(ctx, slug)
+	if err != nil {
+		return Series{}, err
+	}
+
+	seriesList := []Series{series}
+	err = s.loadBooks(ctx, seriesList)
+	if err != nil {
+		return Series{}, err
+	}
+
+	return seriesList[0], nil
+}
+
+func (s *Service) GetByTitle(ctx context.Context, title string) (Series, error) {
+	series, err := s.storageSeries.GetByTitle(ctx, title)
+	if err != nil {
+		return Series{}, err
 	}
 
-	savedSeries, err := s.storageSeries.Save(ctx, series)
+	seriesList := []Series{series}
+	err = s.loadBooks(ctx, seriesList)
 	if err != nil {
 		return Series{}, err
 	}
 
-	return savedSeries, nil
+	return seriesList[0], nil
+}
+
+func (s *Service) GetByBook(ctx context.Context, bookID string) ([]Series, error) {
+	seriesList, err := s.storageSeries.GetByBook(ctx, bookID)
+	if err != nil {
+		return []Series{}, err
+	}
+
+	return seriesList, nil
 }
 
 func (s *Service) GetAll(ctx context.Context) ([]Series, error) {
@@ -53,16 +161,104 @@ func (s *Service) GetAll(ctx context.Context) ([]Series, error) {
 		return []Series{}, err
 	}
 
+	err = s.loadBooks(ctx, seriesList)
+	if err != nil {
+		return []Series{}, err
+	}
+
+	return seriesList, nil
+}
+
+func (s *Service) GetPage(ctx context.Context, limit int32, cursor string) ([]Series, string, error) {
+	seriesList, nextCursor, err := s.storageSeries.GetPage(ctx, limit, cursor)
+	if err != nil {
+		return []Series{}, "", err
+	}
+
+	err = s.loadBooks(ctx, seriesList)
+	if err != nil {
+		return []Series{}, "", err
+	}
+
+	return seriesList, nextCursor, nil
+}
+
+func (s *Service) Documents(ctx context.Context) ([]search.Document, error) {
+	seriesList, err := s.storageSeries.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []search.Document
 	for _, series := range seriesList {
-		for i := range series.Books {
-			book, err := s.storageBook.GetById(ctx, series.Books[i].ID)
-			if err != nil {
-				return []Series{}, err
-			}
+		documents = append(documents, newSearchDocument(series))
+	}
+
+	return documents, nil
+}
 
-			series.Books[i].Book = book
+func (s *Service) getBooks(ctx context.Context, booksOrderList []BooksOrder) ([]BooksOrder, error) {
+	seriesBooks := []BooksOrder{}
+	if len(booksOrderList) == 0 {
+		return seriesBooks, nil
+	}
+
+	var bookTitles []string
+	for _, bookOrder := range booksOrderList {
+		bookTitles = append(bookTitles, bookOrder.Book.Title)
+	}
+
+	booksList, err := s.storageBook.GetBookListByTitles(ctx, bookTitles)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, bookOrder := range booksOrderList {
+		seriesBooks = append(seriesBooks, BooksOrder{
+			Order: bookOrder.Order,
+			Book:  booksList[i],
+		})
+	}
+
+	return seriesBooks, nil
+}
+
+func (s *Service) loadBooks(ctx context.Context, seriesList []Series) error {
+	var bookIDs []string
+	for _, series := range seriesList {
+		for _, book := range series.Books {
+			bookIDs = append(bookIDs, book.ID)
 		}
 	}
 
-	return seriesList, nil
+	if len(bookIDs) == 0 {
+		return nil
+	}
+
+	booksList, err := s.storageBook.GetByIds(ctx, bookIDs)
+	if err != nil {
+		return err
+	}
+
+	booksByID := map[string]books.Book{}
+	for _, book := range booksList {
+		booksByID[book.ID] = book
+	}
+
+	for i, series := range seriesList {
+		var seriesBooks []BooksOrder
+		for _, book := range series.Books {
+			if loaded, ok := booksByID[book.ID]; ok {
+				book.Book = loaded
+				seriesBooks = append(seriesBooks, book)
+			}
+		}
+		seriesList[i].Books = seriesBooks
+	}
+
+	return nil
+}
+
+func newSearchDocument(series Series) search.Document {
+	return search.Document{Type: searchDocumentType, ID: series.ID, Title: series.Title}
 }
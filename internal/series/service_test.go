@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ggoulart/michael-connelly-api/internal/books"
+	"github.com/ggoulart/michael-connelly-api/internal/search"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,35 +13,64 @@ import (
 func TestService_Create(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
-		name    string
-		setup   func(*StorageSeriesMock, *StorageBookMock)
-		want    Series
-		wantErr error
+		name        string
+		setup       func(*StorageSeriesMock, *StorageBookMock, *IndexerMock)
+		want        Series
+		wantCreated bool
+		wantErr     error
 	}{
 		{
 			name: "when failed to get book by title",
-			setup: func(_ *StorageSeriesMock, b *StorageBookMock) {
-				b.On("GetByTitle", ctx, "The Black Echo").Return(books.Book{}, assert.AnError)
+			setup: func(_ *StorageSeriesMock, b *StorageBookMock, _ *IndexerMock) {
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{}, assert.AnError)
 			},
 			wantErr: assert.AnError,
 		},
 		{
 			name: "when failed to save series",
-			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+			setup: func(s *StorageSeriesMock, b *StorageBookMock, _ *IndexerMock) {
 				getByTitleOutput := books.Book{Title: "The Black Echo"}
-				b.On("GetByTitle", ctx, "The Black Echo").Return(getByTitleOutput, nil)
-				s.On("Save", ctx, Series{Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: getByTitleOutput}}}).Return(Series{}, assert.AnError)
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{getByTitleOutput}, nil)
+				s.On("Save", ctx, Series{Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: getByTitleOutput}}}).Return(Series{}, false, assert.AnError)
 			},
 			wantErr: assert.AnError,
 		},
 		{
 			name: "when successful to save series",
-			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+			setup: func(s *StorageSeriesMock, b *StorageBookMock, i *IndexerMock) {
 				getByTitleOutput := books.Book{ID: "the-black-echo-book-id", Title: "The Black Echo"}
-				b.On("GetByTitle", ctx, "The Black Echo").Return(getByTitleOutput, nil)
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{getByTitleOutput}, nil)
 				saveInput := Series{Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: getByTitleOutput}}}
 				savedSeries := Series{ID: "harry-bosch-series-id", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: getByTitleOutput}}}
-				s.On("Save", ctx, saveInput).Return(savedSeries, nil)
+				s.On("Save", ctx, saveInput).Return(savedSeries, true, nil)
+				i.On("Put", search.Document{Type: "series", ID: "harry-bosch-series-id", Title: "Harry Bosch"})
+			},
+			want:        Series{ID: "harry-bosch-series-id", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "the-black-echo-book-id", Title: "The Black Echo"}}}},
+			wantCreated: true,
+		},
+		{
+			name: "when failed to load books of existing series",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock, i *IndexerMock) {
+				getByTitleOutput := books.Book{ID: "the-black-echo-book-id", Title: "The Black Echo"}
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{getByTitleOutput}, nil)
+				saveInput := Series{Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: getByTitleOutput}}}
+				existingSeries := Series{ID: "harry-bosch-series-id", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "the-black-echo-book-id"}}}}
+				s.On("Save", ctx, saveInput).Return(existingSeries, false, nil)
+				i.On("Put", search.Document{Type: "series", ID: "harry-bosch-series-id", Title: "Harry Bosch"})
+				b.On("GetByIds", ctx, []string{"the-black-echo-book-id"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when series already exists",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock, i *IndexerMock) {
+				getByTitleOutput := books.Book{ID: "the-black-echo-book-id", Title: "The Black Echo"}
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{getByTitleOutput}, nil)
+				saveInput := Series{Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: getByTitleOutput}}}
+				existingSeries := Series{ID: "harry-bosch-series-id", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "the-black-echo-book-id"}}}}
+				s.On("Save", ctx, saveInput).Return(existingSeries, false, nil)
+				i.On("Put", search.Document{Type: "series", ID: "harry-bosch-series-id", Title: "Harry Bosch"})
+				b.On("GetByIds", ctx, []string{"the-black-echo-book-id"}).Return([]books.Book{getByTitleOutput}, nil)
 			},
 			want: Series{ID: "harry-bosch-series-id", Title: "Harry Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "the-black-echo-book-id", Title: "The Black Echo"}}}},
 		},
@@ -49,16 +79,19 @@ func TestService_Create(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			storageSeries := new(StorageSeriesMock)
 			storageBook := new(StorageBookMock)
-			tt.setup(storageSeries, storageBook)
+			indexer := new(IndexerMock)
+			tt.setup(storageSeries, storageBook, indexer)
 
-			s := NewService(storageSeries, storageBook)
+			s := NewService(storageSeries, storageBook, indexer)
 
-			got, err := s.Create(ctx, Series{Title: "Harry Bosch"}, []BooksOrder{{Order: 1, Book: books.Book{Title: "The Black Echo"}}})
+			got, created, err := s.Create(ctx, Series{Title: "Harry Bosch"}, []BooksOrder{{Order: 1, Book: books.Book{Title: "The Black Echo"}}})
 
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCreated, created)
 			assert.Equal(t, tt.wantErr, err)
 			storageSeries.AssertExpectations(t)
 			storageBook.AssertExpectations(t)
+			indexer.AssertExpectations(t)
 		})
 	}
 }
@@ -83,7 +116,7 @@ func TestService_GetAll(t *testing.T) {
 			name: "when failed to get book by id",
 			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
 				s.On("GetAll", ctx).Return([]Series{{Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}}, nil)
-				b.On("GetById", ctx, "123").Return(books.Book{}, assert.AnError)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{}, assert.AnError)
 			},
 			want:    []Series{},
 			wantErr: assert.AnError,
@@ -92,10 +125,25 @@ func TestService_GetAll(t *testing.T) {
 			name: "when successful to get all series",
 			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
 				s.On("GetAll", ctx).Return([]Series{{Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}}, nil)
-				b.On("GetById", ctx, "123").Return(books.Book{ID: "123", Title: "The Black Echo", Year: 0}, nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{{ID: "123", Title: "The Black Echo", Year: 0}}, nil)
 			},
 			want: []Series{{Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123", Title: "The Black Echo"}}}}},
 		},
+		{
+			name: "when books of every series are loaded in a single batch",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				seriesList := []Series{
+					{Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}, {Order: 2, Book: books.Book{ID: "456"}}}},
+					{Title: "Lincoln Lawyer", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "789"}}}},
+				}
+				s.On("GetAll", ctx).Return(seriesList, nil)
+				b.On("GetByIds", ctx, []string{"123", "456", "789"}).Return([]books.Book{{ID: "123", Title: "The Black Echo"}, {ID: "456", Title: "The Black Ice"}, {ID: "789", Title: "The Lincoln Lawyer"}}, nil).Once()
+			},
+			want: []Series{
+				{Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123", Title: "The Black Echo"}}, {Order: 2, Book: books.Book{ID: "456", Title: "The Black Ice"}}}},
+				{Title: "Lincoln Lawyer", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "789", Title: "The Lincoln Lawyer"}}}},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -103,7 +151,7 @@ func TestService_GetAll(t *testing.T) {
 			storageBook := new(StorageBookMock)
 			tt.setup(storageSeries, storageBook)
 
-			s := NewService(storageSeries, storageBook)
+			s := NewService(storageSeries, storageBook, new(IndexerMock))
 
 			got, err := s.GetAll(ctx)
 
@@ -115,37 +163,459 @@ func TestService_GetAll(t *testing.T) {
 	}
 }
 
+func TestService_GetPage(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		setup      func(*StorageSeriesMock, *StorageBookMock)
+		want       []Series
+		wantCursor string
+		wantErr    error
+	}{
+		{
+			name: "when failed to get page of series",
+			setup: func(s *StorageSeriesMock, _ *StorageBookMock) {
+				s.On("GetPage", ctx, int32(10), "a-cursor").Return([]Series{}, "", assert.AnError)
+			},
+			want:    []Series{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to get book by id",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				s.On("GetPage", ctx, int32(10), "a-cursor").Return([]Series{{Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}}, "next-cursor", nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{}, assert.AnError)
+			},
+			want:    []Series{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successful to get page of series",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				s.On("GetPage", ctx, int32(10), "a-cursor").Return([]Series{{Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}}, "next-cursor", nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{{ID: "123", Title: "The Black Echo"}}, nil)
+			},
+			want:       []Series{{Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123", Title: "The Black Echo"}}}}},
+			wantCursor: "next-cursor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageSeries := new(StorageSeriesMock)
+			storageBook := new(StorageBookMock)
+			tt.setup(storageSeries, storageBook)
+
+			s := NewService(storageSeries, storageBook, new(IndexerMock))
+
+			got, gotCursor, err := s.GetPage(ctx, 10, "a-cursor")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCursor, gotCursor)
+			assert.Equal(t, tt.wantErr, err)
+			storageSeries.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		booksOrderList []BooksOrder
+		setup          func(*StorageSeriesMock, *StorageBookMock, *IndexerMock)
+		want           Series
+		wantErr        error
+	}{
+		{
+			name:           "when failed to get book by title",
+			booksOrderList: []BooksOrder{{Order: 1, Book: books.Book{Title: "The Black Echo"}}},
+			setup: func(_ *StorageSeriesMock, b *StorageBookMock, _ *IndexerMock) {
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:           "when failed to update series",
+			booksOrderList: []BooksOrder{{Order: 1, Book: books.Book{Title: "The Black Echo"}}},
+			setup: func(s *StorageSeriesMock, b *StorageBookMock, _ *IndexerMock) {
+				book := books.Book{ID: "123", Title: "The Black Echo"}
+				b.On("GetBookListByTitles", ctx, []string{"The Black Echo"}).Return([]books.Book{book}, nil)
+				s.On("Update", ctx, Series{ID: "the-harry-bosch-id", Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: book}}}).Return(Series{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when books are not sent",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock, i *IndexerMock) {
+				s.On("Update", ctx, Series{ID: "the-harry-bosch-id", Title: "Bosch"}).Return(Series{ID: "the-harry-bosch-id", Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}, nil)
+				i.On("Put", search.Document{Type: "series", ID: "the-harry-bosch-id", Title: "Bosch"})
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{{ID: "123", Title: "The Black Echo"}}, nil)
+			},
+			want: Series{ID: "the-harry-bosch-id", Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123", Title: "The Black Echo"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageSeries := new(StorageSeriesMock)
+			storageBook := new(StorageBookMock)
+			indexer := new(IndexerMock)
+			tt.setup(storageSeries, storageBook, indexer)
+
+			s := NewService(storageSeries, storageBook, indexer)
+
+			got, err := s.Update(ctx, Series{ID: "the-harry-bosch-id", Title: "Bosch"}, tt.booksOrderList)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageSeries.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+			indexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetById(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageSeriesMock, *StorageBookMock)
+		want    Series
+		wantErr error
+	}{
+		{
+			name: "when failed to get series",
+			setup: func(s *StorageSeriesMock, _ *StorageBookMock) {
+				s.On("GetById", ctx, "the-harry-bosch-id").Return(Series{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to get book by id",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				s.On("GetById", ctx, "the-harry-bosch-id").Return(Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}, nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successful to get series",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				s.On("GetById", ctx, "the-harry-bosch-id").Return(Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}, nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{{ID: "123", Title: "The Black Echo"}}, nil)
+			},
+			want: Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123", Title: "The Black Echo"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageSeries := new(StorageSeriesMock)
+			storageBook := new(StorageBookMock)
+			tt.setup(storageSeries, storageBook)
+
+			s := NewService(storageSeries, storageBook, new(IndexerMock))
+
+			got, err := s.GetById(ctx, "the-harry-bosch-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageSeries.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetBySlug(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageSeriesMock, *StorageBookMock)
+		want    Series
+		wantErr error
+	}{
+		{
+			name: "when failed to get series",
+			setup: func(s *StorageSeriesMock, _ *StorageBookMock) {
+				s.On("GetBySlug", ctx, "harry-bosch").Return(Series{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to get book by id",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				s.On("GetBySlug", ctx, "harry-bosch").Return(Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}, nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successful to get series",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				s.On("GetBySlug", ctx, "harry-bosch").Return(Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}, nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{{ID: "123", Title: "The Black Echo"}}, nil)
+			},
+			want: Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123", Title: "The Black Echo"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageSeries := new(StorageSeriesMock)
+			storageBook := new(StorageBookMock)
+			tt.setup(storageSeries, storageBook)
+
+			s := NewService(storageSeries, storageBook, new(IndexerMock))
+
+			got, err := s.GetBySlug(ctx, "harry-bosch")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageSeries.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetByTitle(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageSeriesMock, *StorageBookMock)
+		want    Series
+		wantErr error
+	}{
+		{
+			name: "when failed to get series",
+			setup: func(s *StorageSeriesMock, _ *StorageBookMock) {
+				s.On("GetByTitle", ctx, "Harry Bosch").Return(Series{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when failed to get book by id",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				s.On("GetByTitle", ctx, "Harry Bosch").Return(Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}, nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successful to get series",
+			setup: func(s *StorageSeriesMock, b *StorageBookMock) {
+				s.On("GetByTitle", ctx, "Harry Bosch").Return(Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}, nil)
+				b.On("GetByIds", ctx, []string{"123"}).Return([]books.Book{{ID: "123", Title: "The Black Echo"}}, nil)
+			},
+			want: Series{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123", Title: "The Black Echo"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageSeries := new(StorageSeriesMock)
+			storageBook := new(StorageBookMock)
+			tt.setup(storageSeries, storageBook)
+
+			s := NewService(storageSeries, storageBook, new(IndexerMock))
+
+			got, err := s.GetByTitle(ctx, "Harry Bosch")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageSeries.AssertExpectations(t)
+			storageBook.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetByBook(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageSeriesMock)
+		want    []Series
+		wantErr error
+	}{
+		{
+			name: "when failed to get series by book",
+			setup: func(s *StorageSeriesMock) {
+				s.On("GetByBook", ctx, "book-id").Return([]Series{}, assert.AnError)
+			},
+			want:    []Series{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successful to get series by book",
+			setup: func(s *StorageSeriesMock) {
+				s.On("GetByBook", ctx, "book-id").Return([]Series{{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id"}}}}}, nil)
+			},
+			want: []Series{{ID: "the-harry-bosch-id", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "book-id"}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageSeries := new(StorageSeriesMock)
+			tt.setup(storageSeries)
+
+			s := NewService(storageSeries, new(StorageBookMock), new(IndexerMock))
+
+			got, err := s.GetByBook(ctx, "book-id")
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageSeries.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageSeriesMock, *IndexerMock)
+		wantErr error
+	}{
+		{
+			name: "when failed to delete series",
+			setup: func(s *StorageSeriesMock, _ *IndexerMock) {
+				s.On("Delete", ctx, "the-harry-bosch-id").Return(assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successful to delete series",
+			setup: func(s *StorageSeriesMock, i *IndexerMock) {
+				s.On("Delete", ctx, "the-harry-bosch-id").Return(nil)
+				i.On("Remove", "series", "the-harry-bosch-id")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageSeries := new(StorageSeriesMock)
+			indexer := new(IndexerMock)
+			tt.setup(storageSeries, indexer)
+
+			s := NewService(storageSeries, nil, indexer)
+
+			err := s.Delete(ctx, "the-harry-bosch-id")
+
+			assert.Equal(t, tt.wantErr, err)
+			storageSeries.AssertExpectations(t)
+			indexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Documents(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		setup   func(*StorageSeriesMock)
+		want    []search.Document
+		wantErr error
+	}{
+		{
+			name: "when failed to get all series",
+			setup: func(s *StorageSeriesMock) {
+				s.On("GetAll", ctx).Return([]Series{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "when successful to get search documents",
+			setup: func(s *StorageSeriesMock) {
+				s.On("GetAll", ctx).Return([]Series{{ID: "the-harry-bosch-id", Title: "Bosch", Books: []BooksOrder{{Order: 1, Book: books.Book{ID: "123"}}}}}, nil)
+			},
+			want: []search.Document{{Type: "series", ID: "the-harry-bosch-id", Title: "Bosch"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageSeries := new(StorageSeriesMock)
+			tt.setup(storageSeries)
+
+			s := NewService(storageSeries, nil, new(IndexerMock))
+
+			got, err := s.Documents(ctx)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			storageSeries.AssertExpectations(t)
+		})
+	}
+}
+
 type StorageSeriesMock struct {
 	StorageSeries
 	mock.Mock
 }
 
-func (s *StorageSeriesMock) Save(ctx context.Context, series Series) (Series, error) {
+func (s *StorageSeriesMock) Save(ctx context.Context, series Series) (Series, bool, error) {
+	args := s.Called(ctx, series)
+	return args.Get(0).(Series), args.Bool(1), args.Error(2)
+}
+
+func (s *StorageSeriesMock) Update(ctx context.Context, series Series) (Series, error) {
 	args := s.Called(ctx, series)
 	return args.Get(0).(Series), args.Error(1)
 }
 
+func (s *StorageSeriesMock) Delete(ctx context.Context, seriesID string) error {
+	args := s.Called(ctx, seriesID)
+	return args.Error(0)
+}
+
+func (s *StorageSeriesMock) GetById(ctx context.Context, seriesID string) (Series, error) {
+	args := s.Called(ctx, seriesID)
+	return args.Get(0).(Series), args.Error(1)
+}
+
 func (s *StorageSeriesMock) GetByTitle(ctx context.Context, title string) (Series, error) {
 	args := s.Called(ctx, title)
 	return args.Get(0).(Series), args.Error(1)
 }
 
+func (s *StorageSeriesMock) GetBySlug(ctx context.Context, slug string) (Series, error) {
+	args := s.Called(ctx, slug)
+	return args.Get(0).(Series), args.Error(1)
+}
+
+func (s *StorageSeriesMock) GetByBook(ctx context.Context, bookID string) ([]Series, error) {
+	args := s.Called(ctx, bookID)
+	return args.Get(0).([]Series), args.Error(1)
+}
+
 func (s *StorageSeriesMock) GetAll(ctx context.Context) ([]Series, error) {
 	args := s.Called(ctx)
 	return args.Get(0).([]Series), args.Error(1)
 }
 
+func (s *StorageSeriesMock) GetPage(ctx context.Context, limit int32, cursor string) ([]Series, string, error) {
+	args := s.Called(ctx, limit, cursor)
+	return args.Get(0).([]Series), args.String(1), args.Error(2)
+}
+
 type StorageBookMock struct {
 	StorageBook
 	mock.Mock
 }
 
-func (s *StorageBookMock) GetByTitle(ctx context.Context, bookTitle string) (books.Book, error) {
-	args := s.Called(ctx, bookTitle)
-	return args.Get(0).(books.Book), args.Error(1)
+func (s *StorageBookMock) GetByIds(ctx context.Context, bookIDs []string) ([]books.Book, error) {
+	args := s.Called(ctx, bookIDs)
+	return args.Get(0).([]books.Book), args.Error(1)
 }
 
-func (s *StorageBookMock) GetById(ctx context.Context, bookID string) (books.Book, error) {
-	args := s.Called(ctx, bookID)
-	return args.Get(0).(books.Book), args.Error(1)
+func (s *StorageBookMock) GetBookListByTitles(ctx context.Context, bookTitles []string) ([]books.Book, error) {
+	args := s.Called(ctx, bookTitles)
+	return args.Get(0).([]books.Book), args.Error(1)
+}
+
+type IndexerMock struct {
+	mock.Mock
+}
+
+func (i *IndexerMock) Put(document search.Document) {
+	i.Called(document)
+}
+
+func (i *IndexerMock) Remove(documentType string, id string) {
+	i.Called(documentType, id)
 }
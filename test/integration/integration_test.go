@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -15,6 +16,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/ggoulart/michael-connelly-api/cmd/router"
+	appconfig "github.com/ggoulart/michael-connelly-api/internal/config"
+	"github.com/ggoulart/michael-connelly-api/internal/dynamo"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,6 +30,9 @@ func TestIntegration(t *testing.T) {
 	container := setupDynamoDB(t)
 	defer container.Terminate(context.Background())
 
+	viper.Set("auth.jwt.secret", "integration-secret")
+	token := signToken(t, "integration-secret", "books:write")
+
 	tests := []struct {
 		name       string
 		httpMethod string
@@ -100,7 +107,7 @@ func TestIntegration(t *testing.T) {
 			r := router.NewRouter()
 
 			req := httptest.NewRequest(tt.httpMethod, tt.targetURL, strings.NewReader(tt.body))
-			req.Header.Set("Authorization", "Bearer meu_token_secreto")
+			req.Header.Set("Authorization", "Bearer "+token)
 			w := httptest.NewRecorder()
 
 			clearTable(t, "books")
@@ -128,10 +135,35 @@ func setupDynamoDB(t *testing.T) testcontainers.Container {
 	require.NoError(t, err)
 
 	viper.Set("aws.dynamodb.endpoint", endpoint)
+	migrate(t)
 
 	return container
 }
 
+func migrate(t *testing.T) {
+	cfg, err := appconfig.Load(viper.GetViper())
+	require.NoError(t, err)
+
+	client, err := appconfig.NewDynamoDB(context.Background(), cfg.AWS)
+	require.NoError(t, err)
+
+	err = dynamo.NewClient(client, nil, cfg.Storage.Tables.UniqueKeys).Migrate(context.Background(), router.Schema(cfg.Storage.Tables))
+	require.NoError(t, err)
+}
+
+func signToken(t *testing.T, secret string, scope string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "integration",
+		"iss":   "michael-connelly-api",
+		"aud":   "michael-connelly-api",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": scope,
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return token
+}
+
 func seedBooks(t *testing.T) {
 	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion("us-east-1"))
 	require.NoError(t, err)